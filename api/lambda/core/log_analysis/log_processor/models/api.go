@@ -60,6 +60,8 @@ const (
 	LogData DataType = "LogData"
 	// RuleData represents log data that have matched some rule
 	RuleData DataType = "RuleMatches"
+	// ClassificationFailureData represents log lines that could not be classified
+	ClassificationFailureData DataType = "ClassificationFailures"
 )
 
 func (d DataType) String() string {
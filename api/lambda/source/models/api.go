@@ -29,12 +29,26 @@ type LambdaInput struct {
 	ListIntegrations          *ListIntegrationsInput          `json:"listIntegrations"`
 	DeleteIntegration         *DeleteIntegrationInput         `json:"deleteIntegration"`
 
+	AddSourceStats *AddSourceStatsInput `json:"addSourceStats"`
+
 	GetIntegrationTemplate *GetIntegrationTemplateInput `json:"getIntegrationTemplate"`
 
 	UpdateIntegrationLastScanEnd   *UpdateIntegrationLastScanEndInput   `json:"updateIntegrationLastScanEnd"`
 	UpdateIntegrationLastScanStart *UpdateIntegrationLastScanStartInput `json:"updateIntegrationLastScanStart"`
 
 	FullScan *FullScanInput `json:"fullScan"`
+
+	PutCustomLogType    *PutCustomLogTypeInput    `json:"putCustomLogType"`
+	ListCustomLogTypes  *ListCustomLogTypesInput  `json:"listCustomLogTypes"`
+	DeleteCustomLogType *DeleteCustomLogTypeInput `json:"deleteCustomLogType"`
+
+	PutThreatIntelIndicators  *PutThreatIntelIndicatorsInput  `json:"putThreatIntelIndicators"`
+	ListThreatIntelIndicators *ListThreatIntelIndicatorsInput `json:"listThreatIntelIndicators"`
+	DeleteThreatIntelFeed     *DeleteThreatIntelFeedInput     `json:"deleteThreatIntelFeed"`
+
+	PutRedactionRules    *PutRedactionRulesInput    `json:"putRedactionRules"`
+	ListRedactionRules   *ListRedactionRulesInput   `json:"listRedactionRules"`
+	DeleteRedactionRules *DeleteRedactionRulesInput `json:"deleteRedactionRules"`
 }
 
 //
@@ -80,6 +94,8 @@ type PutIntegrationSettings struct {
 	S3Prefix           *string   `json:"s3Prefix,omitempty" validate:"omitempty,min=1"`
 	KmsKey             *string   `json:"kmsKey,omitempty" validate:"omitempty,kmsKeyArn"`
 	LogTypes           []*string `json:"logTypes,omitempty" validate:"omitempty,min=1"`
+	// How the lines of the data are joined into events, if not set each line is an event
+	Multiline *MultilineConfig `json:"multiline,omitempty"`
 }
 
 //
@@ -89,6 +105,8 @@ type PutIntegrationSettings struct {
 // ListIntegrationsInput allows filtering by the IntegrationType or Enabled fields
 type ListIntegrationsInput struct {
 	IntegrationType *string `json:"integrationType" validate:"omitempty,oneof=aws-scan aws-s3"`
+	// IncludeStats adds the classification stats of the log analysis integrations, each one costs extra DynamoDB queries
+	IncludeStats *bool `json:"includeStats,omitempty"`
 }
 
 // UpdateIntegrationSettingsInput is used to update integration settings.
@@ -102,6 +120,8 @@ type UpdateIntegrationSettingsInput struct {
 	S3Prefix           *string   `json:"s3Prefix,omitempty" validate:"omitempty,min=1"`
 	KmsKey             *string   `json:"kmsKey,omitempty" validate:"omitempty,kmsKeyArn"`
 	LogTypes           []*string `json:"logTypes,omitempty" validate:"omitempty,min=1"`
+	// How the lines of the data are joined into events, if not set each line is an event
+	Multiline *MultilineConfig `json:"multiline,omitempty"`
 }
 
 // DeleteIntegrationInput is used to delete a specific item from the database.
@@ -109,6 +129,15 @@ type DeleteIntegrationInput struct {
 	IntegrationID *string `json:"integrationId" validate:"required,uuid4"`
 }
 
+//
+// AddSourceStats: Used by the log processor to record the data received by log analysis integrations
+//
+
+// AddSourceStatsInput is used to add the stats of the data processed by an invocation of the log processor.
+type AddSourceStatsInput struct {
+	Stats []*SourceStats `json:"stats" validate:"required,min=1,dive"`
+}
+
 //
 // FullScan: Used by the Scheduler to scan integrations
 //
@@ -153,3 +182,73 @@ type UpdateIntegrationLastScanEndInput struct {
 	LastScanErrorMessage *string    `json:"lastScanErrorMessage"`
 	ScanStatus           *string    `json:"scanStatus" validate:"required,oneof=ok error scanning"`
 }
+
+//
+// CustomLogTypes: Used by the UI to manage user defined log schemas and by the log processor to load them
+//
+
+// PutCustomLogTypeInput is used to add or replace a custom log type.
+type PutCustomLogTypeInput struct {
+	CustomLogType
+	UserID *string `json:"userId" validate:"required,uuid4"`
+}
+
+// ListCustomLogTypesInput is used to list all custom log types.
+type ListCustomLogTypesInput struct{}
+
+// DeleteCustomLogTypeInput is used to delete a custom log type.
+type DeleteCustomLogTypeInput struct {
+	LogType *string `json:"logType" validate:"required,customLogType"`
+}
+
+//
+// ThreatIntel: Used to manage the indicators of threat intel feeds and by the log processor to match them
+//
+
+// PutThreatIntelIndicatorsInput is used to add or replace indicators of a feed.
+type PutThreatIntelIndicatorsInput struct {
+	Feed       *string                 `json:"feed" validate:"required,threatIntelFeed"`
+	Indicators []*ThreatIntelIndicator `json:"indicators" validate:"required,min=1,max=10000,dive"`
+	UserID     *string                 `json:"userId" validate:"required,uuid4"`
+}
+
+// ListThreatIntelIndicatorsInput is used to list a page of the indicators of all feeds or of a single feed.
+type ListThreatIntelIndicatorsInput struct {
+	Feed              *string `json:"feed,omitempty" validate:"omitempty,threatIntelFeed"`
+	PageSize          *int    `json:"pageSize,omitempty" validate:"omitempty,min=1"`
+	ExclusiveStartKey *string `json:"exclusiveStartKey,omitempty"`
+}
+
+// ListThreatIntelIndicatorsOutput is a page of indicators.
+type ListThreatIntelIndicatorsOutput struct {
+	Indicators []*ThreatIntelIndicator `json:"indicators"`
+	// LastEvaluatedKey is set if there are more indicators, it is the ExclusiveStartKey of the next page.
+	LastEvaluatedKey *string `json:"lastEvaluatedKey,omitempty"`
+}
+
+// DeleteThreatIntelFeedInput is used to delete a feed with all its indicators.
+type DeleteThreatIntelFeedInput struct {
+	Feed *string `json:"feed" validate:"required,threatIntelFeed"`
+}
+
+//
+// Redaction: Used to manage the redaction rules of log types and by the log processor to apply them
+//
+
+// PutRedactionRulesInput is used to set the redaction rules of a log type, replacing any previous ones.
+type PutRedactionRulesInput struct {
+	LogType *string          `json:"logType" validate:"required,min=1,max=128"`
+	Rules   []*RedactionRule `json:"rules" validate:"required,min=1,max=100,dive"`
+	UserID  *string          `json:"userId" validate:"required,uuid4"`
+}
+
+// ListRedactionRulesInput is used to list the redaction rules of all log types.
+type ListRedactionRulesInput struct {
+	// The salts of the hashes are only needed by the log processor
+	IncludeSalts bool `json:"includeSalts,omitempty"`
+}
+
+// DeleteRedactionRulesInput is used to delete the redaction rules of a log type.
+type DeleteRedactionRulesInput struct {
+	LogType *string `json:"logType" validate:"required,min=1,max=128"`
+}
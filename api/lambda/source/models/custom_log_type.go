@@ -0,0 +1,71 @@
+package models
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import "time"
+
+// Supported types for the fields of a custom log type
+const (
+	CustomFieldTypeString    = "string"
+	CustomFieldTypeBigInt    = "bigint"
+	CustomFieldTypeDouble    = "double"
+	CustomFieldTypeBoolean   = "boolean"
+	CustomFieldTypeTimestamp = "timestamp"
+	CustomFieldTypeJSON      = "json"
+)
+
+// Supported indicators, the values of fields tagged with an indicator are added to the respective Panther field
+const (
+	CustomIndicatorIP     = "ip"
+	CustomIndicatorDomain = "domain"
+	CustomIndicatorMD5    = "md5"
+	CustomIndicatorSHA1   = "sha1"
+	CustomIndicatorSHA256 = "sha256"
+)
+
+// Supported timestamp formats besides Go time layouts
+const (
+	CustomTimestampRFC3339         = "rfc3339"
+	CustomTimestampUnix            = "unix"
+	CustomTimestampUnixMillisecond = "unix_ms"
+)
+
+// CustomLogTypePrefix is the required prefix for the names of all custom log types
+const CustomLogTypePrefix = "Custom."
+
+// CustomLogType is a user defined schema for JSON logs
+type CustomLogType struct {
+	LogType         *string               `json:"logType" validate:"required,customLogType"`
+	Description     *string               `json:"description" validate:"required,min=1"`
+	TimestampField  *string               `json:"timestampField,omitempty" validate:"omitempty,min=1"`
+	TimestampFormat *string               `json:"timestampFormat,omitempty" validate:"omitempty,min=1"`
+	Fields          []*CustomLogTypeField `json:"fields" validate:"required,min=1,dive"`
+	Multiline       *MultilineConfig      `json:"multiline,omitempty"`
+	CreatedAtTime   *time.Time            `json:"createdAtTime"`
+	CreatedBy       *string               `json:"createdBy"`
+}
+
+// CustomLogTypeField describes a single top level field of a custom log type
+type CustomLogTypeField struct {
+	Name        *string   `json:"name" validate:"required,min=1"`
+	Type        *string   `json:"type" validate:"required,oneof=string bigint double boolean timestamp json"`
+	Description *string   `json:"description,omitempty"`
+	Required    *bool     `json:"required,omitempty"`
+	Indicators  []*string `json:"indicators,omitempty" validate:"omitempty,dive,oneof=ip domain md5 sha1 sha256"`
+}
@@ -25,6 +25,7 @@ type SourceIntegration struct {
 	SourceIntegrationMetadata
 	SourceIntegrationStatus
 	SourceIntegrationScanInformation
+	SourceIntegrationStats
 }
 
 // SourceIntegrationStatus provides context that the full scan works and that events are being received.
@@ -40,6 +41,40 @@ type SourceIntegrationScanInformation struct {
 	LastScanStartTime    *time.Time `json:"lastScanStartTime"`
 }
 
+// SourceIntegrationStats are the classification stats of the data received by a log analysis integration.
+type SourceIntegrationStats struct {
+	LastEventReceivedTime *time.Time `json:"lastEventReceivedTime,omitempty"`
+	LastEventTime         *time.Time `json:"lastEventTime,omitempty"`
+	EventsLast24Hours     *uint64    `json:"eventsLast24Hours,omitempty"`
+	// ClassificationFailureRate is the ratio of the log lines received in the last 24 hours that failed classification
+	ClassificationFailureRate *float64                    `json:"classificationFailureRate,omitempty"`
+	LogTypesLast24Hours       []*SourceIntegrationLogType `json:"logTypesLast24Hours,omitempty"`
+}
+
+// SourceIntegrationLogType are the classification stats of the data of a log type received by an integration.
+type SourceIntegrationLogType struct {
+	LogType       *string    `json:"logType"`
+	EventCount    *uint64    `json:"eventCount"`
+	LastEventTime *time.Time `json:"lastEventTime,omitempty"`
+}
+
+// SourceStats are the classification stats of the data received by a log analysis integration in an hour,
+// for all log types or for a single log type.
+type SourceStats struct {
+	IntegrationID *string `json:"integrationId" validate:"required,uuid4"`
+	// LogType is empty for the stats of all log types
+	LogType *string `json:"logType,omitempty"`
+	// ReceivedTime is when data was last received, the stats are added to the ones of its hour
+	ReceivedTime  *time.Time `json:"receivedTime" validate:"required"`
+	LastEventTime *time.Time `json:"lastEventTime,omitempty"`
+
+	LogLineCount        uint64 `json:"logLineCount"`
+	BytesProcessedCount uint64 `json:"bytesProcessedCount"`
+	EventCount          uint64 `json:"eventCount"`
+	// ClassificationFailureCount is only counted for all log types
+	ClassificationFailureCount uint64 `json:"classificationFailureCount"`
+}
+
 // SourceIntegrationMetadata is general settings and metadata for an integration.
 type SourceIntegrationMetadata struct {
 	AWSAccountID       *string    `json:"awsAccountId"`
@@ -57,6 +92,9 @@ type SourceIntegrationMetadata struct {
 	LogTypes           []*string  `json:"logTypes,omitempty"`
 	LogProcessingRole  *string    `json:"logProcessingRole,omitempty"`
 	StackName          *string    `json:"stackName,omitempty"`
+
+	// How the lines of the data are joined into events, if not set each line is an event
+	Multiline *MultilineConfig `json:"multiline,omitempty"`
 }
 
 type SourceIntegrationHealth struct {
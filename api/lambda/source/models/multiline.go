@@ -0,0 +1,36 @@
+package models
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// DefaultMultilineMaxEventSize is the size limit of the events assembled from multiple lines, unless configured otherwise
+const DefaultMultilineMaxEventSize = 1024 * 1024
+
+// MultilineConfig configures how the lines of the data of a source or log type are joined into events before they
+// are classified (e.g., stack traces or pretty printed JSON).
+//
+// A line is joined to the event of the previous lines if it matches ContinuationPattern or if it does not match
+// StartPattern. If JSON is set, lines starting a JSON object or array are joined with the following lines until
+// the braces are balanced. Events larger than MaxEventSize are truncated.
+type MultilineConfig struct {
+	StartPattern        *string `json:"startPattern,omitempty" validate:"omitempty,min=1,regexp"`
+	ContinuationPattern *string `json:"continuationPattern,omitempty" validate:"omitempty,min=1,regexp"`
+	JSON                *bool   `json:"json,omitempty"`
+	// At most 10 MiB, so that an event fits in the memory of the log processor
+	MaxEventSize *int `json:"maxEventSize,omitempty" validate:"omitempty,min=1,max=10485760"`
+}
@@ -0,0 +1,57 @@
+package models
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import "time"
+
+// Actions of the redaction rules
+const (
+	// RedactionDrop removes the field from the event
+	RedactionDrop = "drop"
+	// RedactionHash replaces the value with its HMAC-SHA256, keyed with the salt of the log type, so values
+	// can still be correlated without being stored
+	RedactionHash = "hash"
+	// RedactionMask replaces all but the last 4 characters of the value with '*', values of up to 8 characters completely
+	RedactionMask = "mask"
+	// RedactionReplace replaces the value with the replacement, which can reference the groups of the pattern
+	RedactionReplace = "replace"
+)
+
+// RedactionRule describes how the values of a field of the events of a log type are redacted before they are stored.
+//
+// Field is the path of the field in the JSON of the event, with '.' separating the names of nested fields
+// (e.g., requestParameters.password), arrays are traversed element by element. If Pattern is set, hash, mask and replace
+// apply to the parts of the values that match it and drop applies only if the value matches it. Values that are not
+// strings (numbers, booleans and objects) cannot hold the redacted values, any rule that applies to them drops them.
+type RedactionRule struct {
+	Field       *string `json:"field" validate:"required,min=1,max=1024"`
+	Action      *string `json:"action" validate:"required,oneof=drop hash mask replace"`
+	Pattern     *string `json:"pattern,omitempty" validate:"omitempty,min=1,regexp"`
+	Replacement *string `json:"replacement,omitempty" validate:"omitempty,max=1024"`
+}
+
+// RedactionRules are the redaction rules of a log type
+type RedactionRules struct {
+	LogType *string          `json:"logType"`
+	Rules   []*RedactionRule `json:"rules"`
+	// The base64 encoded salt of the hashes, only returned to the log processor
+	Salt             *string    `json:"salt,omitempty"`
+	LastModifiedTime *time.Time `json:"lastModifiedTime,omitempty"`
+	LastModifiedBy   *string    `json:"lastModifiedBy,omitempty"`
+}
@@ -0,0 +1,87 @@
+package models
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Supported types of threat intel indicators, they are matched against the respective Panther fields
+const (
+	ThreatIntelIndicatorIP     = "ip"
+	ThreatIntelIndicatorDomain = "domain"
+	ThreatIntelIndicatorMD5    = "md5"
+	ThreatIntelIndicatorSHA1   = "sha1"
+	ThreatIntelIndicatorSHA256 = "sha256"
+)
+
+// ThreatIntelIndicator is an indicator of compromise of a threat intel feed
+type ThreatIntelIndicator struct {
+	Feed          *string    `json:"feed,omitempty"`
+	Type          *string    `json:"type" validate:"required,oneof=ip domain md5 sha1 sha256"`
+	Value         *string    `json:"value" validate:"required,min=1,max=1024"`
+	Description   *string    `json:"description,omitempty"`
+	CreatedAtTime *time.Time `json:"createdAtTime,omitempty"`
+	CreatedBy     *string    `json:"createdBy,omitempty"`
+}
+
+var (
+	threatIntelDomainRegex = regexp.MustCompile(`^[a-z0-9_]([a-z0-9_-]*[a-z0-9_])?(\.[a-z0-9_]([a-z0-9_-]*[a-z0-9_])?)*$`)
+	threatIntelHexRegex    = regexp.MustCompile(`^[0-9a-f]+$`)
+
+	// the length of the hex encoded hashes
+	threatIntelHashLengths = map[string]int{
+		ThreatIntelIndicatorMD5:    32,
+		ThreatIntelIndicatorSHA1:   40,
+		ThreatIntelIndicatorSHA256: 64,
+	}
+)
+
+// NormalizeThreatIntelIndicator returns the canonical form of an indicator, the form the Panther fields are compared in:
+// ip addresses as formatted by net.IP, domains and hashes in lower case.
+func NormalizeThreatIntelIndicator(indicatorType, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch indicatorType {
+	case ThreatIntelIndicatorIP:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", errors.Errorf("invalid ip address %q", value)
+		}
+		return ip.String(), nil
+	case ThreatIntelIndicatorDomain:
+		domain := strings.TrimSuffix(strings.ToLower(value), ".")
+		if !threatIntelDomainRegex.MatchString(domain) {
+			return "", errors.Errorf("invalid domain %q", value)
+		}
+		return domain, nil
+	case ThreatIntelIndicatorMD5, ThreatIntelIndicatorSHA1, ThreatIntelIndicatorSHA256:
+		hash := strings.ToLower(value)
+		if len(hash) != threatIntelHashLengths[indicatorType] || !threatIntelHexRegex.MatchString(hash) {
+			return "", errors.Errorf("invalid %s hash %q", indicatorType, value)
+		}
+		return hash, nil
+	default:
+		return "", errors.Errorf("unknown indicator type %q", indicatorType)
+	}
+}
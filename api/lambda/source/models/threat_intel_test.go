@@ -0,0 +1,54 @@
+package models
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeThreatIntelIndicator(t *testing.T) {
+	for _, tc := range []struct {
+		indicatorType string
+		value         string
+		expected      string
+	}{
+		{"ip", " 192.0.2.1 ", "192.0.2.1"},
+		{"ip", "2001:DB8:0:0::1", "2001:db8::1"},
+		{"ip", "::ffff:192.0.2.1", "192.0.2.1"},
+		{"ip", "192.0.2.256", ""},
+		{"domain", "Evil.Example.COM.", "evil.example.com"},
+		{"domain", "evil example.com", ""},
+		{"domain", "-evil.com", ""},
+		{"md5", "D41D8CD98F00B204E9800998ECF8427E", "d41d8cd98f00b204e9800998ecf8427e"},
+		{"md5", "d41d8cd98f00b204e9800998ecf8427", ""},
+		{"sha1", "da39a3ee5e6b4b0d3255bfef95601890afd80709", "da39a3ee5e6b4b0d3255bfef95601890afd80709"},
+		{"sha256", "da39a3ee5e6b4b0d3255bfef95601890afd80709", ""},
+		{"url", "http://evil.com", ""},
+	} {
+		actual, err := NormalizeThreatIntelIndicator(tc.indicatorType, tc.value)
+		if tc.expected == "" {
+			assert.Error(t, err, tc.value)
+			continue
+		}
+		assert.NoError(t, err, tc.value)
+		assert.Equal(t, tc.expected, actual)
+	}
+}
@@ -32,6 +32,8 @@ const (
 
 var (
 	integrationLabelValidatorRegex = regexp.MustCompile("^[0-9a-zA-Z- ]+$")
+	customLogTypeValidatorRegex    = regexp.MustCompile(`^Custom\.[0-9a-zA-Z_]+$`)
+	threatIntelFeedValidatorRegex  = regexp.MustCompile(`^[0-9a-zA-Z_.-]{1,64}$`)
 )
 
 // Validator builds a custom struct validator.
@@ -43,6 +45,15 @@ func Validator() (*validator.Validate, error) {
 	if err := result.RegisterValidation("kmsKeyArn", validateKmsKeyArn); err != nil {
 		return nil, err
 	}
+	if err := result.RegisterValidation("customLogType", validateCustomLogType); err != nil {
+		return nil, err
+	}
+	if err := result.RegisterValidation("threatIntelFeed", validateThreatIntelFeed); err != nil {
+		return nil, err
+	}
+	if err := result.RegisterValidation("regexp", validateRegexp); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
@@ -66,3 +77,19 @@ func validateKmsKeyArn(fl validator.FieldLevel) bool {
 	}
 	return true
 }
+
+// Custom log types must have the Custom. prefix and a name that can be used as a Glue table name
+func validateCustomLogType(fl validator.FieldLevel) bool {
+	return customLogTypeValidatorRegex.MatchString(fl.Field().String())
+}
+
+// Threat intel feed names are recorded in the matches of every event, they are kept short and simple
+func validateThreatIntelFeed(fl validator.FieldLevel) bool {
+	return threatIntelFeedValidatorRegex.MatchString(fl.Field().String())
+}
+
+// Patterns are compiled by the log processor, they must be valid Go regular expressions
+func validateRegexp(fl validator.FieldLevel) bool {
+	_, err := regexp.Compile(fl.Field().String())
+	return err == nil
+}
@@ -81,3 +81,68 @@ func TestValidateKmsKey(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestValidateCustomLogType(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	require.NoError(t, validator.Struct(&DeleteCustomLogTypeInput{
+		LogType: aws.String("Custom.My_Service1"),
+	}))
+	require.Error(t, validator.Struct(&DeleteCustomLogTypeInput{
+		LogType: aws.String("AWS.CloudTrail"),
+	}))
+	require.Error(t, validator.Struct(&DeleteCustomLogTypeInput{
+		LogType: aws.String("Custom.my-service"),
+	}))
+}
+
+func TestValidateThreatIntelFeed(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	require.NoError(t, validator.Struct(&DeleteThreatIntelFeedInput{
+		Feed: aws.String("abuse.ch-feodo_tracker"),
+	}))
+	require.Error(t, validator.Struct(&DeleteThreatIntelFeedInput{
+		Feed: aws.String("my feed"),
+	}))
+	require.Error(t, validator.Struct(&DeleteThreatIntelFeedInput{
+		Feed: aws.String(""),
+	}))
+}
+
+func TestValidateMultilineConfig(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	require.NoError(t, validator.Struct(&MultilineConfig{
+		StartPattern: aws.String(`^\d{4}-\d{2}-\d{2} `),
+		MaxEventSize: aws.Int(64 * 1024),
+	}))
+	require.NoError(t, validator.Struct(&MultilineConfig{
+		JSON: aws.Bool(true),
+	}))
+	require.Error(t, validator.Struct(&MultilineConfig{
+		ContinuationPattern: aws.String(`^\s+at (`),
+	}))
+	require.Error(t, validator.Struct(&MultilineConfig{
+		MaxEventSize: aws.Int(100 * 1024 * 1024),
+	}))
+}
+
+func TestValidateRedactionRules(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := &PutRedactionRulesInput{
+		LogType: aws.String("AWS.CloudTrail"),
+		Rules: []*RedactionRule{
+			{Field: aws.String("userIdentity.userName"), Action: aws.String(RedactionHash)},
+			{Field: aws.String("requestParameters"), Action: aws.String(RedactionReplace), Pattern: aws.String(`\d{16}`)},
+		},
+		UserID: aws.String("a7e2d3a0-6d9f-4f0e-8d8f-9a0b1c2d3e4f"),
+	}
+	require.NoError(t, validator.Struct(input))
+	input.Rules[0].Action = aws.String("encrypt")
+	require.Error(t, validator.Struct(input))
+	input.Rules[0].Action = aws.String(RedactionHash)
+	input.Rules[1].Pattern = aws.String(`(\d{16}`)
+	require.Error(t, validator.Struct(input))
+}
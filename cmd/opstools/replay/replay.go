@@ -0,0 +1,432 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
+	"github.com/panther-labs/panther/pkg/awsbatch/sqsbatch"
+)
+
+const (
+	pageSize             = 1000
+	fakeTopicArnTemplate = "arn:aws:sns:us-east-1:%s:panther-fake-replay-topic" // account is added for sqs messages
+	progressNotify       = 5000                                                 // log a line every this many to show progress
+
+	maxBufferedMemBytes = 512 * 1024 * 1024 // memory used to buffer the output when processing locally
+
+	sqsBatchTimeout = time.Minute
+	sqsBatchSize    = 10
+)
+
+// Config describes the data to replay and where the results are written
+type Config struct {
+	// S3Path is the s3 path to list (e.g., s3://<bucket>/<prefix>), ignored if S3Keys are set
+	S3Path string
+	// S3Keys are the s3 paths of the objects to replay (e.g., s3://<bucket>/<key>)
+	S3Keys []string
+	// LogType is the log type the data is parsed as
+	LogType string
+	// Only objects last modified in [Start, End) are replayed, zero values are unbounded
+	Start time.Time
+	End   time.Time
+	// Limit is the maximum number of objects replayed, zero means no limit
+	Limit uint64
+	// QueueName is the log processor queue the objects are sent to, if empty they are processed locally
+	QueueName string
+	// Account is the Panther AWS account id, needed when sending to the queue
+	Account string
+	// Bucket is the bucket the results are written to when processing locally
+	Bucket string
+	// DestinationPrefix is the S3 key prefix the results are written under.
+	// When sending to the queue, it is relative to destinations.ReplayS3Prefix in the processed data bucket and
+	// if it is empty the results are written to the log tables and analyzed.
+	DestinationPrefix string
+	Verbose           bool
+}
+
+type Stats struct {
+	NumFiles uint64
+	NumBytes uint64
+}
+
+// Replay re-processes the S3 objects described by config as the configured log type
+func Replay(sess *session.Session, s3Region string, config *Config, stats *Stats) error {
+	s3Client := s3.New(sess.Copy(&aws.Config{Region: &s3Region}))
+	if config.QueueName != "" {
+		return replayQueue(s3Client, sqs.New(sess), config, stats)
+	}
+	return replayLocal(s3Client, s3manager.NewUploader(sess), config, stats)
+}
+
+// replayLocal runs the log processor pipeline in this process, writing to the configured bucket
+func replayLocal(s3Client s3iface.S3API, s3Uploader s3manageriface.UploaderAPI, config *Config, stats *Stats) error {
+	if config.Bucket == "" || config.DestinationPrefix == "" {
+		return errors.New("a bucket and a destination prefix are needed to replay locally")
+	}
+
+	objectChan := make(chan *sources.S3ObjectInfo, pageSize)
+	listErrChan := make(chan error, 1)
+	go func() {
+		listErrChan <- listObjects(s3Client, config, objectChan, stats)
+	}()
+
+	streamChan := make(chan *common.DataStream) // unbuffered so objects are opened only when the processor needs them
+	readErrChan := make(chan error, 1)
+	go func() {
+		defer close(streamChan)
+		var readErr error
+		for object := range objectChan {
+			if readErr != nil { // drain channel
+				continue
+			}
+			var dataStreams []*common.DataStream
+			dataStreams, readErr = newDataStreams(s3Client, object, config.LogType)
+			if readErr != nil {
+				continue
+			}
+			if config.Verbose {
+				zap.L().Info("processing file",
+					zap.String("bucket", object.S3Bucket),
+					zap.String("key", object.S3ObjectKey))
+			}
+			for _, dataStream := range dataStreams {
+				streamChan <- dataStream
+			}
+		}
+		readErrChan <- readErr
+	}()
+
+	destination := destinations.NewS3ReplayDestination(s3Uploader, config.Bucket,
+		normalizePrefix(config.DestinationPrefix), maxBufferedMemBytes)
+	if err := processor.Process(streamChan, destination); err != nil {
+		return err
+	}
+	if err := <-readErrChan; err != nil {
+		return err
+	}
+	return <-listErrChan
+}
+
+func newDataStreams(s3Client s3iface.S3API, object *sources.S3ObjectInfo, logType string) ([]*common.DataStream, error) {
+	dataStreams, err := sources.ReadS3Object(s3Client, object)
+	if err != nil {
+		return nil, err
+	}
+	for _, dataStream := range dataStreams {
+		// classification failures are replayed as the lines that were originally received
+		if isFailureObject(object.S3ObjectKey) {
+			dataStream.Reader = newFailureLinesReader(dataStream.Reader)
+		}
+		dataStream.LogTypes = []string{logType}
+	}
+	return dataStreams, nil
+}
+
+// HasFailureObjects returns true if the objects to replay are classification failures.
+// Objects listed under a broader path are only checked when they are sent to the queue.
+func HasFailureObjects(config *Config) bool {
+	s3Paths := config.S3Keys
+	if config.S3Path != "" {
+		s3Paths = []string{config.S3Path}
+	}
+	for _, s3Path := range s3Paths {
+		if _, key, err := ParseS3Path(s3Path); err == nil && isFailureObject(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFailureObject returns true if the key is an object of the classification failures table
+func isFailureObject(key string) bool {
+	return strings.HasPrefix(key, classification.FailureTable.Prefix())
+}
+
+// newFailureLinesReader returns a reader of the log lines of the stored classification failures read from reader
+func newFailureLinesReader(reader io.Reader) io.Reader {
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		var err error
+		decoder := jsoniter.NewDecoder(reader)
+		for decoder.More() {
+			var failure struct {
+				Line *string `json:"line"`
+			}
+			if err = decoder.Decode(&failure); err != nil {
+				break
+			}
+			if failure.Line == nil {
+				continue
+			}
+			if _, err = io.WriteString(pipeWriter, *failure.Line+"\n"); err != nil { // reader was closed
+				return
+			}
+		}
+		pipeWriter.CloseWithError(err) // nil closes with io.EOF
+	}()
+	return pipeReader
+}
+
+// replayQueue posts a message per object to the log processor queue as-if it was an S3 notification
+func replayQueue(s3Client s3iface.S3API, sqsClient sqsiface.SQSAPI, config *Config, stats *Stats) error {
+	queueURL, err := sqsClient.GetQueueUrl(&sqs.GetQueueUrlInput{
+		QueueName: &config.QueueName,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not get queue url for %s", config.QueueName)
+	}
+
+	objectChan := make(chan *sources.S3ObjectInfo, pageSize)
+	listErrChan := make(chan error, 1)
+	go func() {
+		listErrChan <- listObjects(s3Client, config, objectChan, stats)
+	}()
+
+	if err := queueNotifications(sqsClient, queueURL.QueueUrl, config, objectChan); err != nil {
+		return err
+	}
+	return <-listErrChan
+}
+
+func queueNotifications(sqsClient sqsiface.SQSAPI, queueURL *string, config *Config,
+	objectChan chan *sources.S3ObjectInfo) (failed error) {
+
+	// the account id is taken from this arn to assume role for reading in the log processor
+	topicARN := fmt.Sprintf(fakeTopicArnTemplate, config.Account)
+
+	// the log processor reads the log type and destination of replayed data from the message attributes
+	messageAttributes := &replayMessageAttributes{
+		LogType: newStringMessageAttribute(config.LogType),
+	}
+	if config.DestinationPrefix != "" {
+		messageAttributes.DestinationPrefix = newStringMessageAttribute(normalizePrefix(config.DestinationPrefix))
+	}
+
+	sendMessageBatchInput := &sqs.SendMessageBatchInput{
+		QueueUrl: queueURL,
+	}
+	for object := range objectChan {
+		if failed != nil { // drain channel
+			continue
+		}
+
+		// the log processor reads the objects as they are, only local processing extracts the failed lines
+		if isFailureObject(object.S3ObjectKey) {
+			failed = errors.Errorf("classification failures cannot be sent to the queue, replay s3://%s/%s locally",
+				object.S3Bucket, object.S3ObjectKey)
+			continue
+		}
+
+		if config.Verbose {
+			zap.L().Info("sending file to SQS",
+				zap.String("bucket", object.S3Bucket),
+				zap.String("key", object.S3ObjectKey))
+		}
+
+		message, err := newNotificationMessage(object, topicARN, messageAttributes)
+		if err != nil {
+			failed = err
+			continue
+		}
+
+		sendMessageBatchInput.Entries = append(sendMessageBatchInput.Entries, &sqs.SendMessageBatchRequestEntry{
+			Id:          aws.String(strconv.Itoa(len(sendMessageBatchInput.Entries))),
+			MessageBody: &message,
+		})
+		if len(sendMessageBatchInput.Entries)%sqsBatchSize == 0 {
+			if _, err = sqsbatch.SendMessageBatch(sqsClient, sqsBatchTimeout, sendMessageBatchInput); err != nil {
+				failed = errors.Wrapf(err, "failed to send %#v", sendMessageBatchInput)
+				continue
+			}
+			sendMessageBatchInput.Entries = make([]*sqs.SendMessageBatchRequestEntry, 0, sqsBatchSize) // reset
+		}
+	}
+
+	// send remaining
+	if failed == nil && len(sendMessageBatchInput.Entries) > 0 {
+		if _, err := sqsbatch.SendMessageBatch(sqsClient, sqsBatchTimeout, sendMessageBatchInput); err != nil {
+			failed = errors.Wrapf(err, "failed to send %#v", sendMessageBatchInput)
+		}
+	}
+	return failed
+}
+
+// newNotificationMessage returns an SNS notification of an S3 event for the object
+func newNotificationMessage(object *sources.S3ObjectInfo, topicARN string,
+	messageAttributes *replayMessageAttributes) (string, error) {
+
+	s3Notification := &events.S3Event{
+		Records: []events.S3EventRecord{
+			{
+				S3: events.S3Entity{
+					Bucket: events.S3Bucket{
+						Name: object.S3Bucket,
+					},
+					Object: events.S3Object{
+						Key: object.S3ObjectKey,
+					},
+				},
+			},
+		},
+	}
+	ctnJSON, err := jsoniter.MarshalToString(s3Notification)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal %#v", s3Notification)
+	}
+
+	snsNotification := replayNotification{
+		SNSEntity: events.SNSEntity{
+			Type:     "Notification",
+			TopicArn: topicARN, // this is needed by the log processor to get account associated with the S3 object
+			Message:  ctnJSON,
+		},
+		MessageAttributes: messageAttributes,
+	}
+	message, err := jsoniter.MarshalToString(snsNotification)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal %#v", snsNotification)
+	}
+	return message, nil
+}
+
+// replayNotification is an SNS notification with the message attributes of replayed data
+type replayNotification struct {
+	events.SNSEntity
+	MessageAttributes *replayMessageAttributes `json:"MessageAttributes"`
+}
+
+type replayMessageAttributes struct {
+	LogType           *messageAttribute `json:"replayLogType,omitempty"`           // sources.ReplayLogTypeAttribute
+	DestinationPrefix *messageAttribute `json:"replayDestinationPrefix,omitempty"` // sources.ReplayDestinationPrefixAttribute
+}
+
+type messageAttribute struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+func newStringMessageAttribute(value string) *messageAttribute {
+	return &messageAttribute{
+		Type:  "String",
+		Value: value,
+	}
+}
+
+// listObjects sends the objects to replay to objectChan, closing it when done
+func listObjects(s3Client s3iface.S3API, config *Config, objectChan chan *sources.S3ObjectInfo, stats *Stats) error {
+	defer close(objectChan) // signal to reader that we are done
+
+	limit := config.Limit
+	if limit == 0 {
+		limit = math.MaxUint64
+	}
+
+	// returns false once the limit is reached
+	sendObject := func(bucket, key string, size int64, lastModified time.Time) bool {
+		if size == 0 || !config.inTimeWindow(lastModified) { // we only care about objects with size in the window
+			return true
+		}
+		stats.NumFiles++
+		if stats.NumFiles%progressNotify == 0 {
+			log.Printf("listed %d files ...", stats.NumFiles)
+		}
+		stats.NumBytes += (uint64)(size)
+		objectChan <- &sources.S3ObjectInfo{
+			S3Bucket:    bucket,
+			S3ObjectKey: key,
+		}
+		return stats.NumFiles < limit
+	}
+
+	if len(config.S3Keys) > 0 {
+		for _, s3Path := range config.S3Keys {
+			bucket, key, err := ParseS3Path(s3Path)
+			if err != nil {
+				return err
+			}
+			head, err := s3Client.HeadObject(&s3.HeadObjectInput{
+				Bucket: &bucket,
+				Key:    &key,
+			})
+			if err != nil {
+				return errors.Wrapf(err, "HeadObject() failed for %s", s3Path)
+			}
+			if !sendObject(bucket, key, aws.Int64Value(head.ContentLength), aws.TimeValue(head.LastModified)) {
+				break
+			}
+		}
+		return nil
+	}
+
+	bucket, prefix, err := ParseS3Path(config.S3Path)
+	if err != nil {
+		return err
+	}
+	inputParams := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucket),
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int64(pageSize),
+	}
+	return s3Client.ListObjectsV2Pages(inputParams, func(page *s3.ListObjectsV2Output, morePages bool) bool {
+		for _, value := range page.Contents {
+			if !sendObject(bucket, *value.Key, aws.Int64Value(value.Size), aws.TimeValue(value.LastModified)) {
+				return false // "To stop iterating, return false from the fn function."
+			}
+		}
+		return true
+	})
+}
+
+func (config *Config) inTimeWindow(t time.Time) bool {
+	return (config.Start.IsZero() || !t.Before(config.Start)) && (config.End.IsZero() || t.Before(config.End))
+}
+
+// ParseS3Path returns the bucket and key of an s3 path (e.g., s3://<bucket>/<key>)
+func ParseS3Path(s3path string) (bucket, key string, err error) {
+	parsedPath, err := url.Parse(s3path)
+	if err != nil {
+		return "", "", errors.Errorf("bad s3 url: %s,", err)
+	}
+	if parsedPath.Scheme != "s3" {
+		return "", "", errors.Errorf("not s3 protocol (expecting s3://): %s,", s3path)
+	}
+	if parsedPath.Host == "" {
+		return "", "", errors.Errorf("missing bucket: %s,", s3path)
+	}
+	if len(parsedPath.Path) > 0 {
+		key = parsedPath.Path[1:] // remove leading '/'
+	}
+	return parsedPath.Host, key, nil
+}
+
+// keys are written under the prefix as a "directory"
+func normalizePrefix(prefix string) string {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		return prefix + "/"
+	}
+	return prefix
+}
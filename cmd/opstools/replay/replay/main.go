@@ -0,0 +1,245 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/panther-labs/panther/cmd/opstools/replay"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+)
+
+const (
+	banner = "re-processes s3 objects as a log type, writing the results under a prefix so they can be checked before promotion"
+)
+
+var (
+	REGION  = flag.String("region", "", "The Panther AWS region (optional, defaults to session env vars).")
+	ACCOUNT = flag.String("account", "", "The Panther AWS account id (optional, defaults to session account)")
+	S3PATH  = flag.String("s3path", "", "The s3 path to list (e.g., s3://<bucket>/<prefix>).")
+	S3KEYS  = flag.String("s3keys", "",
+		"Comma separated s3 paths of the objects to replay (e.g., s3://<bucket>/<key>), instead of listing -s3path.")
+	LOGTYPE = flag.String("logtype", "", "The log type the data is parsed as (e.g., AWS.VPCFlow).")
+	START   = flag.String("start", "", "If set, only objects last modified at or after this RFC3339 time are replayed.")
+	END     = flag.String("end", "", "If set, only objects last modified before this RFC3339 time are replayed.")
+	LIMIT   = flag.Uint64("limit", 0, "If non-zero, then limit the number of files to this number.")
+	TOQ     = flag.String("queue", "",
+		"If set, the name of the log processor queue to send notifications to (e.g., panther-input-data-notifications-queue)."+
+			" The objects must be in a bucket of a configured source. If not set, the objects are processed locally.")
+	BUCKET = flag.String("bucket", "", "The bucket the results are written to when processing locally.")
+	PREFIX = flag.String("prefix", "",
+		"The S3 key prefix the results are written under. Required when processing locally."+
+			" When sending to the queue, it is relative to the "+destinations.ReplayS3Prefix+" prefix of the processed data bucket"+
+			" and if not set the results are written to the log tables and analyzed.")
+	PARQUET = flag.Bool("parquet", false,
+		"Write the results as Parquet when processing locally, set it for log types stored as Parquet.")
+	VERBOSE = flag.Bool("verbose", false, "Enable verbose logging")
+
+	logger *zap.SugaredLogger
+)
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(),
+		"%s %s\nUsage:\n",
+		filepath.Base(os.Args[0]), banner)
+	flag.PrintDefaults()
+}
+
+func init() {
+	flag.Usage = usage
+
+	config := zap.NewDevelopmentConfig() // DEBUG by default
+	if !*VERBOSE {
+		// In normal mode, hide DEBUG messages and file/line numbers
+		config.DisableCaller = true
+		config.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	}
+
+	// Always disable error traces and use color-coded log levels and short timestamps
+	config.DisableStacktrace = true
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+
+	rawLogger, err := config.Build()
+	if err != nil {
+		log.Fatalf("failed to build logger: %s", err)
+	}
+	zap.ReplaceGlobals(rawLogger)
+	logger = rawLogger.Sugar()
+}
+
+func main() {
+	flag.Parse()
+
+	config := validateFlags()
+
+	sess, err := session.NewSession()
+	if err != nil {
+		logger.Fatal(err)
+		return
+	}
+
+	if *REGION != "" { //override
+		sess.Config.Region = REGION
+	} else {
+		REGION = sess.Config.Region
+	}
+
+	// custom log types are registered in the Panther account
+	if err = registry.LoadCustomLogTypes(lambda.New(sess)); err != nil {
+		logger.Warnf("failed to load custom log types: %v", err)
+	}
+	if registry.AvailableParsers().LookupParser(config.LogType) == nil {
+		logger.Fatalf("unknown log type %s", config.LogType)
+	}
+	if *PARQUET {
+		if err = destinations.EnableParquet([]string{config.LogType}); err != nil {
+			logger.Fatal(err)
+		}
+	}
+
+	s3Region := getS3Region(sess, config)
+
+	if *ACCOUNT == "" {
+		identity, err := sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		if err != nil {
+			logger.Fatalf("failed to get caller identity: %v", err)
+		}
+		ACCOUNT = identity.Account
+	}
+	config.Account = *ACCOUNT
+
+	startTime := time.Now()
+	destination := *TOQ
+	if destination == "" {
+		destination = "s3://" + *BUCKET + "/" + *PREFIX
+	}
+	if *VERBOSE {
+		logger.Infof("replaying files from %s in %s as %s to %s", source(config), s3Region, config.LogType, destination)
+	}
+
+	stats := &replay.Stats{}
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGTERM, syscall.SIGINT)
+		caught := <-sig // wait for it
+		logger.Fatalf("caught %v, replayed %d files (%.2fMB) to %s in %v",
+			caught, stats.NumFiles, float32(stats.NumBytes)/(1024.0*1024.0), destination, time.Since(startTime))
+	}()
+
+	err = replay.Replay(sess, s3Region, config, stats)
+	if err != nil {
+		logger.Fatal(err)
+	} else {
+		logger.Infof("replayed %d files (%.2fMB) to %s (%s) in %v",
+			stats.NumFiles, float32(stats.NumBytes)/(1024.0*1024.0), destination, *REGION, time.Since(startTime))
+	}
+}
+
+func validateFlags() *replay.Config {
+	var err error
+	defer func() {
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			flag.Usage()
+			os.Exit(-2)
+		}
+	}()
+
+	config := &replay.Config{
+		S3Path:            *S3PATH,
+		LogType:           *LOGTYPE,
+		Limit:             *LIMIT,
+		QueueName:         *TOQ,
+		Bucket:            *BUCKET,
+		DestinationPrefix: *PREFIX,
+		Verbose:           *VERBOSE,
+	}
+	if *S3KEYS != "" {
+		config.S3Keys = strings.Split(*S3KEYS, ",")
+	}
+
+	if (config.S3Path == "") == (len(config.S3Keys) == 0) {
+		err = errors.New("exactly one of -s3path or -s3keys must be set")
+		return nil
+	}
+	if config.LogType == "" {
+		err = errors.New("-logtype not set")
+		return nil
+	}
+	if config.QueueName != "" && replay.HasFailureObjects(config) {
+		err = errors.New("classification failures can only be replayed locally, -queue cannot be set")
+		return nil
+	}
+	if config.QueueName == "" {
+		if config.Bucket == "" {
+			err = errors.New("-bucket not set")
+			return nil
+		}
+		if config.DestinationPrefix == "" {
+			err = errors.New("-prefix not set")
+			return nil
+		}
+	}
+	if *START != "" {
+		if config.Start, err = time.Parse(time.RFC3339, *START); err != nil {
+			err = errors.Wrap(err, "bad -start")
+			return nil
+		}
+	}
+	if *END != "" {
+		if config.End, err = time.Parse(time.RFC3339, *END); err != nil {
+			err = errors.Wrap(err, "bad -end")
+			return nil
+		}
+	}
+	return config
+}
+
+func source(config *replay.Config) string {
+	if config.S3Path != "" {
+		return config.S3Path
+	}
+	return strings.Join(config.S3Keys, ",")
+}
+
+// all objects are expected to be in the same region
+func getS3Region(sess *session.Session, config *replay.Config) string {
+	s3Path := config.S3Path
+	if s3Path == "" {
+		s3Path = config.S3Keys[0]
+	}
+	bucket, _, err := replay.ParseS3Path(s3Path)
+	if err != nil {
+		logger.Fatalf("failed to find bucket region for provided path %s: %s", s3Path, err)
+	}
+
+	input := &s3.GetBucketLocationInput{Bucket: aws.String(bucket)}
+	location, err := s3.New(sess).GetBucketLocation(input)
+	if err != nil {
+		logger.Fatalf("failed to find bucket region for provided path %s: %s", s3Path, err)
+	}
+
+	// Method may return nil if region is us-east-1,https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketLocation.html
+	// and https://docs.aws.amazon.com/general/latest/gr/rande.html#s3_region
+	if location.LocationConstraint == nil {
+		return endpoints.UsEast1RegionID
+	}
+	return *location.LocationConstraint
+}
@@ -0,0 +1,210 @@
+package replay
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
+)
+
+const (
+	testAccount   = "012345678912"
+	testBucket    = "foo"
+	testKey       = "bar"
+	testS3Path    = "s3://" + testBucket + "/" + testKey
+	testQueueName = "testQueue"
+	testLogType   = "AWS.VPCFlow"
+)
+
+var (
+	testStart = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	testEnd   = testStart.Add(time.Hour)
+)
+
+func TestReplayQueue(t *testing.T) {
+	s3Client := &mockS3{}
+	page := &s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			{ // before window
+				Size:         aws.Int64(1),
+				Key:          aws.String("before"),
+				LastModified: aws.Time(testStart.Add(-time.Second)),
+			},
+			{
+				Size:         aws.Int64(1),
+				Key:          aws.String(testKey),
+				LastModified: aws.Time(testStart),
+			},
+			{ // after window
+				Size:         aws.Int64(1),
+				Key:          aws.String("after"),
+				LastModified: aws.Time(testEnd),
+			},
+		},
+	}
+	s3Client.On("ListObjectsV2Pages", mock.Anything, mock.Anything).Return(page, nil).Once()
+	sqsClient := &mockSQS{}
+	sqsClient.On("GetQueueUrl", mock.Anything).Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("arn")}, nil).Once()
+	sqsClient.On("SendMessageBatch", mock.Anything).Return(&sqs.SendMessageBatchOutput{}, nil).Once()
+
+	config := &Config{
+		S3Path:            testS3Path,
+		LogType:           testLogType,
+		Start:             testStart,
+		End:               testEnd,
+		QueueName:         testQueueName,
+		Account:           testAccount,
+		DestinationPrefix: "replay/test",
+	}
+	stats := &Stats{}
+	err := replayQueue(s3Client, sqsClient, config, stats)
+	require.NoError(t, err)
+	s3Client.AssertExpectations(t)
+	sqsClient.AssertExpectations(t)
+	assert.Equal(t, uint64(1), stats.NumFiles)
+
+	// the log processor must find the object, the log type and the prefix in the message
+	input := sqsClient.Calls[1].Arguments.Get(0).(*sqs.SendMessageBatchInput)
+	require.Len(t, input.Entries, 1)
+	notification := &sources.SnsNotification{}
+	require.NoError(t, jsoniter.UnmarshalFromString(*input.Entries[0].MessageBody, notification))
+	s3Objects, err := sources.ParseNotification(notification.Message)
+	require.NoError(t, err)
+	assert.Equal(t, []*sources.S3ObjectInfo{{S3Bucket: testBucket, S3ObjectKey: testKey}}, s3Objects)
+	assert.Equal(t, map[string]interface{}{
+		sources.ReplayLogTypeAttribute:           map[string]interface{}{"Type": "String", "Value": testLogType},
+		sources.ReplayDestinationPrefixAttribute: map[string]interface{}{"Type": "String", "Value": "replay/test/"},
+	}, notification.MessageAttributes)
+}
+
+func TestReplayQueueKeys(t *testing.T) {
+	s3Client := &mockS3{}
+	s3Client.On("HeadObject", mock.Anything).Return(&s3.HeadObjectOutput{
+		ContentLength: aws.Int64(1),
+		LastModified:  aws.Time(testStart),
+	}, nil).Twice()
+	s3Client.On("HeadObject", mock.Anything).Return(&s3.HeadObjectOutput{
+		ContentLength: aws.Int64(0), // empty objects are skipped
+		LastModified:  aws.Time(testStart),
+	}, nil).Once()
+	sqsClient := &mockSQS{}
+	sqsClient.On("GetQueueUrl", mock.Anything).Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("arn")}, nil).Once()
+	sqsClient.On("SendMessageBatch", mock.Anything).Return(&sqs.SendMessageBatchOutput{}, nil).Once()
+
+	config := &Config{
+		S3Keys:    []string{testS3Path, testS3Path, testS3Path},
+		LogType:   testLogType,
+		QueueName: testQueueName,
+		Account:   testAccount,
+	}
+	stats := &Stats{}
+	err := replayQueue(s3Client, sqsClient, config, stats)
+	require.NoError(t, err)
+	s3Client.AssertExpectations(t)
+	sqsClient.AssertExpectations(t)
+	assert.Equal(t, uint64(2), stats.NumFiles)
+
+	// without a prefix the results go to the log tables
+	input := sqsClient.Calls[1].Arguments.Get(0).(*sqs.SendMessageBatchInput)
+	require.Len(t, input.Entries, 2)
+	notification := &events.SNSEntity{}
+	require.NoError(t, jsoniter.UnmarshalFromString(*input.Entries[0].MessageBody, notification))
+	assert.NotContains(t, notification.MessageAttributes, sources.ReplayDestinationPrefixAttribute)
+}
+
+func TestReplayQueueRefusesFailures(t *testing.T) {
+	failureKey := classification.FailureTable.Prefix() + "year=2020/month=01/day=01/hour=00/failures.json.gz"
+	s3Client := &mockS3{}
+	s3Client.On("HeadObject", mock.Anything).Return(&s3.HeadObjectOutput{
+		ContentLength: aws.Int64(1),
+		LastModified:  aws.Time(testStart),
+	}, nil).Once()
+	sqsClient := &mockSQS{}
+	sqsClient.On("GetQueueUrl", mock.Anything).Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("arn")}, nil).Once()
+
+	config := &Config{
+		S3Keys:    []string{"s3://" + testBucket + "/" + failureKey},
+		LogType:   testLogType,
+		QueueName: testQueueName,
+		Account:   testAccount,
+	}
+	assert.True(t, HasFailureObjects(config))
+	err := replayQueue(s3Client, sqsClient, config, &Stats{})
+	require.Error(t, err)
+	sqsClient.AssertNotCalled(t, "SendMessageBatch", mock.Anything)
+
+	assert.False(t, HasFailureObjects(&Config{S3Path: testS3Path}))
+}
+
+func TestReplayLocalRequiresDestination(t *testing.T) {
+	err := replayLocal(&mockS3{}, nil, &Config{S3Path: testS3Path, LogType: testLogType}, &Stats{})
+	require.Error(t, err)
+}
+
+func TestFailureLinesReader(t *testing.T) {
+	failures := `{"line":"line 1","lineNum":1,"p_log_type":"Panther.ClassificationFailure"}
+{"line":"{\"line\":2}","lineNum":2,"p_log_type":"Panther.ClassificationFailure"}
+`
+	lines, err := ioutil.ReadAll(newFailureLinesReader(strings.NewReader(failures)))
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\n{\"line\":2}\n", string(lines))
+
+	_, err = ioutil.ReadAll(newFailureLinesReader(strings.NewReader(`{"line":`)))
+	require.Error(t, err)
+}
+
+func TestParseS3Path(t *testing.T) {
+	bucket, key, err := ParseS3Path(testS3Path)
+	require.NoError(t, err)
+	assert.Equal(t, testBucket, bucket)
+	assert.Equal(t, testKey, key)
+
+	_, _, err = ParseS3Path("https://foo/bar")
+	require.Error(t, err)
+}
+
+type mockS3 struct {
+	s3iface.S3API
+	mock.Mock
+}
+
+func (m *mockS3) ListObjectsV2Pages(input *s3.ListObjectsV2Input, f func(page *s3.ListObjectsV2Output, morePages bool) bool) error {
+	args := m.Called(input, f)
+	f(args.Get(0).(*s3.ListObjectsV2Output), false)
+	return args.Error(1)
+}
+
+func (m *mockS3) HeadObject(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*s3.HeadObjectOutput), args.Error(1)
+}
+
+type mockSQS struct {
+	sqsiface.SQSAPI
+	mock.Mock
+}
+
+// nolint (golint)
+func (m *mockSQS) GetQueueUrl(input *sqs.GetQueueUrlInput) (*sqs.GetQueueUrlOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*sqs.GetQueueUrlOutput), args.Error(1)
+}
+
+func (m *mockSQS) SendMessageBatch(input *sqs.SendMessageBatchInput) (*sqs.SendMessageBatchOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*sqs.SendMessageBatchOutput), args.Error(1)
+}
@@ -19,11 +19,14 @@ require (
 	github.com/joho/godotenv v1.3.0
 	github.com/json-iterator/go v1.1.9
 	github.com/kelseyhightower/envconfig v1.4.0
+	github.com/klauspost/compress v1.9.7
 	github.com/leodido/go-urn v1.2.0 // indirect
 	github.com/magefile/mage v1.9.0
+	github.com/oschwald/maxminddb-golang v1.6.0
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.5.1
 	github.com/tidwall/gjson v1.6.0
+	github.com/xitongsys/parquet-go v1.5.2
 	go.uber.org/zap v1.14.1
 	golang.org/x/net v0.0.0-20200324143707-d3edc9973b7e // indirect
 	golang.org/x/tools v0.0.0-20200407144507-5fc56a9a2104 // indirect
@@ -26,6 +26,10 @@ const RuleType = "RULE"
 // PolicyType identifies the Alert to be for a Policy
 const PolicyType = "POLICY"
 
+// SystemHealthType identifies the Alert to be for a problem of Panther itself, e.g. a log source that stopped
+// receiving data. The PolicyID is the id of the affected component.
+const SystemHealthType = "SYSTEM_HEALTH"
+
 // Alert is the schema for each row in the Dynamo alerts table.
 type Alert struct {
 
@@ -59,8 +63,8 @@ type Alert struct {
 	// AlertID specifies the alertId that this Alert is associated with.
 	AlertID *string `json:"alertId,omitempty"`
 
-	// Type specifies if an alert is for a policy or a rule
-	Type *string `json:"type,omitempty" validate:"omitempty,oneof=RULE POLICY"`
+	// Type specifies if an alert is for a policy, a rule or the health of the system
+	Type *string `json:"type,omitempty" validate:"omitempty,oneof=RULE POLICY SYSTEM_HEALTH"`
 
 	// Title is the optional title for the alert
 	Title *string `json:"title,omitempty"`
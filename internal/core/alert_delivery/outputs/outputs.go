@@ -35,6 +35,7 @@ import (
 var (
 	policyURLPrefix = os.Getenv("POLICY_URL_PREFIX")
 	alertURLPrefix  = os.Getenv("ALERT_URL_PREFIX")
+	logSourcesURL   = os.Getenv("LOG_SOURCES_URL")
 )
 
 // HTTPWrapper encapsulates the Golang's http client
@@ -98,8 +99,11 @@ func New(sess *session.Session) *OutputClient {
 const detailedMessageTemplate = "%s\nFor more details please visit: %s\nSeverity: %s\nRunbook: %s\nDescription:%s"
 
 func generateAlertMessage(alert *alertmodels.Alert) string {
-	if aws.StringValue(alert.Type) == alertmodels.RuleType {
+	switch aws.StringValue(alert.Type) {
+	case alertmodels.RuleType:
 		return getDisplayName(alert) + " triggered"
+	case alertmodels.SystemHealthType:
+		return getDisplayName(alert) + " is unhealthy"
 	}
 	return getDisplayName(alert) + " failed on new resources"
 }
@@ -116,6 +120,12 @@ func generateDetailedAlertMessage(alert *alertmodels.Alert) string {
 }
 
 func generateAlertTitle(alert *alertmodels.Alert) string {
+	if aws.StringValue(alert.Type) == alertmodels.SystemHealthType {
+		if alert.Title != nil {
+			return "System Health: " + *alert.Title
+		}
+		return "System Health: " + getDisplayName(alert)
+	}
 	if alert.Title != nil {
 		return "New Alert: " + *alert.Title
 	}
@@ -133,8 +143,11 @@ func getDisplayName(alert *alertmodels.Alert) string {
 }
 
 func generateURL(alert *alertmodels.Alert) string {
-	if aws.StringValue(alert.Type) == alertmodels.RuleType {
+	switch aws.StringValue(alert.Type) {
+	case alertmodels.RuleType:
 		return alertURLPrefix + *alert.AlertID
+	case alertmodels.SystemHealthType:
+		return logSourcesURL
 	}
 	return policyURLPrefix + *alert.PolicyID
 }
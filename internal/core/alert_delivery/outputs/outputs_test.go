@@ -31,6 +31,7 @@ import (
 func init() {
 	policyURLPrefix = "https://panther.io/policies/"
 	alertURLPrefix = "https://panther.io/alerts/"
+	logSourcesURL = "https://panther.io/log-analysis/sources/"
 }
 
 type mockHTTPWrapper struct {
@@ -82,3 +83,14 @@ func TestGenerateAlertTitlePolicyId(t *testing.T) {
 	}
 	assert.Equal(t, "Policy Failure: policy.id", generateAlertTitle(alert))
 }
+
+func TestGenerateAlertTitleSystemHealth(t *testing.T) {
+	alert := &alertModel.Alert{
+		Type:     aws.String(alertModel.SystemHealthType),
+		PolicyID: aws.String("source-id"),
+		Title:    aws.String("Log source my-source stopped receiving data"),
+	}
+	assert.Equal(t, "System Health: Log source my-source stopped receiving data", generateAlertTitle(alert))
+	assert.Equal(t, "source-id is unhealthy", generateAlertMessage(alert))
+	assert.Equal(t, "https://panther.io/log-analysis/sources/", generateURL(alert))
+}
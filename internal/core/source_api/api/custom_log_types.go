@@ -0,0 +1,122 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/customlogs"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+var (
+	customLogTypeInternalError = &genericapi.InternalError{Message: "Failed to update custom log types. Please try again later"}
+)
+
+// PutCustomLogType adds or replaces a custom log type.
+//
+// The schema is compiled before it is stored, so the log processor will always be able to load it.
+func (API) PutCustomLogType(input *models.PutCustomLogTypeInput) (*models.CustomLogType, error) {
+	if _, err := customlogs.NewParser(&input.CustomLogType); err != nil {
+		return nil, &genericapi.InvalidInputError{Message: err.Error()}
+	}
+
+	item := customLogTypeToItem(&input.CustomLogType)
+	item.CreatedAtTime = aws.Time(time.Now())
+	item.CreatedBy = input.UserID
+	if err := dynamoClient.PutCustomLogType(item); err != nil {
+		zap.L().Error("failed to put custom log type", zap.String("logType", *input.LogType), zap.Error(err))
+		return nil, customLogTypeInternalError
+	}
+	return itemToCustomLogType(item), nil
+}
+
+// ListCustomLogTypes returns all custom log types.
+func (API) ListCustomLogTypes(_ *models.ListCustomLogTypesInput) ([]*models.CustomLogType, error) {
+	items, err := dynamoClient.ScanCustomLogTypes()
+	if err != nil {
+		zap.L().Error("failed to list custom log types", zap.Error(err))
+		return nil, &genericapi.InternalError{Message: "Failed to list custom log types"}
+	}
+
+	result := make([]*models.CustomLogType, len(items))
+	for i, item := range items {
+		result[i] = itemToCustomLogType(item)
+	}
+	return result, nil
+}
+
+// DeleteCustomLogType deletes a custom log type that is not used by any source.
+func (API) DeleteCustomLogType(input *models.DeleteCustomLogTypeInput) error {
+	integrations, err := dynamoClient.ScanIntegrations(aws.String(models.IntegrationTypeAWS3))
+	if err != nil {
+		zap.L().Error("failed to list integrations", zap.Error(err))
+		return customLogTypeInternalError
+	}
+	for _, integration := range integrations {
+		for _, logType := range integration.LogTypes {
+			if aws.StringValue(logType) == *input.LogType {
+				return &genericapi.InUseError{
+					Message: "Log type is used by source " + aws.StringValue(integration.IntegrationLabel),
+				}
+			}
+		}
+	}
+
+	deleted, err := dynamoClient.DeleteCustomLogType(input.LogType)
+	if err != nil {
+		zap.L().Error("failed to delete custom log type", zap.String("logType", *input.LogType), zap.Error(err))
+		return customLogTypeInternalError
+	}
+	if !deleted {
+		return &genericapi.DoesNotExistError{Message: "Custom log type does not exist"}
+	}
+	return nil
+}
+
+func customLogTypeToItem(input *models.CustomLogType) *ddb.CustomLogTypeItem {
+	return &ddb.CustomLogTypeItem{
+		LogType:         input.LogType,
+		Description:     input.Description,
+		TimestampField:  input.TimestampField,
+		TimestampFormat: input.TimestampFormat,
+		Fields:          input.Fields,
+		Multiline:       input.Multiline,
+		CreatedAtTime:   input.CreatedAtTime,
+		CreatedBy:       input.CreatedBy,
+	}
+}
+
+func itemToCustomLogType(item *ddb.CustomLogTypeItem) *models.CustomLogType {
+	return &models.CustomLogType{
+		LogType:         item.LogType,
+		Description:     item.Description,
+		TimestampField:  item.TimestampField,
+		TimestampFormat: item.TimestampFormat,
+		Fields:          item.Fields,
+		Multiline:       item.Multiline,
+		CreatedAtTime:   item.CreatedAtTime,
+		CreatedBy:       item.CreatedBy,
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb/modelstest"
+	"github.com/panther-labs/panther/pkg/genericapi"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+const testCustomLogType = "Custom.Test"
+
+func testCustomLogTypeInput() *models.PutCustomLogTypeInput {
+	return &models.PutCustomLogTypeInput{
+		CustomLogType: models.CustomLogType{
+			LogType:     aws.String(testCustomLogType),
+			Description: aws.String("Test logs"),
+			Fields: []*models.CustomLogTypeField{
+				{
+					Name:     aws.String("message"),
+					Type:     aws.String(models.CustomFieldTypeString),
+					Required: aws.Bool(true),
+				},
+			},
+		},
+		UserID: aws.String(testUserID),
+	}
+}
+
+func TestPutCustomLogType(t *testing.T) {
+	mockClient := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockClient, CustomLogTypesTableName: "test"}
+	mockClient.On("PutItem", mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
+
+	out, err := apiTest.PutCustomLogType(testCustomLogTypeInput())
+	require.NoError(t, err)
+	assert.Equal(t, testCustomLogType, *out.LogType)
+	assert.Equal(t, testUserID, *out.CreatedBy)
+	assert.NotNil(t, out.CreatedAtTime)
+	mockClient.AssertExpectations(t)
+}
+
+func TestPutCustomLogTypeInvalidSchema(t *testing.T) {
+	mockClient := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockClient, CustomLogTypesTableName: "test"}
+
+	input := testCustomLogTypeInput()
+	input.Fields[0].Required = nil // no required fields, would match any JSON object
+	out, err := apiTest.PutCustomLogType(input)
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.InvalidInputError{}, err)
+	assert.Nil(t, out)
+	mockClient.AssertExpectations(t)
+}
+
+func TestListCustomLogTypes(t *testing.T) {
+	dynamoClient = &ddb.DDB{
+		Client: &modelstest.MockDDBClient{
+			MockScanAttributes: []map[string]*dynamodb.AttributeValue{
+				{
+					"logType":     {S: aws.String(testCustomLogType)},
+					"description": {S: aws.String("Test logs")},
+					"fields": {L: []*dynamodb.AttributeValue{
+						{M: map[string]*dynamodb.AttributeValue{
+							"name": {S: aws.String("message")},
+							"type": {S: aws.String(models.CustomFieldTypeString)},
+						}},
+					}},
+				},
+			},
+		},
+		CustomLogTypesTableName: "test",
+	}
+
+	out, err := apiTest.ListCustomLogTypes(&models.ListCustomLogTypesInput{})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, testCustomLogType, *out[0].LogType)
+	require.Len(t, out[0].Fields, 1)
+	assert.Equal(t, "message", *out[0].Fields[0].Name)
+}
+
+func TestDeleteCustomLogType(t *testing.T) {
+	mockClient := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockClient, TableName: "test", CustomLogTypesTableName: "test"}
+	mockClient.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, nil)
+	mockClient.On("DeleteItem", mock.Anything).Return(&dynamodb.DeleteItemOutput{
+		Attributes: map[string]*dynamodb.AttributeValue{"logType": {S: aws.String(testCustomLogType)}},
+	}, nil)
+
+	err := apiTest.DeleteCustomLogType(&models.DeleteCustomLogTypeInput{LogType: aws.String(testCustomLogType)})
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDeleteCustomLogTypeInUse(t *testing.T) {
+	mockClient := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockClient, TableName: "test", CustomLogTypesTableName: "test"}
+	source := generateDDBAttributes(models.IntegrationTypeAWS3)
+	source["logTypes"] = &dynamodb.AttributeValue{SS: aws.StringSlice([]string{testCustomLogType})}
+	mockClient.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{
+		Items: []map[string]*dynamodb.AttributeValue{source},
+	}, nil)
+
+	err := apiTest.DeleteCustomLogType(&models.DeleteCustomLogTypeInput{LogType: aws.String(testCustomLogType)})
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.InUseError{}, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDeleteCustomLogTypeDoesNotExist(t *testing.T) {
+	mockClient := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockClient, TableName: "test", CustomLogTypesTableName: "test"}
+	mockClient.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, nil)
+	mockClient.On("DeleteItem", mock.Anything).Return(&dynamodb.DeleteItemOutput{}, nil)
+
+	err := apiTest.DeleteCustomLogType(&models.DeleteCustomLogTypeInput{LogType: aws.String(testCustomLogType)})
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.DoesNotExistError{}, err)
+	mockClient.AssertExpectations(t)
+}
@@ -19,13 +19,19 @@ package api
  */
 
 import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"go.uber.org/zap"
+
 	"github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/pkg/genericapi"
 )
 
 // ListIntegrations returns all enabled integrations across each organization.
 //
-// The output of this handler is used to schedule pollers.
+// The output of this handler is used to schedule pollers, the stats of the log analysis integrations
+// are only added when requested.
 func (API) ListIntegrations(
 	input *models.ListIntegrationsInput) ([]*models.SourceIntegration, error) {
 
@@ -34,9 +40,24 @@ func (API) ListIntegrations(
 		return nil, &genericapi.InternalError{Message: "Failed to list integrations"}
 	}
 
+	now := time.Now()
 	result := make([]*models.SourceIntegration, len(integrationItems))
 	for i, item := range integrationItems {
 		result[i] = itemToIntegration(item)
+		// the stats are only computed for the UI, the other callers list the integrations often
+		if !aws.BoolValue(input.IncludeStats) || aws.StringValue(item.IntegrationType) != models.IntegrationTypeAWS3 {
+			continue
+		}
+		if item.IntegrationID == nil {
+			continue
+		}
+		// the integrations are still listed without stats
+		stats, err := integrationStats(*item.IntegrationID, now)
+		if err != nil {
+			zap.L().Error("failed to get integration stats", zap.String("integrationId", *item.IntegrationID), zap.Error(err))
+			continue
+		}
+		result[i].SourceIntegrationStats = *stats
 	}
 
 	return result, nil
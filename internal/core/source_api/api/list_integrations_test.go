@@ -117,3 +117,121 @@ func TestHandleListIntegrationsScanError(t *testing.T) {
 	require.NotNil(t, err)
 	assert.Nil(t, out)
 }
+
+func TestListIntegrationsStats(t *testing.T) {
+	lastReceivedTime := time.Now().UTC().Truncate(time.Second)
+	earlierTime := lastReceivedTime.Add(-time.Hour)
+	stats := func(timeBin, logType string, lines, failures, events int, lastEventTime time.Time) map[string]*dynamodb.AttributeValue {
+		item := map[string]*dynamodb.AttributeValue{
+			"integrationId":              {S: aws.String(testIntegrationID)},
+			"timeBin":                    {S: aws.String(timeBin)},
+			"logLineCount":               {N: aws.String(strconv.Itoa(lines))},
+			"classificationFailureCount": {N: aws.String(strconv.Itoa(failures))},
+			"eventCount":                 {N: aws.String(strconv.Itoa(events))},
+			"lastReceivedTime":           {S: aws.String(lastEventTime.Format(time.RFC3339))},
+			"lastEventTime":              {S: aws.String(lastEventTime.Format(time.RFC3339))},
+		}
+		if logType != "" {
+			item["logType"] = &dynamodb.AttributeValue{S: aws.String(logType)}
+		}
+		return item
+	}
+
+	dynamoClient = &ddb.DDB{
+		Client: &modelstest.MockDDBClient{
+			MockScanAttributes: []map[string]*dynamodb.AttributeValue{
+				{
+					"integrationId":    {S: aws.String(testIntegrationID)},
+					"integrationLabel": {S: aws.String(testIntegrationLabel)},
+					"integrationType":  {S: aws.String(models.IntegrationTypeAWS3)},
+				},
+			},
+			MockQueryAttributes: []map[string]*dynamodb.AttributeValue{
+				stats("2020-01-01T10:00:00Z", "", 10, 2, 8, earlierTime),
+				stats("2020-01-01T10:00:00Z#AWS.VPCFlow", "AWS.VPCFlow", 8, 0, 8, earlierTime),
+				stats("2020-01-01T11:00:00Z", "", 10, 0, 10, lastReceivedTime),
+				stats("2020-01-01T11:00:00Z#AWS.S3ServerAccess", "AWS.S3ServerAccess", 4, 0, 4, lastReceivedTime),
+				stats("2020-01-01T11:00:00Z#AWS.VPCFlow", "AWS.VPCFlow", 6, 0, 6, earlierTime),
+			},
+		},
+		TableName:            "test",
+		SourceStatsTableName: "testStats",
+	}
+
+	out, err := apiTest.ListIntegrations(&models.ListIntegrationsInput{IncludeStats: aws.Bool(true)})
+
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, models.SourceIntegrationStats{
+		LastEventReceivedTime:     &lastReceivedTime,
+		LastEventTime:             &lastReceivedTime,
+		EventsLast24Hours:         aws.Uint64(18),
+		ClassificationFailureRate: aws.Float64(0.1),
+		LogTypesLast24Hours: []*models.SourceIntegrationLogType{
+			{
+				LogType:       aws.String("AWS.S3ServerAccess"),
+				EventCount:    aws.Uint64(4),
+				LastEventTime: &lastReceivedTime,
+			},
+			{
+				LogType:       aws.String("AWS.VPCFlow"),
+				EventCount:    aws.Uint64(14),
+				LastEventTime: &earlierTime,
+			},
+		},
+	}, out[0].SourceIntegrationStats)
+}
+
+func TestListIntegrationsNoRecentStats(t *testing.T) {
+	dynamoClient = &ddb.DDB{
+		Client: &modelstest.MockDDBClient{
+			MockScanAttributes: []map[string]*dynamodb.AttributeValue{
+				{
+					"integrationId":   {S: aws.String(testIntegrationID)},
+					"integrationType": {S: aws.String(models.IntegrationTypeAWS3)},
+				},
+			},
+			MockQueryAttributes: []map[string]*dynamodb.AttributeValue{},
+		},
+		TableName:            "test",
+		SourceStatsTableName: "testStats",
+	}
+
+	out, err := apiTest.ListIntegrations(&models.ListIntegrationsInput{IncludeStats: aws.Bool(true)})
+
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, models.SourceIntegrationStats{EventsLast24Hours: aws.Uint64(0)}, out[0].SourceIntegrationStats)
+}
+
+func TestListIntegrationsWithoutStats(t *testing.T) {
+	dynamoClient = &ddb.DDB{
+		Client: &modelstest.MockDDBClient{
+			MockScanAttributes: []map[string]*dynamodb.AttributeValue{
+				{
+					"integrationId":   {S: aws.String(testIntegrationID)},
+					"integrationType": {S: aws.String(models.IntegrationTypeAWS3)},
+				},
+				{
+					// the stats cannot be found without an id
+					"integrationType": {S: aws.String(models.IntegrationTypeAWS3)},
+				},
+			},
+			MockQueryAttributes: []map[string]*dynamodb.AttributeValue{},
+		},
+		TableName:            "test",
+		SourceStatsTableName: "testStats",
+	}
+
+	// the stats are only added when requested
+	out, err := apiTest.ListIntegrations(&models.ListIntegrationsInput{})
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	assert.Equal(t, models.SourceIntegrationStats{}, out[0].SourceIntegrationStats)
+
+	out, err = apiTest.ListIntegrations(&models.ListIntegrationsInput{IncludeStats: aws.Bool(true)})
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	assert.Equal(t, models.SourceIntegrationStats{EventsLast24Hours: aws.Uint64(0)}, out[0].SourceIntegrationStats)
+	assert.Equal(t, models.SourceIntegrationStats{}, out[1].SourceIntegrationStats)
+}
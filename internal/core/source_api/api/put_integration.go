@@ -233,6 +233,7 @@ func generateNewIntegration(input *models.PutIntegrationInput) *models.SourceInt
 		metadata.S3Prefix = input.S3Prefix
 		metadata.KmsKey = input.KmsKey
 		metadata.LogTypes = input.LogTypes
+		metadata.Multiline = input.Multiline
 		metadata.StackName = aws.String(getStackName(*input.IntegrationType, *input.IntegrationLabel))
 		metadata.LogProcessingRole = aws.String(generateLogProcessingRoleArn(*input.AWSAccountID, *input.IntegrationLabel))
 	}
@@ -0,0 +1,140 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/redaction"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+// the size of the random salts of the redaction hashes
+const redactionSaltSize = 32
+
+var (
+	redactionInternalError = &genericapi.InternalError{Message: "Failed to update redaction rules. Please try again later"}
+)
+
+// PutRedactionRules sets the redaction rules of a log type.
+//
+// The salt of the hashes is generated with the first rules of the log type and kept when they are replaced,
+// so the hashes of the same values do not change. It is stored encrypted with the redaction KMS key.
+func (API) PutRedactionRules(input *models.PutRedactionRulesInput) error {
+	existing, err := dynamoClient.GetRedactionRules(input.LogType)
+	if err != nil {
+		zap.L().Error("failed to get redaction rules", zap.String("logType", *input.LogType), zap.Error(err))
+		return redactionInternalError
+	}
+
+	var salt []byte
+	var encryptedSalt []byte
+	if existing != nil {
+		encryptedSalt = existing.EncryptedSalt
+		if salt, err = decryptRedactionSalt(encryptedSalt); err != nil {
+			zap.L().Error("failed to decrypt redaction salt", zap.String("logType", *input.LogType), zap.Error(err))
+			return redactionInternalError
+		}
+	} else {
+		salt = make([]byte, redactionSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			zap.L().Error("failed to generate redaction salt", zap.Error(err))
+			return redactionInternalError
+		}
+		if encryptedSalt, err = redactionKey.EncryptConfig(base64.StdEncoding.EncodeToString(salt)); err != nil {
+			zap.L().Error("failed to encrypt redaction salt", zap.String("logType", *input.LogType), zap.Error(err))
+			return redactionInternalError
+		}
+	}
+
+	// the rules are checked the way the log processor compiles them, it would fail to load invalid rules
+	if _, err := redaction.Compile(input.Rules, salt); err != nil {
+		return &genericapi.InvalidInputError{Message: err.Error()}
+	}
+
+	item := &ddb.RedactionRulesItem{
+		LogType:          input.LogType,
+		Rules:            input.Rules,
+		EncryptedSalt:    encryptedSalt,
+		LastModifiedTime: aws.Time(time.Now()),
+		LastModifiedBy:   input.UserID,
+	}
+	if err := dynamoClient.PutRedactionRules(item); err != nil {
+		zap.L().Error("failed to put redaction rules", zap.String("logType", *input.LogType), zap.Error(err))
+		return redactionInternalError
+	}
+	return nil
+}
+
+// ListRedactionRules returns the redaction rules of all log types.
+func (API) ListRedactionRules(input *models.ListRedactionRulesInput) ([]*models.RedactionRules, error) {
+	items, err := dynamoClient.ScanRedactionRules()
+	if err != nil {
+		zap.L().Error("failed to list redaction rules", zap.Error(err))
+		return nil, &genericapi.InternalError{Message: "Failed to list redaction rules"}
+	}
+
+	result := make([]*models.RedactionRules, len(items))
+	for i, item := range items {
+		result[i] = &models.RedactionRules{
+			LogType:          item.LogType,
+			Rules:            item.Rules,
+			LastModifiedTime: item.LastModifiedTime,
+			LastModifiedBy:   item.LastModifiedBy,
+		}
+		if !input.IncludeSalts {
+			continue
+		}
+		salt, err := decryptRedactionSalt(item.EncryptedSalt)
+		if err != nil {
+			zap.L().Error("failed to decrypt redaction salt", zap.String("logType", *item.LogType), zap.Error(err))
+			return nil, &genericapi.InternalError{Message: "Failed to list redaction rules"}
+		}
+		result[i].Salt = aws.String(base64.StdEncoding.EncodeToString(salt))
+	}
+	return result, nil
+}
+
+// DeleteRedactionRules deletes the redaction rules of a log type.
+func (API) DeleteRedactionRules(input *models.DeleteRedactionRulesInput) error {
+	deleted, err := dynamoClient.DeleteRedactionRules(input.LogType)
+	if err != nil {
+		zap.L().Error("failed to delete redaction rules", zap.String("logType", *input.LogType), zap.Error(err))
+		return redactionInternalError
+	}
+	if !deleted {
+		return &genericapi.DoesNotExistError{Message: "Log type does not have redaction rules"}
+	}
+	return nil
+}
+
+func decryptRedactionSalt(encryptedSalt []byte) ([]byte, error) {
+	var salt string
+	if err := redactionKey.DecryptConfig(encryptedSalt, &salt); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(salt)
+}
@@ -0,0 +1,139 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/pkg/encryption"
+	"github.com/panther-labs/panther/pkg/genericapi"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+const testRedactionLogType = "AWS.CloudTrail"
+
+type mockEncryptionKey struct {
+	encryption.Key
+	mock.Mock
+}
+
+func (m *mockEncryptionKey) DecryptConfig(ciphertext []byte, config interface{}) error {
+	args := m.Called(ciphertext, config)
+	return args.Error(0)
+}
+
+func (m *mockEncryptionKey) EncryptConfig(config interface{}) ([]byte, error) {
+	args := m.Called(config)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func testPutRedactionRulesInput() *models.PutRedactionRulesInput {
+	return &models.PutRedactionRulesInput{
+		LogType: aws.String(testRedactionLogType),
+		Rules: []*models.RedactionRule{
+			{Field: aws.String("userIdentity.userName"), Action: aws.String(models.RedactionHash)},
+		},
+		UserID: aws.String(testUserID),
+	}
+}
+
+func TestPutRedactionRules(t *testing.T) {
+	mockClient := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockClient, RedactionTableName: "test"}
+	mockKey := &mockEncryptionKey{}
+	redactionKey = mockKey
+
+	mockClient.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+	mockKey.On("EncryptConfig", mock.Anything).Return([]byte("encrypted"), nil).Once()
+	var item ddb.RedactionRulesItem
+	mockClient.On("PutItem", mock.Anything).Run(func(args mock.Arguments) {
+		require.NoError(t, dynamodbattribute.UnmarshalMap(args.Get(0).(*dynamodb.PutItemInput).Item, &item))
+	}).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	require.NoError(t, apiTest.PutRedactionRules(testPutRedactionRulesInput()))
+	mockClient.AssertExpectations(t)
+	mockKey.AssertExpectations(t)
+	assert.Equal(t, testRedactionLogType, *item.LogType)
+	assert.Equal(t, []byte("encrypted"), item.EncryptedSalt)
+	assert.Equal(t, testUserID, *item.LastModifiedBy)
+	require.Len(t, item.Rules, 1)
+}
+
+func TestPutRedactionRulesKeepsSalt(t *testing.T) {
+	mockClient := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockClient, RedactionTableName: "test"}
+	mockKey := &mockEncryptionKey{}
+	redactionKey = mockKey
+
+	existing, err := dynamodbattribute.MarshalMap(&ddb.RedactionRulesItem{
+		LogType:       aws.String(testRedactionLogType),
+		EncryptedSalt: []byte("encrypted"),
+	})
+	require.NoError(t, err)
+	mockClient.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{Item: existing}, nil).Once()
+	mockKey.On("DecryptConfig", []byte("encrypted"), mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(1).(*string) = "c2FsdA=="
+	}).Return(nil).Once()
+	var item ddb.RedactionRulesItem
+	mockClient.On("PutItem", mock.Anything).Run(func(args mock.Arguments) {
+		require.NoError(t, dynamodbattribute.UnmarshalMap(args.Get(0).(*dynamodb.PutItemInput).Item, &item))
+	}).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	require.NoError(t, apiTest.PutRedactionRules(testPutRedactionRulesInput()))
+	mockClient.AssertExpectations(t)
+	mockKey.AssertExpectations(t)
+	assert.Equal(t, []byte("encrypted"), item.EncryptedSalt)
+}
+
+func TestPutRedactionRulesInvalid(t *testing.T) {
+	mockClient := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockClient, RedactionTableName: "test"}
+	mockKey := &mockEncryptionKey{}
+	redactionKey = mockKey
+
+	mockClient.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+	mockKey.On("EncryptConfig", mock.Anything).Return([]byte("encrypted"), nil).Once()
+
+	input := testPutRedactionRulesInput()
+	input.Rules[0].Field = aws.String("userIdentity..userName")
+	err := apiTest.PutRedactionRules(input)
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.InvalidInputError{}, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDeleteRedactionRulesDoesNotExist(t *testing.T) {
+	mockClient := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockClient, RedactionTableName: "test"}
+	mockClient.On("DeleteItem", mock.Anything).Return(&dynamodb.DeleteItemOutput{}, nil).Once()
+
+	err := apiTest.DeleteRedactionRules(&models.DeleteRedactionRulesInput{LogType: aws.String(testRedactionLogType)})
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.DoesNotExistError{}, err)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,133 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+// the stats are aggregated by hour, so the window can include up to an extra hour
+const sourceStatsWindow = 24 * time.Hour
+
+// AddSourceStats adds the stats of the data processed by the log processor to the stored ones
+// and records when each integration last received data.
+func (API) AddSourceStats(input *models.AddSourceStatsInput) error {
+	for _, stats := range input.Stats {
+		logType := aws.StringValue(stats.LogType)
+		item := &ddb.SourceStatsItem{
+			IntegrationID:              stats.IntegrationID,
+			TimeBin:                    aws.String(ddb.SourceStatsTimeBin(*stats.ReceivedTime, logType)),
+			LogType:                    stats.LogType,
+			LogLineCount:               stats.LogLineCount,
+			BytesProcessedCount:        stats.BytesProcessedCount,
+			EventCount:                 stats.EventCount,
+			ClassificationFailureCount: stats.ClassificationFailureCount,
+			LastReceivedTime:           stats.ReceivedTime,
+			LastEventTime:              stats.LastEventTime,
+			ExpiresAt:                  stats.ReceivedTime.Add(ddb.SourceStatsRetention).Unix(),
+		}
+		if err := dynamoClient.AddSourceStats(item); err != nil {
+			zap.L().Error("failed to add source stats",
+				zap.String("integrationId", *stats.IntegrationID), zap.String("logType", logType), zap.Error(err))
+			return &genericapi.InternalError{Message: "Failed to add source stats"}
+		}
+		if stats.LogType != nil {
+			continue
+		}
+		if err := dynamoClient.UpdateLastReceived(*stats.IntegrationID, *stats.ReceivedTime, stats.EventCount > 0); err != nil {
+			zap.L().Error("failed to update source last received time",
+				zap.String("integrationId", *stats.IntegrationID), zap.Error(err))
+			return &genericapi.InternalError{Message: "Failed to add source stats"}
+		}
+	}
+	return nil
+}
+
+// integrationStats returns the classification stats of the data received by a log analysis integration
+func integrationStats(integrationID string, now time.Time) (*models.SourceIntegrationStats, error) {
+	items, err := dynamoClient.QuerySourceStats(integrationID, now.Add(-sourceStatsWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	var eventCount uint64
+	stats := &models.SourceIntegrationStats{
+		EventsLast24Hours: &eventCount,
+	}
+	if len(items) == 0 { // nothing received recently, find when data was last received
+		latest, err := dynamoClient.GetLatestSourceStats(integrationID)
+		if err != nil {
+			return nil, err
+		}
+		if latest != nil {
+			stats.LastEventReceivedTime = latest.LastReceivedTime
+			stats.LastEventTime = latest.LastEventTime
+		}
+		return stats, nil
+	}
+
+	var logLineCount, classificationFailureCount uint64
+	logTypes := make(map[string]*models.SourceIntegrationLogType)
+	for _, item := range items {
+		stats.LastEventReceivedTime = latestTime(stats.LastEventReceivedTime, item.LastReceivedTime)
+		stats.LastEventTime = latestTime(stats.LastEventTime, item.LastEventTime)
+
+		if item.LogType == nil { // stats of all log types
+			logLineCount += item.LogLineCount
+			classificationFailureCount += item.ClassificationFailureCount
+			eventCount += item.EventCount
+			continue
+		}
+
+		logType, ok := logTypes[*item.LogType]
+		if !ok {
+			logType = &models.SourceIntegrationLogType{
+				LogType:    item.LogType,
+				EventCount: aws.Uint64(0),
+			}
+			logTypes[*item.LogType] = logType
+			stats.LogTypesLast24Hours = append(stats.LogTypesLast24Hours, logType)
+		}
+		*logType.EventCount += item.EventCount
+		logType.LastEventTime = latestTime(logType.LastEventTime, item.LastEventTime)
+	}
+
+	if logLineCount > 0 {
+		stats.ClassificationFailureRate = aws.Float64(float64(classificationFailureCount) / float64(logLineCount))
+	}
+	sort.Slice(stats.LogTypesLast24Hours, func(i, j int) bool {
+		return *stats.LogTypesLast24Hours[i].LogType < *stats.LogTypesLast24Hours[j].LogType
+	})
+	return stats, nil
+}
+
+func latestTime(t1, t2 *time.Time) *time.Time {
+	if t1 == nil || (t2 != nil && t2.After(*t1)) {
+		return t2
+	}
+	return t1
+}
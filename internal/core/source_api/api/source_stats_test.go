@@ -0,0 +1,83 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb/modelstest"
+)
+
+func TestAddSourceStats(t *testing.T) {
+	receivedTime := time.Date(2020, 1, 1, 10, 30, 0, 0, time.UTC)
+	client := &modelstest.MockDDBClient{}
+	client.On("UpdateItem", mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Times(3)
+	dynamoClient = &ddb.DDB{Client: client, TableName: "test", SourceStatsTableName: "testStats"}
+
+	err := apiTest.AddSourceStats(&models.AddSourceStatsInput{
+		Stats: []*models.SourceStats{
+			{
+				IntegrationID: aws.String(testIntegrationID),
+				ReceivedTime:  &receivedTime,
+				LogLineCount:  2,
+				EventCount:    1,
+			},
+			{
+				IntegrationID: aws.String(testIntegrationID),
+				LogType:       aws.String("AWS.VPCFlow"),
+				ReceivedTime:  &receivedTime,
+				LogLineCount:  1,
+				EventCount:    1,
+			},
+		},
+	})
+	require.NoError(t, err)
+	client.AssertExpectations(t)
+
+	input := client.Calls[0].Arguments.Get(0).(*dynamodb.UpdateItemInput)
+	assert.Equal(t, "testStats", *input.TableName)
+	assert.Equal(t, "2020-01-01T10:00:00Z", *input.Key["timeBin"].S)
+	input = client.Calls[1].Arguments.Get(0).(*dynamodb.UpdateItemInput)
+	assert.Equal(t, "test", *input.TableName) // the last received times are recorded once per integration
+	assert.Len(t, input.ExpressionAttributeValues, 2)
+	input = client.Calls[2].Arguments.Get(0).(*dynamodb.UpdateItemInput)
+	assert.Equal(t, "testStats", *input.TableName)
+	assert.Equal(t, "2020-01-01T10:00:00Z#AWS.VPCFlow", *input.Key["timeBin"].S)
+}
+
+func TestAddSourceStatsError(t *testing.T) {
+	client := &modelstest.MockDDBClient{}
+	client.On("UpdateItem", mock.Anything).Return(&dynamodb.UpdateItemOutput{}, assert.AnError).Once()
+	dynamoClient = &ddb.DDB{Client: client, TableName: "test", SourceStatsTableName: "testStats"}
+
+	err := apiTest.AddSourceStats(&models.AddSourceStatsInput{
+		Stats: []*models.SourceStats{{IntegrationID: aws.String(testIntegrationID), ReceivedTime: aws.Time(time.Now())}},
+	})
+	require.Error(t, err)
+	client.AssertExpectations(t)
+}
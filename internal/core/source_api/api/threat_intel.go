@@ -0,0 +1,111 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+var (
+	threatIntelInternalError = &genericapi.InternalError{Message: "Failed to update threat intel indicators. Please try again later"}
+)
+
+// PutThreatIntelIndicators adds or replaces indicators of a feed.
+//
+// The indicators are stored normalized, in the form they are matched against the Panther fields of events.
+func (API) PutThreatIntelIndicators(input *models.PutThreatIntelIndicatorsInput) error {
+	now := time.Now()
+	// a batch write fails if it has the same key twice, the last duplicate wins
+	items := make(map[string]*ddb.ThreatIntelIndicatorItem, len(input.Indicators))
+	for i, indicator := range input.Indicators {
+		value, err := models.NormalizeThreatIntelIndicator(*indicator.Type, *indicator.Value)
+		if err != nil {
+			return &genericapi.InvalidInputError{Message: "indicator " + strconv.Itoa(i) + ": " + err.Error()}
+		}
+		key := ddb.ThreatIntelIndicatorKey(*indicator.Type, value)
+		items[key] = &ddb.ThreatIntelIndicatorItem{
+			Feed:          input.Feed,
+			Indicator:     aws.String(key),
+			Type:          indicator.Type,
+			Value:         aws.String(value),
+			Description:   indicator.Description,
+			CreatedAtTime: aws.Time(now),
+			CreatedBy:     input.UserID,
+		}
+	}
+
+	batch := make([]*ddb.ThreatIntelIndicatorItem, 0, len(items))
+	for _, item := range items {
+		batch = append(batch, item)
+	}
+	if err := dynamoClient.PutThreatIntelIndicators(batch); err != nil {
+		zap.L().Error("failed to put threat intel indicators", zap.String("feed", *input.Feed), zap.Error(err))
+		return threatIntelInternalError
+	}
+	return nil
+}
+
+// ListThreatIntelIndicators returns a page of the indicators of all feeds, or of a single feed.
+//
+// A page is at most one DynamoDB page (1MB), which keeps the response within the Lambda payload limit.
+func (API) ListThreatIntelIndicators(input *models.ListThreatIntelIndicatorsInput) (*models.ListThreatIntelIndicatorsOutput, error) {
+	var limit *int64
+	if input.PageSize != nil {
+		limit = aws.Int64(int64(*input.PageSize))
+	}
+	items, lastEvaluatedKey, err := dynamoClient.ListThreatIntelIndicators(input.Feed, input.ExclusiveStartKey, limit)
+	if err != nil {
+		zap.L().Error("failed to list threat intel indicators", zap.Error(err))
+		return nil, &genericapi.InternalError{Message: "Failed to list threat intel indicators"}
+	}
+
+	result := make([]*models.ThreatIntelIndicator, len(items))
+	for i, item := range items {
+		result[i] = &models.ThreatIntelIndicator{
+			Feed:          item.Feed,
+			Type:          item.Type,
+			Value:         item.Value,
+			Description:   item.Description,
+			CreatedAtTime: item.CreatedAtTime,
+			CreatedBy:     item.CreatedBy,
+		}
+	}
+	return &models.ListThreatIntelIndicatorsOutput{Indicators: result, LastEvaluatedKey: lastEvaluatedKey}, nil
+}
+
+// DeleteThreatIntelFeed deletes all indicators of a feed.
+func (API) DeleteThreatIntelFeed(input *models.DeleteThreatIntelFeedInput) error {
+	deleted, err := dynamoClient.DeleteThreatIntelFeed(*input.Feed)
+	if err != nil {
+		zap.L().Error("failed to delete threat intel feed", zap.String("feed", *input.Feed), zap.Error(err))
+		return threatIntelInternalError
+	}
+	if !deleted {
+		return &genericapi.DoesNotExistError{Message: "Threat intel feed does not exist"}
+	}
+	return nil
+}
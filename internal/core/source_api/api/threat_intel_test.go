@@ -0,0 +1,171 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb/modelstest"
+	"github.com/panther-labs/panther/pkg/genericapi"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+const testThreatIntelFeed = "tor_exit_nodes"
+
+func TestPutThreatIntelIndicators(t *testing.T) {
+	mockClient := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockClient, ThreatIntelTableName: "test"}
+	var written []*dynamodb.WriteRequest
+	mockClient.On("BatchWriteItem", mock.Anything).Run(func(args mock.Arguments) {
+		written = append(written, args.Get(0).(*dynamodb.BatchWriteItemInput).RequestItems["test"]...)
+	}).Return(&dynamodb.BatchWriteItemOutput{}, nil).Once()
+
+	err := apiTest.PutThreatIntelIndicators(&models.PutThreatIntelIndicatorsInput{
+		Feed: aws.String(testThreatIntelFeed),
+		Indicators: []*models.ThreatIntelIndicator{
+			{Type: aws.String(models.ThreatIntelIndicatorDomain), Value: aws.String("Evil.Example.com")},
+			{Type: aws.String(models.ThreatIntelIndicatorDomain), Value: aws.String("evil.example.com.")}, // duplicate
+		},
+		UserID: aws.String(testUserID),
+	})
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+	require.Len(t, written, 1)
+	item := written[0].PutRequest.Item
+	assert.Equal(t, testThreatIntelFeed, *item["feed"].S)
+	assert.Equal(t, "domain#evil.example.com", *item["indicator"].S)
+	assert.Equal(t, "evil.example.com", *item["value"].S)
+	assert.Equal(t, testUserID, *item["createdBy"].S)
+}
+
+func TestPutThreatIntelIndicatorsInvalid(t *testing.T) {
+	mockClient := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockClient, ThreatIntelTableName: "test"}
+
+	err := apiTest.PutThreatIntelIndicators(&models.PutThreatIntelIndicatorsInput{
+		Feed: aws.String(testThreatIntelFeed),
+		Indicators: []*models.ThreatIntelIndicator{
+			{Type: aws.String(models.ThreatIntelIndicatorIP), Value: aws.String("192.0.2.1")},
+			{Type: aws.String(models.ThreatIntelIndicatorIP), Value: aws.String("evil.example.com")},
+		},
+		UserID: aws.String(testUserID),
+	})
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.InvalidInputError{}, err)
+	assert.Contains(t, err.Error(), "indicator 1")
+	mockClient.AssertExpectations(t)
+}
+
+func TestListThreatIntelIndicators(t *testing.T) {
+	dynamoClient = &ddb.DDB{
+		Client: &modelstest.MockDDBClient{
+			MockScanAttributes: []map[string]*dynamodb.AttributeValue{
+				{
+					"feed":      {S: aws.String(testThreatIntelFeed)},
+					"indicator": {S: aws.String("ip#192.0.2.1")},
+					"type":      {S: aws.String(models.ThreatIntelIndicatorIP)},
+					"value":     {S: aws.String("192.0.2.1")},
+				},
+			},
+		},
+		ThreatIntelTableName: "test",
+	}
+
+	out, err := apiTest.ListThreatIntelIndicators(&models.ListThreatIntelIndicatorsInput{})
+	require.NoError(t, err)
+	require.Len(t, out.Indicators, 1)
+	assert.Equal(t, testThreatIntelFeed, *out.Indicators[0].Feed)
+	assert.Equal(t, models.ThreatIntelIndicatorIP, *out.Indicators[0].Type)
+	assert.Equal(t, "192.0.2.1", *out.Indicators[0].Value)
+	assert.Nil(t, out.LastEvaluatedKey)
+}
+
+func TestListThreatIntelIndicatorsPage(t *testing.T) {
+	dynamoClient = &ddb.DDB{
+		Client: &modelstest.MockDDBClient{
+			MockQueryAttributes: []map[string]*dynamodb.AttributeValue{
+				{
+					"feed":      {S: aws.String(testThreatIntelFeed)},
+					"indicator": {S: aws.String("ip#192.0.2.1")},
+					"type":      {S: aws.String(models.ThreatIntelIndicatorIP)},
+					"value":     {S: aws.String("192.0.2.1")},
+				},
+			},
+			MockLastEvaluatedKey: map[string]*dynamodb.AttributeValue{
+				"feed":      {S: aws.String(testThreatIntelFeed)},
+				"indicator": {S: aws.String("ip#192.0.2.1")},
+			},
+		},
+		ThreatIntelTableName: "test",
+	}
+
+	out, err := apiTest.ListThreatIntelIndicators(&models.ListThreatIntelIndicatorsInput{
+		Feed:              aws.String(testThreatIntelFeed),
+		PageSize:          aws.Int(1),
+		ExclusiveStartKey: aws.String(testThreatIntelFeed + "#ip#192.0.2.0"),
+	})
+	require.NoError(t, err)
+	require.Len(t, out.Indicators, 1)
+	assert.Equal(t, testThreatIntelFeed+"#ip#192.0.2.1", aws.StringValue(out.LastEvaluatedKey))
+}
+
+func TestListThreatIntelIndicatorsInvalidStartKey(t *testing.T) {
+	dynamoClient = &ddb.DDB{Client: &modelstest.MockDDBClient{}, ThreatIntelTableName: "test"}
+
+	_, err := apiTest.ListThreatIntelIndicators(&models.ListThreatIntelIndicatorsInput{
+		ExclusiveStartKey: aws.String("invalid"),
+	})
+	require.Error(t, err)
+}
+
+func TestDeleteThreatIntelFeed(t *testing.T) {
+	dynamoClient = &ddb.DDB{
+		Client: &modelstest.MockDDBClient{
+			MockQueryAttributes: []map[string]*dynamodb.AttributeValue{
+				{
+					"feed":      {S: aws.String(testThreatIntelFeed)},
+					"indicator": {S: aws.String("ip#192.0.2.1")},
+				},
+			},
+		},
+		ThreatIntelTableName: "test",
+	}
+
+	err := apiTest.DeleteThreatIntelFeed(&models.DeleteThreatIntelFeedInput{Feed: aws.String(testThreatIntelFeed)})
+	require.NoError(t, err)
+}
+
+func TestDeleteThreatIntelFeedDoesNotExist(t *testing.T) {
+	dynamoClient = &ddb.DDB{
+		Client:               &modelstest.MockDDBClient{},
+		ThreatIntelTableName: "test",
+	}
+
+	err := apiTest.DeleteThreatIntelFeed(&models.DeleteThreatIntelFeedInput{Feed: aws.String(testThreatIntelFeed)})
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.DoesNotExistError{}, err)
+}
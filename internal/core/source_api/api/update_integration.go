@@ -82,6 +82,7 @@ func (api API) UpdateIntegrationSettings(input *models.UpdateIntegrationSettings
 		existingIntegrationItem.S3Prefix = input.S3Prefix
 		existingIntegrationItem.KmsKey = input.KmsKey
 		existingIntegrationItem.LogTypes = input.LogTypes
+		existingIntegrationItem.Multiline = input.Multiline
 	}
 
 	err = dynamoClient.PutItem(existingIntegrationItem)
@@ -51,6 +51,7 @@ func integrationToItem(input *models.SourceIntegration) *ddb.IntegrationItem {
 		item.S3Prefix = input.S3Prefix
 		item.KmsKey = input.KmsKey
 		item.LogTypes = input.LogTypes
+		item.Multiline = input.Multiline
 		item.StackName = input.StackName
 		item.LogProcessingRole = aws.String(generateLogProcessingRoleArn(*input.AWSAccountID, *input.IntegrationLabel))
 	case models.IntegrationTypeAWSScan:
@@ -84,6 +85,7 @@ func itemToIntegration(item *ddb.IntegrationItem) *models.SourceIntegration {
 		integration.S3Prefix = item.S3Prefix
 		integration.KmsKey = item.KmsKey
 		integration.LogTypes = item.LogTypes
+		integration.Multiline = item.Multiline
 		integration.StackName = item.StackName
 		integration.LogProcessingRole = item.LogProcessingRole
 	case models.IntegrationTypeAWSScan:
@@ -31,6 +31,7 @@ import (
 	"github.com/kelseyhightower/envconfig"
 
 	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/pkg/encryption"
 )
 
 const (
@@ -45,6 +46,8 @@ var (
 	dynamoClient     *ddb.DDB
 	sqsClient        sqsiface.SQSAPI
 	templateS3Client s3iface.S3API
+	// encrypts the salts of the redaction rules
+	redactionKey encryption.API
 )
 
 type envConfig struct {
@@ -52,6 +55,11 @@ type envConfig struct {
 	LogProcessorQueueURL    string `required:"true" split_words:"true"`
 	LogProcessorQueueArn    string `required:"true" split_words:"true"`
 	TableName               string `required:"true" split_words:"true"`
+	CustomLogTypesTableName string `required:"true" split_words:"true"`
+	SourceStatsTableName    string `required:"true" split_words:"true"`
+	ThreatIntelTableName    string `required:"true" split_words:"true"`
+	RedactionTableName      string `required:"true" split_words:"true"`
+	RedactionKeyID          string `required:"true" split_words:"true"`
 }
 
 // Setup parses the environment and constructs AWS and http clients on a cold Lambda start.
@@ -60,7 +68,9 @@ func Setup() {
 	envconfig.MustProcess("", &env)
 
 	awsSession = session.Must(session.NewSession())
-	dynamoClient = ddb.New(env.TableName)
+	dynamoClient = ddb.New(env.TableName, env.CustomLogTypesTableName, env.SourceStatsTableName, env.ThreatIntelTableName,
+		env.RedactionTableName)
+	redactionKey = encryption.New(env.RedactionKeyID, awsSession)
 	sqsClient = sqs.New(awsSession)
 	templateS3Client = s3.New(awsSession, &aws.Config{
 		Region: aws.String(templateBucketRegion),
@@ -0,0 +1,84 @@
+package ddb
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/pkg/errors"
+)
+
+// PutCustomLogType adds or replaces a custom log type in the database
+func (ddb *DDB) PutCustomLogType(input *CustomLogTypeItem) error {
+	item, err := dynamodbattribute.MarshalMap(input)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal custom log type")
+	}
+
+	_, err = ddb.Client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(ddb.CustomLogTypesTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to put custom log type")
+	}
+	return nil
+}
+
+// ScanCustomLogTypes returns all custom log types.
+func (ddb *DDB) ScanCustomLogTypes() ([]*CustomLogTypeItem, error) {
+	var items []*CustomLogTypeItem
+	var unmarshalErr error
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(ddb.CustomLogTypesTableName),
+	}
+	err := ddb.Client.ScanPages(scanInput, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		var pageItems []*CustomLogTypeItem
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageItems); unmarshalErr != nil {
+			return false
+		}
+		items = append(items, pageItems...)
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scan custom log types")
+	}
+	if unmarshalErr != nil {
+		return nil, errors.Wrap(unmarshalErr, "failed to unmarshal scan results")
+	}
+	return items, nil
+}
+
+// DeleteCustomLogType deletes a custom log type from the database.
+//
+// Returns false if the log type did not exist.
+func (ddb *DDB) DeleteCustomLogType(logType *string) (bool, error) {
+	output, err := ddb.Client.DeleteItem(&dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			customLogTypeHashKey: {S: logType},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllOld),
+		TableName:    aws.String(ddb.CustomLogTypesTableName),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to delete custom log type from DDB")
+	}
+	return len(output.Attributes) > 0, nil
+}
@@ -25,19 +25,33 @@ import (
 )
 
 const (
-	hashKey = "integrationId"
+	hashKey              = "integrationId"
+	customLogTypeHashKey = "logType"
+	sourceStatsHashKey   = "integrationId"
+	sourceStatsRangeKey  = "timeBin"
+	threatIntelHashKey   = "feed"
+	threatIntelRangeKey  = "indicator"
+	redactionHashKey     = "logType"
 )
 
-// DDB is a struct containing the DynamoDB client, and the table name to retrieve data.
+// DDB is a struct containing the DynamoDB client, and the table names to retrieve data.
 type DDB struct {
-	Client    dynamodbiface.DynamoDBAPI
-	TableName string
+	Client                  dynamodbiface.DynamoDBAPI
+	TableName               string
+	CustomLogTypesTableName string
+	SourceStatsTableName    string
+	ThreatIntelTableName    string
+	RedactionTableName      string
 }
 
 // New instantiates a new client.
-func New(tableName string) *DDB {
+func New(tableName, customLogTypesTableName, sourceStatsTableName, threatIntelTableName, redactionTableName string) *DDB {
 	return &DDB{
-		Client:    dynamodb.New(session.Must(session.NewSession())),
-		TableName: tableName,
+		Client:                  dynamodb.New(session.Must(session.NewSession())),
+		TableName:               tableName,
+		CustomLogTypesTableName: customLogTypesTableName,
+		SourceStatsTableName:    sourceStatsTableName,
+		ThreatIntelTableName:    threatIntelTableName,
+		RedactionTableName:      redactionTableName,
 	}
 }
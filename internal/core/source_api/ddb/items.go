@@ -18,7 +18,11 @@ package ddb
  * along with this program.  If not, see <https://www.gnu.org/licenses/>.
  */
 
-import "time"
+import (
+	"time"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+)
 
 // IntegrationItem represents an integration item as it is stored in DynamoDB.
 type IntegrationItem struct {
@@ -45,4 +49,67 @@ type IntegrationItem struct {
 	LogTypes          []*string `json:"logTypes" dynamodbav:"logTypes,stringset"`
 	StackName         *string   `json:"stackName,omitempty"`
 	LogProcessingRole *string   `json:"logProcessingRole,omitempty"`
+
+	// How the lines of the data are joined into events, nil if each line is an event
+	Multiline *models.MultilineConfig `json:"multiline,omitempty"`
+
+	// Set by the log processor, see UpdateLastReceived()
+	LastObjectReceivedTime *time.Time `json:"lastObjectReceivedTime,omitempty"`
+	LastEventReceivedTime  *time.Time `json:"lastEventReceivedTime,omitempty"`
+	// StaleAlertTime is when an alert was last sent because the source stopped receiving data
+	StaleAlertTime *time.Time `json:"staleAlertTime,omitempty"`
+}
+
+// CustomLogTypeItem represents a user-defined log type schema as it is stored in DynamoDB.
+type CustomLogTypeItem struct {
+	LogType         *string                      `json:"logType"`
+	Description     *string                      `json:"description"`
+	TimestampField  *string                      `json:"timestampField,omitempty"`
+	TimestampFormat *string                      `json:"timestampFormat,omitempty"`
+	Fields          []*models.CustomLogTypeField `json:"fields"`
+	Multiline       *models.MultilineConfig      `json:"multiline,omitempty"`
+	CreatedAtTime   *time.Time                   `json:"createdAtTime"`
+	CreatedBy       *string                      `json:"createdBy"`
+}
+
+// SourceStatsItem represents the classification stats of the data received by a source integration in an hour,
+// either for all log types or for a single log type, as they are stored in DynamoDB.
+type SourceStatsItem struct {
+	IntegrationID *string `json:"integrationId"`
+	// TimeBin is the hour the data was processed in and the log type of the stats, see SourceStatsTimeBin()
+	TimeBin *string `json:"timeBin"`
+	// LogType is empty for the stats of all log types
+	LogType *string `json:"logType,omitempty"`
+
+	LogLineCount        uint64 `json:"logLineCount"`
+	BytesProcessedCount uint64 `json:"bytesProcessedCount"`
+	EventCount          uint64 `json:"eventCount"`
+	// ClassificationFailureCount is only counted for all log types
+	ClassificationFailureCount uint64 `json:"classificationFailureCount"`
+
+	LastReceivedTime *time.Time `json:"lastReceivedTime,omitempty"`
+	LastEventTime    *time.Time `json:"lastEventTime,omitempty"`
+	ExpiresAt        int64      `json:"expiresAt"`
+}
+
+// ThreatIntelIndicatorItem represents an indicator of a threat intel feed as it is stored in DynamoDB.
+type ThreatIntelIndicatorItem struct {
+	Feed *string `json:"feed"`
+	// Indicator is the type and the normalized value of the indicator, see ThreatIntelIndicatorKey()
+	Indicator     *string    `json:"indicator"`
+	Type          *string    `json:"type"`
+	Value         *string    `json:"value"`
+	Description   *string    `json:"description,omitempty"`
+	CreatedAtTime *time.Time `json:"createdAtTime"`
+	CreatedBy     *string    `json:"createdBy"`
+}
+
+// RedactionRulesItem represents the redaction rules of a log type as they are stored in DynamoDB.
+type RedactionRulesItem struct {
+	LogType *string                 `json:"logType"`
+	Rules   []*models.RedactionRule `json:"rules"`
+	// EncryptedSalt is the salt of the hashes, encrypted with the redaction KMS key
+	EncryptedSalt    []byte     `json:"encryptedSalt"`
+	LastModifiedTime *time.Time `json:"lastModifiedTime"`
+	LastModifiedBy   *string    `json:"lastModifiedBy"`
 }
@@ -33,6 +33,7 @@ type MockDDBClient struct {
 	MockScanAttributes      []map[string]*dynamodb.AttributeValue
 	MockItemAttributeOutput map[string]*dynamodb.AttributeValue
 	MockQueryAttributes     []map[string]*dynamodb.AttributeValue
+	MockLastEvaluatedKey    map[string]*dynamodb.AttributeValue
 	TestErr                 bool
 }
 
@@ -56,7 +57,16 @@ func (client *MockDDBClient) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutp
 	if client.TestErr {
 		return nil, errors.New("fake dynamodb.Scan error")
 	}
-	return &dynamodb.ScanOutput{Items: client.MockScanAttributes}, nil
+	return &dynamodb.ScanOutput{Items: client.MockScanAttributes, LastEvaluatedKey: client.MockLastEvaluatedKey}, nil
+}
+
+// ScanPages is a mock DynamoDB ScanPages request, all items are returned in a single page.
+func (client *MockDDBClient) ScanPages(input *dynamodb.ScanInput, fn func(*dynamodb.ScanOutput, bool) bool) error {
+	if client.TestErr {
+		return errors.New("fake dynamodb.ScanPages error")
+	}
+	fn(&dynamodb.ScanOutput{Items: client.MockScanAttributes}, true)
+	return nil
 }
 
 // Query is a mock DynamoDB Query request.
@@ -64,7 +74,7 @@ func (client *MockDDBClient) Query(input *dynamodb.QueryInput) (*dynamodb.QueryO
 	if client.TestErr {
 		return nil, errors.New("fake dynamodb.Query error")
 	}
-	return &dynamodb.QueryOutput{Items: client.MockQueryAttributes}, nil
+	return &dynamodb.QueryOutput{Items: client.MockQueryAttributes, LastEvaluatedKey: client.MockLastEvaluatedKey}, nil
 }
 
 // PutItem is a mock DynamoDB PutItem request.
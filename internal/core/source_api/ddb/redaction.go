@@ -0,0 +1,105 @@
+package ddb
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/pkg/errors"
+)
+
+// PutRedactionRules adds or replaces the redaction rules of a log type in the database
+func (ddb *DDB) PutRedactionRules(input *RedactionRulesItem) error {
+	item, err := dynamodbattribute.MarshalMap(input)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal redaction rules")
+	}
+
+	_, err = ddb.Client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(ddb.RedactionTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to put redaction rules")
+	}
+	return nil
+}
+
+// GetRedactionRules returns the redaction rules of a log type, nil if it has none.
+func (ddb *DDB) GetRedactionRules(logType *string) (*RedactionRulesItem, error) {
+	output, err := ddb.Client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(ddb.RedactionTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			redactionHashKey: {S: logType},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get redaction rules")
+	}
+	if output.Item == nil {
+		return nil, nil
+	}
+	var item RedactionRulesItem
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &item); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal redaction rules")
+	}
+	return &item, nil
+}
+
+// ScanRedactionRules returns the redaction rules of all log types.
+func (ddb *DDB) ScanRedactionRules() ([]*RedactionRulesItem, error) {
+	var items []*RedactionRulesItem
+	var unmarshalErr error
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(ddb.RedactionTableName),
+	}
+	err := ddb.Client.ScanPages(scanInput, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		var pageItems []*RedactionRulesItem
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageItems); unmarshalErr != nil {
+			return false
+		}
+		items = append(items, pageItems...)
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scan redaction rules")
+	}
+	if unmarshalErr != nil {
+		return nil, errors.Wrap(unmarshalErr, "failed to unmarshal scan results")
+	}
+	return items, nil
+}
+
+// DeleteRedactionRules deletes the redaction rules of a log type from the database.
+//
+// Returns false if the log type did not have any.
+func (ddb *DDB) DeleteRedactionRules(logType *string) (bool, error) {
+	output, err := ddb.Client.DeleteItem(&dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			redactionHashKey: {S: logType},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllOld),
+		TableName:    aws.String(ddb.RedactionTableName),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to delete redaction rules")
+	}
+	return len(output.Attributes) > 0, nil
+}
@@ -0,0 +1,72 @@
+package ddb
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/pkg/errors"
+)
+
+// UpdateLastReceived records that a source integration received an object at receivedTime,
+// and classified events from it if eventsReceived.
+//
+// Integrations that have been deleted are not updated.
+func (ddb *DDB) UpdateLastReceived(integrationID string, receivedTime time.Time, eventsReceived bool) error {
+	update := expression.Set(expression.Name("lastObjectReceivedTime"), expression.Value(receivedTime))
+	if eventsReceived {
+		update = update.Set(expression.Name("lastEventReceivedTime"), expression.Value(receivedTime))
+	}
+	return ddb.updateIntegration(integrationID, update)
+}
+
+// UpdateStaleAlertTime records when an alert was sent because a source integration stopped receiving data.
+func (ddb *DDB) UpdateStaleAlertTime(integrationID string, alertTime time.Time) error {
+	return ddb.updateIntegration(integrationID, expression.Set(expression.Name("staleAlertTime"), expression.Value(alertTime)))
+}
+
+func (ddb *DDB) updateIntegration(integrationID string, update expression.UpdateBuilder) error {
+	condition := expression.AttributeExists(expression.Name(hashKey))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return errors.Wrap(err, "failed to build update expression")
+	}
+
+	_, err = ddb.Client.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(ddb.TableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			hashKey: {S: aws.String(integrationID)},
+		},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return nil // the integration was deleted
+		}
+		return errors.Wrap(err, "failed to update integration")
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package ddb
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SourceStatsRetention is how long the stats are kept, the expired stats are deleted by DynamoDB TTL
+	SourceStatsRetention = 30 * 24 * time.Hour
+
+	sourceStatsTimeBinFormat    = time.RFC3339
+	sourceStatsLogTypeSeparator = "#"
+)
+
+// SourceStatsTimeBin returns the range key of the stats of a log type in the hour of t.
+//
+// The keys sort by hour, so the stats of a time window can be queried with a range of keys.
+// The stats of all log types use an empty log type.
+func SourceStatsTimeBin(t time.Time, logType string) string {
+	timeBin := t.UTC().Truncate(time.Hour).Format(sourceStatsTimeBinFormat)
+	if logType == "" {
+		return timeBin
+	}
+	return timeBin + sourceStatsLogTypeSeparator + logType
+}
+
+// AddSourceStats adds the counts of the item to the stored stats of the same source and time bin.
+func (ddb *DDB) AddSourceStats(item *SourceStatsItem) error {
+	update := expression.
+		Add(expression.Name("logLineCount"), expression.Value(item.LogLineCount)).
+		Add(expression.Name("bytesProcessedCount"), expression.Value(item.BytesProcessedCount)).
+		Add(expression.Name("eventCount"), expression.Value(item.EventCount)).
+		Add(expression.Name("classificationFailureCount"), expression.Value(item.ClassificationFailureCount)).
+		Set(expression.Name("expiresAt"), expression.Value(item.ExpiresAt))
+	if item.LogType != nil {
+		update = update.Set(expression.Name("logType"), expression.Value(item.LogType))
+	}
+	if item.LastReceivedTime != nil {
+		update = update.Set(expression.Name("lastReceivedTime"), expression.Value(item.LastReceivedTime))
+	}
+	// NOTE: concurrent updates of the same time bin can store an earlier time, the difference is bound by the
+	// processing time of a file
+	if item.LastEventTime != nil {
+		update = update.Set(expression.Name("lastEventTime"), expression.Value(item.LastEventTime))
+	}
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return errors.Wrap(err, "failed to build update expression")
+	}
+
+	_, err = ddb.Client.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(ddb.SourceStatsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			sourceStatsHashKey:  {S: item.IntegrationID},
+			sourceStatsRangeKey: {S: item.TimeBin},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update source stats")
+	}
+	return nil
+}
+
+// QuerySourceStats returns the stats of a source integration for all log types and for each log type,
+// from the hour of since onwards.
+func (ddb *DDB) QuerySourceStats(integrationID string, since time.Time) ([]*SourceStatsItem, error) {
+	keyCondition := expression.Key(sourceStatsHashKey).Equal(expression.Value(integrationID)).
+		And(expression.Key(sourceStatsRangeKey).GreaterThanEqual(expression.Value(SourceStatsTimeBin(since, ""))))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build key condition expression")
+	}
+	queryInput := &dynamodb.QueryInput{
+		TableName:                 aws.String(ddb.SourceStatsTableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+
+	var items []*SourceStatsItem
+	for {
+		output, err := ddb.Client.Query(queryInput)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to query source stats")
+		}
+		var pageItems []*SourceStatsItem
+		if err := dynamodbattribute.UnmarshalListOfMaps(output.Items, &pageItems); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal source stats")
+		}
+		items = append(items, pageItems...)
+		if len(output.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		queryInput.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// GetLatestSourceStats returns the stats of the last hour a source integration received data in,
+// for all log types or for a single one, nil if there are no stats stored.
+func (ddb *DDB) GetLatestSourceStats(integrationID string) (*SourceStatsItem, error) {
+	keyCondition := expression.Key(sourceStatsHashKey).Equal(expression.Value(integrationID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build key condition expression")
+	}
+	output, err := ddb.Client.Query(&dynamodb.QueryInput{
+		TableName:                 aws.String(ddb.SourceStatsTableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false), // latest first
+		Limit:                     aws.Int64(1),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query source stats")
+	}
+	if len(output.Items) == 0 {
+		return nil, nil
+	}
+	item := &SourceStatsItem{}
+	if err := dynamodbattribute.UnmarshalMap(output.Items[0], item); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal source stats")
+	}
+	return item, nil
+}
@@ -0,0 +1,177 @@
+package ddb
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/pkg/awsbatch/dynamodbbatch"
+)
+
+const (
+	threatIntelIndicatorSeparator = "#"
+	// how long a batch of indicators is retried
+	threatIntelMaxWriteBackoff = 10 * time.Second
+)
+
+// ThreatIntelIndicatorKey returns the range key of an indicator in its feed.
+//
+// Indicators of different types never collide, the type is part of the key for listing them by type.
+func ThreatIntelIndicatorKey(indicatorType, value string) string {
+	return indicatorType + threatIntelIndicatorSeparator + value
+}
+
+// PutThreatIntelIndicators adds or replaces indicators in the database
+func (ddb *DDB) PutThreatIntelIndicators(items []*ThreatIntelIndicatorItem) error {
+	requests := make([]*dynamodb.WriteRequest, len(items))
+	for i, item := range items {
+		attributes, err := dynamodbattribute.MarshalMap(item)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal threat intel indicator")
+		}
+		requests[i] = &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: attributes}}
+	}
+	return ddb.batchWriteThreatIntelIndicators(requests)
+}
+
+// ListThreatIntelIndicators returns a page of the indicators of all feeds, or of a single feed if feed is set.
+//
+// The returned key is nil on the last page, otherwise it is the exclusiveStartKey of the next page.
+func (ddb *DDB) ListThreatIntelIndicators(feed, exclusiveStartKey *string, limit *int64) (
+	[]*ThreatIntelIndicatorItem, *string, error) {
+
+	var startKey map[string]*dynamodb.AttributeValue
+	if exclusiveStartKey != nil {
+		// feed names never contain the separator
+		parts := strings.SplitN(*exclusiveStartKey, threatIntelIndicatorSeparator, 2)
+		if len(parts) != 2 {
+			return nil, nil, errors.Errorf("invalid exclusive start key %q", *exclusiveStartKey)
+		}
+		startKey = map[string]*dynamodb.AttributeValue{
+			threatIntelHashKey:  {S: aws.String(parts[0])},
+			threatIntelRangeKey: {S: aws.String(parts[1])},
+		}
+	}
+
+	var pageItems []map[string]*dynamodb.AttributeValue
+	var lastEvaluatedKey map[string]*dynamodb.AttributeValue
+	if feed != nil {
+		keyCondition := expression.Key(threatIntelHashKey).Equal(expression.Value(*feed))
+		expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to build key condition expression")
+		}
+		output, err := ddb.Client.Query(&dynamodb.QueryInput{
+			TableName:                 aws.String(ddb.ThreatIntelTableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ExclusiveStartKey:         startKey,
+			Limit:                     limit,
+		})
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to query threat intel indicators")
+		}
+		pageItems, lastEvaluatedKey = output.Items, output.LastEvaluatedKey
+	} else {
+		output, err := ddb.Client.Scan(&dynamodb.ScanInput{
+			TableName:         aws.String(ddb.ThreatIntelTableName),
+			ExclusiveStartKey: startKey,
+			Limit:             limit,
+		})
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to scan threat intel indicators")
+		}
+		pageItems, lastEvaluatedKey = output.Items, output.LastEvaluatedKey
+	}
+
+	var items []*ThreatIntelIndicatorItem
+	if err := dynamodbattribute.UnmarshalListOfMaps(pageItems, &items); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal threat intel indicators")
+	}
+	if len(lastEvaluatedKey) == 0 {
+		return items, nil, nil
+	}
+	feedKey, indicatorKey := lastEvaluatedKey[threatIntelHashKey], lastEvaluatedKey[threatIntelRangeKey]
+	if feedKey == nil || indicatorKey == nil {
+		return nil, nil, errors.New("invalid last evaluated key")
+	}
+	nextKey := aws.StringValue(feedKey.S) + threatIntelIndicatorSeparator + aws.StringValue(indicatorKey.S)
+	return items, &nextKey, nil
+}
+
+// QueryThreatIntelIndicators returns all indicators of a feed.
+func (ddb *DDB) QueryThreatIntelIndicators(feed string) ([]*ThreatIntelIndicatorItem, error) {
+	var items []*ThreatIntelIndicatorItem
+	var startKey *string
+	for {
+		pageItems, lastEvaluatedKey, err := ddb.ListThreatIntelIndicators(&feed, startKey, nil)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, pageItems...)
+		if lastEvaluatedKey == nil {
+			return items, nil
+		}
+		startKey = lastEvaluatedKey
+	}
+}
+
+// DeleteThreatIntelFeed deletes all indicators of a feed from the database.
+//
+// Returns false if the feed did not have any indicators.
+func (ddb *DDB) DeleteThreatIntelFeed(feed string) (bool, error) {
+	items, err := ddb.QueryThreatIntelIndicators(feed)
+	if err != nil {
+		return false, err
+	}
+	if len(items) == 0 {
+		return false, nil
+	}
+	requests := make([]*dynamodb.WriteRequest, len(items))
+	for i, item := range items {
+		requests[i] = &dynamodb.WriteRequest{DeleteRequest: &dynamodb.DeleteRequest{
+			Key: map[string]*dynamodb.AttributeValue{
+				threatIntelHashKey:  {S: item.Feed},
+				threatIntelRangeKey: {S: item.Indicator},
+			},
+		}}
+	}
+	if err := ddb.batchWriteThreatIntelIndicators(requests); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (ddb *DDB) batchWriteThreatIntelIndicators(requests []*dynamodb.WriteRequest) error {
+	input := &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{ddb.ThreatIntelTableName: requests},
+	}
+	if err := dynamodbbatch.BatchWriteItem(ddb.Client, threatIntelMaxWriteBackoff, input); err != nil {
+		return errors.Wrap(err, "failed to write threat intel indicators")
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package checker
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	alertmodels "github.com/panther-labs/panther/internal/core/alert_delivery/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+)
+
+// staleSource is a log source that has not received data for longer than the stale window
+type staleSource struct {
+	integration *ddb.IntegrationItem
+	// lastReceived is when the source last received the data that is missing
+	lastReceived time.Time
+	missing      string
+}
+
+// CheckSources sends an alert for each log source that stopped receiving data.
+//
+// A single alert is sent until the source receives data again.
+func CheckSources(now time.Time) error {
+	integrations, err := dynamoClient.ScanIntegrations(aws.String(models.IntegrationTypeAWS3))
+	if err != nil {
+		return err
+	}
+
+	var checkErr error
+	for _, integration := range integrations {
+		source := checkSource(integration, now, env.staleWindow())
+		if source == nil || alerted(source) {
+			continue
+		}
+
+		zap.L().Warn("log source stopped receiving data",
+			zap.String("integrationId", *integration.IntegrationID),
+			zap.String("missing", source.missing),
+			zap.Time("lastReceived", source.lastReceived))
+		if err := sendAlert(source, now); err != nil {
+			checkErr = err
+			continue
+		}
+		if err := dynamoClient.UpdateStaleAlertTime(*integration.IntegrationID, now); err != nil {
+			checkErr = err
+		}
+	}
+	return checkErr
+}
+
+// checkSource returns the stale source if an integration has not received objects or classified events
+// within the window, nil otherwise
+func checkSource(integration *ddb.IntegrationItem, now time.Time, window time.Duration) *staleSource {
+	lastObjectReceived, ok := lastReceived(integration.LastObjectReceivedTime, integration.CreatedAtTime)
+	if ok && now.Sub(lastObjectReceived) >= window {
+		return &staleSource{integration: integration, lastReceived: lastObjectReceived, missing: "objects"}
+	}
+
+	lastEventReceived, ok := lastReceived(integration.LastEventReceivedTime, integration.CreatedAtTime)
+	if ok && now.Sub(lastEventReceived) >= window {
+		return &staleSource{integration: integration, lastReceived: lastEventReceived, missing: "classified events"}
+	}
+	return nil
+}
+
+// lastReceived returns when data was last received, sources that never received data are checked from their creation.
+// It returns false if neither time is known, e.g. for integrations created before the creation time was stored.
+func lastReceived(receivedAt, createdAt *time.Time) (time.Time, bool) {
+	if receivedAt != nil {
+		return *receivedAt, true
+	}
+	if createdAt != nil {
+		return *createdAt, true
+	}
+	return time.Time{}, false
+}
+
+// alerted returns true if an alert was already sent since the source last received data
+func alerted(source *staleSource) bool {
+	alertTime := source.integration.StaleAlertTime
+	return alertTime != nil && alertTime.After(source.lastReceived)
+}
+
+func sendAlert(source *staleSource, now time.Time) error {
+	integration := source.integration
+	label := aws.StringValue(integration.IntegrationLabel)
+	alert := &alertmodels.Alert{
+		AlertID:    aws.String(fmt.Sprintf("%s-%d", *integration.IntegrationID, now.Unix())),
+		CreatedAt:  aws.Time(now),
+		PolicyID:   integration.IntegrationID,
+		PolicyName: aws.String("Log source " + label),
+		PolicyDescription: aws.String(fmt.Sprintf(
+			"The log source %s (s3://%s/%s) has not received any %s since %s.",
+			label, aws.StringValue(integration.S3Bucket), aws.StringValue(integration.S3Prefix),
+			source.missing, source.lastReceived.Format(time.RFC3339))),
+		Runbook: aws.String("Check that data is still written to the S3 bucket of the source, that the bucket notifications " +
+			"are sent to Panther, that the log processing role can read the data, and that the data is of the log types " +
+			"of the source."),
+		Severity: aws.String(env.AlertSeverity),
+		Type:     aws.String(alertmodels.SystemHealthType),
+		Title:    aws.String(fmt.Sprintf("Log source %s stopped receiving %s", label, source.missing)),
+	}
+
+	body, err := jsoniter.MarshalToString(alert)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal alert")
+	}
+	_, err = sqsClient.SendMessage(&sqs.SendMessageInput{
+		QueueUrl:    aws.String(env.AlertQueueURL),
+		MessageBody: aws.String(body),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to send alert for log source %s", *integration.IntegrationID)
+	}
+	return nil
+}
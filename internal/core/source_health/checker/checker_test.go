@@ -0,0 +1,156 @@
+package checker
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	alertmodels "github.com/panther-labs/panther/internal/core/alert_delivery/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+var (
+	testNow     = time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	testCreated = testNow.Add(-7 * 24 * time.Hour)
+)
+
+func init() {
+	env = envConfig{
+		TableName:       "testTable",
+		AlertQueueURL:   "testQueue",
+		StaleWindowMins: 60,
+		AlertSeverity:   "MEDIUM",
+	}
+}
+
+func TestCheckSource(t *testing.T) {
+	window := time.Hour
+	recently := testNow.Add(-time.Minute)
+	longAgo := testNow.Add(-2 * time.Hour)
+
+	// receiving data
+	assert.Nil(t, checkSource(&ddb.IntegrationItem{
+		CreatedAtTime:          &testCreated,
+		LastObjectReceivedTime: &recently,
+		LastEventReceivedTime:  &recently,
+	}, testNow, window))
+	// new source
+	assert.Nil(t, checkSource(&ddb.IntegrationItem{CreatedAtTime: &recently}, testNow, window))
+
+	// never received data
+	assert.Equal(t, &staleSource{
+		integration:  &ddb.IntegrationItem{CreatedAtTime: &testCreated},
+		lastReceived: testCreated,
+		missing:      "objects",
+	}, checkSource(&ddb.IntegrationItem{CreatedAtTime: &testCreated}, testNow, window))
+
+	// receiving objects that fail classification
+	integration := &ddb.IntegrationItem{
+		CreatedAtTime:          &testCreated,
+		LastObjectReceivedTime: &recently,
+		LastEventReceivedTime:  &longAgo,
+	}
+	assert.Equal(t, &staleSource{
+		integration:  integration,
+		lastReceived: longAgo,
+		missing:      "classified events",
+	}, checkSource(integration, testNow, window))
+
+	// no creation time
+	assert.Nil(t, checkSource(&ddb.IntegrationItem{}, testNow, window))
+	assert.Nil(t, checkSource(&ddb.IntegrationItem{LastObjectReceivedTime: &recently}, testNow, window))
+	integration = &ddb.IntegrationItem{LastObjectReceivedTime: &longAgo}
+	assert.Equal(t, &staleSource{
+		integration:  integration,
+		lastReceived: longAgo,
+		missing:      "objects",
+	}, checkSource(integration, testNow, window))
+}
+
+func TestCheckSources(t *testing.T) {
+	recently := testNow.Add(-time.Minute)
+	longAgo := testNow.Add(-2 * time.Hour)
+	alertTime := testNow.Add(-time.Hour)
+	integrations := []*ddb.IntegrationItem{
+		{ // healthy
+			IntegrationID:          aws.String("healthy"),
+			CreatedAtTime:          &testCreated,
+			LastObjectReceivedTime: &recently,
+			LastEventReceivedTime:  &recently,
+		},
+		{
+			IntegrationID:          aws.String("stale"),
+			IntegrationLabel:       aws.String("stale-label"),
+			CreatedAtTime:          &testCreated,
+			LastObjectReceivedTime: &longAgo,
+			LastEventReceivedTime:  &longAgo,
+			S3Bucket:               aws.String("bucket"),
+		},
+		{ // already alerted
+			IntegrationID:          aws.String("alerted"),
+			CreatedAtTime:          &testCreated,
+			LastObjectReceivedTime: &longAgo,
+			LastEventReceivedTime:  &longAgo,
+			StaleAlertTime:         &alertTime,
+		},
+	}
+	var items []map[string]*dynamodb.AttributeValue
+	for _, integration := range integrations {
+		item, err := dynamodbattribute.MarshalMap(integration)
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+
+	mockDynamo := &testutils.DynamoDBMock{}
+	dynamoClient = &ddb.DDB{Client: mockDynamo, TableName: "testTable"}
+	mockDynamo.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{Items: items}, nil).Once()
+	mockDynamo.On("UpdateItem", mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	mockSqs := &testutils.SqsMock{}
+	sqsClient = mockSqs
+	mockSqs.On("SendMessage", mock.Anything).Return(&sqs.SendMessageOutput{}, nil).Once()
+
+	require.NoError(t, CheckSources(testNow))
+	mockDynamo.AssertExpectations(t)
+	mockSqs.AssertExpectations(t)
+
+	update := mockDynamo.Calls[1].Arguments.Get(0).(*dynamodb.UpdateItemInput)
+	assert.Equal(t, "stale", *update.Key["integrationId"].S)
+
+	input := mockSqs.Calls[0].Arguments.Get(0).(*sqs.SendMessageInput)
+	assert.Equal(t, "testQueue", *input.QueueUrl)
+	alert := &alertmodels.Alert{}
+	require.NoError(t, jsoniter.UnmarshalFromString(*input.MessageBody, alert))
+	assert.Equal(t, alertmodels.SystemHealthType, *alert.Type)
+	assert.Equal(t, "stale", *alert.PolicyID)
+	assert.Equal(t, "MEDIUM", *alert.Severity)
+	assert.Equal(t, "Log source stale-label stopped receiving objects", *alert.Title)
+	assert.Equal(t, "The log source stale-label (s3://bucket/) has not received any objects since 2020-01-01T22:00:00Z.",
+		*alert.PolicyDescription)
+}
@@ -0,0 +1,59 @@
+package checker
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+)
+
+var (
+	env envConfig
+
+	dynamoClient *ddb.DDB
+	sqsClient    sqsiface.SQSAPI
+)
+
+type envConfig struct {
+	TableName     string `required:"true" split_words:"true"`
+	AlertQueueURL string `required:"true" split_words:"true"`
+	// StaleWindowMins is how long a source can go without receiving data before an alert is sent
+	StaleWindowMins int    `required:"true" split_words:"true"`
+	AlertSeverity   string `default:"MEDIUM" split_words:"true"`
+}
+
+func (env *envConfig) staleWindow() time.Duration {
+	return time.Duration(env.StaleWindowMins) * time.Minute
+}
+
+// Setup parses the environment and constructs the AWS clients on a cold Lambda start.
+// All required environment variables must be present or this function will panic.
+func Setup() {
+	envconfig.MustProcess("", &env)
+
+	awsSession := session.Must(session.NewSession())
+	dynamoClient = ddb.New(env.TableName, "", "", "", "")
+	sqsClient = sqs.New(awsSession)
+}
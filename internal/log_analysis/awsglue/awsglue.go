@@ -27,8 +27,9 @@ import (
 // This file registers the Panther specific assumptions about tables and partition formats with associated functions.
 
 const (
-	logS3Prefix       = "logs"
-	ruleMatchS3Prefix = "rules"
+	logS3Prefix                   = "logs"
+	ruleMatchS3Prefix             = "rules"
+	classificationFailureS3Prefix = "classification_failures"
 
 	LogProcessingDatabaseName        = "panther_logs"
 	LogProcessingDatabaseDescription = "Holds tables with data from Panther log processing"
@@ -52,20 +53,26 @@ var (
 	}
 )
 
-// Returns the prefix of the table in S3 or error if it failed to generate it
+// Returns the database of the table
 func getDatabase(dataType models.DataType) string {
-	if dataType == models.LogData {
+	switch dataType {
+	case models.LogData, models.ClassificationFailureData: // failures are queried along with the logs
 		return LogProcessingDatabaseName
+	default:
+		return RuleMatchDatabaseName
 	}
-	return RuleMatchDatabaseName
 }
 
 // Returns the prefix of the table in S3 or error if it failed to generate it
 func getTablePrefix(dataType models.DataType, tableName string) string {
-	if dataType == models.LogData {
+	switch dataType {
+	case models.LogData:
 		return logS3Prefix + "/" + tableName + "/"
+	case models.ClassificationFailureData:
+		return classificationFailureS3Prefix + "/" + tableName + "/"
+	default:
+		return ruleMatchS3Prefix + "/" + tableName + "/"
 	}
-	return ruleMatchS3Prefix + "/" + tableName + "/"
 }
 
 func GetTableName(logType string) string {
@@ -32,6 +32,16 @@ import (
 	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
 )
 
+// StorageFormat is the file format of the data of a table
+type StorageFormat string
+
+const (
+	// JSONStorageFormat stores the data as gzip compressed newline delimited JSON (the default)
+	JSONStorageFormat StorageFormat = "json"
+	// ParquetStorageFormat stores the data as Parquet files, this reduces the data scanned by columnar queries
+	ParquetStorageFormat StorageFormat = "parquet"
+)
+
 type PartitionKey struct {
 	Name string
 	Type string
@@ -39,6 +49,7 @@ type PartitionKey struct {
 
 // Metadata about Glue table
 type GlueTableMetadata struct {
+	datatype     models.DataType
 	databaseName string
 	tableName    string
 	description  string
@@ -46,6 +57,7 @@ type GlueTableMetadata struct {
 	prefix       string
 	timebin      GlueTableTimebin // at what time resolution is this table partitioned
 	eventStruct  interface{}
+	format       StorageFormat
 }
 
 // Creates a new GlueTableMetadata object for Panther log sources
@@ -55,6 +67,7 @@ func NewGlueTableMetadata(
 	tableName := GetTableName(logType)
 	tablePrefix := getTablePrefix(datatype, tableName)
 	return &GlueTableMetadata{
+		datatype:     datatype,
 		databaseName: getDatabase(datatype),
 		tableName:    tableName,
 		description:  logDescription,
@@ -62,9 +75,14 @@ func NewGlueTableMetadata(
 		logType:      logType,
 		prefix:       tablePrefix,
 		eventStruct:  eventStruct,
+		format:       JSONStorageFormat,
 	}
 }
 
+func (gm *GlueTableMetadata) DataType() models.DataType {
+	return gm.datatype
+}
+
 func (gm *GlueTableMetadata) DatabaseName() string {
 	return gm.databaseName
 }
@@ -94,6 +112,16 @@ func (gm *GlueTableMetadata) EventStruct() interface{} {
 	return gm.eventStruct
 }
 
+func (gm *GlueTableMetadata) Format() StorageFormat {
+	return gm.format
+}
+
+// SetFormat sets the format the data of the table is stored in.
+// NOTE: this is not safe for concurrent use, it should be set before processing starts.
+func (gm *GlueTableMetadata) SetFormat(format StorageFormat) {
+	gm.format = format
+}
+
 func (gm *GlueTableMetadata) HasPartitions(glueClient glueiface.GlueAPI) (bool, error) {
 	return TableHasPartitions(glueClient, gm.databaseName, gm.tableName)
 }
@@ -206,16 +234,17 @@ func (gm *GlueTableMetadata) SyncPartitions(glueClient glueiface.GlueAPI, s3Clie
 	return <-errChan
 }
 
-func (gm *GlueTableMetadata) CreateJSONPartition(client glueiface.GlueAPI, t time.Time) (created bool, err error) {
+// CreatePartition creates the partition of the table for time t, the partition is stored in the same format as the table
+func (gm *GlueTableMetadata) CreatePartition(client glueiface.GlueAPI, t time.Time) (created bool, err error) {
 	// inherit StorageDescriptor from table
 	tableOutput, err := GetTable(client, gm.databaseName, gm.tableName)
 	if err != nil {
 		return false, err
 	}
 
-	// ensure this is a JSON table, use Contains() because there are multiple json serdes
-	if !IsJSONPartition(tableOutput.Table.StorageDescriptor) {
-		return false, errors.Errorf("not a JSON table: %#v", *tableOutput.Table.StorageDescriptor)
+	// ensure this is a JSON or Parquet table, use Contains() because there are multiple json serdes
+	if !IsJSONPartition(tableOutput.Table.StorageDescriptor) && !IsParquetPartition(tableOutput.Table.StorageDescriptor) {
+		return false, errors.Errorf("not a JSON or Parquet table: %#v", *tableOutput.Table.StorageDescriptor)
 	}
 
 	return gm.createPartition(client, t, tableOutput)
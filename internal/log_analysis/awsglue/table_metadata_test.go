@@ -94,9 +94,20 @@ func TestGlueTableMetadataLogData(t *testing.T) {
 	assert.Equal(t, LogProcessingDatabaseName, gm.DatabaseName())
 	assert.Equal(t, "logs/my_logs_type/", gm.Prefix())
 	assert.Equal(t, partitionTestEvent{}, gm.eventStruct)
+	assert.Equal(t, JSONStorageFormat, gm.Format())
 	assert.Equal(t, "logs/my_logs_type/year=2020/month=01/day=03/hour=01/", gm.GetPartitionPrefix(refTime))
 }
 
+func TestGlueTableMetadataClassificationFailures(t *testing.T) {
+	gm := NewGlueTableMetadata(models.ClassificationFailureData, "My.Failure", "description", GlueTableHourly, partitionTestEvent{})
+
+	assert.Equal(t, models.ClassificationFailureData, gm.DataType())
+	assert.Equal(t, "my_failure", gm.TableName())
+	assert.Equal(t, LogProcessingDatabaseName, gm.DatabaseName())
+	assert.Equal(t, "classification_failures/my_failure/", gm.Prefix())
+	assert.Equal(t, "classification_failures/my_failure/year=2020/month=01/day=03/hour=01/", gm.GetPartitionPrefix(refTime))
+}
+
 func TestGlueTableMetadataRuleMatches(t *testing.T) {
 	gm := NewGlueTableMetadata(models.RuleData, "My.Rule", "description", GlueTableHourly, partitionTestEvent{})
 
@@ -110,38 +121,83 @@ func TestGlueTableMetadataRuleMatches(t *testing.T) {
 	assert.Equal(t, "rules/my_rule/year=2020/month=01/day=03/hour=01/", gm.GetPartitionPrefix(refTime))
 }
 
-func TestCreateJSONPartition(t *testing.T) {
+func TestCreatePartition(t *testing.T) {
 	gm := NewGlueTableMetadata(models.LogData, "Test.Logs", "Description", GlueTableHourly, partitionTestEvent{})
 
 	// test no errors and partition does not exist (no error)
 	glueClient := &testutils.GlueMock{}
 	glueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil).Once()
 	glueClient.On("CreatePartition", mock.Anything).Return(testCreatePartitionOutput, nil).Once()
-	created, err := gm.CreateJSONPartition(glueClient, refTime)
+	created, err := gm.CreatePartition(glueClient, refTime)
 	assert.NoError(t, err)
 	assert.True(t, created)
 	glueClient.AssertExpectations(t)
 }
 
-func TestCreateJSONPartitionPartitionExists(t *testing.T) {
+func TestCreateTablePartitionParquet(t *testing.T) {
+	gm := NewGlueTableMetadata(models.LogData, "Test.Logs", "Description", GlueTableHourly, partitionTestEvent{})
+	gm.SetFormat(ParquetStorageFormat)
+	assert.Equal(t, ParquetStorageFormat, gm.Format())
+
+	storageDescriptor := *testStorageDescriptor
+	storageDescriptor.SerdeInfo = &glue.SerDeInfo{
+		SerializationLibrary: aws.String("org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe"),
+	}
+	getTableOutput := &glue.GetTableOutput{
+		Table: &glue.TableData{
+			StorageDescriptor: &storageDescriptor,
+		},
+	}
+
+	glueClient := &testutils.GlueMock{}
+	glueClient.On("GetTable", mock.Anything).Return(getTableOutput, nil).Once()
+	glueClient.On("CreatePartition", mock.Anything).Return(testCreatePartitionOutput, nil).Once()
+	created, err := gm.CreatePartition(glueClient, refTime)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	glueClient.AssertExpectations(t)
+}
+
+func TestCreateTablePartitionUnsupportedFormat(t *testing.T) {
+	gm := NewGlueTableMetadata(models.LogData, "Test.Logs", "Description", GlueTableHourly, partitionTestEvent{})
+
+	storageDescriptor := *testStorageDescriptor
+	storageDescriptor.SerdeInfo = &glue.SerDeInfo{
+		SerializationLibrary: aws.String("org.apache.hadoop.hive.serde2.lazy.LazySimpleSerDe"),
+	}
+	getTableOutput := &glue.GetTableOutput{
+		Table: &glue.TableData{
+			StorageDescriptor: &storageDescriptor,
+		},
+	}
+
+	glueClient := &testutils.GlueMock{}
+	glueClient.On("GetTable", mock.Anything).Return(getTableOutput, nil).Once()
+	created, err := gm.CreatePartition(glueClient, refTime)
+	assert.Error(t, err)
+	assert.False(t, created)
+	glueClient.AssertExpectations(t)
+}
+
+func TestCreateTablePartitionPartitionExists(t *testing.T) {
 	gm := NewGlueTableMetadata(models.LogData, "Test.Logs", "Description", GlueTableHourly, partitionTestEvent{})
 
 	// test partition exists at start
 	glueClient := &testutils.GlueMock{}
 	glueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil)
 	glueClient.On("CreatePartition", mock.Anything).Return(testCreatePartitionOutput, entityExistsError)
-	created, err := gm.CreateJSONPartition(glueClient, refTime)
+	created, err := gm.CreatePartition(glueClient, refTime)
 	assert.NoError(t, err)
 	assert.False(t, created)
 	glueClient.AssertExpectations(t)
 }
 
-func TestCreateJSONPartitionErrorGettingTable(t *testing.T) {
+func TestCreateTablePartitionErrorGettingTable(t *testing.T) {
 	gm := NewGlueTableMetadata(models.LogData, "Test.Logs", "Description", GlueTableHourly, partitionTestEvent{})
 	// test error in GetTable
 	glueClient := &testutils.GlueMock{}
 	glueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nonAWSError).Once()
-	created, err := gm.CreateJSONPartition(glueClient, refTime)
+	created, err := gm.CreatePartition(glueClient, refTime)
 	assert.Error(t, err)
 	assert.False(t, created)
 	assert.Equal(t, errors.Wrapf(nonAWSError, "cannot get table: %s.%s", LogProcessingDatabaseName, "test_logs").Error(),
@@ -149,13 +205,13 @@ func TestCreateJSONPartitionErrorGettingTable(t *testing.T) {
 	glueClient.AssertExpectations(t)
 }
 
-func TestCreateJSONPartitionNonAWSError(t *testing.T) {
+func TestCreateTablePartitionNonAWSError(t *testing.T) {
 	gm := NewGlueTableMetadata(models.LogData, "Test.Logs", "Description", GlueTableHourly, partitionTestEvent{})
 	// test error in CreatePartition
 	glueClient := &testutils.GlueMock{}
 	glueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil).Once()
 	glueClient.On("CreatePartition", mock.Anything).Return(testCreatePartitionOutput, nonAWSError).Once()
-	created, err := gm.CreateJSONPartition(glueClient, refTime)
+	created, err := gm.CreatePartition(glueClient, refTime)
 	assert.Error(t, err)
 	assert.False(t, created)
 	assert.Equal(t, nonAWSError, err)
@@ -123,6 +123,10 @@ func IsJSONPartition(storageDescriptor *glue.StorageDescriptor) bool {
 	return strings.Contains(*storageDescriptor.SerdeInfo.SerializationLibrary, "json")
 }
 
+func IsParquetPartition(storageDescriptor *glue.StorageDescriptor) bool {
+	return strings.Contains(*storageDescriptor.SerdeInfo.SerializationLibrary, "parquet")
+}
+
 func ParseS3URL(s3URL string) (bucket, key string, err error) {
 	parsedPath, err := url.Parse(s3URL)
 	if err != nil {
@@ -0,0 +1,157 @@
+package process
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/athenaviews"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/glueschema"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+)
+
+var (
+	// customTablePrefix is the prefix of the Glue tables of all custom log types
+	customTablePrefix = awsglue.GetTableName(sourcemodels.CustomLogTypePrefix)
+
+	// customTableCache stores the custom tables known to exist, to avoid looking them up for every partition
+	customTableCache = make(map[string]struct{})
+
+	// updateViews is a variable so it can be replaced in tests
+	updateViews = func() error {
+		// nil s3 path uses the default output location of the workgroup
+		return athenaviews.UpdateViews(athenaClient, registry.AvailableTables(), nil)
+	}
+)
+
+// ensureCustomTable creates the Glue table of a custom log type if it does not exist yet and adds it to the views
+func ensureCustomTable(gluePartition *awsglue.GluePartition) error {
+	if !strings.HasPrefix(gluePartition.GetTable(), customTablePrefix) {
+		return nil
+	}
+	databaseName, tableName := gluePartition.GetDatabase(), gluePartition.GetTable()
+	cacheKey := databaseName + "." + tableName
+	if _, ok := customTableCache[cacheKey]; ok {
+		return nil
+	}
+
+	_, err := awsglue.GetTable(glueClient, databaseName, tableName)
+	if err == nil {
+		customTableCache[cacheKey] = struct{}{}
+		return nil
+	}
+	if awsErr, ok := errors.Cause(err).(awserr.Error); !ok || awsErr.Code() != glue.ErrCodeEntityNotFoundException {
+		return err
+	}
+
+	// the log type may have been added after this container started
+	if err := registry.LoadCustomLogTypes(lambdaClient); err != nil {
+		return err
+	}
+	parserMetadata := registry.LookupTable(tableName)
+	if parserMetadata == nil {
+		return errors.Errorf("no custom log type for table %s", tableName)
+	}
+
+	logTable := parserMetadata.GlueTableMetadata
+	var extraColumns []glueschema.Column
+	if databaseName == awsglue.RuleMatchDatabaseName {
+		logTable = awsglue.NewGlueTableMetadata(
+			models.RuleData, logTable.LogType(), logTable.Description(), awsglue.GlueTableHourly, logTable.EventStruct())
+		extraColumns = glueschema.RuleMatchColumns
+	}
+	if err := createJSONLTable(logTable, gluePartition.GetS3Bucket(), extraColumns...); err != nil {
+		return err
+	}
+	zap.L().Info("created table for custom log type",
+		zap.String("database", databaseName), zap.String("table", tableName))
+
+	if err := updateViews(); err != nil {
+		return err
+	}
+	customTableCache[cacheKey] = struct{}{}
+	return nil
+}
+
+// createJSONLTable creates a table with the same layout as the tables generated by gluecf.GenerateTables()
+func createJSONLTable(table *awsglue.GlueTableMetadata, bucket string, extraColumns ...glueschema.Column) error {
+	columns := append(glueschema.InferJSONColumns(table.EventStruct(), glueschema.GlueMappings...), extraColumns...)
+	serdeParameters := make(map[string]*string)
+	for key, value := range glueschema.JSONLSerdeParameters(columns) {
+		serdeParameters[key] = aws.String(value)
+	}
+	_, err := glueClient.CreateTable(&glue.CreateTableInput{
+		DatabaseName: aws.String(table.DatabaseName()),
+		TableInput: &glue.TableInput{
+			Name:          aws.String(table.TableName()),
+			Description:   aws.String(table.Description()),
+			TableType:     aws.String(glueschema.ExternalTableType),
+			PartitionKeys: glueColumns(partitionKeyColumns(table)),
+			StorageDescriptor: &glue.StorageDescriptor{
+				InputFormat:  aws.String(glueschema.JSONLInputFormat),
+				OutputFormat: aws.String(glueschema.JSONLOutputFormat),
+				Location:     aws.String("s3://" + bucket + "/" + table.Prefix()),
+				Columns:      glueColumns(columns),
+				SerdeInfo: &glue.SerDeInfo{
+					SerializationLibrary: aws.String(glueschema.JSONLSerializationLibrary),
+					Parameters:           serdeParameters,
+				},
+			},
+		},
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == glue.ErrCodeAlreadyExistsException {
+			return nil // created concurrently
+		}
+		return errors.Wrapf(err, "cannot create table: %s.%s", table.DatabaseName(), table.TableName())
+	}
+	return nil
+}
+
+func partitionKeyColumns(table *awsglue.GlueTableMetadata) (columns []glueschema.Column) {
+	for _, partitionKey := range table.PartitionKeys() {
+		columns = append(columns, glueschema.Column{
+			Name:    partitionKey.Name,
+			Type:    partitionKey.Type,
+			Comment: partitionKey.Name,
+		})
+	}
+	return columns
+}
+
+func glueColumns(columns []glueschema.Column) []*glue.Column {
+	result := make([]*glue.Column, len(columns))
+	for i, column := range columns {
+		result[i] = &glue.Column{
+			Name:    aws.String(column.Name),
+			Type:    aws.String(column.Type),
+			Comment: aws.String(column.Comment),
+		}
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package process
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func TestProcessCreatesCustomTable(t *testing.T) {
+	initProcessTest()
+	customTableCache = make(map[string]struct{})
+	viewsUpdated := 0
+	updateViews = func() error {
+		viewsUpdated++
+		return nil
+	}
+
+	mockLambdaClient := &testutils.LambdaMock{}
+	lambdaClient = mockLambdaClient
+	payload, err := jsoniter.Marshal([]*sourcemodels.CustomLogType{
+		{
+			LogType:     aws.String("Custom.Updater"),
+			Description: aws.String("Updater test logs"),
+			Fields: []*sourcemodels.CustomLogTypeField{
+				{
+					Name:     aws.String("message"),
+					Type:     aws.String(sourcemodels.CustomFieldTypeString),
+					Required: aws.Bool(true),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	mockLambdaClient.On("Invoke", mock.Anything).Return(&lambda.InvokeOutput{Payload: payload}, nil).Once()
+
+	notFound := awserr.New(glue.ErrCodeEntityNotFoundException, "not found", nil)
+	mockGlueClient.On("GetTable", mock.Anything).Return(&glue.GetTableOutput{}, notFound).Once()
+	mockGlueClient.On("CreateTable", mock.MatchedBy(func(input *glue.CreateTableInput) bool {
+		return *input.DatabaseName == "panther_logs" && *input.TableInput.Name == "custom_updater" &&
+			*input.TableInput.StorageDescriptor.Location == "s3://bucket/logs/custom_updater/"
+	})).Return(&glue.CreateTableOutput{}, nil).Once()
+	mockGlueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil).Once()
+	mockGlueClient.On("CreatePartition", mock.Anything).Return(&glue.CreatePartitionOutput{}, nil).Once()
+
+	assert.NoError(t, SQS(getEvent(t, "logs/custom_updater/year=2020/month=02/day=26/hour=15/item.json.gz")))
+	// table is known to exist now, it should not be looked up again
+	mockGlueClient.On("CreatePartition", mock.Anything).Return(&glue.CreatePartitionOutput{}, nil).Once()
+	mockGlueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil).Once()
+	assert.NoError(t, SQS(getEvent(t, "logs/custom_updater/year=2020/month=02/day=26/hour=16/item.json.gz")))
+
+	assert.Equal(t, 1, viewsUpdated)
+	mockGlueClient.AssertExpectations(t)
+	mockLambdaClient.AssertExpectations(t)
+}
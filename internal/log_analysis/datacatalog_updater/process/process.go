@@ -59,8 +59,13 @@ func SQS(event events.SQSEvent) error {
 				continue
 			}
 
+			// tables of custom log types are not part of the deployment, they are created on their first data
+			if err = ensureCustomTable(gluePartition); err != nil {
+				return errors.Wrapf(err, "failed to create table for %#v", notification)
+			}
+
 			// attempt to create the partition
-			_, err = gluePartition.GetGlueTableMetadata().CreateJSONPartition(glueClient, gluePartition.GetTime())
+			_, err = gluePartition.GetGlueTableMetadata().CreatePartition(glueClient, gluePartition.GetTime())
 			if err != nil {
 				return errors.Wrapf(err, "failed to create partition %#v", notification)
 			}
@@ -21,8 +21,12 @@ package process
 import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/athena/athenaiface"
 	"github.com/aws/aws-sdk-go/service/glue"
 	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 )
 
 const (
@@ -30,11 +34,15 @@ const (
 )
 
 var (
-	awsSession *session.Session
-	glueClient glueiface.GlueAPI
+	awsSession   *session.Session
+	glueClient   glueiface.GlueAPI
+	athenaClient athenaiface.AthenaAPI
+	lambdaClient lambdaiface.LambdaAPI
 )
 
 func Setup() {
 	awsSession = session.Must(session.NewSession(aws.NewConfig().WithMaxRetries(maxRetries)))
 	glueClient = glue.New(awsSession)
+	athenaClient = athena.New(awsSession)
+	lambdaClient = lambda.New(awsSession)
 }
@@ -0,0 +1,152 @@
+package glueschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package glueschema infers the columns of the Glue tables of the log types from their event structs.
+// It is shared by the CloudFormation generation of the tables and by the Lambda functions that create tables at runtime.
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+// ExternalTableType is the type of all the tables, their data is stored in S3
+const ExternalTableType = "EXTERNAL_TABLE"
+
+// The storage formats of the tables, see https://docs.aws.amazon.com/athena/latest/ug/supported-serdes.html
+const (
+	JSONLInputFormat          = "org.apache.hadoop.mapred.TextInputFormat"
+	JSONLOutputFormat         = "org.apache.hadoop.hive.ql.io.HiveIgnoreKeyTextOutputFormat"
+	JSONLSerializationLibrary = "org.openx.data.jsonserde.JsonSerDe"
+
+	ParquetInputFormat          = "org.apache.hadoop.hive.ql.io.parquet.MapredParquetInputFormat"
+	ParquetOutputFormat         = "org.apache.hadoop.hive.ql.io.parquet.MapredParquetOutputFormat"
+	ParquetSerializationLibrary = "org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe"
+)
+
+type Column struct {
+	Name     string
+	Type     string // this is the Glue type
+	Comment  string `json:",omitempty"`
+	Required bool   `json:"-"` // do NOT serialize! Not used for Glue CF (used for doc).
+}
+
+// Glue tables typ for timestamps that we will re-map Go times
+const GlueTimestampType = "timestamp"
+
+var (
+	// GlueMappings for custom Panther types.
+	GlueMappings = []CustomMapping{
+		{
+			From: reflect.TypeOf(timestamp.RFC3339{}),
+			To:   GlueTimestampType,
+		},
+		{
+			From: reflect.TypeOf(timestamp.ANSICwithTZ{}),
+			To:   GlueTimestampType,
+		},
+		{
+			From: reflect.TypeOf(timestamp.UnixMillisecond{}),
+			To:   GlueTimestampType,
+		},
+		{
+			From: reflect.TypeOf(timestamp.FluentdTimestamp{}),
+			To:   GlueTimestampType,
+		},
+		{
+			From: reflect.TypeOf(timestamp.UnixFloat{}),
+			To:   GlueTimestampType,
+		},
+		{
+			From: reflect.TypeOf(timestamp.SuricataTimestamp{}),
+			To:   GlueTimestampType,
+		},
+		{
+			From: reflect.TypeOf(parsers.PantherAnyString{}),
+			To:   "array<string>",
+		},
+		{
+			From: reflect.TypeOf(jsoniter.RawMessage{}),
+			To:   "string",
+		},
+		{
+			From: reflect.TypeOf(*new(numerics.Integer)),
+			To:   "bigint",
+		},
+		{
+			From: reflect.TypeOf(*new(numerics.Int64)),
+			To:   "bigint",
+		},
+	}
+
+	// RuleMatchColumns are columns added by the rules engine
+	RuleMatchColumns = []Column{
+		{
+			Name:    "p_rule_id",
+			Type:    "string",
+			Comment: "Rule id",
+		},
+		{
+			Name:    "p_alert_id",
+			Type:    "string",
+			Comment: "Alert id",
+		},
+		{
+			Name:    "p_alert_creation_time",
+			Type:    "timestamp",
+			Comment: "The time the alert was initially created (first match)",
+		},
+		{
+			Name:    "p_alert_update_time",
+			Type:    "timestamp",
+			Comment: "The time the alert last updated (last match)",
+		},
+		{
+			Name:    "p_rule_tags",
+			Type:    "array<string>",
+			Comment: "The tags of the rule that generated this alert",
+		},
+		{
+			Name:    "p_rule_reports",
+			Type:    "map<string,array<string>>",
+			Comment: "The tags of the rule that generated this alert",
+		},
+	}
+)
+
+// JSONLSerdeParameters returns the parameters of the JSON SerDe of a table with the columns
+func JSONLSerdeParameters(columns []Column) map[string]string {
+	parameters := map[string]string{
+		"serialization.format": "1",
+		"case.insensitive":     "false", // Need to be case sensitive to deal with columns that have same name but different casing
+	}
+
+	// Adding mapping for column names. This is required when columns are case sensitive
+	for _, column := range columns {
+		parameters[fmt.Sprintf("mapping.%s", strings.ToLower(column.Name))] = column.Name
+	}
+	return parameters
+}
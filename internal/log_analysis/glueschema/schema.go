@@ -1,4 +1,4 @@
-package gluecf
+package glueschema
 
 /**
  * Panther is a Cloud-Native SIEM for the Modern Security Team.
@@ -40,7 +40,11 @@ type CustomMapping struct {
 	To   string       // glue type to emit
 }
 
-// Walk object, create columns using JSON Serde expected types, allow optional custom mappings
+// The columns of this embedded struct are placed at the end of the table, after those of the structs extending PantherLog
+var pantherLogExtensionType = reflect.TypeOf(parsers.PantherLogExtension{})
+
+// Walk object, create columns using JSON Serde expected types, allow optional custom mappings.
+// The columns of parsers.PantherLogExtension are the last columns.
 func InferJSONColumns(obj interface{}, customMappings ...CustomMapping) (cols []Column) {
 	customMappingsTable := make(map[string]string)
 	for _, customMapping := range customMappings {
@@ -55,14 +59,22 @@ func InferJSONColumns(obj interface{}, customMappings ...CustomMapping) (cols []
 		objType = objType.Elem()
 	}
 
-	return inferJSONColumns(objType, customMappingsTable)
+	cols, lastCols := inferJSONColumns(objType, customMappingsTable)
+	return append(cols, lastCols...)
 }
 
-func inferJSONColumns(t reflect.Type, customMappingsTable map[string]string) (cols []Column) {
+// inferJSONColumns returns the columns of the struct and separately those of parsers.PantherLogExtension
+func inferJSONColumns(t reflect.Type, customMappingsTable map[string]string) (cols, lastCols []Column) {
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 		if field.Anonymous { // if composing a struct, treat fields as part of this struct
-			cols = append(cols, inferJSONColumns(field.Type, customMappingsTable)...)
+			embeddedCols, embeddedLastCols := inferJSONColumns(field.Type, customMappingsTable)
+			if field.Type == pantherLogExtensionType {
+				lastCols = append(lastCols, embeddedCols...)
+			} else {
+				cols = append(cols, embeddedCols...)
+			}
+			lastCols = append(lastCols, embeddedLastCols...)
 		} else {
 			fieldName, glueType, comment, required, skip := inferStructFieldType(field, customMappingsTable)
 			if skip {
@@ -84,7 +96,7 @@ func inferJSONColumns(t reflect.Type, customMappingsTable map[string]string) (co
 			})
 		}
 	}
-	return cols
+	return cols, lastCols
 }
 
 func inferStructFieldType(sf reflect.StructField, customMappingsTable map[string]string) (fieldName, glueType, comment string,
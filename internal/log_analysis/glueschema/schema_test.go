@@ -1,4 +1,4 @@
-package gluecf
+package glueschema
 
 /**
  * Panther is a Cloud-Native SIEM for the Modern Security Team.
@@ -26,6 +26,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 )
 
 type TestCustomSimpleType int
@@ -274,3 +276,27 @@ func TestComposeStructs(t *testing.T) {
 	}
 	require.Equal(t, expectedColumns, cols)
 }
+
+// extends PantherLog with its own fields, like the AWS log types
+type extendedPantherLog struct {
+	parsers.PantherLog
+	PantherAnyFoo *parsers.PantherAnyString `json:"p_any_foo,omitempty" description:"test field"`
+}
+
+type extendedEvent struct {
+	Foo string `description:"test field"`
+	extendedPantherLog
+}
+
+func TestPantherLogExtensionColumnsLast(t *testing.T) {
+	cols := InferJSONColumns(&extendedEvent{})
+	var names []string
+	for _, col := range cols {
+		names = append(names, col.Name)
+	}
+	extensionCols := InferJSONColumns(&parsers.PantherLogExtension{})
+	require.NotEmpty(t, extensionCols)
+	// the columns of existing tables keep their position, the extension is added at the end
+	require.Equal(t, "p_any_foo", names[len(names)-len(extensionCols)-1])
+	require.Equal(t, extensionCols, cols[len(cols)-len(extensionCols):])
+}
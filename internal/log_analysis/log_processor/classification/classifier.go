@@ -49,12 +49,22 @@ type ClassifierResult struct {
 	Events []*parsers.PantherLog
 	// LogType is the identified type of the log
 	LogType *string
+	// ParsersTried are the log types of the parsers that failed to parse the log
+	ParsersTried []string
+	// ParserPanics are the log types of the parsers that panicked while parsing the log
+	ParserPanics []string
 }
 
-// NewClassifier returns a new instance of a ClassifierAPI implementation
+// NewClassifier returns a new instance of a ClassifierAPI implementation that tries all registered parsers
 func NewClassifier() ClassifierAPI {
+	return NewClassifierForLogTypes(nil)
+}
+
+// NewClassifierForLogTypes returns a new instance of a ClassifierAPI implementation that only tries the parsers
+// of the given log types. If no log types are given, all registered parsers are tried.
+func NewClassifierForLogTypes(logTypes []string) ClassifierAPI {
 	parserQueue := &ParserPriorityQueue{}
-	parserQueue.initialize()
+	parserQueue.initialize(logTypes)
 	return &Classifier{
 		parsers:     parserQueue,
 		parserStats: make(map[string]*ParserStats),
@@ -79,7 +89,7 @@ func (c *Classifier) ParserStats() map[string]*ParserStats {
 }
 
 // catch panics from parsers, log and continue
-func safeLogParse(parser parsers.LogParser, log string) (parsedEvents []*parsers.PantherLog) {
+func safeLogParse(parser parsers.LogParser, log string) (parsedEvents []*parsers.PantherLog, panicked bool) {
 	defer func() {
 		if r := recover(); r != nil {
 			zap.L().Debug("parser panic",
@@ -87,6 +97,7 @@ func safeLogParse(parser parsers.LogParser, log string) (parsedEvents []*parsers
 				zap.Error(errors.Errorf("%v", r)),
 				zap.String("stacktrace", string(debug.Stack())))
 			parsedEvents = nil // return indicator that parse failed
+			panicked = true
 		}
 	}()
 	parsedEvents, err := parser.Parse(log)
@@ -94,9 +105,9 @@ func safeLogParse(parser parsers.LogParser, log string) (parsedEvents []*parsers
 		zap.L().Debug("parser failed",
 			zap.String("parser", parser.LogType()),
 			zap.Error(err))
-		return nil
+		return nil, false
 	}
-	return parsedEvents
+	return parsedEvents, false
 }
 
 // Classify attempts to classify the provided log line
@@ -104,6 +115,8 @@ func (c *Classifier) Classify(log string) *ClassifierResult {
 	startClassify := time.Now().UTC()
 	// Slice containing the popped queue items
 	var popped []interface{}
+	// log types of the parsers that failed, reported only if the log is not classified
+	var parsersTried []string
 	result := &ClassifierResult{}
 
 	if len(log) == 0 { // likely empty file, nothing to do
@@ -135,14 +148,18 @@ func (c *Classifier) Classify(log string) *ClassifierResult {
 		currentItem := c.parsers.Peek()
 
 		startParseTime := time.Now().UTC()
-		parsedEvents := safeLogParse(currentItem.parser, log)
+		parsedEvents, panicked := safeLogParse(currentItem.parser, log)
 		endParseTime := time.Now().UTC()
 
 		logType := currentItem.parser.LogType()
+		if panicked {
+			result.ParserPanics = append(result.ParserPanics, logType)
+		}
 
 		// Parser failed to parse event
 		if parsedEvents == nil {
 			zap.L().Debug("failed to parse event", zap.String("expectedLogType", currentItem.parser.LogType()))
+			parsersTried = append(parsersTried, logType)
 			// Removing parser from queue
 			popped = append(popped, heap.Pop(c.parsers))
 			// Increasing penalty of the parser
@@ -172,10 +189,19 @@ func (c *Classifier) Classify(log string) *ClassifierResult {
 		parserStat.BytesProcessedCount += uint64(len(log))
 		parserStat.LogLineCount++
 		parserStat.EventCount += uint64(len(result.Events))
+		for _, event := range result.Events {
+			if event.PantherEventTime != nil && (time.Time)(*event.PantherEventTime).After(parserStat.LastEventTime) {
+				parserStat.LastEventTime = (time.Time)(*event.PantherEventTime)
+			}
+		}
 
 		break
 	}
 
+	if result.LogType == nil {
+		result.ParsersTried = parsersTried
+	}
+
 	// Put back the popped items to the ParserPriorityQueue.
 	for _, item := range popped {
 		heap.Push(c.parsers, item)
@@ -191,6 +217,7 @@ type ClassifierStats struct {
 	EventCount                  uint64 // output records
 	SuccessfullyClassifiedCount uint64
 	ClassificationFailureCount  uint64
+	RedactedValueCount          uint64 // values removed or obfuscated by the redaction rules of the log types
 }
 
 // per parser stats
@@ -200,4 +227,6 @@ type ParserStats struct {
 	LogLineCount           uint64 // input records
 	EventCount             uint64 // output records
 	LogType                string
+	LastEventTime          time.Time // latest event time of the output records
+	RedactedValueCount     uint64    // values removed or obfuscated by the redaction rules of the log type
 }
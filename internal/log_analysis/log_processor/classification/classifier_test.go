@@ -143,7 +143,7 @@ func TestClassifyRespectsPriorityOfParsers(t *testing.T) {
 func TestClassifyNoMatch(t *testing.T) {
 	failingParser := &mockParser{}
 
-	failingParser.On("Parse", mock.Anything).Return(nil)
+	failingParser.On("Parse", mock.Anything).Return(nil, errors.New("fail"))
 	failingParser.On("LogType").Return("failure")
 
 	availableParsers := []*registry.LogParserMetadata{
@@ -173,7 +173,7 @@ func TestClassifyNoMatch(t *testing.T) {
 	expectedStats.ClassifyTimeMicroseconds = classifier.Stats().ClassifyTimeMicroseconds
 	require.Equal(t, expectedStats, classifier.Stats())
 
-	require.Equal(t, &ClassifierResult{}, result)
+	require.Equal(t, &ClassifierResult{ParsersTried: []string{failingParser.LogType()}}, result)
 	failingParser.AssertNumberOfCalls(t, "Parse", 1)
 	require.Nil(t, classifier.ParserStats()[failingParser.LogType()])
 }
@@ -219,10 +219,42 @@ func TestClassifyParserPanic(t *testing.T) {
 	expectedStats.ClassifyTimeMicroseconds = classifier.Stats().ClassifyTimeMicroseconds
 	require.Equal(t, expectedStats, classifier.Stats())
 
-	require.Equal(t, &ClassifierResult{}, result)
+	require.Equal(t, &ClassifierResult{
+		ParsersTried: []string{"panic parser"},
+		ParserPanics: []string{"panic parser"},
+	}, result)
 	panicParser.AssertNumberOfCalls(t, "Parse", 1)
 }
 
+func TestClassifyOnlyConfiguredLogTypes(t *testing.T) {
+	configuredParser := &mockParser{}
+	otherParser := &mockParser{}
+
+	configuredParser.On("Parse", mock.Anything).Return(nil, errors.New("fail"))
+	configuredParser.On("LogType").Return("configured")
+	otherParser.On("Parse", mock.Anything).Return([]*parsers.PantherLog{{}}, nil)
+	otherParser.On("LogType").Return("other")
+
+	availableParsers := []*registry.LogParserMetadata{
+		{Parser: configuredParser},
+		{Parser: otherParser},
+	}
+	testRegistry := NewTestRegistry()
+	parserRegistry = testRegistry // re-bind as interface
+	for i := range availableParsers {
+		testRegistry.Add(availableParsers[i]) // update registry
+	}
+
+	// unknown log types are skipped, duplicates are added once
+	classifier := NewClassifierForLogTypes([]string{"configured", "doesnotexist", "configured"})
+
+	result := classifier.Classify("log")
+
+	require.Equal(t, &ClassifierResult{ParsersTried: []string{"configured"}}, result)
+	configuredParser.AssertNumberOfCalls(t, "Parse", 1)
+	otherParser.AssertNotCalled(t, "Parse", mock.Anything)
+}
+
 func TestClassifyNoLogline(t *testing.T) {
 	testSkipClassify("", t)
 }
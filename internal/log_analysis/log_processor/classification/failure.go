@@ -0,0 +1,67 @@
+package classification
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+// FailureLogType is the log type of the records of log lines that could not be classified
+const FailureLogType = "Panther.ClassificationFailure"
+
+var FailureDesc = `ClassificationFailure is a log line that could not be classified or made a parser panic.
+The lines are stored so they can be inspected and replayed once a parser is fixed.`
+
+// FailureTable is the Glue table of the classification failures, kept apart from the log tables
+var FailureTable = awsglue.NewGlueTableMetadata(
+	models.ClassificationFailureData, FailureLogType, FailureDesc, awsglue.GlueTableHourly, &Failure{})
+
+// Failure is a log line that could not be classified or made a parser panic
+// nolint:lll
+type Failure struct {
+	Line         *string  `json:"line" validate:"required" description:"The log line that failed classification."`
+	SourceID     *string  `json:"sourceId,omitempty" description:"The id of the source integration the log line was received from."`
+	SourceLabel  *string  `json:"sourceLabel,omitempty" description:"The label of the source integration the log line was received from."`
+	S3Bucket     *string  `json:"s3Bucket,omitempty" description:"The S3 bucket of the object containing the log line."`
+	S3Key        *string  `json:"s3Key,omitempty" description:"The S3 key of the object containing the log line."`
+	LineNum      *uint64  `json:"lineNum" validate:"required" description:"The number of the log line in the object, starting from 1."`
+	LogType      *string  `json:"logType,omitempty" description:"The log type the line was classified as, if a parser panicked but another one succeeded."`
+	ParsersTried []string `json:"parsersTried,omitempty" description:"The log types of the parsers that failed to parse the log line."`
+	ParserPanics []string `json:"parserPanics,omitempty" description:"The log types of the parsers that panicked while parsing the log line."`
+
+	// NOTE: added to end of struct to allow expansion later
+	parsers.PantherLog
+}
+
+// NewFailure returns the record of a log line that failed classification
+func NewFailure(line string, lineNum uint64, result *ClassifierResult) *Failure {
+	failure := &Failure{
+		Line:         &line,
+		LineNum:      &lineNum,
+		LogType:      result.LogType,
+		ParsersTried: result.ParsersTried,
+		ParserPanics: result.ParserPanics,
+	}
+	now := timestamp.Now()
+	failure.SetCoreFields(FailureLogType, &now, failure)
+	return failure
+}
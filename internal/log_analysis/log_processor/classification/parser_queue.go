@@ -19,6 +19,8 @@ package classification
  */
 
 import (
+	"go.uber.org/zap"
+
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
 )
@@ -30,17 +32,38 @@ type ParserPriorityQueue struct {
 	items []*ParserQueueItem
 }
 
-// initialize adds all registered parsers to the priority queue
+// initialize adds the parsers of the given log types to the priority queue,
+// if no log types are given all registered parsers are added
 // All parsers have the same priority
-func (q *ParserPriorityQueue) initialize() {
-	for _, parserMetadata := range parserRegistry.Elements() {
-		q.items = append(q.items, &ParserQueueItem{
-			parser:  parserMetadata.Parser.New(),
-			penalty: 1,
-		})
+func (q *ParserPriorityQueue) initialize(logTypes []string) {
+	if len(logTypes) == 0 {
+		for _, parserMetadata := range parserRegistry.Elements() {
+			q.add(parserMetadata)
+		}
+		return
+	}
+	added := make(map[string]struct{}, len(logTypes))
+	for _, logType := range logTypes {
+		if _, duplicate := added[logType]; duplicate {
+			continue
+		}
+		parserMetadata, found := parserRegistry.Elements()[logType]
+		if !found { // the source may reference a log type that is no longer supported, skip it
+			zap.L().Warn("cannot find parser for log type", zap.String("logType", logType))
+			continue
+		}
+		q.add(parserMetadata)
+		added[logType] = struct{}{}
 	}
 }
 
+func (q *ParserPriorityQueue) add(parserMetadata *registry.LogParserMetadata) {
+	q.items = append(q.items, &ParserQueueItem{
+		parser:  parserMetadata.Parser.New(),
+		penalty: 1,
+	})
+}
+
 // ParserQueueItem contains all the information needed to initialize a schema.
 type ParserQueueItem struct {
 	parser parsers.LogParser
@@ -23,6 +23,10 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/firehose/firehoseiface"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
 	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -32,6 +36,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/kelseyhightower/envconfig"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
 )
 
 const (
@@ -41,11 +47,13 @@ const (
 
 var (
 	// Session and clients that can be used by components of the log processor
-	Session      *session.Session
-	LambdaClient lambdaiface.LambdaAPI
-	S3Uploader   s3manageriface.UploaderAPI
-	SqsClient    sqsiface.SQSAPI
-	SnsClient    snsiface.SNSAPI
+	Session        *session.Session
+	LambdaClient   lambdaiface.LambdaAPI
+	S3Uploader     s3manageriface.UploaderAPI
+	SqsClient      sqsiface.SQSAPI
+	SnsClient      snsiface.SNSAPI
+	KinesisClient  kinesisiface.KinesisAPI
+	FirehoseClient firehoseiface.FirehoseAPI
 
 	Config EnvConfig
 )
@@ -55,6 +63,18 @@ type EnvConfig struct {
 	ProcessedDataBucket         string `required:"true" split_words:"true"`
 	SqsQueueURL                 string `required:"true" split_words:"true"`
 	SnsTopicARN                 string `required:"true" split_words:"true"`
+	// The log types stored as Parquet, the others are stored as JSON
+	ParquetLogTypes []string `split_words:"true"`
+	// The destinations the processed events are sent to (s3, kinesis, firehose or local), defaults to s3
+	Destinations []string
+	// The names of the streams and the directory used by the corresponding destinations
+	KinesisStreamName   string `split_words:"true"`
+	FirehoseStreamName  string `split_words:"true"`
+	LocalDestinationDir string `split_words:"true"`
+	// The MaxMind databases used to enrich the events with the geolocation of their ip addresses,
+	// they are keys in the bucket or local paths if the bucket is empty
+	GeoIPDatabaseBucket string   `split_words:"true"`
+	GeoIPDatabases      []string `split_words:"true"`
 }
 
 func Setup() {
@@ -63,6 +83,8 @@ func Setup() {
 	S3Uploader = s3manager.NewUploader(Session)
 	SqsClient = sqs.New(Session)
 	SnsClient = sns.New(Session)
+	KinesisClient = kinesis.New(Session)
+	FirehoseClient = firehose.New(Session)
 
 	err := envconfig.Process("", &Config)
 	if err != nil {
@@ -77,11 +99,35 @@ type DataStream struct {
 	// The log type if known
 	// If it is nil, it means the log type hasn't been identified yet
 	LogType *string
+	// The log types configured on the source of the data
+	// If it is empty, the data can be of any registered log type
+	LogTypes []string
+	// The id and label of the source integration of the data, empty if unknown
+	SourceID    string
+	SourceLabel string
+	// The S3 key prefix the events are written under when the data is replayed
+	// If it is empty, the events are written to the log tables
+	DestinationPrefix string
+	// How the lines of the data are joined into events, configured on the source of the data
+	// If it is nil, the configuration of the log type applies
+	Multiline *sourcemodels.MultilineConfig
+	// Closes the data (e.g., the S3 object body or the temporary file of an archive) once it is processed or abandoned
+	// If it is nil, there is nothing to close
+	Closer io.Closer
+}
+
+// Close closes the data of the stream, it must be called whether the stream was processed or not
+func (dataStream *DataStream) Close() error {
+	if dataStream.Closer == nil {
+		return nil
+	}
+	return dataStream.Closer.Close()
 }
 
 // Used in a DataStream as meta data to describe the data
 type DataStreamHints struct {
-	S3 *S3DataStreamHints // if nil, no hint
+	S3      *S3DataStreamHints      // if nil, no hint
+	Archive *ArchiveDataStreamHints // if nil, the data is not a file inside an archive
 }
 
 // Used in a DataStreamHints as meta data to describe the S3 object backing the stream
@@ -90,3 +136,8 @@ type S3DataStreamHints struct {
 	Key         string
 	ContentType string
 }
+
+// Used in a DataStreamHints as meta data to describe the file of an archive (e.g., zip) backing the stream
+type ArchiveDataStreamHints struct {
+	Path string // the path of the file inside the archive
+}
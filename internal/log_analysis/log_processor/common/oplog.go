@@ -41,6 +41,8 @@ var (
 	OpLogSNSServiceDim       = zap.String(OpLogServiceDim, "sns")
 	OpLogProcessorServiceDim = zap.String(OpLogServiceDim, "processor")
 	OpLogGlueServiceDim      = zap.String(OpLogServiceDim, "glue")
+	OpLogKinesisServiceDim   = zap.String(OpLogServiceDim, "kinesis")
+	OpLogFirehoseServiceDim  = zap.String(OpLogServiceDim, "firehose")
 
 	/*
 			  Example CloudWatch Insight queries this structure enables:
@@ -19,10 +19,62 @@ package destinations
  */
 
 import (
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 )
 
+// The names of the destinations in the configuration
+const (
+	S3DestinationName       = "s3"
+	KinesisDestinationName  = "kinesis"
+	FirehoseDestinationName = "firehose"
+	LocalDestinationName    = "local"
+)
+
 // Destination defines the interface that all Destinations should follow
 type Destination interface {
 	SendEvents(parsedEventChannel chan *parsers.PantherLog, errChan chan error)
 }
+
+// CreateDestination returns the destination configured in the environment.
+// If several destinations are configured the events are sent to all of them, if none is configured they are sent to S3.
+// NOTE: the rules engine analyzes the events written to S3, other destinations only forward them.
+// Classification failures are stored only by the S3 and local destinations, streams skip them.
+func CreateDestination() (Destination, error) {
+	names := common.Config.Destinations
+	if len(names) == 0 {
+		names = []string{S3DestinationName}
+	}
+
+	var destinations []Destination
+	for _, name := range names {
+		switch name {
+		case S3DestinationName:
+			destinations = append(destinations, CreateS3Destination())
+		case KinesisDestinationName:
+			if common.Config.KinesisStreamName == "" {
+				return nil, errors.New("the kinesis destination needs a stream name")
+			}
+			destinations = append(destinations, NewKinesisDestination(common.KinesisClient, common.Config.KinesisStreamName))
+		case FirehoseDestinationName:
+			if common.Config.FirehoseStreamName == "" {
+				return nil, errors.New("the firehose destination needs a delivery stream name")
+			}
+			destinations = append(destinations, NewFirehoseDestination(common.FirehoseClient, common.Config.FirehoseStreamName))
+		case LocalDestinationName:
+			if common.Config.LocalDestinationDir == "" {
+				return nil, errors.New("the local destination needs a directory")
+			}
+			destinations = append(destinations, NewLocalDestination(common.Config.LocalDestinationDir))
+		default:
+			return nil, errors.Errorf("unknown destination %q", name)
+		}
+	}
+
+	if len(destinations) == 1 {
+		return destinations[0], nil
+	}
+	return NewFanOutDestination(destinations...), nil
+}
@@ -0,0 +1,60 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+)
+
+func TestCreateDestination(t *testing.T) {
+	initTest()
+	defer func() {
+		common.Config.Destinations = nil
+		common.Config.FirehoseStreamName = ""
+		common.Config.LocalDestinationDir = ""
+	}()
+
+	destination, err := CreateDestination()
+	require.NoError(t, err)
+	assert.IsType(t, &S3Destination{}, destination)
+
+	common.Config.Destinations = []string{S3DestinationName, FirehoseDestinationName}
+	common.Config.FirehoseStreamName = "testDeliveryStream"
+	destination, err = CreateDestination()
+	require.NoError(t, err)
+	require.IsType(t, &FanOutDestination{}, destination)
+	assert.Len(t, destination.(*FanOutDestination).destinations, 2)
+
+	common.Config.Destinations = []string{LocalDestinationName}
+	_, err = CreateDestination()
+	assert.Error(t, err)
+	common.Config.LocalDestinationDir = "/tmp/panther"
+	destination, err = CreateDestination()
+	require.NoError(t, err)
+	assert.IsType(t, &LocalDestination{}, destination)
+
+	common.Config.Destinations = []string{"unknown"}
+	_, err = CreateDestination()
+	assert.Error(t, err)
+}
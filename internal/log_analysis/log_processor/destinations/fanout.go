@@ -0,0 +1,64 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"sync"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// FanOutDestination sends normalized events to several destinations at once
+type FanOutDestination struct {
+	destinations []Destination
+}
+
+// NewFanOutDestination returns a destination sending every event to all the destinations
+func NewFanOutDestination(destinations ...Destination) *FanOutDestination {
+	return &FanOutDestination{
+		destinations: destinations,
+	}
+}
+
+// SendEvents sends each event read from parsedEventChannel to all the destinations.
+// The destinations run concurrently, the slowest one sets the pace. Errors of any destination are written to errChan.
+// NOTE: the events are shared by the destinations so they must not modify them.
+func (destination *FanOutDestination) SendEvents(parsedEventChannel chan *parsers.PantherLog, errChan chan error) {
+	var wg sync.WaitGroup
+	eventChannels := make([]chan *parsers.PantherLog, len(destination.destinations))
+	for i, dest := range destination.destinations {
+		eventChannels[i] = make(chan *parsers.PantherLog, cap(parsedEventChannel))
+		wg.Add(1)
+		go func(dest Destination, eventChannel chan *parsers.PantherLog) {
+			defer wg.Done()
+			dest.SendEvents(eventChannel, errChan)
+		}(dest, eventChannels[i])
+	}
+
+	for event := range parsedEventChannel {
+		for _, eventChannel := range eventChannels {
+			eventChannel <- event
+		}
+	}
+
+	for _, eventChannel := range eventChannels {
+		close(eventChannel)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,67 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// recordingDestination keeps the events it receives and optionally fails
+type recordingDestination struct {
+	events []*parsers.PantherLog
+	err    error
+}
+
+func (d *recordingDestination) SendEvents(parsedEventChannel chan *parsers.PantherLog, errChan chan error) {
+	for event := range parsedEventChannel {
+		d.events = append(d.events, event)
+	}
+	if d.err != nil {
+		errChan <- d.err
+	}
+}
+
+func TestFanOutDestination(t *testing.T) {
+	first, second := &recordingDestination{}, &recordingDestination{}
+	destination := NewFanOutDestination(first, second)
+
+	event1, event2 := newSimpleTestEvent(), newSimpleTestEvent()
+	require.Empty(t, sendEvents(destination, event1, event2))
+
+	assert.Equal(t, []*parsers.PantherLog{event1, event2}, first.events)
+	assert.Equal(t, []*parsers.PantherLog{event1, event2}, second.events)
+}
+
+func TestFanOutDestinationFails(t *testing.T) {
+	first, second := &recordingDestination{}, &recordingDestination{err: errors.New("fail")}
+	destination := NewFanOutDestination(first, second)
+
+	event := newSimpleTestEvent()
+	errs := sendEvents(destination, event)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "fail", errs[0].Error())
+	// the other destinations are not affected
+	assert.Equal(t, []*parsers.PantherLog{event}, first.events)
+}
@@ -0,0 +1,125 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// LocalDestination writes normalized events to files in a local directory, it is meant for development.
+// The files are laid out like the objects written by the S3 destination but they are not compressed.
+type LocalDestination struct {
+	dir string
+}
+
+// NewLocalDestination returns a destination writing events under dir
+func NewLocalDestination(dir string) *LocalDestination {
+	return &LocalDestination{
+		dir: dir,
+	}
+}
+
+// localEventFile is a file holding the events of a log type for an hour
+type localEventFile struct {
+	file   *os.File
+	writer *bufio.Writer
+}
+
+func (f *localEventFile) close() error {
+	if err := f.writer.Flush(); err != nil {
+		f.file.Close() // nolint:errcheck
+		return err
+	}
+	return f.file.Close()
+}
+
+// SendEvents writes events to files, one per log type and hour.
+// If the method encounters an error it writes an error to the errorChannel and continues until channel is closed
+// (skipping events).
+func (destination *LocalDestination) SendEvents(parsedEventChannel chan *parsers.PantherLog, errChan chan error) {
+	files := make(map[string]*localEventFile) // keyed by partition prefix
+	failed := false                           // set to true on error and loop will drain channel
+	for event := range parsedEventChannel {
+		if failed { // drain channel
+			continue
+		}
+
+		// Use renaming field JSON serializer
+		data, err := parsers.JSON.Marshal(event.Event())
+		if err != nil {
+			failed = true
+			errChan <- errors.Wrap(err, "failed to marshal log parser event for local file")
+			continue
+		}
+
+		file, err := destination.getFile(files, event)
+		if err != nil {
+			failed = true
+			errChan <- err
+			continue
+		}
+		if _, err = file.writer.Write(append(data, common.EventDelimiter)); err != nil {
+			failed = true
+			errChan <- errors.Wrapf(err, "failed to write to %s", file.file.Name())
+			continue
+		}
+	}
+
+	for _, file := range files {
+		if err := file.close(); err != nil && !failed {
+			failed = true
+			errChan <- errors.Wrapf(err, "failed to close %s", file.file.Name())
+		}
+	}
+}
+
+func (destination *LocalDestination) getFile(files map[string]*localEventFile, event *parsers.PantherLog) (*localEventFile, error) {
+	// bin by hour (this is our partition size)
+	hour := (time.Time)(*event.PantherEventTime).Truncate(time.Hour)
+	logType := *event.PantherLogType
+	partitionPrefix := getGlueTableMetadata(logType).GetPartitionPrefix(hour.UTC())
+	if file, found := files[partitionPrefix]; found {
+		return file, nil
+	}
+
+	key := strings.TrimSuffix(getS3ObjectKey(logType, hour), ".gz") // not compressed
+	path := filepath.Join(destination.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory for %s", path)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s", path)
+	}
+	file := &localEventFile{
+		file:   f,
+		writer: bufio.NewWriter(f),
+	}
+	files[partitionPrefix] = file
+	return file, nil
+}
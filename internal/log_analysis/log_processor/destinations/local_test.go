@@ -0,0 +1,73 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendEventsToLocalDir(t *testing.T) {
+	initTest()
+
+	dir, err := ioutil.TempDir("", "local_destination")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	registerMockParser(testLogType, newSimpleTestEvent())
+	destination := NewLocalDestination(dir)
+
+	require.Empty(t, sendEvents(destination,
+		newSimpleTestEvent(), newSimpleTestEvent(), newTestEvent(testLogType, refTimePlusHour)))
+
+	firstHour, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(expectedS3Prefix)) + "*.json")
+	require.NoError(t, err)
+	require.Len(t, firstHour, 1)
+	data, err := ioutil.ReadFile(firstHour[0])
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"p_log_type":"testLogType"`)
+
+	secondHour, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(expectedS3Prefix2)) + "*.json")
+	require.NoError(t, err)
+	require.Len(t, secondHour, 1)
+}
+
+func TestSendEventsToLocalDirFails(t *testing.T) {
+	initTest()
+
+	file, err := ioutil.TempFile("", "local_destination")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	defer os.Remove(file.Name())
+
+	registerMockParser(testLogType, newSimpleTestEvent())
+	destination := NewLocalDestination(file.Name()) // not a directory
+
+	errs := sendEvents(destination, newSimpleTestEvent(), newSimpleTestEvent())
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "failed to create directory")
+}
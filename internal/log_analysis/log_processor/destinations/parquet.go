@@ -0,0 +1,499 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/glueschema"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+)
+
+const (
+	// The layout of timestamps in the JSON events (see the timestamp package)
+	parquetTimestampLayout = "2006-01-02 15:04:05.000000000"
+
+	// Parquet timestamps are stored as INT96, the nanoseconds of the day followed by the Julian day
+	julianDayOfUnixEpoch = 2440588
+	secondsPerDay        = 24 * 60 * 60
+
+	// smaller than the parquet-go default (128MB) to bound the memory used while converting a buffer
+	parquetRowGroupSize = 32 * bytesPerMB
+	// the rows of a row group are held in memory as Go values until they are written, they take more space than encoded
+	parquetRowGroupExpansionFactor = 3
+	// memory to reserve for converting a buffer, the Parquet file itself is written to disk
+	parquetConversionMemMB = parquetRowGroupSize * parquetRowGroupExpansionFactor / bytesPerMB
+)
+
+var (
+	// the schemas of the log types stored as Parquet, set by EnableParquet()
+	parquetSchemas = make(map[string]*parquetSchema)
+
+	// Glue (Hive) primitive types to Parquet types as understood by parquet-go
+	glueToParquetTypes = map[string]string{
+		"string":    "UTF8",
+		"boolean":   "BOOLEAN",
+		"tinyint":   "INT_8",
+		"smallint":  "INT_16",
+		"int":       "INT32",
+		"bigint":    "INT64",
+		"float":     "FLOAT",
+		"double":    "DOUBLE",
+		"timestamp": "INT96",
+	}
+)
+
+// EnableParquet stores the data of the given log types as Parquet instead of JSON.
+// The Glue tables of the log types are marked as Parquet tables so that the generated tables use the Parquet serde.
+// NOTE: this is not safe for concurrent use, it should be called before processing starts.
+func EnableParquet(logTypes []string) error {
+	for _, logType := range logTypes {
+		lpm, found := registry.AvailableParsers()[logType]
+		if !found {
+			return errors.Errorf("cannot store unknown log type %s as Parquet", logType)
+		}
+		schema, err := newParquetSchema(lpm.GlueTableMetadata)
+		if err != nil {
+			return errors.Wrapf(err, "cannot store log type %s as Parquet", logType)
+		}
+		lpm.GlueTableMetadata.SetFormat(awsglue.ParquetStorageFormat)
+		parquetSchemas[logType] = schema
+	}
+	return nil
+}
+
+// parquetColumn describes how a JSON value is stored in a Parquet column
+type parquetColumn struct {
+	name string
+	// the parquet-go type of primitive columns, empty for nested columns
+	parquetType string
+	// the Glue type of primitive columns
+	glueType string
+	isList   bool
+	isMap    bool
+	// the fields of struct columns
+	fields map[string]*parquetColumn
+	// the ordered field names of struct columns (to generate the same schema every time)
+	fieldNames []string
+	// the element of list columns and the value of map columns
+	element *parquetColumn
+}
+
+// parquetSchema converts JSON events of a table to Parquet
+type parquetSchema struct {
+	root *parquetColumn
+	// the schema in the parquet-go JSON format
+	jsonSchema string
+}
+
+// parquetSchemaItem is an element of the parquet-go JSON schema
+type parquetSchemaItem struct {
+	Tag    string
+	Fields []*parquetSchemaItem `json:",omitempty"`
+}
+
+// newParquetSchema returns the schema of the Parquet files of the table, using the same columns as the Glue table
+func newParquetSchema(table *awsglue.GlueTableMetadata) (*parquetSchema, error) {
+	root := &parquetColumn{
+		name:   "parquet_go_root",
+		fields: make(map[string]*parquetColumn),
+	}
+	for _, column := range glueschema.InferJSONColumns(table.EventStruct(), glueschema.GlueMappings...) {
+		col, rest, err := parseGlueType(column.Type)
+		if err != nil {
+			return nil, errors.Wrapf(err, "column %s", column.Name)
+		}
+		if rest != "" {
+			return nil, errors.Errorf("column %s: unexpected %q in type %s", column.Name, rest, column.Type)
+		}
+		col.name = column.Name
+		if err := root.addField(col); err != nil {
+			return nil, err
+		}
+	}
+
+	jsonSchema, err := jsoniter.MarshalToString(root.schemaItem(true))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal Parquet schema")
+	}
+	return &parquetSchema{
+		root:       root,
+		jsonSchema: jsonSchema,
+	}, nil
+}
+
+// addField adds a field to a struct column.
+// Parquet columns are matched by Athena ignoring case so field names must be unique ignoring case.
+func (col *parquetColumn) addField(field *parquetColumn) error {
+	if field.name == "" || strings.ContainsAny(field.name, ",=") {
+		return errors.Errorf("invalid Parquet field name %q", field.name)
+	}
+	for _, name := range col.fieldNames {
+		if strings.EqualFold(name, field.name) {
+			return errors.Errorf("fields %s and %s of %s differ only by case", name, field.name, col.name)
+		}
+	}
+	col.fields[field.name] = field
+	col.fieldNames = append(col.fieldNames, field.name)
+	return nil
+}
+
+// parseGlueType parses the first Glue type in s (e.g., struct<a:string,b:array<int>>) and returns the rest of s
+func parseGlueType(s string) (col *parquetColumn, rest string, err error) {
+	switch {
+	case strings.HasPrefix(s, "array<"):
+		element, rest, err := parseGlueType(s[len("array<"):])
+		if err != nil {
+			return nil, "", err
+		}
+		element.name = "element"
+		rest, err = consume(rest, ">")
+		return &parquetColumn{isList: true, element: element}, rest, err
+	case strings.HasPrefix(s, "map<"):
+		key, rest, err := parseGlueType(s[len("map<"):])
+		if err != nil {
+			return nil, "", err
+		}
+		// JSON object keys are always strings
+		if key.glueType != "string" {
+			return nil, "", errors.Errorf("unsupported map key type in %s", s)
+		}
+		if rest, err = consume(rest, ","); err != nil {
+			return nil, "", err
+		}
+		value, rest, err := parseGlueType(rest)
+		if err != nil {
+			return nil, "", err
+		}
+		value.name = "value"
+		rest, err = consume(rest, ">")
+		return &parquetColumn{isMap: true, element: value}, rest, err
+	case strings.HasPrefix(s, "struct<"):
+		col = &parquetColumn{fields: make(map[string]*parquetColumn)}
+		rest = s[len("struct<"):]
+		for !strings.HasPrefix(rest, ">") {
+			if len(col.fieldNames) > 0 {
+				if rest, err = consume(rest, ","); err != nil {
+					return nil, "", err
+				}
+			}
+			pos := strings.Index(rest, ":")
+			if pos < 0 {
+				return nil, "", errors.Errorf("missing field type in %s", s)
+			}
+			name := rest[:pos]
+			field, fieldRest, err := parseGlueType(rest[pos+1:])
+			if err != nil {
+				return nil, "", err
+			}
+			field.name = name
+			if err := col.addField(field); err != nil {
+				return nil, "", err
+			}
+			rest = fieldRest
+		}
+		// Parquet does not allow empty groups
+		if len(col.fieldNames) == 0 {
+			return nil, "", errors.Errorf("empty struct in %s", s)
+		}
+		return col, rest[1:], nil
+	default:
+		pos := strings.IndexAny(s, ",<>")
+		if pos < 0 {
+			pos = len(s)
+		}
+		glueType := s[:pos]
+		parquetType, ok := glueToParquetTypes[glueType]
+		if !ok {
+			return nil, "", errors.Errorf("unsupported type %q", glueType)
+		}
+		return &parquetColumn{glueType: glueType, parquetType: parquetType}, s[pos:], nil
+	}
+}
+
+func consume(s, token string) (string, error) {
+	if !strings.HasPrefix(s, token) {
+		return "", errors.Errorf("expected %q at %q", token, s)
+	}
+	return s[len(token):], nil
+}
+
+// schemaItem returns the parquet-go schema of the column, all columns are optional since any JSON value can be null
+func (col *parquetColumn) schemaItem(isRoot bool) *parquetSchemaItem {
+	repetitionType := "OPTIONAL"
+	if isRoot {
+		repetitionType = "REQUIRED"
+	}
+	tag := "name=" + col.name
+	switch {
+	case col.isList:
+		return &parquetSchemaItem{
+			Tag:    tag + ", type=LIST, repetitiontype=" + repetitionType,
+			Fields: []*parquetSchemaItem{col.element.schemaItem(false)},
+		}
+	case col.isMap:
+		return &parquetSchemaItem{
+			Tag: tag + ", type=MAP, repetitiontype=" + repetitionType,
+			Fields: []*parquetSchemaItem{
+				{Tag: "name=key, type=UTF8, repetitiontype=REQUIRED"},
+				col.element.schemaItem(false),
+			},
+		}
+	case col.fields != nil:
+		item := &parquetSchemaItem{
+			Tag: tag + ", repetitiontype=" + repetitionType,
+		}
+		for _, name := range col.fieldNames {
+			item.Fields = append(item.Fields, col.fields[name].schemaItem(false))
+		}
+		return item
+	default:
+		return &parquetSchemaItem{
+			Tag: tag + ", type=" + col.parquetType + ", repetitiontype=" + repetitionType,
+		}
+	}
+}
+
+// convert reads the gzip compressed JSON lines of payload and writes them as a Parquet file in the temp directory.
+// The file is written to disk so that only the current row group is held in memory, it is returned positioned at
+// its start and the caller must remove it with removeParquetFile().
+func (schema *parquetSchema) convert(payload []byte) (*parquetFile, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read gzip payload")
+	}
+
+	file, err := newParquetFile()
+	if err != nil {
+		return nil, err
+	}
+	if err = schema.write(bufio.NewReader(gzipReader), file); err != nil {
+		removeParquetFile(file)
+		return nil, err
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		removeParquetFile(file)
+		return nil, errors.Wrap(err, "failed to rewind Parquet file")
+	}
+	return file, nil
+}
+
+// write converts the JSON lines of reader to Parquet rows
+func (schema *parquetSchema) write(reader *bufio.Reader, file *parquetFile) error {
+	parquetWriter, err := writer.NewJSONWriter(schema.jsonSchema, file, int64(runtime.NumCPU()))
+	if err != nil {
+		return errors.Wrap(err, "failed to create Parquet writer")
+	}
+	parquetWriter.RowGroupSize = parquetRowGroupSize
+
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	iter := jsoniter.ConfigDefault.BorrowIterator(nil)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			stream.Reset(nil)
+			iter.ResetBytes(line)
+			if !schema.root.transcode(iter, stream) {
+				return errors.New("event is not a JSON object")
+			}
+			if iter.Error != nil && iter.Error != io.EOF {
+				return errors.Wrap(iter.Error, "failed to read JSON event")
+			}
+			if err := parquetWriter.Write(string(stream.Buffer())); err != nil {
+				return errors.Wrap(err, "failed to write Parquet row")
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read gzip payload")
+		}
+	}
+
+	if err := parquetWriter.WriteStop(); err != nil {
+		return errors.Wrap(err, "failed to write Parquet file")
+	}
+	return nil
+}
+
+// transcode copies the JSON value of iter to stream keeping only what fits in the column.
+// It returns false if nothing was written because the value is null or does not match the column type.
+// The value is always consumed from iter.
+func (col *parquetColumn) transcode(iter *jsoniter.Iterator, stream *jsoniter.Stream) bool {
+	next := iter.WhatIsNext()
+	if next == jsoniter.NilValue {
+		iter.Skip()
+		return false
+	}
+
+	switch {
+	case col.isList:
+		if next != jsoniter.ArrayValue {
+			iter.Skip()
+			return false
+		}
+		stream.WriteArrayStart()
+		empty := true
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			// parquet-go does not handle null list elements so they are dropped
+			empty = !col.element.transcodeElement(iter, stream, empty, nil) && empty
+			return true
+		})
+		stream.WriteArrayEnd()
+		return true
+	case col.isMap, col.fields != nil:
+		if next != jsoniter.ObjectValue {
+			iter.Skip()
+			return false
+		}
+		stream.WriteObjectStart()
+		empty := true
+		iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+			element := col.element
+			if !col.isMap {
+				if element = col.fields[key]; element == nil {
+					iter.Skip()
+					return true
+				}
+			}
+			// null values are dropped, missing fields are null and parquet-go does not handle null map values
+			empty = !element.transcodeElement(iter, stream, empty, &key) && empty
+			return true
+		})
+		stream.WriteObjectEnd()
+		return true
+	}
+
+	switch col.glueType {
+	case "string":
+		if next == jsoniter.StringValue {
+			stream.WriteString(iter.ReadString())
+		} else {
+			// nested JSON is stored as text, the same as the JSON serde does
+			stream.WriteString(string(iter.SkipAndReturnBytes()))
+		}
+		return true
+	case "boolean":
+		if next != jsoniter.BoolValue {
+			iter.Skip()
+			return false
+		}
+		stream.WriteBool(iter.ReadBool())
+		return true
+	case "timestamp":
+		if next != jsoniter.StringValue {
+			iter.Skip()
+			return false
+		}
+		t, err := time.Parse(parquetTimestampLayout, iter.ReadString())
+		if err != nil {
+			return false
+		}
+		stream.WriteRaw(int96Timestamp(t))
+		return true
+	default: // numbers
+		if next != jsoniter.NumberValue {
+			iter.Skip()
+			return false
+		}
+		stream.WriteRaw(string(iter.ReadNumber()))
+		return true
+	}
+}
+
+// transcodeElement writes an element of a list (key is nil) or an object, preceded by a separator if it is not the first.
+// Elements that are not written are removed from the stream, it returns true if the element was written.
+func (col *parquetColumn) transcodeElement(iter *jsoniter.Iterator, stream *jsoniter.Stream, first bool, key *string) bool {
+	mark := len(stream.Buffer())
+	if !first {
+		stream.WriteMore()
+	}
+	if key != nil {
+		stream.WriteObjectField(*key)
+	}
+	if !col.transcode(iter, stream) {
+		stream.SetBuffer(stream.Buffer()[:mark])
+		return false
+	}
+	return true
+}
+
+// int96Timestamp returns the decimal value of the INT96 representation of t, this is what parquet-go expects
+func int96Timestamp(t time.Time) string {
+	seconds := t.Unix()
+	days := seconds / secondsPerDay
+	if seconds%secondsPerDay < 0 {
+		days-- // floor for times before the epoch
+	}
+	nanosOfDay := t.Sub(time.Unix(days*secondsPerDay, 0)).Nanoseconds()
+
+	value := new(big.Int).Lsh(big.NewInt(days+julianDayOfUnixEpoch), 64)
+	return value.Add(value, big.NewInt(nanosOfDay)).String()
+}
+
+// parquetFile is a source.ParquetFile writing Parquet files to the temp directory
+type parquetFile struct {
+	*os.File
+}
+
+var _ source.ParquetFile = (*parquetFile)(nil)
+
+func newParquetFile() (*parquetFile, error) {
+	file, err := ioutil.TempFile("", "*.parquet")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Parquet file")
+	}
+	return &parquetFile{File: file}, nil
+}
+
+// removeParquetFile closes and deletes a file created by newParquetFile()
+func removeParquetFile(file *parquetFile) {
+	_ = file.Close()
+	if err := os.Remove(file.Name()); err != nil {
+		zap.L().Warn("failed to remove Parquet file", zap.String("file", file.Name()), zap.Error(err))
+	}
+}
+
+func (f *parquetFile) Open(name string) (source.ParquetFile, error) {
+	return nil, errors.New("open is not supported")
+}
+
+func (f *parquetFile) Create(name string) (source.ParquetFile, error) {
+	return nil, errors.New("create is not supported")
+}
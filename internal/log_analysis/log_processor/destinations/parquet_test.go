@@ -0,0 +1,237 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+
+	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+)
+
+type parquetTestNested struct {
+	Name  *string `json:"name" description:"test field"`
+	Count *int32  `json:"count" description:"test field"`
+}
+
+type parquetTestEvent struct {
+	Time    *timestamp.RFC3339           `json:"time" description:"test field"`
+	Message *string                      `json:"message" description:"test field"`
+	Bytes   *numerics.Integer            `json:"bytes" description:"test field"`
+	Ratio   *float64                     `json:"ratio" description:"test field"`
+	OK      *bool                        `json:"ok" description:"test field"`
+	Tags    []string                     `json:"tags" description:"test field"`
+	Labels  map[string]string            `json:"labels" description:"test field"`
+	Nested  *parquetTestNested           `json:"nested" description:"test field"`
+	Items   []parquetTestNested          `json:"items" description:"test field"`
+	Extra   map[string]parquetTestNested `json:"extra" description:"test field"`
+}
+
+type parquetTestCaseEvent struct {
+	Name    *string `json:"name" description:"test field"`
+	NameTwo *string `json:"Name" description:"test field"`
+}
+
+// parquetReadBuffer is an in-memory source.ParquetFile to read Parquet files
+type parquetReadBuffer struct {
+	*bytes.Reader
+	data []byte
+}
+
+func (b *parquetReadBuffer) Write(p []byte) (int, error) { return 0, nil }
+func (b *parquetReadBuffer) Close() error                { return nil }
+func (b *parquetReadBuffer) Open(name string) (source.ParquetFile, error) {
+	return newParquetReadBuffer(b.data), nil
+}
+func (b *parquetReadBuffer) Create(name string) (source.ParquetFile, error) { return b, nil }
+
+func newParquetReadBuffer(data []byte) *parquetReadBuffer {
+	return &parquetReadBuffer{Reader: bytes.NewReader(data), data: data}
+}
+
+func TestParquetSchema(t *testing.T) {
+	table := awsglue.NewGlueTableMetadata(models.LogData, "Parquet.Test", "test", awsglue.GlueTableHourly,
+		&parquetTestEvent{})
+	schema, err := newParquetSchema(table)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"time", "message", "bytes", "ratio", "ok", "tags", "labels", "nested", "items", "extra"},
+		schema.root.fieldNames)
+	assert.Equal(t, "INT96", schema.root.fields["time"].parquetType)
+	assert.Equal(t, "INT64", schema.root.fields["bytes"].parquetType)
+	assert.True(t, schema.root.fields["tags"].isList)
+	assert.True(t, schema.root.fields["extra"].isMap)
+	assert.Equal(t, []string{"name", "count"}, schema.root.fields["extra"].element.fieldNames)
+}
+
+func TestParquetSchemaCaseCollision(t *testing.T) {
+	table := awsglue.NewGlueTableMetadata(models.LogData, "Parquet.Test", "test", awsglue.GlueTableHourly,
+		&parquetTestCaseEvent{})
+	_, err := newParquetSchema(table)
+	require.Error(t, err)
+}
+
+func TestParseGlueType(t *testing.T) {
+	col, rest, err := parseGlueType("map<string,array<struct<a:bigint,b:timestamp>>>,x")
+	require.NoError(t, err)
+	assert.Equal(t, ",x", rest)
+	assert.True(t, col.isMap)
+	assert.True(t, col.element.isList)
+	assert.Equal(t, []string{"a", "b"}, col.element.element.fieldNames)
+
+	_, _, err = parseGlueType("map<int,string>")
+	assert.Error(t, err)
+	_, _, err = parseGlueType("struct<>")
+	assert.Error(t, err)
+	_, _, err = parseGlueType("decimal(10,2)")
+	assert.Error(t, err)
+}
+
+func TestInt96Timestamp(t *testing.T) {
+	// 2020-01-01 is Julian day 2458850
+	tm := time.Date(2020, 1, 1, 0, 0, 1, 5, time.UTC)
+	assert.Equal(t, "45357776665640731991001605", int96Timestamp(tm)) // 2458850 << 64 + 1000000005
+	// before the epoch the day is rounded down
+	tm = time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC)
+	assert.Equal(t, "45020883778708972449838592", int96Timestamp(tm)) // 2440587 << 64 + 86399000000000
+}
+
+func TestParquetConvert(t *testing.T) {
+	table := awsglue.NewGlueTableMetadata(models.LogData, "Parquet.Test", "test", awsglue.GlueTableHourly,
+		&parquetTestEvent{})
+	schema, err := newParquetSchema(table)
+	require.NoError(t, err)
+
+	lines := []string{
+		`{"time":"2020-01-01 00:00:01.000000005","message":"hello","bytes":42,"ratio":0.5,"ok":true,` +
+			`"tags":["a",null,"b"],"labels":{"k":"v","n":null},"nested":{"name":"x","count":3,"unknown":1},` +
+			`"items":[{"name":"y"},null],"extra":{"e":{"count":7}}}`,
+		`{"message":{"raw":"json"},"bytes":"not a number","time":"bad","unknown":[1,2]}`,
+		`{}`,
+	}
+	var payload bytes.Buffer
+	gzipWriter := gzip.NewWriter(&payload)
+	for _, line := range lines {
+		_, err = gzipWriter.Write([]byte(line + "\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, gzipWriter.Close())
+
+	file, err := schema.convert(payload.Bytes())
+	require.NoError(t, err)
+	defer removeParquetFile(file)
+	data, err := ioutil.ReadAll(file)
+	require.NoError(t, err)
+
+	parquetReader, err := reader.NewParquetReader(newParquetReadBuffer(data), nil, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(lines)), parquetReader.GetNumRows())
+	// the file uses the column names of the table (the reader renames them when opening the file)
+	for _, name := range []string{"message", "element", "key_value"} {
+		assert.True(t, bytes.Contains(data, []byte(name)), name)
+	}
+	assert.False(t, bytes.Contains(data, []byte("Key_value")))
+
+	readColumn := func(path string) []interface{} {
+		values, _, _, err := parquetReader.ReadColumnByPath("Parquet_go_root."+path, int64(len(lines)+1))
+		require.NoError(t, err)
+		return values
+	}
+
+	expectedTime := make([]byte, 12)
+	binary.LittleEndian.PutUint64(expectedTime, 1000000005)
+	binary.LittleEndian.PutUint32(expectedTime[8:], 2458850)
+	assert.Equal(t, []interface{}{string(expectedTime), nil, nil}, readColumn("Time"))
+	// nested JSON is stored as text
+	assert.Equal(t, []interface{}{"hello", `{"raw":"json"}`, nil}, readColumn("Message"))
+	// values that do not match the column type are null
+	assert.Equal(t, []interface{}{int64(42), nil, nil}, readColumn("Bytes"))
+	assert.Equal(t, []interface{}{0.5, nil, nil}, readColumn("Ratio"))
+	assert.Equal(t, []interface{}{true, nil, nil}, readColumn("Ok"))
+	// null list elements and map values are dropped
+	assert.Equal(t, []interface{}{"a", "b", nil, nil}, readColumn("Tags.List.Element"))
+	assert.Equal(t, []interface{}{"k", nil, nil}, readColumn("Labels.Key_value.Key"))
+	assert.Equal(t, []interface{}{"v", nil, nil}, readColumn("Labels.Key_value.Value"))
+	assert.Equal(t, []interface{}{int32(3), nil, nil}, readColumn("Nested.Count"))
+	assert.Equal(t, []interface{}{"y", nil, nil}, readColumn("Items.List.Element.Name"))
+	assert.Equal(t, []interface{}{"e", nil, nil}, readColumn("Extra.Key_value.Key"))
+	assert.Equal(t, []interface{}{int32(7), nil, nil}, readColumn("Extra.Key_value.Value.Count"))
+	parquetReader.ReadStop()
+}
+
+func TestParquetConvertNotAnObject(t *testing.T) {
+	table := awsglue.NewGlueTableMetadata(models.LogData, "Parquet.Test", "test", awsglue.GlueTableHourly,
+		&parquetTestEvent{})
+	schema, err := newParquetSchema(table)
+	require.NoError(t, err)
+
+	var payload bytes.Buffer
+	gzipWriter := gzip.NewWriter(&payload)
+	_, err = gzipWriter.Write([]byte("[1,2]\n"))
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	_, err = schema.convert(payload.Bytes())
+	assert.Error(t, err)
+}
+
+func TestEnableParquet(t *testing.T) {
+	logTypes := []string{"AWS.CloudTrail", "AWS.VPCFlow"}
+	require.NoError(t, EnableParquet(logTypes))
+	defer func() {
+		for _, logType := range logTypes {
+			registry.AvailableParsers().LookupParser(logType).GlueTableMetadata.SetFormat(awsglue.JSONStorageFormat)
+			delete(parquetSchemas, logType)
+		}
+	}()
+
+	for _, logType := range logTypes {
+		assert.Equal(t, awsglue.ParquetStorageFormat, registry.AvailableParsers().LookupParser(logType).GlueTableMetadata.Format())
+		assert.NotNil(t, parquetSchemas[logType])
+	}
+	assert.Equal(t, awsglue.JSONStorageFormat, registry.AvailableParsers().LookupParser("AWS.ALB").GlueTableMetadata.Format())
+
+	assert.Error(t, EnableParquet([]string{"Unknown.LogType"}))
+}
+
+func TestParquetSchemaAllLogTypes(t *testing.T) {
+	for logType, lpm := range registry.AvailableParsers() {
+		_, err := newParquetSchema(lpm.GlueTableMetadata)
+		assert.NoError(t, err, logType)
+	}
+}
+
+func TestParquetConversionMemory(t *testing.T) {
+	jsonOnly := maxS3BufferMemUsageBytes(1024)
+	parquetSchemas["Parquet.Test"] = &parquetSchema{}
+	defer delete(parquetSchemas, "Parquet.Test")
+	assert.Equal(t, jsonOnly-parquetConversionMemMB*bytesPerMB, maxS3BufferMemUsageBytes(1024))
+}
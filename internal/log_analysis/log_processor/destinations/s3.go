@@ -22,7 +22,10 @@ import (
 	"bytes"
 	"compress/gzip"
 	"fmt"
+	"io"
+	"path"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -37,6 +40,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
@@ -46,19 +51,31 @@ const (
 	// s3ObjectKeyFormat represents the format of the S3 object key
 	// It has 3 parts:
 	// 1. The key prefix 2. Timestamp in format `s3ObjectTimestampFormat` 3. UUID4
-	s3ObjectKeyFormat = "%s%s-%s.json.gz"
+	s3ObjectKeyFormat = "%s%s-%s" + jsonObjectKeySuffix
+
+	jsonObjectKeySuffix    = ".json.gz"
+	parquetObjectKeySuffix = ".parquet"
+
+	// analysisCopyTagging tags the JSON copy of data stored as Parquet, the bucket expires the copies
+	analysisCopyTagging = "PantherAnalysisCopy=true"
 
 	// The timestamp format in the S3 objects with second precision: yyyyMMddTHHmmssZ
 	S3ObjectTimestampFormat = "20060102T150405Z"
 
 	logDataTypeAttributeName = "type"
 	logTypeAttributeName     = "id"
+	// analysisCopyKeyAttributeName holds the key of the JSON copy of data stored as Parquet, the rules engine reads it
+	analysisCopyKeyAttributeName = "analysisCopyKey"
 
 	messageAttributeDataType = "String"
 
 	//  maximum time to hold an s3 buffer in memory (controls latency of rules engine which processes this output
 	maxDuration = 2 * time.Minute
 
+	// ReplayS3Prefix is the prefix of the data replayed through the log processor queue in the processed data bucket,
+	// the log processor is only allowed to write replayed data under it
+	ReplayS3Prefix = "replay/"
+
 	bytesPerMB                  = 1024 * 1024
 	defaultMaxS3BufferSizeBytes = 50 * bytesPerMB
 )
@@ -71,6 +88,7 @@ var (
 	parserRegistry registry.Interface = registry.AvailableParsers() // initialize
 
 	memUsedAtStartupMB int // set in init(), used to size memory buffers for S3 write
+	reservedMemMB      int // set by ReserveMemory(), used to size memory buffers for S3 write
 )
 
 func init() {
@@ -79,6 +97,19 @@ func init() {
 	memUsedAtStartupMB = (int)(memStats.Sys/(bytesPerMB)) + 1
 }
 
+// ReserveMemory sets aside memory used for the life of the process that was allocated after startup
+// (e.g., the GeoIP databases), the buffers of the S3 destinations created afterwards are sized without it.
+// It fails if the memory left for the buffers would be too small.
+func ReserveMemory(bytes int64) error {
+	memMB := int(bytes/bytesPerMB) + 1
+	lambdaSizeMB := common.Config.AwsLambdaFunctionMemorySize
+	if availableBufferMemMB(lambdaSizeMB)-memMB < minBufferMemMB {
+		return errors.Errorf("available memory too small to reserve %dMB, increase lambda size from %dMB", memMB, lambdaSizeMB)
+	}
+	reservedMemMB += memMB
+	return nil
+}
+
 func CreateS3Destination() Destination {
 	return &S3Destination{
 		s3Uploader:          common.S3Uploader,
@@ -90,30 +121,62 @@ func CreateS3Destination() Destination {
 	}
 }
 
+// CreateS3ReplayDestination returns a destination writing replayed data under ReplayS3Prefix + keyPrefix
+// in the processed data bucket.
+// No notifications are sent so the data is not analyzed nor added to the log tables until it is promoted.
+func CreateS3ReplayDestination(keyPrefix string) Destination {
+	return NewS3ReplayDestination(common.S3Uploader, common.Config.ProcessedDataBucket, ReplayS3Prefix+keyPrefix,
+		maxS3BufferMemUsageBytes(common.Config.AwsLambdaFunctionMemorySize))
+}
+
+// NewS3ReplayDestination returns a destination writing replayed data under keyPrefix in s3Bucket using the given uploader
+func NewS3ReplayDestination(s3Uploader s3manageriface.UploaderAPI, s3Bucket, keyPrefix string,
+	maxBufferedMemBytes uint64) *S3Destination {
+
+	return &S3Destination{
+		s3Uploader:          s3Uploader,
+		s3Bucket:            s3Bucket,
+		keyPrefix:           keyPrefix,
+		maxBufferedMemBytes: maxBufferedMemBytes,
+		maxDuration:         maxDuration,
+	}
+}
+
+// the smallest memory for the output buffers the log processor can run with
+const minBufferMemMB = 5
+
 // the largest we let total size of compressed output buffers get before calling sendData() to write to S3 in bytes
 // NOTE: this presumes processing 1 file at a time
 func maxS3BufferMemUsageBytes(lambdaSizeMB int) uint64 {
+	maxBufferUsageMB := availableBufferMemMB(lambdaSizeMB)
+	if maxBufferUsageMB < minBufferMemMB {
+		panic(fmt.Sprintf("available memory too small for log processing, increase lambda size from %dMB", lambdaSizeMB))
+	}
+
+	return (uint64)(maxBufferUsageMB) * bytesPerMB // to bytes
+}
+
+// availableBufferMemMB returns the memory left for the output buffers, it can be negative
+func availableBufferMemMB(lambdaSizeMB int) int {
 	const (
 		/*
-					NOTE:
-					  "More specifically CloudTrail will collect logs for 5 mins or until the max file size of 45MB has been reached.
-					  An important thing worth noting is that these logs get compressed before being sent to S3, once the file size
-					  limit is met or the time limit has been exceeded"
-				    Because CT files are "document" JSON and all on 1 line we currently need to read ALL the uncompressed data into memory.
-			        FIXME: we should switch to streaming JSON reader
-					Below we set the lower bound on memory to be 45MB * 4 (because we convert all the records and parse) plus some for overhead
+			NOTE:
+			  Files are streamed one line at a time, and "document" JSON like CloudTrail's {"Records":[...]}
+			  one record at a time (see processor.processRecords()). Other documents, like single JSON objects,
+			  CloudWatch envelopes and multiline events, are still read whole, so they can be as large as a
+			  CloudTrail file: CloudTrail collects logs for 5 mins or until the max file size of 45MB has been reached.
+			  Below we set the lower bound on memory to be 45MB * 4 (because we convert and parse) plus some for overhead
 		*/
-		largestAllInMemFileMB     = 45
+		largestInMemEventMB       = 45
 		processingExpansionFactor = 4
-		memoryFootprint           = largestAllInMemFileMB * processingExpansionFactor
+		memoryFootprint           = largestInMemEventMB * processingExpansionFactor
 		minimumScratchMemMB       = 5 // how much overhead is needed to process a file
 	)
-	maxBufferUsageMB := lambdaSizeMB - memUsedAtStartupMB - memoryFootprint - minimumScratchMemMB
-	if maxBufferUsageMB < 5 {
-		panic(fmt.Sprintf("available memory too small for log processing, increase lambda size from %dMB", lambdaSizeMB))
+	maxBufferUsageMB := lambdaSizeMB - memUsedAtStartupMB - reservedMemMB - memoryFootprint - minimumScratchMemMB
+	if len(parquetSchemas) > 0 { // buffers are converted one at a time so this is needed only once
+		maxBufferUsageMB -= parquetConversionMemMB
 	}
-
-	return (uint64)(maxBufferUsageMB) * bytesPerMB // to bytes
+	return maxBufferUsageMB
 }
 
 // S3Destination sends normalized events to S3
@@ -122,8 +185,10 @@ type S3Destination struct {
 	snsClient  snsiface.SNSAPI
 	// s3Bucket is the s3Bucket where the data will be stored
 	s3Bucket string
+	// keyPrefix is prepended to the keys of the stored data, it is set when replaying data
+	keyPrefix string
 	// snsTopic is the SNS Topic ARN where we will send the notification
-	// when we store new data in S3
+	// when we store new data in S3, if empty no notification is sent
 	snsTopicArn string
 	// thresholds for ejection
 	maxBufferedMemBytes uint64 // max will hold in buffers before ejection
@@ -240,7 +305,7 @@ func (destination *S3Destination) sendData(buffer *s3EventBuffer, errChan chan e
 	var err error
 	var contentLength int64 = 0
 
-	key := getS3ObjectKey(buffer.logType, buffer.hour)
+	key := destination.keyPrefix + getS3ObjectKey(buffer.logType, buffer.hour)
 
 	operation := common.OpLogManager.Start("sendData", common.OpLogS3ServiceDim)
 	defer func() {
@@ -260,22 +325,72 @@ func (destination *S3Destination) sendData(buffer *s3EventBuffer, errChan chan e
 
 	contentLength = int64(len(payload)) // for logging above
 
-	if _, err := destination.s3Uploader.Upload(&s3manager.UploadInput{
-		Bucket: aws.String(destination.s3Bucket),
-		Key:    aws.String(key),
-		Body:   bytes.NewReader(payload),
-	}); err != nil {
-		errChan <- errors.Wrap(err, "S3Upload")
+	var analysisCopyKey string
+	if getGlueTableMetadata(buffer.logType).Format() == awsglue.ParquetStorageFormat {
+		key, analysisCopyKey, err = destination.sendParquetData(key, buffer, payload)
+		if err != nil {
+			errChan <- err
+			return
+		}
+	} else if err = destination.upload(key, bytes.NewReader(payload), nil); err != nil {
+		errChan <- err
 		return
 	}
 
-	err = destination.sendSNSNotification(key, buffer) // if send fails we fail whole operation
+	if destination.snsTopicArn == "" { // replayed data is not analyzed
+		return
+	}
+
+	err = destination.sendSNSNotification(key, analysisCopyKey, buffer) // if send fails we fail whole operation
 	if err != nil {
 		errChan <- err
 	}
 }
 
-func (destination *S3Destination) sendSNSNotification(key string, buffer *s3EventBuffer) error {
+// sendParquetData stores the data of the buffer as Parquet and returns its key and the key of the JSON data to analyze.
+// The rules engine reads JSON so a copy of the data is kept next to the Parquet file, its name starts
+// with '_' so it is ignored by Athena. It is tagged to be expired by the bucket lifecycle rules.
+func (destination *S3Destination) sendParquetData(key string, buffer *s3EventBuffer,
+	payload []byte) (parquetKey, analysisCopyKey string, err error) {
+
+	schema, found := parquetSchemas[buffer.logType]
+	if !found {
+		return "", "", errors.Errorf("no Parquet schema for log type %s", buffer.logType)
+	}
+	parquetData, err := schema.convert(payload)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to convert %s data to Parquet", buffer.logType)
+	}
+	defer removeParquetFile(parquetData)
+	parquetKey = strings.TrimSuffix(key, jsonObjectKeySuffix) + parquetObjectKeySuffix
+	if err = destination.upload(parquetKey, parquetData, nil); err != nil {
+		return "", "", err
+	}
+
+	if destination.snsTopicArn == "" { // replayed data is not analyzed, no copy is needed
+		return parquetKey, "", nil
+	}
+	analysisCopyKey = path.Join(path.Dir(key), "_"+path.Base(key))
+	if err = destination.upload(analysisCopyKey, bytes.NewReader(payload), aws.String(analysisCopyTagging)); err != nil {
+		return "", "", err
+	}
+	return parquetKey, analysisCopyKey, nil
+}
+
+func (destination *S3Destination) upload(key string, body io.Reader, tagging *string) error {
+	if _, err := destination.s3Uploader.Upload(&s3manager.UploadInput{
+		Bucket:  aws.String(destination.s3Bucket),
+		Key:     aws.String(key),
+		Body:    body,
+		Tagging: tagging,
+	}); err != nil {
+		return errors.Wrap(err, "S3Upload")
+	}
+	return nil
+}
+
+// sendSNSNotification notifies the stored data, the key of its JSON copy is sent as an attribute if the data is not JSON
+func (destination *S3Destination) sendSNSNotification(key, analysisCopyKey string, buffer *s3EventBuffer) error {
 	var err error
 	operation := common.OpLogManager.Start("sendSNSNotification", common.OpLogSNSServiceDim)
 	defer func() {
@@ -297,7 +412,7 @@ func (destination *S3Destination) sendSNSNotification(key string, buffer *s3Even
 		Message:  aws.String(marshalledNotification),
 		MessageAttributes: map[string]*sns.MessageAttributeValue{
 			logDataTypeAttributeName: {
-				StringValue: aws.String(models.LogData.String()),
+				StringValue: aws.String(getGlueTableMetadata(buffer.logType).DataType().String()),
 				DataType:    aws.String(messageAttributeDataType),
 			},
 			logTypeAttributeName: {
@@ -306,6 +421,12 @@ func (destination *S3Destination) sendSNSNotification(key string, buffer *s3Even
 			},
 		},
 	}
+	if analysisCopyKey != "" {
+		input.MessageAttributes[analysisCopyKeyAttributeName] = &sns.MessageAttributeValue{
+			StringValue: aws.String(analysisCopyKey),
+			DataType:    aws.String(messageAttributeDataType),
+		}
+	}
 	if _, err = destination.snsClient.Publish(input); err != nil {
 		err = errors.Wrap(err, "failed to send notification to topic")
 		return err
@@ -316,11 +437,19 @@ func (destination *S3Destination) sendSNSNotification(key string, buffer *s3Even
 
 func getS3ObjectKey(logType string, timestamp time.Time) string {
 	return fmt.Sprintf(s3ObjectKeyFormat,
-		parserRegistry.LookupParser(logType).GlueTableMetadata.GetPartitionPrefix(timestamp.UTC()), // get the path to store the data in S3
+		getGlueTableMetadata(logType).GetPartitionPrefix(timestamp.UTC()), // get the path to store the data in S3
 		timestamp.Format(S3ObjectTimestampFormat),
 		uuid.New().String())
 }
 
+// classification failures are not produced by a parser, so their table is not in the registry
+func getGlueTableMetadata(logType string) *awsglue.GlueTableMetadata {
+	if logType == classification.FailureLogType {
+		return classification.FailureTable
+	}
+	return parserRegistry.LookupParser(logType).GlueTableMetadata
+}
+
 // s3BufferSet is a group of buffers associated with hour time bins, pointing to maps logtype->s3EventBuffer
 type s3EventBufferSet struct {
 	totalBufferedMemBytes uint64 // managed by addEvent() and removeBuffer()
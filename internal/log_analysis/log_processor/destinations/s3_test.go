@@ -42,6 +42,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
@@ -182,6 +183,41 @@ func newS3Destination() *testS3Destination {
 	}
 }
 
+func TestSendReplayDataToS3(t *testing.T) {
+	initTest()
+
+	destination := newS3Destination()
+	destination.snsTopicArn = "" // replay destinations do not notify
+	destination.keyPrefix = "replay/test/"
+	eventChannel := make(chan *parsers.PantherLog, 1)
+
+	testEvent := newSimpleTestEvent()
+
+	// wire it up
+	registerMockParser(testLogType, testEvent)
+
+	eventChannel <- testEvent
+
+	destination.mockS3Uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Once()
+
+	runSendEvents(t, destination, eventChannel, false)
+
+	destination.mockS3Uploader.AssertExpectations(t)
+	destination.mockSns.AssertNotCalled(t, "Publish", mock.Anything)
+
+	uploadInput := destination.mockS3Uploader.Calls[0].Arguments.Get(0).(*s3manager.UploadInput)
+	assert.True(t, strings.HasPrefix(*uploadInput.Key, "replay/test/"+expectedS3Prefix))
+}
+
+func TestCreateS3ReplayDestination(t *testing.T) {
+	initTest()
+
+	// the log processor can only write replayed data under the replay prefix
+	destination := CreateS3ReplayDestination("test/").(*S3Destination)
+	assert.Equal(t, "replay/test/", destination.keyPrefix)
+	assert.Empty(t, destination.snsTopicArn)
+}
+
 func TestSendDataToS3BeforeTerminating(t *testing.T) {
 	initTest()
 
@@ -248,6 +284,73 @@ func TestSendDataToS3BeforeTerminating(t *testing.T) {
 	assert.Equal(t, expectedSnsPublishInput, publishInput)
 }
 
+// testParquetEvent is a test event for log types stored as Parquet, their columns need descriptions
+type testParquetEvent struct {
+	Data string `json:"data" description:"test field"`
+	parsers.PantherLog
+}
+
+func TestSendParquetDataToS3(t *testing.T) {
+	initTest()
+
+	const parquetLogType = "testParquetLogType"
+	destination := newS3Destination()
+	eventChannel := make(chan *parsers.PantherLog, 1)
+
+	te := &testParquetEvent{
+		Data: "test",
+	}
+	te.SetCoreFields(parquetLogType, &refTime, te)
+	testEvent := &te.PantherLog
+
+	// wire it up
+	registerMockParser(parquetLogType, testEvent)
+	table := testRegistry.LookupParser(parquetLogType).GlueTableMetadata
+	schema, err := newParquetSchema(table)
+	require.NoError(t, err)
+	table.SetFormat(awsglue.ParquetStorageFormat)
+	parquetSchemas[parquetLogType] = schema
+	defer delete(parquetSchemas, parquetLogType)
+
+	eventChannel <- testEvent
+
+	// the Parquet file is removed after the upload so the body is read during the call
+	var parquetBytes []byte
+	destination.mockS3Uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Run(
+		func(args mock.Arguments) {
+			input := args.Get(0).(*s3manager.UploadInput)
+			if strings.HasSuffix(*input.Key, ".parquet") {
+				parquetBytes, _ = ioutil.ReadAll(input.Body)
+			}
+		}).Twice()
+	destination.mockSns.On("Publish", mock.Anything).Return(&sns.PublishOutput{}, nil).Once()
+
+	runSendEvents(t, destination, eventChannel, false)
+
+	destination.mockS3Uploader.AssertExpectations(t)
+	destination.mockSns.AssertExpectations(t)
+
+	// the data is stored as Parquet
+	const expectedPrefix = "logs/testparquetlogtype/year=2020/month=01/day=01/hour=00/"
+	parquetInput := destination.mockS3Uploader.Calls[0].Arguments.Get(0).(*s3manager.UploadInput)
+	assert.True(t, strings.HasPrefix(*parquetInput.Key, expectedPrefix+"20200101T000000Z"))
+	assert.True(t, strings.HasSuffix(*parquetInput.Key, ".parquet"))
+	assert.Nil(t, parquetInput.Tagging)
+	assert.True(t, bytes.HasPrefix(parquetBytes, []byte("PAR1")))
+
+	// a tagged JSON copy ignored by Athena is written for the rules engine
+	copyInput := destination.mockS3Uploader.Calls[1].Arguments.Get(0).(*s3manager.UploadInput)
+	assert.Equal(t, strings.Replace(strings.TrimSuffix(*parquetInput.Key, ".parquet")+".json.gz",
+		expectedPrefix, expectedPrefix+"_", 1), *copyInput.Key)
+	assert.Equal(t, aws.String("PantherAnalysisCopy=true"), copyInput.Tagging)
+
+	// the Parquet data is notified, the JSON copy is sent as an attribute
+	publishInput := destination.mockSns.Calls[0].Arguments.Get(0).(*sns.PublishInput)
+	assert.Contains(t, *publishInput.Message, *parquetInput.Key)
+	assert.NotContains(t, *publishInput.Message, *copyInput.Key)
+	assert.Equal(t, *copyInput.Key, *publishInput.MessageAttributes["analysisCopyKey"].StringValue)
+}
+
 func TestSendDataIfTotalMemSizeLimitHasBeenReached(t *testing.T) {
 	initTest()
 
@@ -533,3 +636,18 @@ func runSendEventsSignaled(t *testing.T, destination Destination, eventChannel c
 	close(errChan) // causes err go routines to to terminate
 	waitErr.Wait()
 }
+
+func TestReserveMemory(t *testing.T) {
+	defer func(memorySize int) {
+		common.Config.AwsLambdaFunctionMemorySize = memorySize
+		reservedMemMB = 0
+	}(common.Config.AwsLambdaFunctionMemorySize)
+	common.Config.AwsLambdaFunctionMemorySize = 1024
+
+	available := maxS3BufferMemUsageBytes(1024)
+	require.NoError(t, ReserveMemory(100*bytesPerMB))
+	assert.Equal(t, available-101*bytesPerMB, maxS3BufferMemUsageBytes(1024))
+	// the reservation is refused if there would not be enough memory left for the buffers
+	require.Error(t, ReserveMemory(1024*bytesPerMB))
+	assert.Equal(t, available-101*bytesPerMB, maxS3BufferMemUsageBytes(1024))
+}
@@ -0,0 +1,236 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/firehose/firehoseiface"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/awsbatch/firehosebatch"
+	"github.com/panther-labs/panther/pkg/awsbatch/kinesisbatch"
+)
+
+const (
+	// maximum time to hold events before sending them to a stream, streams are meant for low latency consumers
+	streamMaxDuration = 10 * time.Second
+
+	// maximum time spent retrying a batch that failed
+	streamMaxBackoff = time.Minute
+
+	// AWS limits: https://docs.aws.amazon.com/kinesis/latest/APIReference/API_PutRecords.html
+	kinesisMaxBatchRecords = 500
+	kinesisMaxBatchBytes   = 5 * bytesPerMB
+	kinesisMaxRecordBytes  = bytesPerMB
+
+	// AWS limits: https://docs.aws.amazon.com/firehose/latest/APIReference/API_PutRecordBatch.html
+	firehoseMaxBatchRecords = 500
+	firehoseMaxBatchBytes   = 4 * bytesPerMB
+	firehoseMaxRecordBytes  = 1000 * 1024
+)
+
+// streamRecord is a serialized event sent to a stream
+type streamRecord struct {
+	data         []byte
+	partitionKey string
+}
+
+// size is the size of the record counted against the stream limits
+func (r *streamRecord) size() int {
+	return len(r.data) + len(r.partitionKey)
+}
+
+// StreamDestination sends normalized events to a Kinesis data stream or a Firehose delivery stream in batches
+type StreamDestination struct {
+	// the name of the stream and the service, used in logs
+	streamName string
+	serviceDim zap.Field
+	// sends a batch of records
+	putRecords func(records []*streamRecord) error
+	// Firehose concatenates the records it delivers so they are terminated by a new line
+	newLineDelimited bool
+	// the limits of the stream
+	maxBatchRecords int
+	maxBatchBytes   int
+	maxRecordBytes  int
+	// maximum time events are held before they are sent
+	maxDuration time.Duration
+}
+
+// NewKinesisDestination returns a destination sending events to a Kinesis data stream
+func NewKinesisDestination(client kinesisiface.KinesisAPI, streamName string) *StreamDestination {
+	return &StreamDestination{
+		streamName: streamName,
+		serviceDim: common.OpLogKinesisServiceDim,
+		putRecords: func(records []*streamRecord) error {
+			input := &kinesis.PutRecordsInput{
+				StreamName: aws.String(streamName),
+				Records:    make([]*kinesis.PutRecordsRequestEntry, len(records)),
+			}
+			for i, record := range records {
+				input.Records[i] = &kinesis.PutRecordsRequestEntry{
+					Data:         record.data,
+					PartitionKey: aws.String(record.partitionKey),
+				}
+			}
+			return kinesisbatch.PutRecords(client, streamMaxBackoff, input)
+		},
+		maxBatchRecords: kinesisMaxBatchRecords,
+		maxBatchBytes:   kinesisMaxBatchBytes,
+		maxRecordBytes:  kinesisMaxRecordBytes,
+		maxDuration:     streamMaxDuration,
+	}
+}
+
+// NewFirehoseDestination returns a destination sending events to a Firehose delivery stream, e.g. to feed a data lake
+func NewFirehoseDestination(client firehoseiface.FirehoseAPI, deliveryStreamName string) *StreamDestination {
+	return &StreamDestination{
+		streamName: deliveryStreamName,
+		serviceDim: common.OpLogFirehoseServiceDim,
+		putRecords: func(records []*streamRecord) error {
+			input := &firehose.PutRecordBatchInput{
+				DeliveryStreamName: aws.String(deliveryStreamName),
+				Records:            make([]*firehose.Record, len(records)),
+			}
+			for i, record := range records {
+				input.Records[i] = &firehose.Record{
+					Data: record.data,
+				}
+			}
+			return firehosebatch.PutRecordBatch(client, streamMaxBackoff, input)
+		},
+		newLineDelimited: true,
+		maxBatchRecords:  firehoseMaxBatchRecords,
+		maxBatchBytes:    firehoseMaxBatchBytes,
+		maxRecordBytes:   firehoseMaxRecordBytes,
+		maxDuration:      streamMaxDuration,
+	}
+}
+
+// SendEvents sends events to the stream.
+// It continuously reads events from parsedEventChannel and sends them in batches within the stream limits.
+// If the method encounters an error it writes an error to the errorChannel and continues until channel is closed
+// (skipping events). Events larger than the stream allows are logged and skipped.
+func (destination *StreamDestination) SendEvents(parsedEventChannel chan *parsers.PantherLog, errChan chan error) {
+	// used to flush batches of events that are held too long
+	flushExpired := time.NewTicker(destination.maxDuration)
+	defer flushExpired.Stop()
+
+	var batch []*streamRecord
+	batchBytes := 0
+	failed := false // set to true on error and loop will drain channel
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := destination.send(batch); err != nil {
+			failed = true
+			errChan <- err
+		}
+		batch, batchBytes = nil, 0
+	}
+
+	for event := range parsedEventChannel {
+		if failed { // drain channel
+			continue
+		}
+		// classification failures are stored only in their S3 table, they are not events for stream consumers
+		if aws.StringValue(event.PantherLogType) == classification.FailureLogType {
+			continue
+		}
+
+		select {
+		case <-flushExpired.C:
+			flush()
+			if failed {
+				continue
+			}
+		default: // makes select non-blocking
+		}
+
+		// Use renaming field JSON serializer
+		data, err := parsers.JSON.Marshal(event.Event())
+		if err != nil {
+			failed = true
+			errChan <- errors.Wrap(err, "failed to marshal log parser event for stream")
+			continue
+		}
+		if destination.newLineDelimited {
+			data = append(data, common.EventDelimiter)
+		}
+
+		record := &streamRecord{
+			data:         data,
+			partitionKey: partitionKey(event),
+		}
+		if record.size() > destination.maxRecordBytes {
+			zap.L().Warn("event is too large for stream, skipping",
+				zap.String("stream", destination.streamName),
+				zap.String("logType", aws.StringValue(event.PantherLogType)),
+				zap.Int("size", record.size()))
+			continue
+		}
+
+		if len(batch) == destination.maxBatchRecords || batchBytes+record.size() > destination.maxBatchBytes {
+			flush()
+			if failed {
+				continue
+			}
+		}
+		batch = append(batch, record)
+		batchBytes += record.size()
+	}
+
+	if !failed {
+		flush()
+	}
+}
+
+func (destination *StreamDestination) send(batch []*streamRecord) (err error) {
+	operation := common.OpLogManager.Start("sendRecords", destination.serviceDim)
+	defer func() {
+		operation.Stop()
+		operation.Log(err,
+			zap.String("stream", destination.streamName),
+			zap.Int("records", len(batch)))
+	}()
+
+	if err = destination.putRecords(batch); err != nil {
+		return errors.Wrapf(err, "failed to send records to stream %s", destination.streamName)
+	}
+	return nil
+}
+
+// partitionKey spreads the events over the shards of Kinesis streams, row ids are unique
+func partitionKey(event *parsers.PantherLog) string {
+	if event.PantherRowID != nil {
+		return *event.PantherRowID
+	}
+	return uuid.New().String()
+}
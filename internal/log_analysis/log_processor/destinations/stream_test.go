@@ -0,0 +1,183 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/firehose/firehoseiface"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+type mockKinesis struct {
+	kinesisiface.KinesisAPI
+	mock.Mock
+}
+
+func (m *mockKinesis) PutRecords(input *kinesis.PutRecordsInput) (*kinesis.PutRecordsOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*kinesis.PutRecordsOutput), args.Error(1)
+}
+
+type mockFirehose struct {
+	firehoseiface.FirehoseAPI
+	mock.Mock
+}
+
+func (m *mockFirehose) PutRecordBatch(input *firehose.PutRecordBatchInput) (*firehose.PutRecordBatchOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*firehose.PutRecordBatchOutput), args.Error(1)
+}
+
+// sendEvents runs the destination over the events and returns the errors it reported
+func sendEvents(destination Destination, events ...*parsers.PantherLog) (errs []error) {
+	eventChannel := make(chan *parsers.PantherLog, len(events))
+	for _, event := range events {
+		eventChannel <- event
+	}
+	close(eventChannel)
+
+	errChan := make(chan error)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for err := range errChan {
+			errs = append(errs, err)
+		}
+	}()
+	destination.SendEvents(eventChannel, errChan)
+	close(errChan)
+	<-done
+	return errs
+}
+
+func TestSendEventsToKinesis(t *testing.T) {
+	initTest()
+
+	mockClient := &mockKinesis{}
+	destination := NewKinesisDestination(mockClient, "testStream")
+	destination.maxBatchRecords = 2
+
+	event1, event2, event3 := newSimpleTestEvent(), newSimpleTestEvent(), newSimpleTestEvent()
+	mockClient.On("PutRecords", mock.Anything).Return(&kinesis.PutRecordsOutput{FailedRecordCount: aws.Int64(0)}, nil).Twice()
+
+	require.Empty(t, sendEvents(destination, event1, event2, event3))
+	mockClient.AssertExpectations(t)
+
+	firstBatch := mockClient.Calls[0].Arguments.Get(0).(*kinesis.PutRecordsInput)
+	assert.Equal(t, "testStream", *firstBatch.StreamName)
+	require.Len(t, firstBatch.Records, 2)
+	assert.Equal(t, *event1.PantherRowID, *firstBatch.Records[0].PartitionKey)
+	assert.Equal(t, *event2.PantherRowID, *firstBatch.Records[1].PartitionKey)
+	assert.Contains(t, string(firstBatch.Records[0].Data), `"p_log_type":"testLogType"`)
+	assert.False(t, bytes.HasSuffix(firstBatch.Records[0].Data, []byte("\n")))
+
+	secondBatch := mockClient.Calls[1].Arguments.Get(0).(*kinesis.PutRecordsInput)
+	require.Len(t, secondBatch.Records, 1)
+	assert.Equal(t, *event3.PantherRowID, *secondBatch.Records[0].PartitionKey)
+}
+
+func TestSendEventsToFirehose(t *testing.T) {
+	initTest()
+
+	mockClient := &mockFirehose{}
+	destination := NewFirehoseDestination(mockClient, "testDeliveryStream")
+
+	mockClient.On("PutRecordBatch", mock.Anything).Return(&firehose.PutRecordBatchOutput{FailedPutCount: aws.Int64(0)}, nil).Once()
+
+	require.Empty(t, sendEvents(destination, newSimpleTestEvent(), newSimpleTestEvent()))
+	mockClient.AssertExpectations(t)
+
+	batch := mockClient.Calls[0].Arguments.Get(0).(*firehose.PutRecordBatchInput)
+	assert.Equal(t, "testDeliveryStream", *batch.DeliveryStreamName)
+	require.Len(t, batch.Records, 2)
+	for _, record := range batch.Records {
+		assert.True(t, bytes.HasSuffix(record.Data, []byte("\n")))
+	}
+}
+
+func TestSendEventsToStreamFlushesOnBatchSize(t *testing.T) {
+	initTest()
+
+	mockClient := &mockFirehose{}
+	destination := NewFirehoseDestination(mockClient, "testDeliveryStream")
+	event := newSimpleTestEvent()
+	recordSize := len(mustMarshalEvent(t, event)) + 1     // new line
+	destination.maxBatchBytes = recordSize + recordSize/2 // only one record fits in a batch
+
+	mockClient.On("PutRecordBatch", mock.Anything).Return(&firehose.PutRecordBatchOutput{FailedPutCount: aws.Int64(0)}, nil).Twice()
+
+	require.Empty(t, sendEvents(destination, event, newSimpleTestEvent()))
+	mockClient.AssertExpectations(t)
+}
+
+func TestSendEventsToStreamSkipsLargeEvents(t *testing.T) {
+	initTest()
+
+	mockClient := &mockKinesis{}
+	destination := NewKinesisDestination(mockClient, "testStream")
+	destination.maxRecordBytes = 1
+
+	require.Empty(t, sendEvents(destination, newSimpleTestEvent()))
+	mockClient.AssertNotCalled(t, "PutRecords", mock.Anything)
+}
+
+func TestSendEventsToStreamSkipsClassificationFailures(t *testing.T) {
+	initTest()
+
+	mockClient := &mockKinesis{}
+	destination := NewKinesisDestination(mockClient, "testStream")
+
+	failure := classification.NewFailure("bad line", 1, &classification.ClassifierResult{})
+	require.Empty(t, sendEvents(destination, failure.Log()))
+	mockClient.AssertNotCalled(t, "PutRecords", mock.Anything)
+}
+
+func TestSendEventsToStreamFails(t *testing.T) {
+	initTest()
+
+	mockClient := &mockKinesis{}
+	destination := NewKinesisDestination(mockClient, "testStream")
+	destination.maxBatchRecords = 1
+
+	mockClient.On("PutRecords", mock.Anything).Return(&kinesis.PutRecordsOutput{}, errors.New("fail")).Once()
+
+	// the remaining events are drained after the first failure
+	errs := sendEvents(destination, newSimpleTestEvent(), newSimpleTestEvent(), newSimpleTestEvent())
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "failed to send records to stream testStream")
+	mockClient.AssertExpectations(t)
+}
+
+func mustMarshalEvent(t *testing.T, event *parsers.PantherLog) []byte {
+	data, err := parsers.JSON.Marshal(event.Event())
+	require.NoError(t, err)
+	return data
+}
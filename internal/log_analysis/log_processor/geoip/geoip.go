@@ -0,0 +1,228 @@
+package geoip
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package geoip enriches parsed events with the geolocation and autonomous system of their public ip addresses,
+// looked up in MaxMind DB (mmdb) files like GeoLite2-City and GeoLite2-ASN.
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// the language of the names of countries and cities
+const namesLanguage = "en"
+
+var (
+	// ip addresses in these networks are not looked up (private, shared, loopback, link local, multicast and reserved)
+	nonPublicNetworks = mustParseCIDRs(
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"224.0.0.0/4",
+		"240.0.0.0/4",
+		"::/128",
+		"::1/128",
+		"fc00::/7",
+		"fe80::/10",
+		"ff00::/8",
+	)
+)
+
+// Enricher looks up the ip addresses of events in MaxMind databases
+type Enricher struct {
+	databases []*maxminddb.Reader
+	size      int64
+}
+
+// record has the fields of the City, Country, ASN and ISP databases that are used, a database has some of them
+type record struct {
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+	AutonomousSystemNumber       uint   `maxminddb:"autonomous_system_number"`
+	AutonomousSystemOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+// New returns an Enricher for the given database files, the results of the databases are merged
+// (e.g., a City and an ASN database) with the first database taking precedence.
+func New(databases ...[]byte) (*Enricher, error) {
+	enricher := &Enricher{}
+	for i, database := range databases {
+		reader, err := maxminddb.FromBytes(database)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid GeoIP database %d", i)
+		}
+		enricher.databases = append(enricher.databases, reader)
+		enricher.size += int64(len(database))
+	}
+	return enricher, nil
+}
+
+// Open returns an Enricher for the database files at the given local paths (e.g., in a Lambda layer)
+func Open(paths ...string) (*Enricher, error) {
+	enricher := &Enricher{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open GeoIP database %s", path)
+		}
+		reader, err := maxminddb.Open(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open GeoIP database %s", path)
+		}
+		enricher.databases = append(enricher.databases, reader)
+		enricher.size += info.Size()
+	}
+	return enricher, nil
+}
+
+// Load returns an Enricher for the database files with the given keys in the bucket.
+// If the bucket is empty the keys are local paths.
+func Load(s3Client s3iface.S3API, bucket string, keys []string) (*Enricher, error) {
+	if bucket == "" {
+		return Open(keys...)
+	}
+	var databases [][]byte
+	for _, key := range keys {
+		output, err := s3Client.GetObject(&s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get GeoIP database s3://%s/%s", bucket, key)
+		}
+		database, err := ioutil.ReadAll(output.Body)
+		output.Body.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read GeoIP database s3://%s/%s", bucket, key)
+		}
+		databases = append(databases, database)
+	}
+	return New(databases...)
+}
+
+// Size returns the memory used by the databases in bytes, they are read (or mapped) entirely in memory
+func (e *Enricher) Size() int64 {
+	return e.size
+}
+
+// Enrich adds the countries, autonomous systems and geolocations of the public ip addresses of the event.
+// Addresses that are not found or cannot be decoded are skipped.
+func (e *Enricher) Enrich(event *parsers.PantherLog) {
+	for _, value := range event.PantherAnyIPAddresses.Values() {
+		ip := net.ParseIP(value)
+		if ip == nil || !isPublic(ip) {
+			continue
+		}
+		geolocation, found := e.lookup(ip)
+		if !found {
+			continue
+		}
+		ipAddress := value
+		geolocation.IP = &ipAddress
+		if geolocation.CountryCode != nil {
+			event.AppendAnyIPCountries(*geolocation.CountryCode)
+		}
+		if geolocation.ASN != nil {
+			event.AppendAnyIPASNs(strconv.FormatInt(*geolocation.ASN, 10))
+		}
+		event.PantherIPGeolocations = append(event.PantherIPGeolocations, *geolocation)
+	}
+}
+
+func (e *Enricher) lookup(ip net.IP) (*parsers.PantherIPGeolocation, bool) {
+	result := record{}
+	found := false
+	// look up in reverse order so that the first database overwrites the fields of the others
+	for i := len(e.databases) - 1; i >= 0; i-- {
+		_, ok, err := e.databases[i].LookupNetwork(ip, &result)
+		if err != nil {
+			return nil, false
+		}
+		found = found || ok
+	}
+	if !found {
+		return nil, false
+	}
+
+	geolocation := &parsers.PantherIPGeolocation{
+		CountryCode: nonEmpty(result.Country.ISOCode),
+		Country:     nonEmpty(result.Country.Names[namesLanguage]),
+		City:        nonEmpty(result.City.Names[namesLanguage]),
+		Latitude:    result.Location.Latitude,
+		Longitude:   result.Location.Longitude,
+	}
+	if result.AutonomousSystemNumber != 0 {
+		asn := int64(result.AutonomousSystemNumber)
+		geolocation.ASN = &asn
+		geolocation.ASOrganization = nonEmpty(result.AutonomousSystemOrganization)
+	}
+	return geolocation, true
+}
+
+func isPublic(ip net.IP) bool {
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func nonEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
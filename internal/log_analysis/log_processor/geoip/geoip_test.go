@@ -0,0 +1,156 @@
+package geoip
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+// the test databases have (made up) records for 81.2.69.0/24, 175.16.199.0/24 and 2001:480::/32 (City)
+// and 81.2.69.0/24, 1.128.0.0/11 and 2001:480::/32 (ASN)
+var testDatabases = []string{"testdata/GeoLite2-City-Test.mmdb", "testdata/GeoLite2-ASN-Test.mmdb"}
+
+func TestEnrich(t *testing.T) {
+	enricher, err := Open(testDatabases...)
+	require.NoError(t, err)
+
+	event := parsers.PantherLog{}
+	for _, ip := range []string{
+		"81.2.69.160",  // city and asn
+		"175.16.199.1", // city only
+		"1.128.0.1",    // asn only
+		"2001:480::10", // ipv6
+		"8.8.8.8",      // not found
+		"10.0.0.1",     // private
+		"::1",          // loopback
+	} {
+		event.AppendAnyIPAddress(ip)
+	}
+	enricher.Enrich(&event)
+
+	expectedCountries := parsers.NewPantherAnyString()
+	parsers.AppendAnyString(expectedCountries, "GB", "CN", "US")
+	require.Equal(t, expectedCountries, event.PantherAnyIPCountries)
+	expectedASNs := parsers.NewPantherAnyString()
+	parsers.AppendAnyString(expectedASNs, "20712", "1221", "7018")
+	require.Equal(t, expectedASNs, event.PantherAnyIPASNs)
+
+	// in the order of p_any_ip_addresses
+	require.Equal(t, []parsers.PantherIPGeolocation{
+		{
+			IP:             aws.String("1.128.0.1"),
+			ASN:            aws.Int64(1221),
+			ASOrganization: aws.String("Telstra Pty Ltd"),
+		},
+		{
+			IP:          aws.String("175.16.199.1"),
+			CountryCode: aws.String("CN"),
+			Country:     aws.String("China"),
+			City:        aws.String("Changchun"),
+			Latitude:    aws.Float64(43.88),
+			Longitude:   aws.Float64(125.3228),
+		},
+		{
+			IP:             aws.String("2001:480::10"),
+			CountryCode:    aws.String("US"),
+			Country:        aws.String("United States"),
+			City:           aws.String("San Diego"),
+			Latitude:       aws.Float64(32.7203),
+			Longitude:      aws.Float64(-117.1552),
+			ASN:            aws.Int64(7018),
+			ASOrganization: aws.String("AT&T Services"),
+		},
+		{
+			IP:             aws.String("81.2.69.160"),
+			CountryCode:    aws.String("GB"),
+			Country:        aws.String("United Kingdom"),
+			City:           aws.String("London"),
+			Latitude:       aws.Float64(51.5142),
+			Longitude:      aws.Float64(-0.0931),
+			ASN:            aws.Int64(20712),
+			ASOrganization: aws.String("Andrews & Arnold Ltd"),
+		},
+	}, event.PantherIPGeolocations)
+}
+
+func TestEnrichNoPublicIPs(t *testing.T) {
+	enricher, err := Open(testDatabases...)
+	require.NoError(t, err)
+
+	event := parsers.PantherLog{}
+	enricher.Enrich(&event) // no ip addresses
+	event.AppendAnyIPAddress("192.168.1.1")
+	enricher.Enrich(&event)
+	require.Nil(t, event.PantherAnyIPCountries)
+	require.Nil(t, event.PantherAnyIPASNs)
+	require.Nil(t, event.PantherIPGeolocations)
+}
+
+func TestLoadS3(t *testing.T) {
+	s3Mock := &testutils.S3Mock{}
+	var size int64
+	for _, path := range testDatabases {
+		database, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		size += int64(len(database))
+		s3Mock.On("GetObject", &s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String(path)}).
+			Return(&s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(database))}, nil).Once()
+	}
+
+	enricher, err := Load(s3Mock, "bucket", testDatabases)
+	require.NoError(t, err)
+	s3Mock.AssertExpectations(t)
+	require.Equal(t, size, enricher.Size())
+
+	// the same files are mapped
+	opened, err := Open(testDatabases...)
+	require.NoError(t, err)
+	require.Equal(t, size, opened.Size())
+
+	event := parsers.PantherLog{}
+	event.AppendAnyIPAddress("81.2.69.160")
+	enricher.Enrich(&event)
+	require.Len(t, event.PantherIPGeolocations, 1)
+	require.Equal(t, "GB", *event.PantherIPGeolocations[0].CountryCode)
+	require.Equal(t, int64(20712), *event.PantherIPGeolocations[0].ASN)
+}
+
+func TestLoadErrors(t *testing.T) {
+	s3Mock := &testutils.S3Mock{}
+	s3Mock.On("GetObject", mock.Anything).Return(&s3.GetObjectOutput{}, errors.New("access denied")).Once()
+	_, err := Load(s3Mock, "bucket", []string{"GeoLite2-City.mmdb"})
+	require.Error(t, err)
+
+	_, err = Load(nil, "", []string{"testdata/missing.mmdb"})
+	require.Error(t, err)
+
+	_, err = New([]byte("not a database"))
+	require.Error(t, err)
+}
@@ -25,24 +25,129 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"go.uber.org/zap"
 
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/geoip"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/redaction"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/threatintel"
 	"github.com/panther-labs/panther/pkg/lambdalogger"
 )
 
+const (
+	// the custom log types are reloaded at most this often, so running containers pick up new and updated types
+	customLogTypesRefreshInterval = 5 * time.Minute
+	// the threat intel indicators are reloaded at most this often, so running containers pick up changes
+	threatIntelRefreshInterval = 5 * time.Minute
+	// the redaction rules are reloaded at most this often, so running containers pick up changes
+	redactionRefreshInterval = 5 * time.Minute
+)
+
+var (
+	// when the custom log types were last loaded, zero until they are first loaded
+	customLogTypesLoadedAt time.Time
+	// set once the GeoIP databases have been loaded (or failed to), they are loaded once per container
+	geoIPLoaded bool
+	// when the threat intel indicators were last loaded (or failed to), zero before the first attempt
+	threatIntelLoadedAt time.Time
+	// when the redaction rules were last loaded, zero until they are first loaded
+	redactionLoadedAt time.Time
+)
+
 func main() {
 	common.Setup()
+	if err := destinations.EnableParquet(common.Config.ParquetLogTypes); err != nil {
+		panic(err)
+	}
 	lambda.Start(handle)
 }
 
 func handle(ctx context.Context, event events.SQSEvent) error {
 	lc, _ := lambdalogger.ConfigureGlobal(ctx, nil)
 	deadline, _ := ctx.Deadline()
+	if time.Since(customLogTypesLoadedAt) > customLogTypesRefreshInterval {
+		if err := loadCustomLogTypes(); err != nil {
+			return err
+		}
+	}
+	if !geoIPLoaded {
+		loadGeoIP()
+		geoIPLoaded = true
+	}
+	if time.Since(threatIntelLoadedAt) > threatIntelRefreshInterval {
+		loadThreatIntel()
+	}
+	if time.Since(redactionLoadedAt) > redactionRefreshInterval {
+		if err := loadRedaction(); err != nil {
+			return err
+		}
+	}
 	return process(lc, deadline, event)
 }
 
+// loadCustomLogTypes fails the invocation if the custom log types were never loaded, so the messages are retried,
+// otherwise data of custom log types would be dropped. The types registered before are kept until the next refresh.
+func loadCustomLogTypes() error {
+	if err := registry.LoadCustomLogTypes(common.LambdaClient); err != nil {
+		if customLogTypesLoadedAt.IsZero() {
+			return err
+		}
+		zap.L().Warn("failed to reload custom log types", zap.Error(err))
+	}
+	customLogTypesLoadedAt = time.Now()
+	return nil
+}
+
+// loadGeoIP enables the GeoIP enrichment if databases are configured, events are processed without it if they fail to load
+func loadGeoIP() {
+	if len(common.Config.GeoIPDatabases) == 0 {
+		return
+	}
+	enricher, err := geoip.Load(s3.New(common.Session), common.Config.GeoIPDatabaseBucket, common.Config.GeoIPDatabases)
+	if err != nil {
+		zap.L().Error("failed to load GeoIP databases, events are not enriched", zap.Error(err))
+		return
+	}
+	// the databases stay in memory, the output buffers must leave room for them
+	if err := destinations.ReserveMemory(enricher.Size()); err != nil {
+		zap.L().Error("GeoIP databases are too large, events are not enriched", zap.Error(err))
+		return
+	}
+	processor.EnableGeoIP(enricher)
+}
+
+// loadThreatIntel keeps the previous indicators if they fail to reload, events are processed without matching
+// until the first load succeeds, it is retried on the next refresh
+func loadThreatIntel() {
+	matcher, err := threatintel.Load(common.LambdaClient)
+	if err != nil {
+		zap.L().Error("failed to load threat intel indicators", zap.Error(err))
+	} else {
+		processor.EnableThreatIntel(matcher)
+	}
+	threatIntelLoadedAt = time.Now()
+}
+
+// loadRedaction fails the invocation if the rules were never loaded, events must not be stored without them,
+// otherwise the previous rules are kept until the next refresh
+func loadRedaction() error {
+	redactor, err := redaction.Load(common.LambdaClient)
+	if err != nil {
+		if redactionLoadedAt.IsZero() {
+			return err
+		}
+		zap.L().Warn("failed to reload redaction rules", zap.Error(err))
+	} else {
+		processor.EnableRedaction(redactor)
+	}
+	redactionLoadedAt = time.Now()
+	return nil
+}
+
 func process(lc *lambdacontext.LambdaContext, deadline time.Time, event events.SQSEvent) (err error) {
 	operation := common.OpLogManager.Start(lc.InvokedFunctionArn, common.OpLogLambdaServiceDim).WithMemUsed(lambdacontext.MemoryLimitInMB)
 
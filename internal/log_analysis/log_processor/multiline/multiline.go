@@ -0,0 +1,189 @@
+package multiline
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+)
+
+// Reader joins the lines of a stream into events (e.g., stack traces or pretty printed JSON)
+// as described by a source or log type, see sourcemodels.MultilineConfig
+type Reader struct {
+	stream       *bufio.Reader
+	start        *regexp.Regexp // nil if not set
+	continuation *regexp.Regexp // nil if not set
+	json         bool
+	maxEventSize int
+
+	event      strings.Builder
+	inJSON     bool   // the event is a JSON value that is joined until the braces are balanced
+	depth      int    // the nesting level of the JSON value
+	inString   bool   // the scan of the JSON value is inside a string
+	escaped    bool   // the scan of the JSON value is right after a backslash inside a string
+	overflow   bool   // the event reached the size limit, the rest of it is dropped
+	pending    string // a line that was read ahead and starts the next event
+	pendingErr error  // the error returned when the pending line was read
+	truncated  uint64
+}
+
+// NewReader returns a Reader of the events of the stream, an error if the patterns of the config do not compile
+func NewReader(stream *bufio.Reader, config *sourcemodels.MultilineConfig) (*Reader, error) {
+	reader := &Reader{
+		stream:       stream,
+		json:         aws.BoolValue(config.JSON),
+		maxEventSize: sourcemodels.DefaultMultilineMaxEventSize,
+	}
+	if config.MaxEventSize != nil {
+		reader.maxEventSize = *config.MaxEventSize
+	}
+	var err error
+	if config.StartPattern != nil {
+		if reader.start, err = regexp.Compile(*config.StartPattern); err != nil {
+			return nil, errors.Wrap(err, "invalid start pattern")
+		}
+	}
+	if config.ContinuationPattern != nil {
+		if reader.continuation, err = regexp.Compile(*config.ContinuationPattern); err != nil {
+			return nil, errors.Wrap(err, "invalid continuation pattern")
+		}
+	}
+	return reader, nil
+}
+
+// ReadEvent returns the next event, including the delimiters of its lines. Like bufio.Reader.ReadString(), it returns
+// the last event with io.EOF at the end of the stream.
+func (r *Reader) ReadEvent() (string, error) {
+	for {
+		line, err := r.readLine()
+		if line == "" && err != nil {
+			return r.flush(), err
+		}
+		if r.event.Len() > 0 && !r.continues(line) {
+			// the line starts a new event, it is returned by the next call
+			r.pending, r.pendingErr = line, err
+			return r.flush(), nil
+		}
+		r.append(line)
+		if err != nil || r.complete() {
+			return r.flush(), err
+		}
+	}
+}
+
+// Truncated returns the number of events that were larger than the size limit
+func (r *Reader) Truncated() uint64 {
+	return r.truncated
+}
+
+func (r *Reader) readLine() (string, error) {
+	if r.pending != "" {
+		line, err := r.pending, r.pendingErr
+		r.pending, r.pendingErr = "", nil
+		return line, err
+	}
+	return r.stream.ReadString(common.EventDelimiter)
+}
+
+// continues returns true if the line is part of the current event
+func (r *Reader) continues(line string) bool {
+	if r.inJSON {
+		return true
+	}
+	if r.json && startsJSON(line) {
+		return false
+	}
+	if r.continuation != nil && r.continuation.MatchString(line) {
+		return true
+	}
+	return r.start != nil && !r.start.MatchString(line)
+}
+
+// complete returns true if the event cannot have more lines
+func (r *Reader) complete() bool {
+	if r.inJSON {
+		return r.depth <= 0
+	}
+	// without patterns, lines that do not start a JSON value are events on their own
+	return r.start == nil && r.continuation == nil
+}
+
+func (r *Reader) append(line string) {
+	if r.event.Len() == 0 && r.json && startsJSON(line) {
+		r.inJSON = true
+	}
+	if r.inJSON {
+		r.scanJSON(line)
+	}
+	if r.overflow {
+		return
+	}
+	if r.event.Len() > 0 && r.event.Len()+len(line) > r.maxEventSize {
+		r.overflow = true
+		r.truncated++
+		return
+	}
+	r.event.WriteString(line)
+}
+
+// scanJSON tracks the nesting level of the JSON value, ignoring braces inside strings
+func (r *Reader) scanJSON(line string) {
+	for i := 0; i < len(line) && r.depth >= 0; i++ {
+		c := line[i]
+		switch {
+		case r.escaped:
+			r.escaped = false
+		case r.inString:
+			switch c {
+			case '\\':
+				r.escaped = true
+			case '"':
+				r.inString = false
+			}
+		case c == '"':
+			r.inString = true
+		case c == '{' || c == '[':
+			r.depth++
+		case c == '}' || c == ']':
+			r.depth--
+			if r.depth == 0 {
+				return // the rest of the line is not part of the value, the parsers reject it if it is not whitespace
+			}
+		}
+	}
+}
+
+func (r *Reader) flush() string {
+	event := r.event.String()
+	r.event.Reset()
+	r.inJSON, r.depth, r.inString, r.escaped, r.overflow = false, 0, false, false, false
+	return event
+}
+
+func startsJSON(line string) bool {
+	line = strings.TrimLeft(line, " \t\r")
+	return strings.HasPrefix(line, "{") || strings.HasPrefix(line, "[")
+}
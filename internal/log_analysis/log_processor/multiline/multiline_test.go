@@ -0,0 +1,119 @@
+package multiline
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
+)
+
+func readEvents(t *testing.T, data string, config *sourcemodels.MultilineConfig) ([]string, *Reader) {
+	reader, err := NewReader(bufio.NewReader(strings.NewReader(data)), config)
+	require.NoError(t, err)
+	var events []string
+	for {
+		event, err := reader.ReadEvent()
+		if event != "" {
+			events = append(events, event)
+		}
+		if err == io.EOF {
+			return events, reader
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestStartPattern(t *testing.T) {
+	data := "header\n2020-06-01 ERROR failed\n  at main.go:10\n  at main.go:20\n2020-06-01 INFO done\n2020-06-01 INFO exit"
+	events, _ := readEvents(t, data, &sourcemodels.MultilineConfig{StartPattern: aws.String(`^\d{4}-`)})
+	assert.Equal(t, []string{
+		"header\n",
+		"2020-06-01 ERROR failed\n  at main.go:10\n  at main.go:20\n",
+		"2020-06-01 INFO done\n",
+		"2020-06-01 INFO exit",
+	}, events)
+}
+
+func TestContinuationPattern(t *testing.T) {
+	data := "Exception in thread main\n\tat Main.run\n\tat Main.main\nINFO done\nINFO exit\n"
+	events, _ := readEvents(t, data, &sourcemodels.MultilineConfig{ContinuationPattern: aws.String(`^\s`)})
+	assert.Equal(t, []string{
+		"Exception in thread main\n\tat Main.run\n\tat Main.main\n",
+		"INFO done\n",
+		"INFO exit\n",
+	}, events)
+}
+
+func TestJSON(t *testing.T) {
+	data := `{
+  "msg": "braces in strings } are \" ignored {",
+  "nested": {"list": [1, 2]}
+}
+{"compact": true}
+plain line
+[
+  {"id": 1}
+]
+{"truncated": `
+	events, _ := readEvents(t, data, &sourcemodels.MultilineConfig{JSON: aws.Bool(true)})
+	assert.Equal(t, []string{
+		"{\n  \"msg\": \"braces in strings } are \\\" ignored {\",\n  \"nested\": {\"list\": [1, 2]}\n}\n",
+		"{\"compact\": true}\n",
+		"plain line\n",
+		"[\n  {\"id\": 1}\n]\n",
+		"{\"truncated\": ",
+	}, events)
+}
+
+func TestJSONWithStartPattern(t *testing.T) {
+	// a JSON value starts a new event even if it does not match the start pattern
+	data := "2020-06-01 request\n  headers\n{\n\"id\": 1\n}\n2020-06-01 response\n"
+	events, _ := readEvents(t, data, &sourcemodels.MultilineConfig{
+		StartPattern: aws.String(`^\d{4}-`),
+		JSON:         aws.Bool(true),
+	})
+	assert.Equal(t, []string{
+		"2020-06-01 request\n  headers\n",
+		"{\n\"id\": 1\n}\n",
+		"2020-06-01 response\n",
+	}, events)
+}
+
+func TestMaxEventSize(t *testing.T) {
+	data := "first\n line1\n line2\n line3\nsecond\n"
+	events, reader := readEvents(t, data, &sourcemodels.MultilineConfig{
+		ContinuationPattern: aws.String(`^\s`),
+		MaxEventSize:        aws.Int(16),
+	})
+	assert.Equal(t, []string{"first\n line1\n", "second\n"}, events)
+	assert.Equal(t, uint64(1), reader.Truncated())
+}
+
+func TestInvalidPattern(t *testing.T) {
+	_, err := NewReader(bufio.NewReader(strings.NewReader("")), &sourcemodels.MultilineConfig{StartPattern: aws.String(`(`)})
+	require.Error(t, err)
+}
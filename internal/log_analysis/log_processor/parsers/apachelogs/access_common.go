@@ -138,4 +138,5 @@ func (event *AccessCommon) updatePantherFields(p *parsers.PantherLog) {
 		// Handle cases where apache config has resolved addresses enabled
 		p.AppendAnyDomainNamePtrs(event.RemoteHostIPAddress)
 	}
+	p.AppendAnyUsernamePtrs(event.UserID)
 }
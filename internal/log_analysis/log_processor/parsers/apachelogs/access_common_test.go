@@ -49,5 +49,6 @@ func TestAccessCommonParser(t *testing.T) {
 	event.PantherLogType = aws.String(TypeAccessCommon)
 	event.SetEvent(&event)
 	event.AppendAnyIPAddress("127.0.0.1")
+	event.AppendAnyUsernames("frank")
 	testutil.CheckPantherParser(t, log, NewAccessCommonParser(), &event.PantherLog)
 }
@@ -109,15 +109,8 @@ func (p *ALBParser) Parse(log string) ([]*parsers.PantherLog, error) {
 		return nil, err
 	}
 
-	var clientIPPort, targetIPPort []string
-	clientIPPort = strings.Split(record[3], ":")
-	if len(clientIPPort) != 2 {
-		clientIPPort = []string{record[3], "-"}
-	}
-	targetIPPort = strings.Split(record[4], ":")
-	if len(targetIPPort) != 2 {
-		targetIPPort = []string{record[4], "-"}
-	}
+	clientIPPort := splitIPPort(record[3])
+	targetIPPort := splitIPPort(record[4])
 
 	requestItems := strings.Split(record[12], " ")
 
@@ -178,3 +171,13 @@ func (event *ALB) updatePantherFields(p *ALBParser) {
 	event.AppendAnyDomainNamePtrs(event.DomainName)
 	event.AppendAnyAWSARNPtrs(event.ChosenCertARN, event.TargetGroupARN)
 }
+
+// splitIPPort splits an `ip:port` field on the last colon, so that IPv6 addresses (with or without brackets) are kept whole.
+// The port is "-" if the field has no port.
+func splitIPPort(value string) []string {
+	separator := strings.LastIndexByte(value, ':')
+	if separator < 0 {
+		return []string{value, "-"}
+	}
+	return []string{strings.Trim(value[:separator], "[]"), value[separator+1:]}
+}
@@ -235,6 +235,50 @@ func TestHTTPSNoTarget(t *testing.T) {
 	checkALBLog(t, log, expectedEvent)
 }
 
+func TestHTTPLogIPv6Client(t *testing.T) {
+	log := "http 2018-08-26T14:17:23.186641Z app/my-loadbalancer/50dc6c495c0c9188 2001:db8:1f18:4d2e::7:2817 " +
+		"10.0.0.1:80 0.000 0.001 0.000 200 200 34 366 \"GET http://www.example.com:80/ HTTP/1.1\" " +
+		"\"curl/7.46.0\" - - arn:aws:elasticloadbalancing:us-east-2:123456789012:targetgroup/my-targets/73e2d6bc24d8a067 " +
+		"\"Root=1-58337262-36d228ad5d99923122bbe354\" \"-\" \"-\" 0 2018-08-26T14:17:23.186641Z \"forward\" \"-\" \"-\""
+
+	expectedTime := time.Unix(1535293043, 186641000).UTC()
+
+	expectedEvent := &ALB{
+		Type:                   aws.String("http"),
+		Timestamp:              (*timestamp.RFC3339)(&expectedTime),
+		ELB:                    aws.String("app/my-loadbalancer/50dc6c495c0c9188"),
+		ClientIP:               aws.String("2001:db8:1f18:4d2e::7"),
+		ClientPort:             aws.Int(2817),
+		TargetIP:               aws.String("10.0.0.1"),
+		TargetPort:             aws.Int(80),
+		RequestProcessingTime:  aws.Float64(0.0),
+		TargetProcessingTime:   aws.Float64(0.001),
+		ResponseProcessingTime: aws.Float64(0.000),
+		ELBStatusCode:          aws.Int(200),
+		TargetStatusCode:       aws.Int(200),
+		ReceivedBytes:          aws.Int(34),
+		SentBytes:              aws.Int(366),
+		RequestHTTPMethod:      aws.String("GET"),
+		RequestHTTPVersion:     aws.String("HTTP/1.1"),
+		RequestURL:             aws.String("http://www.example.com:80/"),
+		UserAgent:              aws.String("curl/7.46.0"),
+		TargetGroupARN:         aws.String("arn:aws:elasticloadbalancing:us-east-2:123456789012:targetgroup/my-targets/73e2d6bc24d8a067"),
+		TraceID:                aws.String("Root=1-58337262-36d228ad5d99923122bbe354"),
+		MatchedRulePriority:    aws.Int(0),
+		RequestCreationTime:    (*timestamp.RFC3339)(&expectedTime),
+		ActionsExecuted:        []string{"forward"},
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("AWS.ALB")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("2001:db8:1f18:4d2e::7")
+	expectedEvent.AppendAnyIPAddress("10.0.0.1")
+	expectedEvent.AppendAnyAWSARNs("arn:aws:elasticloadbalancing:us-east-2:123456789012:targetgroup/my-targets/73e2d6bc24d8a067")
+
+	checkALBLog(t, log, expectedEvent)
+}
+
 func TestAlbLogType(t *testing.T) {
 	parser := &ALBParser{}
 	require.Equal(t, "AWS.ALB", parser.LogType())
@@ -0,0 +1,147 @@
+package awslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/csvstream"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var ClassicELBDesc = `Classic Load Balancer access logs of the requests sent to your Classic Load Balancer.
+Reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/classic/access-log-collection.html`
+
+const (
+	classicELBMinNumberOfColumns = 15
+)
+
+// nolint:lll
+type ClassicELB struct {
+	Timestamp              *timestamp.RFC3339 `json:"timestamp,omitempty" validate:"required" description:"The time when the load balancer received the request from the client (UTC)."`
+	ELB                    *string            `json:"elb,omitempty" validate:"required" description:"The name of the load balancer."`
+	ClientIP               *string            `json:"clientIp,omitempty" description:"The IP address of the requesting client."`
+	ClientPort             *int               `json:"clientPort,omitempty" description:"The port of the requesting client."`
+	BackendIP              *string            `json:"backendIp,omitempty" description:"The IP address of the registered instance that processed this request. If the load balancer can't send the request to a registered instance, or if the instance closes the connection before a response can be sent, this value is set to NULL."`
+	BackendPort            *int               `json:"backendPort,omitempty" description:"The port of the registered instance that processed this request."`
+	RequestProcessingTime  *float64           `json:"requestProcessingTime,omitempty" description:"[HTTP listener] The total time elapsed, in seconds, from the time the load balancer received the request until the time it sent it to a registered instance. [TCP listener] The total time elapsed, in seconds, from the time the load balancer accepted a TCP/SSL connection from a client to the time the load balancer sends the first byte of data to a registered instance. This value is set to -1 if the load balancer can't dispatch the request to a registered instance."`
+	BackendProcessingTime  *float64           `json:"backendProcessingTime,omitempty" description:"[HTTP listener] The total time elapsed, in seconds, from the time the load balancer sent the request to a registered instance until the instance started to send the response headers. [TCP listener] The total time elapsed, in seconds, for the load balancer to successfully establish a connection to a registered instance. This value is set to -1 if the load balancer can't dispatch the request to a registered instance."`
+	ResponseProcessingTime *float64           `json:"responseProcessingTime,omitempty" description:"[HTTP listener] The total time elapsed (in seconds) from the time the load balancer received the response header from the registered instance until it started to send the response to the client. [TCP listener] The total time elapsed, in seconds, from the time the load balancer received the first byte from the registered instance until it started to send the response to the client. This value is set to -1 if the load balancer can't dispatch the request to a registered instance."`
+	ELBStatusCode          *int               `json:"elbStatusCode,omitempty" description:"[HTTP listener] The status code of the response from the load balancer."`
+	BackendStatusCode      *int               `json:"backendStatusCode,omitempty" description:"[HTTP listener] The status code of the response from the registered instance."`
+	ReceivedBytes          *int               `json:"receivedBytes,omitempty" description:"The size of the request, in bytes, received from the client (requester). [HTTP listener] The value includes the request body but not the headers. [TCP listener] The value includes the request body and the headers."`
+	SentBytes              *int               `json:"sentBytes,omitempty" description:"The size of the response, in bytes, sent to the client (requester). [HTTP listener] The value includes the response body but not the headers. [TCP listener] The value includes the request body and the headers."`
+	RequestHTTPMethod      *string            `json:"requestHttpMethod,omitempty" description:"[HTTP listener] The HTTP method parsed from the request. This value is set to NULL for TCP listeners."`
+	RequestURL             *string            `json:"requestUrl,omitempty" description:"[HTTP listener] The HTTP URL parsed from the request. This value is set to NULL for TCP listeners."`
+	RequestHTTPVersion     *string            `json:"requestHttpVersion,omitempty" description:"[HTTP listener] The HTTP version parsed from the request. This value is set to NULL for TCP listeners."`
+	UserAgent              *string            `json:"userAgent,omitempty" description:"[HTTP/HTTPS listener] A User-Agent string that identifies the client that originated the request."`
+	SSLCipher              *string            `json:"sslCipher,omitempty" description:"[HTTPS/SSL listener] The SSL cipher. This value is set to NULL if the listener is not an HTTPS/SSL listener."`
+	SSLProtocol            *string            `json:"sslProtocol,omitempty" description:"[HTTPS/SSL listener] The SSL protocol. This value is set to NULL if the listener is not an HTTPS/SSL listener."`
+
+	// NOTE: added to end of struct to allow expansion later
+	AWSPantherLog
+}
+
+// ClassicELBParser parses AWS Classic Load Balancer logs
+type ClassicELBParser struct {
+	CSVReader *csvstream.StreamingCSVReader
+}
+
+var _ parsers.LogParser = (*ClassicELBParser)(nil)
+
+func (p *ClassicELBParser) New() parsers.LogParser {
+	reader := csvstream.NewStreamingCSVReader()
+	// non-default settings
+	reader.CVSReader.Comma = ' '
+	return &ClassicELBParser{
+		CSVReader: reader,
+	}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *ClassicELBParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	record, err := p.CSVReader.Parse(log)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(record) < classicELBMinNumberOfColumns {
+		return nil, errors.New("invalid number of columns")
+	}
+
+	timeStamp, err := timestamp.Parse(time.RFC3339Nano, record[0])
+	if err != nil {
+		return nil, err
+	}
+
+	clientIPPort := splitIPPort(record[2])
+	backendIPPort := splitIPPort(record[3])
+
+	// TCP listeners log the request as "- - - "
+	requestItems := strings.Fields(record[11])
+
+	if len(requestItems) != 3 {
+		return nil, errors.New("invalid record")
+	}
+
+	event := &ClassicELB{
+		Timestamp:              &timeStamp,
+		ELB:                    parsers.CsvStringToPointer(record[1]),
+		ClientIP:               parsers.CsvStringToPointer(clientIPPort[0]),
+		ClientPort:             parsers.CsvStringToIntPointer(clientIPPort[1]),
+		BackendIP:              parsers.CsvStringToPointer(backendIPPort[0]),
+		BackendPort:            parsers.CsvStringToIntPointer(backendIPPort[1]),
+		RequestProcessingTime:  parsers.CsvStringToFloat64Pointer(record[4]),
+		BackendProcessingTime:  parsers.CsvStringToFloat64Pointer(record[5]),
+		ResponseProcessingTime: parsers.CsvStringToFloat64Pointer(record[6]),
+		ELBStatusCode:          parsers.CsvStringToIntPointer(record[7]),
+		BackendStatusCode:      parsers.CsvStringToIntPointer(record[8]),
+		ReceivedBytes:          parsers.CsvStringToIntPointer(record[9]),
+		SentBytes:              parsers.CsvStringToIntPointer(record[10]),
+		RequestHTTPMethod:      parsers.CsvStringToPointer(requestItems[0]),
+		RequestURL:             parsers.CsvStringToPointer(requestItems[1]),
+		RequestHTTPVersion:     parsers.CsvStringToPointer(requestItems[2]),
+		UserAgent:              parsers.CsvStringToPointer(record[12]),
+		SSLCipher:              parsers.CsvStringToPointer(record[13]),
+		SSLProtocol:            parsers.CsvStringToPointer(record[14]),
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *ClassicELBParser) LogType() string {
+	return "AWS.ClassicELB"
+}
+
+func (event *ClassicELB) updatePantherFields(p *ClassicELBParser) {
+	event.SetCoreFields(p.LogType(), event.Timestamp, event)
+	event.AppendAnyIPAddressPtr(event.ClientIP)
+	event.AppendAnyIPAddressPtr(event.BackendIP)
+}
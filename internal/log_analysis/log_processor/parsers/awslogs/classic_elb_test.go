@@ -0,0 +1,106 @@
+package awslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestClassicELBHTTPLog(t *testing.T) {
+	log := `2015-05-13T23:39:43.945958Z my-loadbalancer 192.168.131.39:2817 10.0.0.1:80 0.000073 0.001048 0.000057 200 200 0 29 ` +
+		`"GET http://www.example.com:80/ HTTP/1.1" "curl/7.38.0" - -`
+
+	expectedTime := time.Unix(1431560383, 945958000).UTC()
+
+	expectedEvent := &ClassicELB{
+		Timestamp:              (*timestamp.RFC3339)(&expectedTime),
+		ELB:                    aws.String("my-loadbalancer"),
+		ClientIP:               aws.String("192.168.131.39"),
+		ClientPort:             aws.Int(2817),
+		BackendIP:              aws.String("10.0.0.1"),
+		BackendPort:            aws.Int(80),
+		RequestProcessingTime:  aws.Float64(0.000073),
+		BackendProcessingTime:  aws.Float64(0.001048),
+		ResponseProcessingTime: aws.Float64(0.000057),
+		ELBStatusCode:          aws.Int(200),
+		BackendStatusCode:      aws.Int(200),
+		ReceivedBytes:          aws.Int(0),
+		SentBytes:              aws.Int(29),
+		RequestHTTPMethod:      aws.String("GET"),
+		RequestURL:             aws.String("http://www.example.com:80/"),
+		RequestHTTPVersion:     aws.String("HTTP/1.1"),
+		UserAgent:              aws.String("curl/7.38.0"),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("AWS.ClassicELB")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("192.168.131.39")
+	expectedEvent.AppendAnyIPAddress("10.0.0.1")
+
+	checkClassicELBLog(t, log, expectedEvent)
+}
+
+func TestClassicELBTCPLog(t *testing.T) {
+	log := `2015-05-13T23:39:43.945958Z my-loadbalancer 192.168.131.39:2817 10.0.0.1:80 0.001069 0.000028 0.000041 - - 82 305 ` +
+		`"- - - " "-" - -`
+
+	expectedTime := time.Unix(1431560383, 945958000).UTC()
+
+	expectedEvent := &ClassicELB{
+		Timestamp:              (*timestamp.RFC3339)(&expectedTime),
+		ELB:                    aws.String("my-loadbalancer"),
+		ClientIP:               aws.String("192.168.131.39"),
+		ClientPort:             aws.Int(2817),
+		BackendIP:              aws.String("10.0.0.1"),
+		BackendPort:            aws.Int(80),
+		RequestProcessingTime:  aws.Float64(0.001069),
+		BackendProcessingTime:  aws.Float64(0.000028),
+		ResponseProcessingTime: aws.Float64(0.000041),
+		ReceivedBytes:          aws.Int(82),
+		SentBytes:              aws.Int(305),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("AWS.ClassicELB")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("192.168.131.39")
+	expectedEvent.AppendAnyIPAddress("10.0.0.1")
+
+	checkClassicELBLog(t, log, expectedEvent)
+}
+
+func TestClassicELBLogType(t *testing.T) {
+	parser := &ClassicELBParser{}
+	require.Equal(t, "AWS.ClassicELB", parser.LogType())
+}
+
+func checkClassicELBLog(t *testing.T, log string, expectedEvent *ClassicELB) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := (&ClassicELBParser{}).New() // important to call New() to initialize reader
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
@@ -0,0 +1,275 @@
+package awslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var CloudFrontAccessDesc = `CloudFrontAccess is a CloudFront standard access log, with the columns listed in the #Fields header.
+Reference: https://docs.aws.amazon.com/AmazonCloudFront/latest/DeveloperGuide/AccessLogs.html#LogFileFormat`
+
+// nolint:lll
+type CloudFrontAccess struct {
+	Timestamp              *timestamp.RFC3339 `json:"timestamp,omitempty" validate:"required" description:"The date and time on which the event occurred (UTC)."`
+	EdgeLocation           *string            `json:"edgeLocation,omitempty" description:"The edge location that served the request. Each edge location is identified by a three-letter code and an arbitrarily assigned number."`
+	SentBytes              *int               `json:"sentBytes,omitempty" description:"The total number of bytes that CloudFront served to the viewer in response to the request, including headers."`
+	ClientIP               *string            `json:"clientIp,omitempty" description:"The IP address of the viewer that made the request."`
+	Method                 *string            `json:"method,omitempty" description:"The HTTP request method."`
+	Host                   *string            `json:"host,omitempty" description:"The domain name of the CloudFront distribution."`
+	URIStem                *string            `json:"uriStem,omitempty" description:"The portion of the URI that identifies the path and object."`
+	Status                 *int               `json:"status,omitempty" description:"The HTTP status code, or 000 if the viewer closed the connection before CloudFront could respond."`
+	Referrer               *string            `json:"referrer,omitempty" description:"The name of the domain that originated the request."`
+	UserAgent              *string            `json:"userAgent,omitempty" description:"The value of the User-Agent header in the request."`
+	URIQuery               *string            `json:"uriQuery,omitempty" description:"The query string portion of the URI, if any."`
+	Cookie                 *string            `json:"cookie,omitempty" description:"The cookie header in the request, including name-value pairs and the associated attributes."`
+	EdgeResultType         *string            `json:"edgeResultType,omitempty" description:"How CloudFront classifies the response after the last byte left the edge location (e.g. Hit, RefreshHit, Miss, LimitExceeded, CapacityExceeded, Error, Redirect)."`
+	EdgeRequestID          *string            `json:"edgeRequestId,omitempty" validate:"required" description:"An encrypted string that uniquely identifies a request."`
+	HostHeader             *string            `json:"hostHeader,omitempty" description:"The value that the viewer included in the Host header for this request."`
+	Protocol               *string            `json:"protocol,omitempty" description:"The protocol of the viewer request (http, https, ws or wss)."`
+	ReceivedBytes          *int               `json:"receivedBytes,omitempty" description:"The number of bytes of data that the viewer included in the request, including headers."`
+	TimeTaken              *float64           `json:"timeTaken,omitempty" description:"The number of seconds between the time a CloudFront edge server receives a viewer's request and the time it writes the last byte of the response."`
+	ForwardedFor           *string            `json:"forwardedFor,omitempty" description:"If the viewer used an HTTP proxy or a load balancer to send the request, the IP address of the viewer; otherwise null."`
+	SSLProtocol            *string            `json:"sslProtocol,omitempty" description:"The SSL/TLS protocol that the viewer and server negotiated for transmitting the request and response."`
+	SSLCipher              *string            `json:"sslCipher,omitempty" description:"The SSL/TLS cipher that the viewer and server negotiated for encrypting the request and response."`
+	EdgeResponseResultType *string            `json:"edgeResponseResultType,omitempty" description:"How CloudFront classified the response just before returning the response to the viewer."`
+	ProtocolVersion        *string            `json:"protocolVersion,omitempty" description:"The HTTP version that the viewer specified in the request."`
+	FLEStatus              *string            `json:"fleStatus,omitempty" description:"When field-level encryption is configured for a distribution, a code that indicates whether the request body was successfully processed."`
+	FLEEncryptedFields     *int               `json:"fleEncryptedFields,omitempty" description:"The number of fields that CloudFront encrypted and forwarded to the origin."`
+	ClientPort             *int               `json:"clientPort,omitempty" description:"The port number of the request from the viewer."`
+	TimeToFirstByte        *float64           `json:"timeToFirstByte,omitempty" description:"The number of seconds between receiving the request and writing the first byte of the response, as measured on the server."`
+	EdgeDetailedResultType *string            `json:"edgeDetailedResultType,omitempty" description:"A more detailed result type than edgeResultType for errors and origin shield requests."`
+	ContentType            *string            `json:"contentType,omitempty" description:"The value of the HTTP Content-Type header of the response."`
+	ContentLength          *int               `json:"contentLength,omitempty" description:"The value of the HTTP Content-Length header of the response."`
+	RangeStart             *int               `json:"rangeStart,omitempty" description:"When the response contains the HTTP Content-Range header, the range start value."`
+	RangeEnd               *int               `json:"rangeEnd,omitempty" description:"When the response contains the HTTP Content-Range header, the range end value."`
+
+	// NOTE: added to end of struct to allow expansion later
+	AWSPantherLog
+}
+
+// CloudFrontAccessParser parses CloudFront standard access logs
+type CloudFrontAccessParser struct {
+	columnMap map[int]string // column position to field name, read from the #Fields header
+}
+
+var _ parsers.LogParser = (*CloudFrontAccessParser)(nil)
+
+func (p *CloudFrontAccessParser) New() parsers.LogParser {
+	return &CloudFrontAccessParser{}
+}
+
+const (
+	cloudFrontVersionHeaderPrefix = "#Version:"
+	cloudFrontFieldsHeaderPrefix  = "#Fields:"
+
+	cloudFrontDate                   = "date"
+	cloudFrontTime                   = "time"
+	cloudFrontEdgeLocation           = "x-edge-location"
+	cloudFrontSentBytes              = "sc-bytes"
+	cloudFrontClientIP               = "c-ip"
+	cloudFrontMethod                 = "cs-method"
+	cloudFrontHost                   = "cs(Host)"
+	cloudFrontURIStem                = "cs-uri-stem"
+	cloudFrontStatus                 = "sc-status"
+	cloudFrontReferrer               = "cs(Referer)"
+	cloudFrontUserAgent              = "cs(User-Agent)"
+	cloudFrontURIQuery               = "cs-uri-query"
+	cloudFrontCookie                 = "cs(Cookie)"
+	cloudFrontEdgeResultType         = "x-edge-result-type"
+	cloudFrontEdgeRequestID          = "x-edge-request-id"
+	cloudFrontHostHeader             = "x-host-header"
+	cloudFrontProtocol               = "cs-protocol"
+	cloudFrontReceivedBytes          = "cs-bytes"
+	cloudFrontTimeTaken              = "time-taken"
+	cloudFrontForwardedFor           = "x-forwarded-for"
+	cloudFrontSSLProtocol            = "ssl-protocol"
+	cloudFrontSSLCipher              = "ssl-cipher"
+	cloudFrontEdgeResponseResultType = "x-edge-response-result-type"
+	cloudFrontProtocolVersion        = "cs-protocol-version"
+	cloudFrontFLEStatus              = "fle-status"
+	cloudFrontFLEEncryptedFields     = "fle-encrypted-fields"
+	cloudFrontClientPort             = "c-port"
+	cloudFrontTimeToFirstByte        = "time-to-first-byte"
+	cloudFrontEdgeDetailedResultType = "x-edge-detailed-result-type"
+	cloudFrontContentType            = "sc-content-type"
+	cloudFrontContentLength          = "sc-content-len"
+	cloudFrontRangeStart             = "sc-range-start"
+	cloudFrontRangeEnd               = "sc-range-end"
+)
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *CloudFrontAccessParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	if strings.HasPrefix(log, cloudFrontVersionHeaderPrefix) {
+		if p.columnMap != nil {
+			return nil, errors.New("unexpected header")
+		}
+		return []*parsers.PantherLog{}, nil
+	}
+	if strings.HasPrefix(log, cloudFrontFieldsHeaderPrefix) {
+		if !p.readFieldsHeader(log) {
+			return nil, errors.New("invalid header")
+		}
+		return []*parsers.PantherLog{}, nil
+	}
+	if p.columnMap == nil { // the #Fields header must come before any record
+		return nil, errors.New("invalid header")
+	}
+
+	columns := strings.Split(strings.TrimRight(log, "\r\n"), "\t")
+	if len(columns) < len(p.columnMap) {
+		return nil, errors.New("invalid number of columns")
+	}
+
+	event, err := p.populateEvent(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *CloudFrontAccessParser) LogType() string {
+	return "AWS.CloudFrontAccess"
+}
+
+// readFieldsHeader sets up the column map if the header lists the fields of a CloudFront log
+func (p *CloudFrontAccessParser) readFieldsHeader(log string) bool {
+	fields := strings.Fields(strings.TrimPrefix(log, cloudFrontFieldsHeaderPrefix))
+	columnMap := make(map[int]string, len(fields))
+	required := map[string]bool{cloudFrontDate: false, cloudFrontTime: false, cloudFrontEdgeLocation: false, cloudFrontEdgeRequestID: false}
+	for i, field := range fields {
+		columnMap[i] = field
+		if _, ok := required[field]; ok {
+			required[field] = true
+		}
+	}
+	for _, found := range required {
+		if !found {
+			return false
+		}
+	}
+	p.columnMap = columnMap
+	return true
+}
+
+func (p *CloudFrontAccessParser) populateEvent(columns []string) (*CloudFrontAccess, error) {
+	event := &CloudFrontAccess{}
+	var date, time string
+
+	for i, value := range columns {
+		switch p.columnMap[i] {
+		case cloudFrontDate:
+			date = value
+		case cloudFrontTime:
+			time = value
+		case cloudFrontEdgeLocation:
+			event.EdgeLocation = parsers.CsvStringToPointer(value)
+		case cloudFrontSentBytes:
+			event.SentBytes = parsers.CsvStringToIntPointer(value)
+		case cloudFrontClientIP:
+			event.ClientIP = parsers.CsvStringToPointer(value)
+		case cloudFrontMethod:
+			event.Method = parsers.CsvStringToPointer(value)
+		case cloudFrontHost:
+			event.Host = parsers.CsvStringToPointer(value)
+		case cloudFrontURIStem:
+			event.URIStem = parsers.CsvStringToPointer(value)
+		case cloudFrontStatus:
+			event.Status = parsers.CsvStringToIntPointer(value)
+		case cloudFrontReferrer:
+			event.Referrer = parsers.CsvStringToPointer(value)
+		case cloudFrontUserAgent:
+			event.UserAgent = parsers.CsvStringToPointer(value)
+		case cloudFrontURIQuery:
+			event.URIQuery = parsers.CsvStringToPointer(value)
+		case cloudFrontCookie:
+			event.Cookie = parsers.CsvStringToPointer(value)
+		case cloudFrontEdgeResultType:
+			event.EdgeResultType = parsers.CsvStringToPointer(value)
+		case cloudFrontEdgeRequestID:
+			event.EdgeRequestID = parsers.CsvStringToPointer(value)
+		case cloudFrontHostHeader:
+			event.HostHeader = parsers.CsvStringToPointer(value)
+		case cloudFrontProtocol:
+			event.Protocol = parsers.CsvStringToPointer(value)
+		case cloudFrontReceivedBytes:
+			event.ReceivedBytes = parsers.CsvStringToIntPointer(value)
+		case cloudFrontTimeTaken:
+			event.TimeTaken = parsers.CsvStringToFloat64Pointer(value)
+		case cloudFrontForwardedFor:
+			event.ForwardedFor = parsers.CsvStringToPointer(value)
+		case cloudFrontSSLProtocol:
+			event.SSLProtocol = parsers.CsvStringToPointer(value)
+		case cloudFrontSSLCipher:
+			event.SSLCipher = parsers.CsvStringToPointer(value)
+		case cloudFrontEdgeResponseResultType:
+			event.EdgeResponseResultType = parsers.CsvStringToPointer(value)
+		case cloudFrontProtocolVersion:
+			event.ProtocolVersion = parsers.CsvStringToPointer(value)
+		case cloudFrontFLEStatus:
+			event.FLEStatus = parsers.CsvStringToPointer(value)
+		case cloudFrontFLEEncryptedFields:
+			event.FLEEncryptedFields = parsers.CsvStringToIntPointer(value)
+		case cloudFrontClientPort:
+			event.ClientPort = parsers.CsvStringToIntPointer(value)
+		case cloudFrontTimeToFirstByte:
+			event.TimeToFirstByte = parsers.CsvStringToFloat64Pointer(value)
+		case cloudFrontEdgeDetailedResultType:
+			event.EdgeDetailedResultType = parsers.CsvStringToPointer(value)
+		case cloudFrontContentType:
+			event.ContentType = parsers.CsvStringToPointer(value)
+		case cloudFrontContentLength:
+			event.ContentLength = parsers.CsvStringToIntPointer(value)
+		case cloudFrontRangeStart:
+			event.RangeStart = parsers.CsvStringToIntPointer(value)
+		case cloudFrontRangeEnd:
+			event.RangeEnd = parsers.CsvStringToIntPointer(value)
+		}
+	}
+
+	eventTime, err := timestamp.Parse("2006-01-02 15:04:05", date+" "+time)
+	if err != nil {
+		return nil, err
+	}
+	event.Timestamp = &eventTime
+	return event, nil
+}
+
+func (event *CloudFrontAccess) updatePantherFields(p *CloudFrontAccessParser) {
+	event.SetCoreFields(p.LogType(), event.Timestamp, event)
+	event.AppendAnyIPAddressPtr(event.ClientIP)
+	event.AppendAnyIPAddressInFieldPtr(event.ForwardedFor)
+	event.AppendAnyDomainNamePtrs(event.Host)
+	if !event.AppendAnyIPAddressPtr(event.HostHeader) {
+		event.AppendAnyDomainNamePtrs(event.HostHeader)
+	}
+}
@@ -0,0 +1,123 @@
+package awslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+const (
+	cloudFrontVersionHeader = "#Version: 1.0"
+	cloudFrontFieldsHeader  = "#Fields: date time x-edge-location sc-bytes c-ip cs-method cs(Host) cs-uri-stem sc-status cs(Referer) cs(User-Agent) cs-uri-query cs(Cookie) x-edge-result-type x-edge-request-id x-host-header cs-protocol cs-bytes time-taken x-forwarded-for ssl-protocol ssl-cipher x-edge-response-result-type cs-protocol-version fle-status fle-encrypted-fields c-port time-to-first-byte x-edge-detailed-result-type sc-content-type sc-content-len sc-range-start sc-range-end" // nolint:lll
+)
+
+func TestCloudFrontAccessLog(t *testing.T) {
+	log := "2019-12-04\t21:02:31\tLAX1\t392\t192.0.2.100\tGET\td111111abcdef8.cloudfront.net\t/index.html\t200\t-\t" +
+		"Mozilla/5.0%20(Windows%20NT%2010.0;%20Win64;%20x64)\t-\t-\tHit\tSOX4xwn4XV6Q4rgb7XiVGOHms_BGlTAC4KyHmureZmBNrjGdRLiNIQ==\t" +
+		"d111111abcdef8.cloudfront.net\thttps\t23\t0.001\t198.51.100.10\tTLSv1.2\tECDHE-RSA-AES128-GCM-SHA256\tHit\tHTTP/2.0\t-\t-\t" +
+		"11040\t0.001\tHit\ttext/html\t78\t-\t-"
+
+	expectedTime := time.Date(2019, 12, 4, 21, 2, 31, 0, time.UTC)
+
+	expectedEvent := &CloudFrontAccess{
+		Timestamp:              (*timestamp.RFC3339)(&expectedTime),
+		EdgeLocation:           aws.String("LAX1"),
+		SentBytes:              aws.Int(392),
+		ClientIP:               aws.String("192.0.2.100"),
+		Method:                 aws.String("GET"),
+		Host:                   aws.String("d111111abcdef8.cloudfront.net"),
+		URIStem:                aws.String("/index.html"),
+		Status:                 aws.Int(200),
+		UserAgent:              aws.String("Mozilla/5.0%20(Windows%20NT%2010.0;%20Win64;%20x64)"),
+		EdgeResultType:         aws.String("Hit"),
+		EdgeRequestID:          aws.String("SOX4xwn4XV6Q4rgb7XiVGOHms_BGlTAC4KyHmureZmBNrjGdRLiNIQ=="),
+		HostHeader:             aws.String("d111111abcdef8.cloudfront.net"),
+		Protocol:               aws.String("https"),
+		ReceivedBytes:          aws.Int(23),
+		TimeTaken:              aws.Float64(0.001),
+		ForwardedFor:           aws.String("198.51.100.10"),
+		SSLProtocol:            aws.String("TLSv1.2"),
+		SSLCipher:              aws.String("ECDHE-RSA-AES128-GCM-SHA256"),
+		EdgeResponseResultType: aws.String("Hit"),
+		ProtocolVersion:        aws.String("HTTP/2.0"),
+		ClientPort:             aws.Int(11040),
+		TimeToFirstByte:        aws.Float64(0.001),
+		EdgeDetailedResultType: aws.String("Hit"),
+		ContentType:            aws.String("text/html"),
+		ContentLength:          aws.Int(78),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("AWS.CloudFrontAccess")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("192.0.2.100")
+	expectedEvent.AppendAnyIPAddress("198.51.100.10")
+	expectedEvent.AppendAnyDomainNames("d111111abcdef8.cloudfront.net")
+
+	checkCloudFrontAccessLog(t, log, expectedEvent)
+}
+
+func TestCloudFrontAccessLogHeader(t *testing.T) {
+	parser := (&CloudFrontAccessParser{}).New()
+	events, err := parser.Parse(cloudFrontVersionHeader)
+	require.NoError(t, err)
+	require.Empty(t, events)
+	events, err = parser.Parse(cloudFrontFieldsHeader)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestCloudFrontAccessLogNoHeader(t *testing.T) {
+	parser := (&CloudFrontAccessParser{}).New()
+	events, err := parser.Parse("2019-12-04\t21:02:31\tLAX1")
+	require.Error(t, err)
+	require.Nil(t, events)
+}
+
+func TestCloudFrontAccessLogInvalidHeader(t *testing.T) {
+	parser := (&CloudFrontAccessParser{}).New()
+	events, err := parser.Parse("#Fields: date time sc-bytes")
+	require.Error(t, err)
+	require.Nil(t, events)
+}
+
+func TestCloudFrontAccessLogType(t *testing.T) {
+	parser := &CloudFrontAccessParser{}
+	require.Equal(t, "AWS.CloudFrontAccess", parser.LogType())
+}
+
+func checkCloudFrontAccessLog(t *testing.T, log string, expectedEvent *CloudFrontAccess) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := (&CloudFrontAccessParser{}).New()
+	events, err := parser.Parse(cloudFrontVersionHeader)
+	require.NoError(t, err)
+	require.Empty(t, events)
+	events, err = parser.Parse(cloudFrontFieldsHeader)
+	require.NoError(t, err)
+	require.Empty(t, events)
+	events, err = parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
@@ -19,6 +19,9 @@ package awslogs
  */
 
 import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
 	jsoniter "github.com/json-iterator/go"
 
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
@@ -163,6 +166,16 @@ func (event *CloudTrail) updatePantherFields(p *CloudTrailParser) {
 	if event.UserIdentity != nil {
 		event.AppendAnyAWSAccountIdPtrs(event.UserIdentity.AccountID)
 		event.AppendAnyAWSARNPtrs(event.UserIdentity.ARN)
+		event.AppendAnyUsernamePtrs(event.UserIdentity.Username)
+		// the session name of an assumed role is usually who assumed it (e.g., the email of an SSO user)
+		if aws.StringValue(event.UserIdentity.Type) == "AssumedRole" {
+			principalID := aws.StringValue(event.UserIdentity.PrincipalID)
+			if i := strings.IndexByte(principalID, ':'); i >= 0 {
+				sessionName := principalID[i+1:]
+				event.AppendAnyUsernames(sessionName)
+				event.AppendAnyEmails(sessionName)
+			}
+		}
 
 		if event.UserIdentity.SessionContext != nil {
 			if event.UserIdentity.SessionContext.SessionIssuer != nil {
@@ -134,6 +134,44 @@ func TestCloudTrailLogDecrypt(t *testing.T) {
 		"arn:aws:lambda:us-east-1:888888888888:function:panther-log-processor")
 	expectedEvent.AppendAnyAWSAccountIds("888888888888")
 	expectedEvent.AppendAnyIPAddress("1.2.3.4")
+	expectedEvent.AppendAnyUsernames("panther-log-processor")
+
+	checkCloudTrailLog(t, log, expectedEvent)
+}
+
+func TestCloudTrailLogSSOCreateUser(t *testing.T) {
+	//nolint:lll
+	log := `{"Records": [{"eventVersion":"1.05","userIdentity":{"type":"AssumedRole","principalId":"AROAQXSBWDWTDYDZAXXXX:Jane.Doe@example.com","arn":"arn:aws:sts::888888888888:assumed-role/AWSReservedSSO_Admin_0123456789abcdef/Jane.Doe@example.com","accountId":"888888888888"},"eventTime":"2018-08-26T14:17:23Z","eventSource":"iam.amazonaws.com","eventName":"CreateUser","awsRegion":"us-east-1","sourceIPAddress":"1.2.3.4","userAgent":"console.amazonaws.com","requestParameters":{"userName":"bob"},"responseElements":null,"requestID":"3c5a008c-80d5-491a-bf76-0cac924f6ebb","eventID":"1852a808-86e8-4b4c-9d4d-01a85b6a39cd","eventType":"AwsApiCall"}]}`
+
+	expectedDate := time.Unix(1535293043, 0).In(time.UTC)
+	expectedEvent := &CloudTrail{
+		EventVersion: aws.String("1.05"),
+		UserIdentity: &CloudTrailUserIdentity{
+			Type:        aws.String("AssumedRole"),
+			PrincipalID: aws.String("AROAQXSBWDWTDYDZAXXXX:Jane.Doe@example.com"),
+			ARN:         aws.String("arn:aws:sts::888888888888:assumed-role/AWSReservedSSO_Admin_0123456789abcdef/Jane.Doe@example.com"),
+			AccountID:   aws.String("888888888888"),
+		},
+		EventTime:         (*timestamp.RFC3339)(&expectedDate),
+		EventSource:       aws.String("iam.amazonaws.com"),
+		EventName:         aws.String("CreateUser"),
+		AWSRegion:         aws.String("us-east-1"),
+		SourceIPAddress:   aws.String("1.2.3.4"),
+		UserAgent:         aws.String("console.amazonaws.com"),
+		RequestID:         aws.String("3c5a008c-80d5-491a-bf76-0cac924f6ebb"),
+		EventID:           aws.String("1852a808-86e8-4b4c-9d4d-01a85b6a39cd"),
+		EventType:         aws.String("AwsApiCall"),
+		RequestParameters: newRawMessage(`{"userName":"bob"}`),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("AWS.CloudTrail")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedDate)
+	expectedEvent.AppendAnyAWSARNs("arn:aws:sts::888888888888:assumed-role/AWSReservedSSO_Admin_0123456789abcdef/Jane.Doe@example.com")
+	expectedEvent.AppendAnyAWSAccountIds("888888888888")
+	expectedEvent.AppendAnyIPAddress("1.2.3.4")
+	expectedEvent.AppendAnyUsernames("Jane.Doe@example.com", "bob")
+	expectedEvent.AppendAnyEmails("jane.doe@example.com")
 
 	checkCloudTrailLog(t, log, expectedEvent)
 }
@@ -111,5 +111,14 @@ func (e *AWSExtractor) Extract(key, value gjson.Result) {
 		"privateDnsName", // found in instanceDetails in CloudTrail and GuardDuty (perhaps others)
 		"domain":         // found in GuardDuty findings
 		e.pl.AppendAnyDomainNames(value.Str)
+
+	case "userName": // found in IAM requestParameters in CloudTrail and accessKeyDetails in GuardDuty findings
+		e.pl.AppendAnyUsernames(value.Str)
+
+	case "macAddress": // found in networkInterfaces in CloudTrail
+		e.pl.AppendAnyMACAddresses(value.Str)
+
+	case "emailAddress", "email": // found in Organizations and SSO requestParameters in CloudTrail
+		e.pl.AppendAnyEmails(value.Str)
 	}
 }
@@ -35,6 +35,11 @@ func TestAWSExtractor(t *testing.T) {
 
 "accountId": "123456789012",
 
+"accessKeyDetails":{
+  "userName":"GeneratedFindingUserName",
+  "emailAddress":"Someone@Example.com"
+},
+
 "encryptionContext": {
   "aws:cloudtrail:arn":"arn:aws:cloudtrail:us-west-2:888888888888:trail/panther-lab-cloudtrail"
 },
@@ -57,6 +62,7 @@ func TestAWSExtractor(t *testing.T) {
       "privateDnsName":"ip-172-31-81-237.ec2.internal",
       "publicIp":"54.152.215.140",
       "networkInterfaceId":"eni-0fd8e8a70bb7804e3",
+      "macAddress":"0E:2B:6D:52:7A:11",
       "vpcId":"vpc-4a486c30","securityGroups":[
          {
            "groupName":"launch-wizard-31",
@@ -138,6 +144,9 @@ func TestAWSExtractor(t *testing.T) {
 	expectedEvent.AppendAnyAWSTags("tag1:val1")
 	expectedEvent.AppendAnyDomainNames("ec2-54-152-215-140.compute-1.amazonaws.com", "GeneratedFindingDomainName",
 		"ip-172-31-81-237.ec2.internal")
+	expectedEvent.AppendAnyUsernames("GeneratedFindingUserName")
+	expectedEvent.AppendAnyEmails("someone@example.com")
+	expectedEvent.AppendAnyMACAddresses("0e:2b:6d:52:7a:11")
 
 	extract.Extract(&json, NewAWSExtractor(&event))
 
@@ -0,0 +1,232 @@
+package awslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var RedshiftUserActivityDesc = `Redshift user activity audit logs of each query before it is run on the database.
+Reference: https://docs.aws.amazon.com/redshift/latest/mgmt/db-auditing.html#db-auditing-logs`
+
+var RedshiftConnectionDesc = `Redshift connection audit logs of authentication attempts, connections and disconnections.
+Reference: https://docs.aws.amazon.com/redshift/latest/mgmt/db-auditing.html#db-auditing-logs`
+
+// nolint:lll
+type RedshiftUserActivity struct {
+	RecordTime *timestamp.RFC3339 `json:"recordTime,omitempty" validate:"required" description:"The time the event occurred (UTC)."`
+	DB         *string            `json:"db,omitempty" description:"The database name."`
+	User       *string            `json:"user,omitempty" description:"The user name."`
+	PID        *int               `json:"pid,omitempty" description:"The process ID associated with the statement."`
+	UserID     *int               `json:"userId,omitempty" description:"The user ID."`
+	XID        *int               `json:"xid,omitempty" description:"The transaction ID."`
+	Query      *string            `json:"query,omitempty" description:"The query text, prefixed with LOG:."`
+
+	// NOTE: added to end of struct to allow expansion later
+	AWSPantherLog
+}
+
+// nolint:lll
+type RedshiftConnection struct {
+	Event           *string            `json:"event,omitempty" validate:"required" description:"The connection or authentication event (e.g. authenticated, initiating session, disconnecting session)."`
+	RecordTime      *timestamp.RFC3339 `json:"recordTime,omitempty" validate:"required" description:"The time the event occurred (UTC)."`
+	RemoteHost      *string            `json:"remoteHost,omitempty" description:"The name or IP address of the remote host."`
+	RemotePort      *int               `json:"remotePort,omitempty" description:"The port number for the remote host."`
+	PID             *int               `json:"pid,omitempty" description:"The process ID associated with the statement."`
+	DBName          *string            `json:"dbName,omitempty" description:"The database name."`
+	UserName        *string            `json:"userName,omitempty" description:"The user name."`
+	AuthMethod      *string            `json:"authMethod,omitempty" description:"The authentication method."`
+	Duration        *int               `json:"duration,omitempty" description:"The duration of the connection in microseconds."`
+	SSLVersion      *string            `json:"sslVersion,omitempty" description:"The Secure Sockets Layer (SSL) version."`
+	SSLCipher       *string            `json:"sslCipher,omitempty" description:"The SSL cipher."`
+	MTU             *int               `json:"mtu,omitempty" description:"The maximum transmission unit (MTU)."`
+	SSLCompression  *string            `json:"sslCompression,omitempty" description:"The SSL compression type."`
+	SSLExpansion    *string            `json:"sslExpansion,omitempty" description:"The SSL expansion type."`
+	IAMAuthGUID     *string            `json:"iamAuthGuid,omitempty" description:"The AWS Identity and Access Management (IAM) authentication ID for the AWS CloudTrail request."`
+	ApplicationName *string            `json:"applicationName,omitempty" description:"The initial or updated name of the application for a session."`
+	OSVersion       *string            `json:"osVersion,omitempty" description:"The version of the operating system that is on the client machine that connects to your Amazon Redshift cluster."`
+	DriverVersion   *string            `json:"driverVersion,omitempty" description:"The version of ODBC or JDBC driver that connects to your Amazon Redshift cluster from your third-party SQL client tools."`
+	PluginName      *string            `json:"pluginName,omitempty" description:"The name of the plugin used to connect to your Amazon Redshift cluster."`
+	ProtocolVersion *int               `json:"protocolVersion,omitempty" description:"The protocol version that is used by Amazon Redshift driver when establishing its connection with the server."`
+	SessionID       *string            `json:"sessionId,omitempty" description:"The unique identifier for the current session."`
+	Compression     *string            `json:"compression,omitempty" description:"The compression algorithm in use for the connection."`
+
+	// NOTE: added to end of struct to allow expansion later
+	AWSPantherLog
+}
+
+var redshiftUserActivityRegex = regexp.MustCompile(
+	`^'(\S+) UTC \[ db=(\S*) user=(\S*) pid=(\d+) userid=(\d+) xid=(\d+) \]' (LOG: .*)$`)
+
+// RedshiftUserActivityParser parses Redshift user activity logs
+type RedshiftUserActivityParser struct{}
+
+var _ parsers.LogParser = (*RedshiftUserActivityParser)(nil)
+
+func (p *RedshiftUserActivityParser) New() parsers.LogParser {
+	return &RedshiftUserActivityParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *RedshiftUserActivityParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	match := redshiftUserActivityRegex.FindStringSubmatch(strings.TrimRight(log, "\r\n"))
+	if match == nil {
+		return nil, errors.New("invalid record")
+	}
+
+	recordTime, err := timestamp.Parse(time.RFC3339, match[1])
+	if err != nil {
+		return nil, err
+	}
+
+	event := &RedshiftUserActivity{
+		RecordTime: &recordTime,
+		DB:         parsers.CsvStringToPointer(match[2]),
+		User:       parsers.CsvStringToPointer(match[3]),
+		PID:        parsers.CsvStringToIntPointer(match[4]),
+		UserID:     parsers.CsvStringToIntPointer(match[5]),
+		XID:        parsers.CsvStringToIntPointer(match[6]),
+		Query:      parsers.CsvStringToPointer(match[7]),
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *RedshiftUserActivityParser) LogType() string {
+	return "AWS.RedshiftUserActivity"
+}
+
+func (event *RedshiftUserActivity) updatePantherFields(p *RedshiftUserActivityParser) {
+	event.SetCoreFields(p.LogType(), event.RecordTime, event)
+	event.AppendAnyUsernamePtrs(event.User)
+}
+
+const (
+	redshiftConnectionMinNumberOfColumns = 9
+	// recordtime has millisecond precision separated by a colon, e.g. "Mon, 30 Mar 2020 08:36:14:389"
+	redshiftConnectionTimeLayout = "Mon, 2 Jan 2006 15:04:05"
+)
+
+// RedshiftConnectionParser parses Redshift connection logs
+type RedshiftConnectionParser struct{}
+
+var _ parsers.LogParser = (*RedshiftConnectionParser)(nil)
+
+func (p *RedshiftConnectionParser) New() parsers.LogParser {
+	return &RedshiftConnectionParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *RedshiftConnectionParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	record := strings.Split(strings.TrimRight(log, "\r\n"), "|")
+	if len(record) < redshiftConnectionMinNumberOfColumns {
+		return nil, errors.New("invalid number of columns")
+	}
+	// columns are space padded and left blank when not applicable, and later versions of the log add columns
+	columns := make([]string, 22)
+	for i := range columns {
+		columns[i] = "-"
+		if i < len(record) {
+			if value := strings.TrimSpace(record[i]); value != "" {
+				columns[i] = value
+			}
+		}
+	}
+
+	recordTime, err := parseRedshiftConnectionTime(columns[1])
+	if err != nil {
+		return nil, err
+	}
+
+	event := &RedshiftConnection{
+		Event:           parsers.CsvStringToPointer(columns[0]),
+		RecordTime:      &recordTime,
+		RemoteHost:      parsers.CsvStringToPointer(columns[2]),
+		RemotePort:      parsers.CsvStringToIntPointer(columns[3]),
+		PID:             parsers.CsvStringToIntPointer(columns[4]),
+		DBName:          parsers.CsvStringToPointer(columns[5]),
+		UserName:        parsers.CsvStringToPointer(columns[6]),
+		AuthMethod:      parsers.CsvStringToPointer(columns[7]),
+		Duration:        parsers.CsvStringToIntPointer(columns[8]),
+		SSLVersion:      parsers.CsvStringToPointer(columns[9]),
+		SSLCipher:       parsers.CsvStringToPointer(columns[10]),
+		MTU:             parsers.CsvStringToIntPointer(columns[11]),
+		SSLCompression:  parsers.CsvStringToPointer(columns[12]),
+		SSLExpansion:    parsers.CsvStringToPointer(columns[13]),
+		IAMAuthGUID:     parsers.CsvStringToPointer(columns[14]),
+		ApplicationName: parsers.CsvStringToPointer(columns[15]),
+		OSVersion:       parsers.CsvStringToPointer(columns[16]),
+		DriverVersion:   parsers.CsvStringToPointer(columns[17]),
+		PluginName:      parsers.CsvStringToPointer(columns[18]),
+		ProtocolVersion: parsers.CsvStringToIntPointer(columns[19]),
+		SessionID:       parsers.CsvStringToPointer(columns[20]),
+		Compression:     parsers.CsvStringToPointer(columns[21]),
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *RedshiftConnectionParser) LogType() string {
+	return "AWS.RedshiftConnection"
+}
+
+func (event *RedshiftConnection) updatePantherFields(p *RedshiftConnectionParser) {
+	event.SetCoreFields(p.LogType(), event.RecordTime, event)
+	event.AppendAnyIPAddressPtr(event.RemoteHost)
+	event.AppendAnyUsernamePtrs(event.UserName)
+}
+
+func parseRedshiftConnectionTime(value string) (timestamp.RFC3339, error) {
+	millis := 0
+	if strings.Count(value, ":") == 3 {
+		i := strings.LastIndexByte(value, ':')
+		ms, err := strconv.Atoi(value[i+1:])
+		if err != nil {
+			return timestamp.RFC3339{}, errors.Wrapf(err, "invalid recordtime %q", value)
+		}
+		value, millis = value[:i], ms
+	}
+	t, err := time.Parse(redshiftConnectionTimeLayout, value)
+	if err != nil {
+		return timestamp.RFC3339{}, err
+	}
+	return timestamp.RFC3339(t.Add(time.Duration(millis) * time.Millisecond).UTC()), nil
+}
@@ -0,0 +1,119 @@
+package awslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestRedshiftUserActivityLog(t *testing.T) {
+	log := `'2020-03-30T08:36:14Z UTC [ db=dev user=rdsdb pid=10520 userid=1 xid=5734 ]' LOG: SELECT 1 FROM pg_user WHERE usename = 'admin';`
+
+	expectedTime := time.Date(2020, 3, 30, 8, 36, 14, 0, time.UTC)
+
+	expectedEvent := &RedshiftUserActivity{
+		RecordTime: (*timestamp.RFC3339)(&expectedTime),
+		DB:         aws.String("dev"),
+		User:       aws.String("rdsdb"),
+		PID:        aws.Int(10520),
+		UserID:     aws.Int(1),
+		XID:        aws.Int(5734),
+		Query:      aws.String("LOG: SELECT 1 FROM pg_user WHERE usename = 'admin';"),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("AWS.RedshiftUserActivity")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyUsernames("rdsdb")
+
+	expectedEvent.SetEvent(expectedEvent)
+	parser := (&RedshiftUserActivityParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
+
+func TestRedshiftUserActivityLogInvalid(t *testing.T) {
+	parser := (&RedshiftUserActivityParser{}).New()
+	events, err := parser.Parse("LOG: SELECT 1;")
+	require.Error(t, err)
+	require.Nil(t, events)
+}
+
+func TestRedshiftUserActivityLogType(t *testing.T) {
+	parser := &RedshiftUserActivityParser{}
+	require.Equal(t, "AWS.RedshiftUserActivity", parser.LogType())
+}
+
+func TestRedshiftConnectionLog(t *testing.T) {
+	//nolint:lll
+	log := `authenticated |Mon, 30 Mar 2020 08:36:14:389|::ffff:10.0.0.10 |52936 |10520 |dev |admin |password |0|TLSv1.2 |ECDHE-RSA-AES256-GCM-SHA384 |0| | | |psql |Linux 4.14 amd64 |PostgreSQL 9.6 |none |0|f0a5c7e8-1d2b |`
+
+	expectedTime := time.Date(2020, 3, 30, 8, 36, 14, 389000000, time.UTC)
+
+	expectedEvent := &RedshiftConnection{
+		Event:           aws.String("authenticated"),
+		RecordTime:      (*timestamp.RFC3339)(&expectedTime),
+		RemoteHost:      aws.String("::ffff:10.0.0.10"),
+		RemotePort:      aws.Int(52936),
+		PID:             aws.Int(10520),
+		DBName:          aws.String("dev"),
+		UserName:        aws.String("admin"),
+		AuthMethod:      aws.String("password"),
+		Duration:        aws.Int(0),
+		SSLVersion:      aws.String("TLSv1.2"),
+		SSLCipher:       aws.String("ECDHE-RSA-AES256-GCM-SHA384"),
+		MTU:             aws.Int(0),
+		ApplicationName: aws.String("psql"),
+		OSVersion:       aws.String("Linux 4.14 amd64"),
+		DriverVersion:   aws.String("PostgreSQL 9.6"),
+		PluginName:      aws.String("none"),
+		ProtocolVersion: aws.Int(0),
+		SessionID:       aws.String("f0a5c7e8-1d2b"),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("AWS.RedshiftConnection")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("::ffff:10.0.0.10")
+	expectedEvent.AppendAnyUsernames("admin")
+
+	expectedEvent.SetEvent(expectedEvent)
+	parser := (&RedshiftConnectionParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
+
+func TestRedshiftConnectionLogInvalid(t *testing.T) {
+	parser := (&RedshiftConnectionParser{}).New()
+	events, err := parser.Parse("authenticated |Mon, 30 Mar 2020 08:36:14:389|[local]")
+	require.Error(t, err)
+	require.Nil(t, events)
+}
+
+func TestRedshiftConnectionLogType(t *testing.T) {
+	parser := &RedshiftConnectionParser{}
+	require.Equal(t, "AWS.RedshiftConnection", parser.LogType())
+}
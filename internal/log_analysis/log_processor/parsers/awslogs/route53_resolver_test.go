@@ -0,0 +1,84 @@
+package awslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestRoute53ResolverQueryLog(t *testing.T) {
+	//nolint:lll
+	log := `{"version":"1.000000","account_id":"123456789012","region":"us-east-1","vpc_id":"vpc-00000000000000000","query_timestamp":"2020-06-02T22:44:30Z","query_name":"www.example.com.","query_type":"A","query_class":"IN","rcode":"NOERROR","answers":[{"Rdata":"example.com.","Type":"CNAME","Class":"IN"},{"Rdata":"93.184.216.34","Type":"A","Class":"IN"}],"srcaddr":"172.31.14.222","srcport":"42543","transport":"UDP","srcids":{"instance":"i-0d15cd0d3f6a49d85"}}`
+
+	expectedTime := time.Date(2020, 6, 2, 22, 44, 30, 0, time.UTC)
+	srcPort := numerics.Integer(42543)
+
+	expectedEvent := &Route53ResolverQuery{
+		Version:        aws.String("1.000000"),
+		AccountID:      aws.String("123456789012"),
+		Region:         aws.String("us-east-1"),
+		VPCID:          aws.String("vpc-00000000000000000"),
+		QueryTimestamp: (*timestamp.RFC3339)(&expectedTime),
+		QueryName:      aws.String("www.example.com."),
+		QueryType:      aws.String("A"),
+		QueryClass:     aws.String("IN"),
+		RCode:          aws.String("NOERROR"),
+		Answers: []Route53ResolverQueryAnswer{
+			{Rdata: aws.String("example.com."), Type: aws.String("CNAME"), Class: aws.String("IN")},
+			{Rdata: aws.String("93.184.216.34"), Type: aws.String("A"), Class: aws.String("IN")},
+		},
+		SrcAddr:   aws.String("172.31.14.222"),
+		SrcPort:   &srcPort,
+		Transport: aws.String("UDP"),
+		SrcIDs: &Route53ResolverQuerySourceID{
+			Instance: aws.String("i-0d15cd0d3f6a49d85"),
+		},
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("AWS.Route53ResolverQuery")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyAWSAccountIds("123456789012")
+	expectedEvent.AppendAnyAWSInstanceIds("i-0d15cd0d3f6a49d85")
+	expectedEvent.AppendAnyIPAddress("172.31.14.222")
+	expectedEvent.AppendAnyIPAddress("93.184.216.34")
+	expectedEvent.AppendAnyDomainNames("www.example.com", "example.com")
+
+	checkRoute53ResolverQueryLog(t, log, expectedEvent)
+}
+
+func TestRoute53ResolverQueryLogType(t *testing.T) {
+	parser := &Route53ResolverQueryParser{}
+	require.Equal(t, "AWS.Route53ResolverQuery", parser.LogType())
+}
+
+func checkRoute53ResolverQueryLog(t *testing.T, log string, expectedEvent *Route53ResolverQuery) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := (&Route53ResolverQueryParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
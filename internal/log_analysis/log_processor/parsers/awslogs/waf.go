@@ -0,0 +1,144 @@
+package awslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+	"github.com/panther-labs/panther/pkg/extract"
+)
+
+var WAFWebACLDesc = `AWS WAF full logs of the traffic analyzed by a web ACL.
+Reference: https://docs.aws.amazon.com/waf/latest/developerguide/logging.html`
+
+// nolint:lll
+type WAFWebACL struct {
+	Timestamp                   *timestamp.UnixMillisecond `json:"timestamp,omitempty" validate:"required" description:"The timestamp in milliseconds."`
+	FormatVersion               *int                       `json:"formatVersion,omitempty" validate:"required" description:"The format version for the log."`
+	WebACLID                    *string                    `json:"webaclId,omitempty" validate:"required" description:"The ID of the web ACL (an ARN for AWS WAF, a GUID for AWS WAF Classic)."`
+	TerminatingRuleID           *string                    `json:"terminatingRuleId,omitempty" description:"The ID of the rule that terminated the request. If nothing terminates the request, the value is Default_Action."`
+	TerminatingRuleType         *string                    `json:"terminatingRuleType,omitempty" description:"The type of rule that terminated the request. Possible values: RATE_BASED, REGULAR, GROUP and MANAGED_RULE_GROUP."`
+	Action                      *string                    `json:"action,omitempty" validate:"required" description:"The action. Possible values for a terminating rule: ALLOW and BLOCK. COUNT is not a valid value for a terminating rule."`
+	TerminatingRuleMatchDetails *jsoniter.RawMessage       `json:"terminatingRuleMatchDetails,omitempty" description:"Detailed information about the terminating rule that matched the request."`
+	HTTPSourceName              *string                    `json:"httpSourceName,omitempty" description:"The source of the request. Possible values: CF for Amazon CloudFront, APIGW for Amazon API Gateway and ALB for Application Load Balancer."`
+	HTTPSourceID                *string                    `json:"httpSourceId,omitempty" description:"The source ID. This field shows the ID of the associated resource."`
+	RuleGroupList               *jsoniter.RawMessage       `json:"ruleGroupList,omitempty" description:"The list of rule groups that acted on this request."`
+	RateBasedRuleList           *jsoniter.RawMessage       `json:"rateBasedRuleList,omitempty" description:"The list of rate-based rules that acted on the request."`
+	NonTerminatingMatchingRules *jsoniter.RawMessage       `json:"nonTerminatingMatchingRules,omitempty" description:"The list of non-terminating rules that match the request."`
+	HTTPRequest                 *WAFHTTPRequest            `json:"httpRequest,omitempty" validate:"required" description:"The metadata about the request."`
+
+	// NOTE: added to end of struct to allow expansion later
+	AWSPantherLog
+}
+
+// nolint:lll
+type WAFHTTPRequest struct {
+	ClientIP    *string         `json:"clientIp,omitempty" description:"The IP address of the client sending the request."`
+	Country     *string         `json:"country,omitempty" description:"The source country of the request. If AWS WAF is unable to determine the country of origin, it sets this field to -."`
+	Headers     []WAFHTTPHeader `json:"headers,omitempty" description:"The list of headers."`
+	URI         *string         `json:"uri,omitempty" description:"The URI of the request."`
+	Args        *string         `json:"args,omitempty" description:"The query string."`
+	HTTPVersion *string         `json:"httpVersion,omitempty" description:"The HTTP version."`
+	HTTPMethod  *string         `json:"httpMethod,omitempty" description:"The HTTP method in the request."`
+	RequestID   *string         `json:"requestId,omitempty" description:"The ID of the request, which is generated by the underlying host service."`
+}
+
+// nolint:lll
+type WAFHTTPHeader struct {
+	Name  *string `json:"name,omitempty" description:"The header name."`
+	Value *string `json:"value,omitempty" description:"The header value."`
+}
+
+// WAFWebACLParser parses AWS WAF web ACL logs
+type WAFWebACLParser struct{}
+
+var _ parsers.LogParser = (*WAFWebACLParser)(nil)
+
+func (p *WAFWebACLParser) New() parsers.LogParser {
+	return &WAFWebACLParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *WAFWebACLParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	event := &WAFWebACL{}
+
+	err := jsoniter.UnmarshalFromString(log, event)
+	if err != nil {
+		return nil, err
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *WAFWebACLParser) LogType() string {
+	return "AWS.WAFWebACL"
+}
+
+func (event *WAFWebACL) updatePantherFields(p *WAFWebACLParser) {
+	event.SetCoreFields(p.LogType(), (*timestamp.RFC3339)(event.Timestamp), event)
+
+	if event.HTTPRequest != nil {
+		event.AppendAnyIPAddressPtr(event.HTTPRequest.ClientIP)
+		for _, header := range event.HTTPRequest.Headers {
+			if header.Name == nil || header.Value == nil {
+				continue
+			}
+			switch strings.ToLower(*header.Name) {
+			case "host":
+				host := *header.Value
+				if h, _, err := net.SplitHostPort(host); err == nil {
+					host = h
+				}
+				if !event.AppendAnyIPAddress(host) {
+					event.AppendAnyDomainNames(host)
+				}
+			case "x-forwarded-for":
+				event.AppendAnyIPAddressInField(*header.Value)
+			}
+		}
+	}
+
+	// AWS WAF (but not AWS WAF Classic) identifies web ACLs by ARN
+	if event.WebACLID != nil {
+		if webACLARN, err := arn.Parse(*event.WebACLID); err == nil {
+			event.AppendAnyAWSARNs(*event.WebACLID)
+			event.AppendAnyAWSAccountIds(webACLARN.AccountID)
+		}
+	}
+
+	// polymorphic (unparsed) fields
+	awsExtractor := NewAWSExtractor(&(event.AWSPantherLog))
+	extract.Extract(event.TerminatingRuleMatchDetails, awsExtractor)
+	extract.Extract(event.RuleGroupList, awsExtractor)
+	extract.Extract(event.RateBasedRuleList, awsExtractor)
+	extract.Extract(event.NonTerminatingMatchingRules, awsExtractor)
+}
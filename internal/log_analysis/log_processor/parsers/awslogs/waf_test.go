@@ -0,0 +1,126 @@
+package awslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestWAFWebACLLog(t *testing.T) {
+	//nolint:lll
+	log := `{"timestamp":1576280412771,"formatVersion":1,"webaclId":"arn:aws:wafv2:ap-southeast-2:111122223333:regional/webacl/STMTest/1EXAMPLE-2ARN-3ARN-4ARN-123456EXAMPLE","terminatingRuleId":"STMTest_SQLi_XSS","terminatingRuleType":"REGULAR","action":"BLOCK","terminatingRuleMatchDetails":[{"conditionType":"SQL_INJECTION","location":"UNKNOWN","matchedData":["10","AND","1"]}],"httpSourceName":"-","httpSourceId":"-","ruleGroupList":[],"rateBasedRuleList":[],"nonTerminatingMatchingRules":[],"httpRequest":{"clientIp":"1.1.1.1","country":"AU","headers":[{"name":"Host","value":"localhost:1989"},{"name":"User-Agent","value":"curl/7.61.1"},{"name":"X-Forwarded-For","value":"10.0.0.1, 10.0.0.2"}],"uri":"/","args":"x=%22%3E%3Cscript%3Ealert(1)%3C/script%3E","httpVersion":"HTTP/1.1","httpMethod":"GET","requestId":"rid"}}`
+
+	expectedTime := time.Unix(1576280412, 771000000).UTC()
+
+	expectedEvent := &WAFWebACL{
+		Timestamp:                   (*timestamp.UnixMillisecond)(&expectedTime),
+		FormatVersion:               aws.Int(1),
+		WebACLID:                    aws.String("arn:aws:wafv2:ap-southeast-2:111122223333:regional/webacl/STMTest/1EXAMPLE-2ARN-3ARN-4ARN-123456EXAMPLE"),
+		TerminatingRuleID:           aws.String("STMTest_SQLi_XSS"),
+		TerminatingRuleType:         aws.String("REGULAR"),
+		Action:                      aws.String("BLOCK"),
+		TerminatingRuleMatchDetails: newRawMessage(`[{"conditionType":"SQL_INJECTION","location":"UNKNOWN","matchedData":["10","AND","1"]}]`),
+		HTTPSourceName:              aws.String("-"),
+		HTTPSourceID:                aws.String("-"),
+		RuleGroupList:               newRawMessage(`[]`),
+		RateBasedRuleList:           newRawMessage(`[]`),
+		NonTerminatingMatchingRules: newRawMessage(`[]`),
+		HTTPRequest: &WAFHTTPRequest{
+			ClientIP: aws.String("1.1.1.1"),
+			Country:  aws.String("AU"),
+			Headers: []WAFHTTPHeader{
+				{Name: aws.String("Host"), Value: aws.String("localhost:1989")},
+				{Name: aws.String("User-Agent"), Value: aws.String("curl/7.61.1")},
+				{Name: aws.String("X-Forwarded-For"), Value: aws.String("10.0.0.1, 10.0.0.2")},
+			},
+			URI:         aws.String("/"),
+			Args:        aws.String("x=%22%3E%3Cscript%3Ealert(1)%3C/script%3E"),
+			HTTPVersion: aws.String("HTTP/1.1"),
+			HTTPMethod:  aws.String("GET"),
+			RequestID:   aws.String("rid"),
+		},
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("AWS.WAFWebACL")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("1.1.1.1")
+	expectedEvent.AppendAnyIPAddress("10.0.0.1")
+	expectedEvent.AppendAnyIPAddress("10.0.0.2")
+	expectedEvent.AppendAnyDomainNames("localhost")
+	expectedEvent.AppendAnyAWSARNs("arn:aws:wafv2:ap-southeast-2:111122223333:regional/webacl/STMTest/1EXAMPLE-2ARN-3ARN-4ARN-123456EXAMPLE")
+	expectedEvent.AppendAnyAWSAccountIds("111122223333")
+
+	checkWAFWebACLLog(t, log, expectedEvent)
+}
+
+func TestWAFWebACLLogClassic(t *testing.T) {
+	//nolint:lll
+	log := `{"timestamp":1542671735010,"formatVersion":1,"webaclId":"385cb038-3a6f-4f2f-ac64-09ab912af590","terminatingRuleId":"Default_Action","terminatingRuleType":"REGULAR","action":"ALLOW","httpSourceName":"CF","httpSourceId":"i-123","httpRequest":{"clientIp":"192.10.23.23","country":"US","headers":[{"name":"Host","value":"127.0.0.1"}],"uri":"/","args":"","httpVersion":"HTTP/1.1","httpMethod":"GET","requestId":"Ov3YDmaEnZ7Yt2hzrMvZBmZi9qZgxp2aXCwpHUq_Ze-EXAMPLE"}}`
+
+	expectedTime := time.Unix(1542671735, 10000000).UTC()
+
+	expectedEvent := &WAFWebACL{
+		Timestamp:           (*timestamp.UnixMillisecond)(&expectedTime),
+		FormatVersion:       aws.Int(1),
+		WebACLID:            aws.String("385cb038-3a6f-4f2f-ac64-09ab912af590"),
+		TerminatingRuleID:   aws.String("Default_Action"),
+		TerminatingRuleType: aws.String("REGULAR"),
+		Action:              aws.String("ALLOW"),
+		HTTPSourceName:      aws.String("CF"),
+		HTTPSourceID:        aws.String("i-123"),
+		HTTPRequest: &WAFHTTPRequest{
+			ClientIP:    aws.String("192.10.23.23"),
+			Country:     aws.String("US"),
+			Headers:     []WAFHTTPHeader{{Name: aws.String("Host"), Value: aws.String("127.0.0.1")}},
+			URI:         aws.String("/"),
+			Args:        aws.String(""),
+			HTTPVersion: aws.String("HTTP/1.1"),
+			HTTPMethod:  aws.String("GET"),
+			RequestID:   aws.String("Ov3YDmaEnZ7Yt2hzrMvZBmZi9qZgxp2aXCwpHUq_Ze-EXAMPLE"),
+		},
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("AWS.WAFWebACL")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("192.10.23.23")
+	expectedEvent.AppendAnyIPAddress("127.0.0.1")
+
+	checkWAFWebACLLog(t, log, expectedEvent)
+}
+
+func TestWAFWebACLLogType(t *testing.T) {
+	parser := &WAFWebACLParser{}
+	require.Equal(t, "AWS.WAFWebACL", parser.LogType())
+}
+
+func checkWAFWebACLLog(t *testing.T, log string, expectedEvent *WAFWebACL) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := (&WAFWebACLParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
@@ -0,0 +1,129 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+// TypeActivity is the log type of Azure Activity Log records
+const TypeActivity = PantherPrefix + ".Activity"
+
+// ActivityDesc describes the Activity log record
+var ActivityDesc = `Azure Activity Log records of subscription level events (e.g. resource changes, service health), as exported to Event Hubs or Storage accounts.
+Reference: https://docs.microsoft.com/en-us/azure/azure-monitor/platform/activity-log-schema#schema-from-storage-account-and-event-hubs`
+
+// upnClaim is the identity claim holding the user principal name of the caller
+const upnClaim = "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn"
+
+// Activity is an Azure Activity Log record
+// nolint:lll
+type Activity struct {
+	Time            *timestamp.RFC3339   `json:"time" validate:"required" description:"The timestamp (UTC) of the event."`
+	ResourceID      *string              `json:"resourceId" validate:"required" description:"The resource ID of the impacted resource."`
+	OperationName   *string              `json:"operationName" validate:"required" description:"The name of the operation."`
+	Category        *string              `json:"category" validate:"required,oneof=Administrative ServiceHealth ResourceHealth Alert Autoscale Security Recommendation Policy" description:"The category of the event (Administrative, ServiceHealth, ResourceHealth, Alert, Autoscale, Security, Recommendation or Policy)."`
+	ResultType      *string              `json:"resultType,omitempty" description:"The status of the event (e.g. Started, In Progress, Succeeded, Failed, Active, Resolved)."`
+	ResultSignature *string              `json:"resultSignature,omitempty" description:"The sub status of the event."`
+	DurationMs      *numerics.Int64      `json:"durationMs,omitempty" description:"The duration of the operation in milliseconds."`
+	CallerIPAddress *string              `json:"callerIpAddress,omitempty" description:"The IP address of the user who has performed the operation."`
+	CorrelationID   *string              `json:"correlationId,omitempty" description:"A GUID used to group together a set of related events."`
+	Identity        *ActivityIdentity    `json:"identity,omitempty" description:"The authorization and claims of the user or application that performed the operation."`
+	Level           *string              `json:"level,omitempty" description:"The level of the event (Critical, Error, Warning or Informational)."`
+	Location        *string              `json:"location,omitempty" description:"The region of the location where the event occurred (or global)."`
+	Properties      *jsoniter.RawMessage `json:"properties,omitempty" description:"The details of the event, which depend on the category."`
+
+	// NOTE: added to end of struct to allow expansion later
+	AzurePantherLog
+}
+
+// ActivityIdentity describes the caller of an operation
+// nolint:lll
+type ActivityIdentity struct {
+	Authorization *ActivityAuthorization `json:"authorization,omitempty" description:"The RBAC properties of the event, including the action, the role and the scope."`
+	Claims        *jsoniter.RawMessage   `json:"claims,omitempty" description:"The JWT token claims used by Active Directory to authenticate the user or application to perform the operation."`
+}
+
+// ActivityAuthorization holds the RBAC properties of the event
+// nolint:lll
+type ActivityAuthorization struct {
+	Scope    *string              `json:"scope,omitempty" description:"The resource ID of the scope of the authorization."`
+	Action   *string              `json:"action,omitempty" description:"The authorized action."`
+	Evidence *jsoniter.RawMessage `json:"evidence,omitempty" description:"The role assignment that granted the action."`
+}
+
+// ActivityParser parses Azure Activity Log records
+type ActivityParser struct{}
+
+var _ parsers.LogParser = (*ActivityParser)(nil)
+
+// New creates a new parser
+func (p *ActivityParser) New() parsers.LogParser {
+	return &ActivityParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *ActivityParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	records, err := splitRecords(log)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*parsers.PantherLog, 0, len(records))
+	for _, record := range records {
+		event := &Activity{}
+
+		err := jsoniter.UnmarshalFromString(record, event)
+		if err != nil {
+			return nil, err
+		}
+
+		event.updatePantherFields(p)
+
+		if err := parsers.Validator.Struct(event); err != nil {
+			return nil, err
+		}
+
+		result = append(result, event.Logs()...)
+	}
+	return result, nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *ActivityParser) LogType() string {
+	return TypeActivity
+}
+
+func (event *Activity) updatePantherFields(p *ActivityParser) {
+	event.SetCoreFields(p.LogType(), event.Time, event)
+	event.AppendAnyIPAddressPtr(event.CallerIPAddress)
+	event.AppendAnyAzureResourceIDPtrs(event.ResourceID)
+	if event.Identity != nil {
+		if event.Identity.Authorization != nil {
+			event.AppendAnyAzureResourceIDPtrs(event.Identity.Authorization.Scope)
+		}
+		if event.Identity.Claims != nil {
+			event.AppendAnyAzureUPNs(jsoniter.Get(*event.Identity.Claims, upnClaim).ToString())
+		}
+	}
+}
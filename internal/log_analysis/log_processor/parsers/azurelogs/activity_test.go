@@ -0,0 +1,110 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+//nolint:lll
+const testActivityLog = `{"time":"2020-03-12T19:55:12.2853117Z","resourceId":"/SUBSCRIPTIONS/00000000-0000-0000-0000-000000000000/RESOURCEGROUPS/RG/PROVIDERS/MICROSOFT.COMPUTE/VIRTUALMACHINES/VM1","operationName":"MICROSOFT.COMPUTE/VIRTUALMACHINES/DELETE","category":"Administrative","resultType":"Success","resultSignature":"Succeeded.OK","durationMs":"1216","callerIpAddress":"40.76.1.2","correlationId":"d5c1b0a3-6d1a-4c36-9bd7-2b7e0c2b1a11","identity":{"authorization":{"scope":"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1","action":"Microsoft.Compute/virtualMachines/delete","evidence":{"role":"Owner"}},"claims":{"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn":"Admin@Contoso.com","ipaddr":"40.76.1.2"}},"level":"Information","location":"global","properties":{"statusCode":"OK"}}`
+
+func TestActivityParser(t *testing.T) {
+	checkActivity(t, testActivityLog, expectedActivity())
+}
+
+func TestActivityParserEventHubRecords(t *testing.T) {
+	log := `{"records":[` + testActivityLog + `,` + testActivityLog + `]}`
+
+	parser := (&ActivityParser{}).New()
+	events, err := parser.Parse(log)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, event := range events {
+		testutil.EqualPantherLog(t, expectedActivity().Log(), []*parsers.PantherLog{event}, nil)
+	}
+}
+
+func TestActivityParserInvalidCategory(t *testing.T) {
+	parser := (&ActivityParser{}).New()
+	events, err := parser.Parse(`{"time":"2020-03-12T19:55:12Z","resourceId":"/tenants/1","operationName":"Sign-in activity","category":"SignInLogs"}`)
+	require.Error(t, err)
+	require.Nil(t, events)
+}
+
+func TestActivityType(t *testing.T) {
+	parser := (&ActivityParser{}).New()
+	require.Equal(t, "Azure.Activity", parser.LogType())
+}
+
+//nolint:lll
+func expectedActivity() *Activity {
+	expectedTime := time.Date(2020, 3, 12, 19, 55, 12, 285311700, time.UTC)
+	duration := numerics.Int64(1216)
+	evidence := jsoniter.RawMessage(`{"role":"Owner"}`)
+	claims := jsoniter.RawMessage(`{"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn":"Admin@Contoso.com","ipaddr":"40.76.1.2"}`)
+	properties := jsoniter.RawMessage(`{"statusCode":"OK"}`)
+	expectedEvent := &Activity{
+		Time:            (*timestamp.RFC3339)(&expectedTime),
+		ResourceID:      aws.String("/SUBSCRIPTIONS/00000000-0000-0000-0000-000000000000/RESOURCEGROUPS/RG/PROVIDERS/MICROSOFT.COMPUTE/VIRTUALMACHINES/VM1"),
+		OperationName:   aws.String("MICROSOFT.COMPUTE/VIRTUALMACHINES/DELETE"),
+		Category:        aws.String("Administrative"),
+		ResultType:      aws.String("Success"),
+		ResultSignature: aws.String("Succeeded.OK"),
+		DurationMs:      &duration,
+		CallerIPAddress: aws.String("40.76.1.2"),
+		CorrelationID:   aws.String("d5c1b0a3-6d1a-4c36-9bd7-2b7e0c2b1a11"),
+		Identity: &ActivityIdentity{
+			Authorization: &ActivityAuthorization{
+				Scope:    aws.String("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm1"),
+				Action:   aws.String("Microsoft.Compute/virtualMachines/delete"),
+				Evidence: &evidence,
+			},
+			Claims: &claims,
+		},
+		Level:      aws.String("Information"),
+		Location:   aws.String("global"),
+		Properties: &properties,
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Azure.Activity")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("40.76.1.2")
+	expectedEvent.AppendAnyAzureResourceIDs("/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.compute/virtualmachines/vm1")
+	expectedEvent.AppendAnyAzureUPNs("admin@contoso.com")
+	expectedEvent.SetEvent(expectedEvent)
+	return expectedEvent
+}
+
+func checkActivity(t *testing.T, log string, expectedEvent *Activity) {
+	parser := (&ActivityParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
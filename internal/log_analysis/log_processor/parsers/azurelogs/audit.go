@@ -0,0 +1,172 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+// TypeAudit is the log type of Azure AD audit log records
+const TypeAudit = PantherPrefix + ".Audit"
+
+// AuditDesc describes the Audit log record
+var AuditDesc = `Azure Active Directory audit log records of changes to users, groups, applications and policies, as exported to Event Hubs or Storage accounts.
+Reference: https://docs.microsoft.com/en-us/azure/active-directory/reports-monitoring/reference-azure-monitor-audit-log-schema`
+
+// Audit is an Azure AD audit log record
+// nolint:lll
+type Audit struct {
+	Time             *timestamp.RFC3339 `json:"time" validate:"required" description:"The date and time (UTC) of the event."`
+	ResourceID       *string            `json:"resourceId,omitempty" description:"The resource ID of the Azure AD tenant."`
+	OperationName    *string            `json:"operationName" validate:"required" description:"The name of the operation."`
+	OperationVersion *string            `json:"operationVersion,omitempty" description:"The REST API version that is requested by the client."`
+	Category         *string            `json:"category" validate:"required,eq=AuditLogs" description:"The category of the event (AuditLogs)."`
+	TenantID         *string            `json:"tenantId,omitempty" description:"The tenant GUID that is associated with the log."`
+	ResultSignature  *string            `json:"resultSignature,omitempty" description:"The result of the operation."`
+	DurationMs       *numerics.Int64    `json:"durationMs,omitempty" description:"The duration of the operation in milliseconds."`
+	CallerIPAddress  *string            `json:"callerIpAddress,omitempty" description:"The IP address of the client that made the request."`
+	CorrelationID    *string            `json:"correlationId,omitempty" description:"The GUID that is passed by the client, used to correlate client-side operations with server-side operations."`
+	Level            *numerics.Integer  `json:"level,omitempty" description:"The type of message (4 is Informational)."`
+	Properties       *AuditProperties   `json:"properties,omitempty" description:"The properties of the audit event."`
+
+	// NOTE: added to end of struct to allow expansion later
+	AzurePantherLog
+}
+
+// AuditProperties are the details of an audit event
+// nolint:lll
+type AuditProperties struct {
+	ID                  *string               `json:"id,omitempty" description:"The unique ID of the audit event."`
+	Category            *string               `json:"category,omitempty" description:"The resource category targeted by the activity (e.g. UserManagement, GroupManagement, ApplicationManagement)."`
+	CorrelationID       *string               `json:"correlationId,omitempty" description:"The GUID used to correlate activities that span across services."`
+	Result              *string               `json:"result,omitempty" description:"The result of the activity (success, failure, timeout or unknownFutureValue)."`
+	ResultReason        *string               `json:"resultReason,omitempty" description:"The reason for failure if the result is failure or timeout."`
+	ActivityDisplayName *string               `json:"activityDisplayName,omitempty" description:"The activity name or the operation name (e.g. Create User, Add member to group)."`
+	ActivityDateTime    *timestamp.RFC3339    `json:"activityDateTime,omitempty" description:"The date and time (UTC) the activity was performed."`
+	LoggedByService     *string               `json:"loggedByService,omitempty" description:"The service that initiated the activity (e.g. Core Directory, Self-service Password Management, Invited Users)."`
+	OperationType       *string               `json:"operationType,omitempty" description:"The type of the operation (e.g. Add, Update, Delete)."`
+	InitiatedBy         *AuditInitiatedBy     `json:"initiatedBy,omitempty" description:"The user or app that initiated the activity."`
+	TargetResources     []AuditTargetResource `json:"targetResources,omitempty" description:"The resources that were changed by the activity (e.g. User, Device, Directory, App, Role, Group, Policy)."`
+	AdditionalDetails   []AuditKeyValue       `json:"additionalDetails,omitempty" description:"Additional details on the activity."`
+}
+
+// AuditInitiatedBy is the initiator of an audit event
+// nolint:lll
+type AuditInitiatedBy struct {
+	User *AuditUserIdentity `json:"user,omitempty" description:"The user that initiated the activity."`
+	App  *AuditAppIdentity  `json:"app,omitempty" description:"The application that initiated the activity."`
+}
+
+// AuditUserIdentity is a user that initiated an audit event
+// nolint:lll
+type AuditUserIdentity struct {
+	ID                *string `json:"id,omitempty" description:"The ID of the user."`
+	DisplayName       *string `json:"displayName,omitempty" description:"The display name of the user."`
+	UserPrincipalName *string `json:"userPrincipalName,omitempty" description:"The user principal name (UPN) of the user."`
+	IPAddress         *string `json:"ipAddress,omitempty" description:"The IP address the user made the request from."`
+}
+
+// AuditAppIdentity is an application that initiated an audit event
+// nolint:lll
+type AuditAppIdentity struct {
+	AppID                *string `json:"appId,omitempty" description:"The ID of the application."`
+	DisplayName          *string `json:"displayName,omitempty" description:"The display name of the application."`
+	ServicePrincipalID   *string `json:"servicePrincipalId,omitempty" description:"The ID of the service principal of the application."`
+	ServicePrincipalName *string `json:"servicePrincipalName,omitempty" description:"The name of the service principal of the application."`
+}
+
+// AuditTargetResource is a resource changed by an audit event
+// nolint:lll
+type AuditTargetResource struct {
+	ID                 *string              `json:"id,omitempty" description:"The ID of the resource."`
+	DisplayName        *string              `json:"displayName,omitempty" description:"The display name of the resource."`
+	Type               *string              `json:"type,omitempty" description:"The type of the resource (e.g. User, Group, Application)."`
+	UserPrincipalName  *string              `json:"userPrincipalName,omitempty" description:"The user principal name (UPN) of the resource, when it is a user."`
+	GroupType          *string              `json:"groupType,omitempty" description:"The type of the group, when the resource is a group."`
+	ModifiedProperties *jsoniter.RawMessage `json:"modifiedProperties,omitempty" description:"The old and new values of the properties that were modified."`
+}
+
+// AuditKeyValue is a key value pair of additional details
+// nolint:lll
+type AuditKeyValue struct {
+	Key   *string `json:"key,omitempty" description:"The key of the detail."`
+	Value *string `json:"value,omitempty" description:"The value of the detail."`
+}
+
+// AuditParser parses Azure AD audit log records
+type AuditParser struct{}
+
+var _ parsers.LogParser = (*AuditParser)(nil)
+
+// New creates a new parser
+func (p *AuditParser) New() parsers.LogParser {
+	return &AuditParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *AuditParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	records, err := splitRecords(log)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*parsers.PantherLog, 0, len(records))
+	for _, record := range records {
+		event := &Audit{}
+
+		err := jsoniter.UnmarshalFromString(record, event)
+		if err != nil {
+			return nil, err
+		}
+
+		event.updatePantherFields(p)
+
+		if err := parsers.Validator.Struct(event); err != nil {
+			return nil, err
+		}
+
+		result = append(result, event.Logs()...)
+	}
+	return result, nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *AuditParser) LogType() string {
+	return TypeAudit
+}
+
+func (event *Audit) updatePantherFields(p *AuditParser) {
+	event.SetCoreFields(p.LogType(), event.Time, event)
+	event.AppendAnyIPAddressPtr(event.CallerIPAddress)
+	event.AppendAnyAzureResourceIDPtrs(event.ResourceID)
+	if event.Properties == nil {
+		return
+	}
+	if initiatedBy := event.Properties.InitiatedBy; initiatedBy != nil && initiatedBy.User != nil {
+		event.AppendAnyIPAddressPtr(initiatedBy.User.IPAddress)
+		event.AppendAnyAzureUPNPtrs(initiatedBy.User.UserPrincipalName)
+	}
+	for _, target := range event.Properties.TargetResources {
+		event.AppendAnyAzureUPNPtrs(target.UserPrincipalName)
+	}
+}
@@ -0,0 +1,105 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestAuditParser(t *testing.T) {
+	//nolint:lll
+	log := `{"time":"2020-04-21T08:30:00.1234567Z","resourceId":"/tenants/11111111-2222-3333-4444-555555555555/providers/Microsoft.aadiam","operationName":"Add member to role","operationVersion":"1.0","category":"AuditLogs","tenantId":"11111111-2222-3333-4444-555555555555","resultSignature":"None","durationMs":0,"callerIpAddress":"<null>","correlationId":"c-1","Level":4,"properties":{"id":"Directory_1","category":"RoleManagement","correlationId":"c-1","result":"success","resultReason":"","activityDisplayName":"Add member to role","activityDateTime":"2020-04-21T08:30:00.1234567+00:00","loggedByService":"Core Directory","operationType":"Assign","initiatedBy":{"user":{"id":"u-1","displayName":null,"userPrincipalName":"admin@contoso.com","ipAddress":"198.51.100.23"}},"targetResources":[{"id":"u-2","displayName":null,"type":"User","userPrincipalName":"Mallory@contoso.com","modifiedProperties":[{"displayName":"Role.DisplayName","oldValue":null,"newValue":"\"Global Administrator\""}]}],"additionalDetails":[{"key":"User-Agent","value":"Mozilla/5.0"}]}}`
+
+	expectedTime := time.Date(2020, 4, 21, 8, 30, 0, 123456700, time.UTC)
+	duration := numerics.Int64(0)
+	level := numerics.Integer(4)
+	modifiedProperties := jsoniter.RawMessage(`[{"displayName":"Role.DisplayName","oldValue":null,"newValue":"\"Global Administrator\""}]`)
+	expectedEvent := &Audit{
+		Time:             (*timestamp.RFC3339)(&expectedTime),
+		ResourceID:       aws.String("/tenants/11111111-2222-3333-4444-555555555555/providers/Microsoft.aadiam"),
+		OperationName:    aws.String("Add member to role"),
+		OperationVersion: aws.String("1.0"),
+		Category:         aws.String("AuditLogs"),
+		TenantID:         aws.String("11111111-2222-3333-4444-555555555555"),
+		ResultSignature:  aws.String("None"),
+		DurationMs:       &duration,
+		CallerIPAddress:  aws.String("<null>"),
+		CorrelationID:    aws.String("c-1"),
+		Level:            &level,
+		Properties: &AuditProperties{
+			ID:                  aws.String("Directory_1"),
+			Category:            aws.String("RoleManagement"),
+			CorrelationID:       aws.String("c-1"),
+			Result:              aws.String("success"),
+			ResultReason:        aws.String(""),
+			ActivityDisplayName: aws.String("Add member to role"),
+			ActivityDateTime:    (*timestamp.RFC3339)(&expectedTime),
+			LoggedByService:     aws.String("Core Directory"),
+			OperationType:       aws.String("Assign"),
+			InitiatedBy: &AuditInitiatedBy{
+				User: &AuditUserIdentity{
+					ID:                aws.String("u-1"),
+					UserPrincipalName: aws.String("admin@contoso.com"),
+					IPAddress:         aws.String("198.51.100.23"),
+				},
+			},
+			TargetResources: []AuditTargetResource{
+				{
+					ID:                 aws.String("u-2"),
+					Type:               aws.String("User"),
+					UserPrincipalName:  aws.String("Mallory@contoso.com"),
+					ModifiedProperties: &modifiedProperties,
+				},
+			},
+			AdditionalDetails: []AuditKeyValue{
+				{Key: aws.String("User-Agent"), Value: aws.String("Mozilla/5.0")},
+			},
+		},
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Azure.Audit")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("198.51.100.23")
+	expectedEvent.AppendAnyAzureResourceIDs("/tenants/11111111-2222-3333-4444-555555555555/providers/microsoft.aadiam")
+	expectedEvent.AppendAnyAzureUPNs("admin@contoso.com", "mallory@contoso.com")
+
+	checkAudit(t, log, expectedEvent)
+}
+
+func TestAuditType(t *testing.T) {
+	parser := (&AuditParser{}).New()
+	require.Equal(t, "Azure.Audit", parser.LogType())
+}
+
+func checkAudit(t *testing.T, log string, expectedEvent *Audit) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := (&AuditParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
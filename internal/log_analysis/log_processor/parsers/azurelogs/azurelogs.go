@@ -0,0 +1,51 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package azurelogs parses Azure Monitor logs exported to Event Hubs or Storage accounts.
+
+import (
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// PantherPrefix is the prefix of all logs parsed by this package
+const PantherPrefix = "Azure"
+
+// recordsEnvelope is how Azure Monitor batches log records streamed to an Event Hub
+type recordsEnvelope struct {
+	Records []jsoniter.RawMessage `json:"records"`
+}
+
+// splitRecords returns the records of an Event Hub envelope, or the log itself if it is a single record
+func splitRecords(log string) ([]string, error) {
+	envelope := recordsEnvelope{}
+	if err := jsoniter.UnmarshalFromString(log, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Records == nil {
+		return []string{strings.TrimSpace(log)}, nil
+	}
+	records := make([]string, len(envelope.Records))
+	for i, record := range envelope.Records {
+		records[i] = string(record)
+	}
+	return records, nil
+}
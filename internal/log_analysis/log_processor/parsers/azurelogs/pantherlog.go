@@ -0,0 +1,79 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// nolint(lll)
+type AzurePantherLog struct {
+	parsers.PantherLog
+
+	PantherAnyAzureResourceIDs *parsers.PantherAnyString `json:"p_any_azure_resource_ids,omitempty" description:"Panther added field with collection of azure resource ids associated with the row"`
+	PantherAnyAzureUPNs        *parsers.PantherAnyString `json:"p_any_azure_upns,omitempty" description:"Panther added field with collection of azure user principal names associated with the row"`
+}
+
+func (pl *AzurePantherLog) AppendAnyAzureResourceIDPtrs(values ...*string) {
+	for _, value := range values {
+		if value != nil {
+			pl.AppendAnyAzureResourceIDs(*value)
+		}
+	}
+}
+
+// NOTE: resource ids are case insensitive (and are logged in mixed case), they are stored lower case
+func (pl *AzurePantherLog) AppendAnyAzureResourceIDs(values ...string) {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if pl.PantherAnyAzureResourceIDs == nil { // lazy create
+			pl.PantherAnyAzureResourceIDs = parsers.NewPantherAnyString()
+		}
+		parsers.AppendAnyString(pl.PantherAnyAzureResourceIDs, strings.ToLower(value))
+	}
+}
+
+func (pl *AzurePantherLog) AppendAnyAzureUPNPtrs(values ...*string) {
+	for _, value := range values {
+		if value != nil {
+			pl.AppendAnyAzureUPNs(*value)
+		}
+	}
+}
+
+// NOTE: user principal names are case insensitive, they are stored lower case.
+// They are also added to the usernames and, since they have the form of an email address, to the emails.
+func (pl *AzurePantherLog) AppendAnyAzureUPNs(values ...string) {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if pl.PantherAnyAzureUPNs == nil { // lazy create
+			pl.PantherAnyAzureUPNs = parsers.NewPantherAnyString()
+		}
+		upn := strings.ToLower(value)
+		parsers.AppendAnyString(pl.PantherAnyAzureUPNs, upn)
+		pl.AppendAnyUsernames(upn)
+		pl.AppendAnyEmails(upn)
+	}
+}
@@ -0,0 +1,182 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+// TypeSignIn is the log type of Azure AD sign-in log records
+const TypeSignIn = PantherPrefix + ".SignIn"
+
+// SignInDesc describes the SignIn log record
+var SignInDesc = `Azure Active Directory sign-in log records of user and application sign-ins, as exported to Event Hubs or Storage accounts.
+Reference: https://docs.microsoft.com/en-us/azure/active-directory/reports-monitoring/reference-azure-monitor-sign-ins-log-schema`
+
+// SignIn is an Azure AD sign-in log record
+// nolint:lll
+type SignIn struct {
+	Time              *timestamp.RFC3339 `json:"time" validate:"required" description:"The date and time (UTC) of the sign-in."`
+	ResourceID        *string            `json:"resourceId,omitempty" description:"The resource ID of the Azure AD tenant."`
+	OperationName     *string            `json:"operationName,omitempty" description:"The name of the operation (Sign-in activity)."`
+	OperationVersion  *string            `json:"operationVersion,omitempty" description:"The REST API version that is requested by the client."`
+	Category          *string            `json:"category" validate:"required,oneof=SignInLogs NonInteractiveUserSignInLogs ServicePrincipalSignInLogs ManagedIdentitySignInLogs" description:"The category of the sign-in (SignInLogs, NonInteractiveUserSignInLogs, ServicePrincipalSignInLogs or ManagedIdentitySignInLogs)."`
+	TenantID          *string            `json:"tenantId,omitempty" description:"The tenant GUID that is associated with the log."`
+	ResultType        *string            `json:"resultType,omitempty" description:"The result of the sign-in, 0 on success, otherwise the error code."`
+	ResultSignature   *string            `json:"resultSignature,omitempty" description:"The error code of the sign-in, if any."`
+	ResultDescription *string            `json:"resultDescription,omitempty" description:"The error description of the sign-in, if any."`
+	DurationMs        *numerics.Int64    `json:"durationMs,omitempty" description:"The duration of the operation in milliseconds."`
+	CallerIPAddress   *string            `json:"callerIpAddress,omitempty" description:"The IP address of the client that made the request."`
+	CorrelationID     *string            `json:"correlationId,omitempty" description:"The GUID that is passed by the client, used to correlate client-side operations with server-side operations."`
+	Identity          *string            `json:"identity,omitempty" description:"The identity from the token that was presented when you made the request."`
+	Level             *numerics.Integer  `json:"level,omitempty" description:"The type of message (4 is Informational)."`
+	Location          *string            `json:"location,omitempty" description:"The location of the data center."`
+	Properties        *SignInProperties  `json:"properties,omitempty" description:"The properties of the sign-in."`
+
+	// NOTE: added to end of struct to allow expansion later
+	AzurePantherLog
+}
+
+// SignInProperties are the details of a sign-in
+// nolint:lll
+type SignInProperties struct {
+	ID                               *string              `json:"id,omitempty" description:"The unique ID of the sign-in."`
+	CreatedDateTime                  *timestamp.RFC3339   `json:"createdDateTime,omitempty" description:"The date and time (UTC) the sign-in was initiated."`
+	UserDisplayName                  *string              `json:"userDisplayName,omitempty" description:"The display name of the user."`
+	UserPrincipalName                *string              `json:"userPrincipalName,omitempty" description:"The user principal name (UPN) of the user."`
+	UserID                           *string              `json:"userId,omitempty" description:"The ID of the user."`
+	AppID                            *string              `json:"appId,omitempty" description:"The unique GUID representing the app ID in the Azure AD."`
+	AppDisplayName                   *string              `json:"appDisplayName,omitempty" description:"The app name displayed in the Azure portal."`
+	IPAddress                        *string              `json:"ipAddress,omitempty" description:"The IP address of the client used to sign in."`
+	ClientAppUsed                    *string              `json:"clientAppUsed,omitempty" description:"The legacy client used for sign-in activity (e.g. Browser, Exchange ActiveSync, IMAP)."`
+	UserAgent                        *string              `json:"userAgent,omitempty" description:"The user agent of the client used to sign in."`
+	CorrelationID                    *string              `json:"correlationId,omitempty" description:"The ID that's sent from the client when sign-in is initiated."`
+	ConditionalAccessStatus          *string              `json:"conditionalAccessStatus,omitempty" description:"The status of the conditional access policy triggered (success, failure or notApplied)."`
+	OriginalRequestID                *string              `json:"originalRequestId,omitempty" description:"The request ID of the first request in the authentication sequence."`
+	IsInteractive                    *bool                `json:"isInteractive,omitempty" description:"Whether the sign-in is interactive."`
+	TokenIssuerName                  *string              `json:"tokenIssuerName,omitempty" description:"The name of the identity provider."`
+	TokenIssuerType                  *string              `json:"tokenIssuerType,omitempty" description:"The type of the identity provider (e.g. AzureAD, ADFederationServices)."`
+	ProcessingTimeInMilliseconds     *int                 `json:"processingTimeInMilliseconds,omitempty" description:"The request processing time in milliseconds in the AD STS."`
+	RiskDetail                       *string              `json:"riskDetail,omitempty" description:"The reason behind a specific state of a risky user, sign-in or risk event."`
+	RiskLevelAggregated              *string              `json:"riskLevelAggregated,omitempty" description:"The aggregated risk level (none, low, medium, high or hidden)."`
+	RiskLevelDuringSignIn            *string              `json:"riskLevelDuringSignIn,omitempty" description:"The risk level during sign-in (none, low, medium, high or hidden)."`
+	RiskState                        *string              `json:"riskState,omitempty" description:"The risk state of a risky user, sign-in or risk event."`
+	RiskEventTypes                   []string             `json:"riskEventTypes,omitempty" description:"The risk event types associated with the sign-in."`
+	ResourceDisplayName              *string              `json:"resourceDisplayName,omitempty" description:"The name of the resource that the user signed in to."`
+	ResourceID                       *string              `json:"resourceId,omitempty" description:"The ID of the resource that the user signed in to."`
+	AuthenticationMethodsUsed        []string             `json:"authenticationMethodsUsed,omitempty" description:"The authentication methods used."`
+	Status                           *SignInStatus        `json:"status,omitempty" description:"The sign-in status."`
+	DeviceDetail                     *SignInDeviceDetail  `json:"deviceDetail,omitempty" description:"The device information from where the sign-in occurred."`
+	Location                         *SignInLocation      `json:"location,omitempty" description:"The city, state and country from where the sign-in occurred."`
+	AppliedConditionalAccessPolicies *jsoniter.RawMessage `json:"appliedConditionalAccessPolicies,omitempty" description:"The conditional access policies triggered by the sign-in."`
+	AuthenticationDetails            *jsoniter.RawMessage `json:"authenticationDetails,omitempty" description:"The steps of the authentication."`
+	NetworkLocationDetails           *jsoniter.RawMessage `json:"networkLocationDetails,omitempty" description:"The named network locations of the sign-in."`
+}
+
+// SignInStatus is the result of a sign-in
+// nolint:lll
+type SignInStatus struct {
+	ErrorCode         *int    `json:"errorCode,omitempty" description:"The error code of the sign-in, 0 on success."`
+	FailureReason     *string `json:"failureReason,omitempty" description:"The cause of the error."`
+	AdditionalDetails *string `json:"additionalDetails,omitempty" description:"The details of the error."`
+}
+
+// SignInDeviceDetail is the device used to sign in
+// nolint:lll
+type SignInDeviceDetail struct {
+	DeviceID        *string `json:"deviceId,omitempty" description:"The ID of the device."`
+	DisplayName     *string `json:"displayName,omitempty" description:"The display name of the device."`
+	OperatingSystem *string `json:"operatingSystem,omitempty" description:"The operating system of the device."`
+	Browser         *string `json:"browser,omitempty" description:"The browser used to sign in."`
+	IsCompliant     *bool   `json:"isCompliant,omitempty" description:"Whether the device is compliant."`
+	IsManaged       *bool   `json:"isManaged,omitempty" description:"Whether the device is managed."`
+	TrustType       *string `json:"trustType,omitempty" description:"How the device is joined to Azure AD."`
+}
+
+// SignInLocation is the location of a sign-in
+// nolint:lll
+type SignInLocation struct {
+	City            *string               `json:"city,omitempty" description:"The city of the sign-in."`
+	State           *string               `json:"state,omitempty" description:"The state of the sign-in."`
+	CountryOrRegion *string               `json:"countryOrRegion,omitempty" description:"The country code of the sign-in."`
+	GeoCoordinates  *SignInGeoCoordinates `json:"geoCoordinates,omitempty" description:"The latitude and longitude of the sign-in."`
+}
+
+// SignInGeoCoordinates are the coordinates of a sign-in
+// nolint:lll
+type SignInGeoCoordinates struct {
+	Latitude  *float64 `json:"latitude,omitempty" description:"The latitude of the sign-in."`
+	Longitude *float64 `json:"longitude,omitempty" description:"The longitude of the sign-in."`
+}
+
+// SignInParser parses Azure AD sign-in log records
+type SignInParser struct{}
+
+var _ parsers.LogParser = (*SignInParser)(nil)
+
+// New creates a new parser
+func (p *SignInParser) New() parsers.LogParser {
+	return &SignInParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *SignInParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	records, err := splitRecords(log)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*parsers.PantherLog, 0, len(records))
+	for _, record := range records {
+		event := &SignIn{}
+
+		err := jsoniter.UnmarshalFromString(record, event)
+		if err != nil {
+			return nil, err
+		}
+
+		event.updatePantherFields(p)
+
+		if err := parsers.Validator.Struct(event); err != nil {
+			return nil, err
+		}
+
+		result = append(result, event.Logs()...)
+	}
+	return result, nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *SignInParser) LogType() string {
+	return TypeSignIn
+}
+
+func (event *SignIn) updatePantherFields(p *SignInParser) {
+	event.SetCoreFields(p.LogType(), event.Time, event)
+	event.AppendAnyIPAddressPtr(event.CallerIPAddress)
+	event.AppendAnyAzureResourceIDPtrs(event.ResourceID)
+	if event.Properties != nil {
+		event.AppendAnyIPAddressPtr(event.Properties.IPAddress)
+		event.AppendAnyAzureUPNPtrs(event.Properties.UserPrincipalName)
+	}
+}
@@ -0,0 +1,110 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestSignInParser(t *testing.T) {
+	//nolint:lll
+	log := `{"time":"2020-04-20T12:00:00.0000000Z","resourceId":"/tenants/11111111-2222-3333-4444-555555555555/providers/Microsoft.aadiam","operationName":"Sign-in activity","operationVersion":"1.0","category":"SignInLogs","tenantId":"11111111-2222-3333-4444-555555555555","resultType":"50126","resultSignature":"None","resultDescription":"Invalid username or password.","durationMs":0,"callerIpAddress":"203.0.113.7","correlationId":"8f0d7c5a-2d1e-4f1a-9a3b-1c2d3e4f5a6b","identity":"Jane Doe","Level":4,"location":"US","properties":{"id":"a1b2c3d4","createdDateTime":"2020-04-20T11:59:58.1234567+00:00","userDisplayName":"Jane Doe","userPrincipalName":"Jane.Doe@contoso.com","userId":"u-1","appId":"app-1","appDisplayName":"Azure Portal","ipAddress":"203.0.113.7","clientAppUsed":"Browser","conditionalAccessStatus":"notApplied","isInteractive":true,"riskEventTypes":[],"status":{"errorCode":50126,"failureReason":"Invalid username or password."},"deviceDetail":{"operatingSystem":"Windows 10","browser":"Chrome 80.0.3987"},"location":{"city":"Seattle","state":"Washington","countryOrRegion":"US","geoCoordinates":{"latitude":47.6,"longitude":-122.3}}}}`
+
+	expectedTime := time.Date(2020, 4, 20, 12, 0, 0, 0, time.UTC)
+	createdTime := time.Date(2020, 4, 20, 11, 59, 58, 123456700, time.UTC)
+	duration := numerics.Int64(0)
+	level := numerics.Integer(4)
+	expectedEvent := &SignIn{
+		Time:              (*timestamp.RFC3339)(&expectedTime),
+		ResourceID:        aws.String("/tenants/11111111-2222-3333-4444-555555555555/providers/Microsoft.aadiam"),
+		OperationName:     aws.String("Sign-in activity"),
+		OperationVersion:  aws.String("1.0"),
+		Category:          aws.String("SignInLogs"),
+		TenantID:          aws.String("11111111-2222-3333-4444-555555555555"),
+		ResultType:        aws.String("50126"),
+		ResultSignature:   aws.String("None"),
+		ResultDescription: aws.String("Invalid username or password."),
+		DurationMs:        &duration,
+		CallerIPAddress:   aws.String("203.0.113.7"),
+		CorrelationID:     aws.String("8f0d7c5a-2d1e-4f1a-9a3b-1c2d3e4f5a6b"),
+		Identity:          aws.String("Jane Doe"),
+		Level:             &level,
+		Location:          aws.String("US"),
+		Properties: &SignInProperties{
+			ID:                      aws.String("a1b2c3d4"),
+			CreatedDateTime:         (*timestamp.RFC3339)(&createdTime),
+			UserDisplayName:         aws.String("Jane Doe"),
+			UserPrincipalName:       aws.String("Jane.Doe@contoso.com"),
+			UserID:                  aws.String("u-1"),
+			AppID:                   aws.String("app-1"),
+			AppDisplayName:          aws.String("Azure Portal"),
+			IPAddress:               aws.String("203.0.113.7"),
+			ClientAppUsed:           aws.String("Browser"),
+			ConditionalAccessStatus: aws.String("notApplied"),
+			IsInteractive:           aws.Bool(true),
+			RiskEventTypes:          []string{},
+			Status: &SignInStatus{
+				ErrorCode:     aws.Int(50126),
+				FailureReason: aws.String("Invalid username or password."),
+			},
+			DeviceDetail: &SignInDeviceDetail{
+				OperatingSystem: aws.String("Windows 10"),
+				Browser:         aws.String("Chrome 80.0.3987"),
+			},
+			Location: &SignInLocation{
+				City:            aws.String("Seattle"),
+				State:           aws.String("Washington"),
+				CountryOrRegion: aws.String("US"),
+				GeoCoordinates: &SignInGeoCoordinates{
+					Latitude:  aws.Float64(47.6),
+					Longitude: aws.Float64(-122.3),
+				},
+			},
+		},
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Azure.SignIn")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("203.0.113.7")
+	expectedEvent.AppendAnyAzureResourceIDs("/tenants/11111111-2222-3333-4444-555555555555/providers/microsoft.aadiam")
+	expectedEvent.AppendAnyAzureUPNs("jane.doe@contoso.com")
+
+	checkSignIn(t, log, expectedEvent)
+}
+
+func TestSignInType(t *testing.T) {
+	parser := (&SignInParser{}).New()
+	require.Equal(t, "Azure.SignIn", parser.LogType())
+}
+
+func checkSignIn(t *testing.T, log string, expectedEvent *SignIn) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := (&SignInParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
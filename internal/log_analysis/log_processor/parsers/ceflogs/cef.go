@@ -0,0 +1,456 @@
+package ceflogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/sysloglogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var CEFDesc = `ArcSight Common Event Format (CEF) events, optionally sent with a syslog header
+Reference: https://community.microfocus.com/t5/ArcSight-Connectors/ArcSight-Common-Event-Format-CEF-Implementation-Standard/ta-p/1645557`
+
+const (
+	cefPrefix = "CEF:"
+	// version, device vendor, device product, device version, signature id, name, severity
+	cefHeaderFields = 7
+)
+
+// nolint:lll
+type CEF struct {
+	Syslog        *sysloglogs.Header `json:"syslog,omitempty" description:"The syslog header the event was sent with."`
+	Version       *int               `json:"version" validate:"required" description:"The version of the CEF format."`
+	DeviceVendor  *string            `json:"deviceVendor" validate:"required" description:"The vendor of the sending device."`
+	DeviceProduct *string            `json:"deviceProduct" validate:"required" description:"The product name of the sending device."`
+	DeviceVersion *string            `json:"deviceVersion" validate:"required" description:"The version of the sending device."`
+	SignatureID   *string            `json:"signatureId" validate:"required" description:"The unique identifier of the event type (Device Event Class ID)."`
+	Name          *string            `json:"name" validate:"required" description:"A human readable description of the event."`
+	Severity      *string            `json:"severity" validate:"required" description:"The importance of the event, from 0 to 10 or one of Unknown, Low, Medium, High and Very-High."`
+
+	// Extension fields defined by the CEF dictionary
+	Action                       *string            `json:"act,omitempty" description:"The action taken by the device."`
+	ApplicationProtocol          *string            `json:"app,omitempty" description:"The application level protocol, e.g. HTTP, HTTPS, SSHv2, Telnet, POP, IMAP."`
+	BaseEventCount               *int64             `json:"cnt,omitempty" description:"The number of times the same event was observed."`
+	DeviceAddress                *string            `json:"dvc,omitempty" description:"The IP address of the device that generated the event."`
+	DeviceHostName               *string            `json:"dvchost,omitempty" description:"The FQDN of the device that generated the event."`
+	DeviceMacAddress             *string            `json:"dvcmac,omitempty" description:"The MAC address of the device that generated the event."`
+	DeviceProcessID              *int64             `json:"dvcpid,omitempty" description:"The process id of the device process that generated the event."`
+	DeviceDirection              *int               `json:"deviceDirection,omitempty" description:"The direction of the observed communication, 0 for inbound or 1 for outbound."`
+	DeviceExternalID             *string            `json:"deviceExternalId,omitempty" description:"A name that uniquely identifies the device generating the event."`
+	DeviceFacility               *string            `json:"deviceFacility,omitempty" description:"The facility generating the event."`
+	DeviceInboundInterface       *string            `json:"deviceInboundInterface,omitempty" description:"The interface on which the packet or data entered the device."`
+	DeviceOutboundInterface      *string            `json:"deviceOutboundInterface,omitempty" description:"The interface on which the packet or data left the device."`
+	DeviceProcessName            *string            `json:"deviceProcessName,omitempty" description:"The process name associated with the event."`
+	DeviceEventCategory          *string            `json:"cat,omitempty" description:"The category assigned by the originating device."`
+	DeviceCustomString1          *string            `json:"cs1,omitempty" description:"Custom string field 1."`
+	DeviceCustomString1Label     *string            `json:"cs1Label,omitempty" description:"The label of custom string field 1."`
+	DeviceCustomString2          *string            `json:"cs2,omitempty" description:"Custom string field 2."`
+	DeviceCustomString2Label     *string            `json:"cs2Label,omitempty" description:"The label of custom string field 2."`
+	DeviceCustomString3          *string            `json:"cs3,omitempty" description:"Custom string field 3."`
+	DeviceCustomString3Label     *string            `json:"cs3Label,omitempty" description:"The label of custom string field 3."`
+	DeviceCustomString4          *string            `json:"cs4,omitempty" description:"Custom string field 4."`
+	DeviceCustomString4Label     *string            `json:"cs4Label,omitempty" description:"The label of custom string field 4."`
+	DeviceCustomString5          *string            `json:"cs5,omitempty" description:"Custom string field 5."`
+	DeviceCustomString5Label     *string            `json:"cs5Label,omitempty" description:"The label of custom string field 5."`
+	DeviceCustomString6          *string            `json:"cs6,omitempty" description:"Custom string field 6."`
+	DeviceCustomString6Label     *string            `json:"cs6Label,omitempty" description:"The label of custom string field 6."`
+	DeviceCustomNumber1          *int64             `json:"cn1,omitempty" description:"Custom number field 1."`
+	DeviceCustomNumber1Label     *string            `json:"cn1Label,omitempty" description:"The label of custom number field 1."`
+	DeviceCustomNumber2          *int64             `json:"cn2,omitempty" description:"Custom number field 2."`
+	DeviceCustomNumber2Label     *string            `json:"cn2Label,omitempty" description:"The label of custom number field 2."`
+	DeviceCustomNumber3          *int64             `json:"cn3,omitempty" description:"Custom number field 3."`
+	DeviceCustomNumber3Label     *string            `json:"cn3Label,omitempty" description:"The label of custom number field 3."`
+	DestinationHostName          *string            `json:"dhost,omitempty" description:"The destination the event refers to in an IP network, a FQDN if available."`
+	DestinationMacAddress        *string            `json:"dmac,omitempty" description:"The destination MAC address."`
+	DestinationNtDomain          *string            `json:"dntdom,omitempty" description:"The Windows domain name of the destination address."`
+	DestinationProcessID         *int64             `json:"dpid,omitempty" description:"The id of the destination process associated with the event."`
+	DestinationUserPrivileges    *string            `json:"dpriv,omitempty" description:"The privileges of the destination user, e.g. Administrator, User, Guest."`
+	DestinationProcessName       *string            `json:"dproc,omitempty" description:"The name of the destination process associated with the event."`
+	DestinationPort              *uint16            `json:"dpt,omitempty" description:"The destination port."`
+	DestinationAddress           *string            `json:"dst,omitempty" description:"The destination IP address."`
+	DestinationUserID            *string            `json:"duid,omitempty" description:"The id of the destination user."`
+	DestinationUserName          *string            `json:"duser,omitempty" description:"The name of the destination user, an email address is possible."`
+	DestinationTranslatedAddress *string            `json:"destinationTranslatedAddress,omitempty" description:"The destination IP address after network address translation."`
+	DestinationTranslatedPort    *uint16            `json:"destinationTranslatedPort,omitempty" description:"The destination port after network address translation."`
+	DestinationServiceName       *string            `json:"destinationServiceName,omitempty" description:"The service targeted by the event."`
+	EndTime                      *timestamp.RFC3339 `json:"end,omitempty" description:"The time at which the activity related to the event ended."`
+	ExternalID                   *string            `json:"externalId,omitempty" description:"The id used by the originating device."`
+	FileHash                     *string            `json:"fileHash,omitempty" description:"The hash of the file."`
+	FileName                     *string            `json:"fname,omitempty" description:"The name of the file."`
+	FilePath                     *string            `json:"filePath,omitempty" description:"The full path of the file, including the file name."`
+	FileSize                     *int64             `json:"fsize,omitempty" description:"The size of the file."`
+	BytesIn                      *int64             `json:"in,omitempty" description:"The number of bytes transferred inbound."`
+	BytesOut                     *int64             `json:"out,omitempty" description:"The number of bytes transferred outbound."`
+	Message                      *string            `json:"msg,omitempty" description:"A message giving more details about the event."`
+	EventOutcome                 *string            `json:"outcome,omitempty" description:"The outcome of the event, e.g. success or failure."`
+	TransportProtocol            *string            `json:"proto,omitempty" description:"The layer-4 protocol used, e.g. TCP or UDP."`
+	Reason                       *string            `json:"reason,omitempty" description:"The reason an audit event was generated."`
+	RequestURL                   *string            `json:"request,omitempty" description:"The URL accessed for an HTTP request."`
+	RequestClientApplication     *string            `json:"requestClientApplication,omitempty" description:"The user agent associated with the request."`
+	RequestMethod                *string            `json:"requestMethod,omitempty" description:"The method used to access a URL, e.g. POST or GET."`
+	ReceiptTime                  *timestamp.RFC3339 `json:"rt,omitempty" description:"The time at which the event related to the activity was received."`
+	SourceHostName               *string            `json:"shost,omitempty" description:"The source the event refers to in an IP network, a FQDN if available."`
+	SourceMacAddress             *string            `json:"smac,omitempty" description:"The source MAC address."`
+	SourceNtDomain               *string            `json:"sntdom,omitempty" description:"The Windows domain name of the source address."`
+	SourceProcessID              *int64             `json:"spid,omitempty" description:"The id of the source process associated with the event."`
+	SourceUserPrivileges         *string            `json:"spriv,omitempty" description:"The privileges of the source user, e.g. Administrator, User, Guest."`
+	SourceProcessName            *string            `json:"sproc,omitempty" description:"The name of the source process associated with the event."`
+	SourcePort                   *uint16            `json:"spt,omitempty" description:"The source port."`
+	SourceAddress                *string            `json:"src,omitempty" description:"The source IP address."`
+	SourceUserID                 *string            `json:"suid,omitempty" description:"The id of the source user."`
+	SourceUserName               *string            `json:"suser,omitempty" description:"The name of the source user, an email address is possible."`
+	SourceTranslatedAddress      *string            `json:"sourceTranslatedAddress,omitempty" description:"The source IP address after network address translation."`
+	SourceTranslatedPort         *uint16            `json:"sourceTranslatedPort,omitempty" description:"The source port after network address translation."`
+	StartTime                    *timestamp.RFC3339 `json:"start,omitempty" description:"The time at which the activity related to the event started."`
+	Extensions                   *map[string]string `json:"extensions,omitempty" description:"The extension fields that are not part of the CEF dictionary (or have invalid values)."`
+
+	// NOTE: added to end of struct to allow expansion later
+	parsers.PantherLog
+}
+
+// CEFParser parses CEF events, with or without a syslog header
+type CEFParser struct {
+	syslogParser *sysloglogs.HeaderParser
+}
+
+var _ parsers.LogParser = (*CEFParser)(nil)
+
+// New returns an initialized LogParser for CEF events
+func (p *CEFParser) New() parsers.LogParser {
+	return &CEFParser{
+		syslogParser: sysloglogs.NewHeaderParser(),
+	}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *CEFParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	if p.syslogParser == nil {
+		return nil, errors.New("parser can not be nil")
+	}
+
+	start := strings.Index(log, cefPrefix)
+	if start < 0 {
+		return nil, errors.New("CEF prefix not found")
+	}
+	event := &CEF{}
+	if header := log[:start]; strings.TrimSpace(header) != "" {
+		syslogHeader, err := p.syslogParser.Parse(header)
+		if err != nil {
+			return nil, err
+		}
+		event.Syslog = syslogHeader
+	}
+
+	fields, extension, err := splitHeader(log[start+len(cefPrefix):])
+	if err != nil {
+		return nil, err
+	}
+	version, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, errors.Errorf("invalid CEF version %q", fields[0])
+	}
+	event.Version = &version
+	event.DeviceVendor = &fields[1]
+	event.DeviceProduct = &fields[2]
+	event.DeviceVersion = &fields[3]
+	event.SignatureID = &fields[4]
+	event.Name = &fields[5]
+	event.Severity = &fields[6]
+
+	for _, pair := range splitExtension(extension) {
+		if setField, found := extensionFields[pair.key]; found && setField(event, pair.value) {
+			continue
+		}
+		if event.Extensions == nil {
+			event.Extensions = &map[string]string{}
+		}
+		(*event.Extensions)[pair.key] = pair.value
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *CEFParser) LogType() string {
+	return "CEF.Event"
+}
+
+func (event *CEF) updatePantherFields(p *CEFParser) {
+	eventTime := event.ReceiptTime
+	if eventTime == nil && event.Syslog != nil {
+		eventTime = event.Syslog.Timestamp
+	}
+	event.SetCoreFields(p.LogType(), eventTime, event)
+
+	if event.Syslog != nil {
+		event.Syslog.UpdatePantherFields(&event.PantherLog)
+	}
+	for _, address := range []*string{
+		event.SourceAddress,
+		event.DestinationAddress,
+		event.DeviceAddress,
+		event.SourceTranslatedAddress,
+		event.DestinationTranslatedAddress,
+	} {
+		event.AppendAnyIPAddressPtr(address)
+	}
+	for _, host := range []*string{event.SourceHostName, event.DestinationHostName, event.DeviceHostName} {
+		// host names should be FQDNs but devices often put IP addresses in them
+		if !event.AppendAnyIPAddressPtr(host) {
+			event.AppendAnyDomainNamePtrs(host)
+		}
+	}
+	// user names can be email addresses, those that are not are ignored by AppendAnyEmailPtrs
+	event.AppendAnyUsernamePtrs(event.SourceUserName, event.DestinationUserName)
+	event.AppendAnyEmailPtrs(event.SourceUserName, event.DestinationUserName)
+	event.AppendAnyMACAddressPtrs(event.SourceMacAddress, event.DestinationMacAddress, event.DeviceMacAddress)
+}
+
+// splitHeader splits the pipe separated header fields (after the CEF: prefix) from the extension
+func splitHeader(log string) (fields []string, extension string, err error) {
+	var field strings.Builder
+	for i := 0; i < len(log); i++ {
+		switch c := log[i]; {
+		case c == '\\' && i+1 < len(log) && (log[i+1] == '|' || log[i+1] == '\\'):
+			i++
+			field.WriteByte(log[i])
+		case c == '|':
+			fields = append(fields, field.String())
+			field.Reset()
+			if len(fields) == cefHeaderFields {
+				return fields, log[i+1:], nil
+			}
+		default:
+			field.WriteByte(c)
+		}
+	}
+	return nil, "", errors.Errorf("expected %d CEF header fields, found %d", cefHeaderFields, len(fields))
+}
+
+type keyValue struct {
+	key   string
+	value string
+}
+
+// Extension keys are followed by an equal sign, equal signs in values are escaped
+var extensionKeyRegex = regexp.MustCompile(`(?:^|\s)([\w.\-\[\]]+)=`)
+
+// splitExtension splits the space separated key=value pairs of the extension, values can contain spaces
+func splitExtension(extension string) (pairs []keyValue) {
+	matches := extensionKeyRegex.FindAllStringSubmatchIndex(extension, -1)
+	for i, match := range matches {
+		valueEnd := len(extension)
+		if i+1 < len(matches) {
+			valueEnd = matches[i+1][0]
+		}
+		pairs = append(pairs, keyValue{
+			key:   extension[match[2]:match[3]],
+			value: unescapeValue(strings.TrimSpace(extension[match[1]:valueEnd])),
+		})
+	}
+	return pairs
+}
+
+var valueReplacer = strings.NewReplacer(`\\`, `\`, `\=`, `=`, `\n`, "\n", `\r`, "\r")
+
+func unescapeValue(value string) string {
+	return valueReplacer.Replace(value)
+}
+
+// extensionSetter sets the field of a dictionary key, it returns false if the value is invalid for the field
+type extensionSetter func(event *CEF, value string) bool
+
+// nolint:lll
+var extensionFields = map[string]extensionSetter{
+	"act":                          stringField(func(e *CEF) **string { return &e.Action }),
+	"app":                          stringField(func(e *CEF) **string { return &e.ApplicationProtocol }),
+	"cnt":                          int64Field(func(e *CEF) **int64 { return &e.BaseEventCount }),
+	"dvc":                          stringField(func(e *CEF) **string { return &e.DeviceAddress }),
+	"dvchost":                      stringField(func(e *CEF) **string { return &e.DeviceHostName }),
+	"dvcmac":                       stringField(func(e *CEF) **string { return &e.DeviceMacAddress }),
+	"dvcpid":                       int64Field(func(e *CEF) **int64 { return &e.DeviceProcessID }),
+	"deviceDirection":              intField(func(e *CEF) **int { return &e.DeviceDirection }),
+	"deviceExternalId":             stringField(func(e *CEF) **string { return &e.DeviceExternalID }),
+	"deviceFacility":               stringField(func(e *CEF) **string { return &e.DeviceFacility }),
+	"deviceInboundInterface":       stringField(func(e *CEF) **string { return &e.DeviceInboundInterface }),
+	"deviceOutboundInterface":      stringField(func(e *CEF) **string { return &e.DeviceOutboundInterface }),
+	"deviceProcessName":            stringField(func(e *CEF) **string { return &e.DeviceProcessName }),
+	"cat":                          stringField(func(e *CEF) **string { return &e.DeviceEventCategory }),
+	"cs1":                          stringField(func(e *CEF) **string { return &e.DeviceCustomString1 }),
+	"cs1Label":                     stringField(func(e *CEF) **string { return &e.DeviceCustomString1Label }),
+	"cs2":                          stringField(func(e *CEF) **string { return &e.DeviceCustomString2 }),
+	"cs2Label":                     stringField(func(e *CEF) **string { return &e.DeviceCustomString2Label }),
+	"cs3":                          stringField(func(e *CEF) **string { return &e.DeviceCustomString3 }),
+	"cs3Label":                     stringField(func(e *CEF) **string { return &e.DeviceCustomString3Label }),
+	"cs4":                          stringField(func(e *CEF) **string { return &e.DeviceCustomString4 }),
+	"cs4Label":                     stringField(func(e *CEF) **string { return &e.DeviceCustomString4Label }),
+	"cs5":                          stringField(func(e *CEF) **string { return &e.DeviceCustomString5 }),
+	"cs5Label":                     stringField(func(e *CEF) **string { return &e.DeviceCustomString5Label }),
+	"cs6":                          stringField(func(e *CEF) **string { return &e.DeviceCustomString6 }),
+	"cs6Label":                     stringField(func(e *CEF) **string { return &e.DeviceCustomString6Label }),
+	"cn1":                          int64Field(func(e *CEF) **int64 { return &e.DeviceCustomNumber1 }),
+	"cn1Label":                     stringField(func(e *CEF) **string { return &e.DeviceCustomNumber1Label }),
+	"cn2":                          int64Field(func(e *CEF) **int64 { return &e.DeviceCustomNumber2 }),
+	"cn2Label":                     stringField(func(e *CEF) **string { return &e.DeviceCustomNumber2Label }),
+	"cn3":                          int64Field(func(e *CEF) **int64 { return &e.DeviceCustomNumber3 }),
+	"cn3Label":                     stringField(func(e *CEF) **string { return &e.DeviceCustomNumber3Label }),
+	"dhost":                        stringField(func(e *CEF) **string { return &e.DestinationHostName }),
+	"dmac":                         stringField(func(e *CEF) **string { return &e.DestinationMacAddress }),
+	"dntdom":                       stringField(func(e *CEF) **string { return &e.DestinationNtDomain }),
+	"dpid":                         int64Field(func(e *CEF) **int64 { return &e.DestinationProcessID }),
+	"dpriv":                        stringField(func(e *CEF) **string { return &e.DestinationUserPrivileges }),
+	"dproc":                        stringField(func(e *CEF) **string { return &e.DestinationProcessName }),
+	"dpt":                          portField(func(e *CEF) **uint16 { return &e.DestinationPort }),
+	"dst":                          stringField(func(e *CEF) **string { return &e.DestinationAddress }),
+	"duid":                         stringField(func(e *CEF) **string { return &e.DestinationUserID }),
+	"duser":                        stringField(func(e *CEF) **string { return &e.DestinationUserName }),
+	"destinationTranslatedAddress": stringField(func(e *CEF) **string { return &e.DestinationTranslatedAddress }),
+	"destinationTranslatedPort":    portField(func(e *CEF) **uint16 { return &e.DestinationTranslatedPort }),
+	"destinationServiceName":       stringField(func(e *CEF) **string { return &e.DestinationServiceName }),
+	"end":                          timeField(func(e *CEF) **timestamp.RFC3339 { return &e.EndTime }),
+	"externalId":                   stringField(func(e *CEF) **string { return &e.ExternalID }),
+	"fileHash":                     stringField(func(e *CEF) **string { return &e.FileHash }),
+	"fname":                        stringField(func(e *CEF) **string { return &e.FileName }),
+	"filePath":                     stringField(func(e *CEF) **string { return &e.FilePath }),
+	"fsize":                        int64Field(func(e *CEF) **int64 { return &e.FileSize }),
+	"in":                           int64Field(func(e *CEF) **int64 { return &e.BytesIn }),
+	"out":                          int64Field(func(e *CEF) **int64 { return &e.BytesOut }),
+	"msg":                          stringField(func(e *CEF) **string { return &e.Message }),
+	"outcome":                      stringField(func(e *CEF) **string { return &e.EventOutcome }),
+	"proto":                        stringField(func(e *CEF) **string { return &e.TransportProtocol }),
+	"reason":                       stringField(func(e *CEF) **string { return &e.Reason }),
+	"request":                      stringField(func(e *CEF) **string { return &e.RequestURL }),
+	"requestClientApplication":     stringField(func(e *CEF) **string { return &e.RequestClientApplication }),
+	"requestMethod":                stringField(func(e *CEF) **string { return &e.RequestMethod }),
+	"rt":                           timeField(func(e *CEF) **timestamp.RFC3339 { return &e.ReceiptTime }),
+	"shost":                        stringField(func(e *CEF) **string { return &e.SourceHostName }),
+	"smac":                         stringField(func(e *CEF) **string { return &e.SourceMacAddress }),
+	"sntdom":                       stringField(func(e *CEF) **string { return &e.SourceNtDomain }),
+	"spid":                         int64Field(func(e *CEF) **int64 { return &e.SourceProcessID }),
+	"spriv":                        stringField(func(e *CEF) **string { return &e.SourceUserPrivileges }),
+	"sproc":                        stringField(func(e *CEF) **string { return &e.SourceProcessName }),
+	"spt":                          portField(func(e *CEF) **uint16 { return &e.SourcePort }),
+	"src":                          stringField(func(e *CEF) **string { return &e.SourceAddress }),
+	"suid":                         stringField(func(e *CEF) **string { return &e.SourceUserID }),
+	"suser":                        stringField(func(e *CEF) **string { return &e.SourceUserName }),
+	"sourceTranslatedAddress":      stringField(func(e *CEF) **string { return &e.SourceTranslatedAddress }),
+	"sourceTranslatedPort":         portField(func(e *CEF) **uint16 { return &e.SourceTranslatedPort }),
+	"start":                        timeField(func(e *CEF) **timestamp.RFC3339 { return &e.StartTime }),
+}
+
+func stringField(field func(event *CEF) **string) extensionSetter {
+	return func(event *CEF, value string) bool {
+		*field(event) = &value
+		return true
+	}
+}
+
+func intField(field func(event *CEF) **int) extensionSetter {
+	return func(event *CEF, value string) bool {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return false
+		}
+		*field(event) = &n
+		return true
+	}
+}
+
+func int64Field(field func(event *CEF) **int64) extensionSetter {
+	return func(event *CEF, value string) bool {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		*field(event) = &n
+		return true
+	}
+}
+
+func portField(field func(event *CEF) **uint16) extensionSetter {
+	return func(event *CEF, value string) bool {
+		n, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return false
+		}
+		port := uint16(n)
+		*field(event) = &port
+		return true
+	}
+}
+
+func timeField(field func(event *CEF) **timestamp.RFC3339) extensionSetter {
+	return func(event *CEF, value string) bool {
+		t, err := parseTime(value)
+		if err != nil {
+			return false
+		}
+		*field(event) = &t
+		return true
+	}
+}
+
+// The CEF timestamp formats, https://community.microfocus.com/t5/ArcSight-Connectors/ArcSight-Common-Event-Format-CEF-Implementation-Standard/ta-p/1645557
+var timeLayouts = []string{
+	"Jan 2 2006 15:04:05.000 MST",
+	"Jan 2 2006 15:04:05 MST",
+	"Jan 2 2006 15:04:05.000",
+	"Jan 2 2006 15:04:05",
+	time.RFC3339Nano,
+}
+
+// timeLayoutsWithoutYear are the formats assumed to be in the current year
+var timeLayoutsWithoutYear = []string{
+	"Jan 2 15:04:05.000 MST",
+	"Jan 2 15:04:05 MST",
+	"Jan 2 15:04:05.000",
+	"Jan 2 15:04:05",
+}
+
+// parseTime parses CEF timestamps, milliseconds since epoch or dates in UTC unless a time zone is included
+func parseTime(value string) (timestamp.RFC3339, error) {
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return timestamp.RFC3339(time.Unix(0, millis*int64(time.Millisecond)).UTC()), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return timestamp.RFC3339(t.UTC()), nil
+		}
+	}
+	for _, layout := range timeLayoutsWithoutYear {
+		if t, err := time.Parse(layout, value); err == nil {
+			return timestamp.RFC3339(timestamp.InferYear(t, time.Now().UTC()).UTC()), nil
+		}
+	}
+	return timestamp.RFC3339{}, errors.Errorf("invalid CEF timestamp %q", value)
+}
@@ -0,0 +1,223 @@
+package ceflogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/sysloglogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestCEF(t *testing.T) {
+	// nolint:lll
+	log := `CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232 dpt=80 shost=client.example.com dhost=www.example.com suser=Alice@Example.com smac=00:0D:60:AF:1B:61 act=blocked rt=1578960000000 msg=Detected a threat. No action needed.`
+
+	expectedTime := time.Date(2020, 1, 14, 0, 0, 0, 0, time.UTC)
+	expectedEvent := &CEF{
+		Version:             aws.Int(0),
+		DeviceVendor:        aws.String("Security"),
+		DeviceProduct:       aws.String("threatmanager"),
+		DeviceVersion:       aws.String("1.0"),
+		SignatureID:         aws.String("100"),
+		Name:                aws.String("worm successfully stopped"),
+		Severity:            aws.String("10"),
+		SourceAddress:       aws.String("10.0.0.1"),
+		DestinationAddress:  aws.String("2.1.2.2"),
+		SourcePort:          aws.Uint16(1232),
+		DestinationPort:     aws.Uint16(80),
+		SourceHostName:      aws.String("client.example.com"),
+		DestinationHostName: aws.String("www.example.com"),
+		SourceUserName:      aws.String("Alice@Example.com"),
+		SourceMacAddress:    aws.String("00:0D:60:AF:1B:61"),
+		Action:              aws.String("blocked"),
+		ReceiptTime:         (*timestamp.RFC3339)(&expectedTime),
+		Message:             aws.String("Detected a threat. No action needed."),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("CEF.Event")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("10.0.0.1")
+	expectedEvent.AppendAnyIPAddress("2.1.2.2")
+	expectedEvent.AppendAnyDomainNames("client.example.com", "www.example.com")
+	expectedEvent.AppendAnyUsernames("Alice@Example.com")
+	expectedEvent.AppendAnyEmails("alice@example.com")
+	expectedEvent.AppendAnyMACAddresses("00:0d:60:af:1b:61")
+
+	checkCEF(t, log, expectedEvent)
+}
+
+func TestCEFEscaping(t *testing.T) {
+	// nolint:lll
+	log := `CEF:1|Vendor\|Inc|prod\\uct|2.0|login|User \| login|Low|suser=a\=b request=https://example.com/?q\=1&r\=2 msg=first line\nsecond line cs1Label=path cs1=C:\\Windows`
+
+	expectedEvent := &CEF{
+		Version:                  aws.Int(1),
+		DeviceVendor:             aws.String("Vendor|Inc"),
+		DeviceProduct:            aws.String(`prod\uct`),
+		DeviceVersion:            aws.String("2.0"),
+		SignatureID:              aws.String("login"),
+		Name:                     aws.String("User | login"),
+		Severity:                 aws.String("Low"),
+		SourceUserName:           aws.String("a=b"),
+		RequestURL:               aws.String("https://example.com/?q=1&r=2"),
+		Message:                  aws.String("first line\nsecond line"),
+		DeviceCustomString1Label: aws.String("path"),
+		DeviceCustomString1:      aws.String(`C:\Windows`),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("CEF.Event")
+	expectedEvent.AppendAnyUsernames("a=b")
+
+	checkCEF(t, log, expectedEvent)
+}
+
+func TestCEFWithRFC3164Header(t *testing.T) {
+	// nolint:lll
+	log := `<134>Feb 14 19:04:54 fw01.example.com CEF:0|Palo Alto Networks|PAN-OS|9.1|url|THREAT|1|src=192.168.0.10 dst=93.184.216.34 dhost=example.com`
+
+	expectedTime := time.Date(time.Now().UTC().Year(), 2, 14, 19, 4, 54, 0, time.UTC)
+	expectedEvent := &CEF{
+		Syslog: &sysloglogs.Header{
+			Priority:  aws.Uint8(134),
+			Facility:  aws.Uint8(16),
+			Severity:  aws.Uint8(6),
+			Timestamp: (*timestamp.RFC3339)(&expectedTime),
+			Hostname:  aws.String("fw01.example.com"),
+		},
+		Version:             aws.Int(0),
+		DeviceVendor:        aws.String("Palo Alto Networks"),
+		DeviceProduct:       aws.String("PAN-OS"),
+		DeviceVersion:       aws.String("9.1"),
+		SignatureID:         aws.String("url"),
+		Name:                aws.String("THREAT"),
+		Severity:            aws.String("1"),
+		SourceAddress:       aws.String("192.168.0.10"),
+		DestinationAddress:  aws.String("93.184.216.34"),
+		DestinationHostName: aws.String("example.com"),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("CEF.Event")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyDomainNames("fw01.example.com")
+	expectedEvent.AppendAnyIPAddress("192.168.0.10")
+	expectedEvent.AppendAnyIPAddress("93.184.216.34")
+	expectedEvent.AppendAnyDomainNames("example.com")
+
+	checkCEF(t, log, expectedEvent)
+}
+
+func TestCEFWithRFC5424Header(t *testing.T) {
+	// nolint:lll
+	log := `<13>1 2020-03-02T10:20:30.123Z 10.1.1.1 CEF - - - CEF:0|Trend Micro|Deep Security Agent|10.0|4000000|Eicar_test_file|6|cn1=1 cn1Label=Host ID dvchost=hostname start=Mar 02 2020 10:20:29 GMT`
+
+	expectedTime := time.Date(2020, 3, 2, 10, 20, 30, 123000000, time.UTC)
+	startTime := time.Date(2020, 3, 2, 10, 20, 29, 0, time.UTC)
+	expectedEvent := &CEF{
+		Syslog: &sysloglogs.Header{
+			Priority:  aws.Uint8(13),
+			Facility:  aws.Uint8(1),
+			Severity:  aws.Uint8(5),
+			Timestamp: (*timestamp.RFC3339)(&expectedTime),
+			Hostname:  aws.String("10.1.1.1"),
+			Appname:   aws.String("CEF"),
+		},
+		Version:                  aws.Int(0),
+		DeviceVendor:             aws.String("Trend Micro"),
+		DeviceProduct:            aws.String("Deep Security Agent"),
+		DeviceVersion:            aws.String("10.0"),
+		SignatureID:              aws.String("4000000"),
+		Name:                     aws.String("Eicar_test_file"),
+		Severity:                 aws.String("6"),
+		DeviceCustomNumber1:      aws.Int64(1),
+		DeviceCustomNumber1Label: aws.String("Host ID"),
+		DeviceHostName:           aws.String("hostname"),
+		StartTime:                (*timestamp.RFC3339)(&startTime),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("CEF.Event")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("10.1.1.1")
+	expectedEvent.AppendAnyDomainNames("hostname")
+
+	checkCEF(t, log, expectedEvent)
+}
+
+func TestCEFExtensionsNotInDictionary(t *testing.T) {
+	log := `CEF:0|Vendor|Product|1.0|100|name|5|dpt=not_a_port customKey=custom value src=10.0.0.1`
+
+	parser := (&CEFParser{}).New()
+	events, err := parser.Parse(log)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	event := events[0].Event().(*CEF)
+	require.Nil(t, event.DestinationPort)
+	require.Equal(t, "10.0.0.1", *event.SourceAddress)
+	require.Equal(t, &map[string]string{
+		"dpt":       "not_a_port",
+		"customKey": "custom value",
+	}, event.Extensions)
+}
+
+func TestCEFInvalid(t *testing.T) {
+	parser := (&CEFParser{}).New()
+	for _, log := range []string{
+		`LEEF:1.0|Vendor|Product|1.0|100|src=10.0.0.1`,
+		`CEF:0|Vendor|Product|1.0|100|name`,
+		`CEF:x|Vendor|Product|1.0|100|name|5|`,
+		`not a syslog header CEF:0|Vendor|Product|1.0|100|name|5|`,
+	} {
+		_, err := parser.Parse(log)
+		require.Error(t, err, log)
+	}
+}
+
+func TestCEFTimeWithoutYear(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, expected := range []time.Time{
+		now.Add(-time.Hour),
+		now.Add(time.Hour),                    // clock skew
+		now.AddDate(-1, 0, 2).Add(-time.Hour), // more than a day in the future is in the previous year
+	} {
+		ts, err := parseTime(expected.Format("Jan 2 15:04:05"))
+		require.NoError(t, err)
+		require.Equal(t, expected, time.Time(ts))
+	}
+}
+
+func TestCEFType(t *testing.T) {
+	parser := &CEFParser{}
+	require.Equal(t, "CEF.Event", parser.LogType())
+}
+
+func checkCEF(t *testing.T, log string, expectedEvent *CEF) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := (&CEFParser{}).New()
+	logs, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), logs, err)
+}
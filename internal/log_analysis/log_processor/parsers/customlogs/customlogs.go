@@ -0,0 +1,296 @@
+package customlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+// Custom log types are user defined JSON schemas. Since they are not known at compile time, the event struct
+// is generated with reflection so that the Glue schema, the Athena views and the JSON output work the same
+// way they do for the built in parsers.
+
+// Go types used for each of the supported field types
+var fieldTypes = map[string]reflect.Type{
+	models.CustomFieldTypeString:    reflect.TypeOf((*string)(nil)),
+	models.CustomFieldTypeBigInt:    reflect.TypeOf((*numerics.Int64)(nil)),
+	models.CustomFieldTypeDouble:    reflect.TypeOf((*float64)(nil)),
+	models.CustomFieldTypeBoolean:   reflect.TypeOf((*bool)(nil)),
+	models.CustomFieldTypeTimestamp: reflect.TypeOf((*timestamp.RFC3339)(nil)),
+	models.CustomFieldTypeJSON:      reflect.TypeOf((*jsoniter.RawMessage)(nil)),
+}
+
+var pantherLogType = reflect.TypeOf(parsers.PantherLog{})
+
+type field struct {
+	name            string
+	fieldType       string
+	required        bool
+	timestampFormat string // only set for timestamp fields
+	indicators      []string
+	index           int // index in the generated struct
+}
+
+// Parser parses the JSON logs of a custom log type
+type Parser struct {
+	logType        string
+	fields         []*field
+	timestampField *field
+	eventType      reflect.Type // the generated event struct
+}
+
+var _ parsers.LogParser = (*Parser)(nil)
+
+// NewParser validates the custom log type and generates a parser for it
+func NewParser(logType *models.CustomLogType) (*Parser, error) {
+	name := aws.StringValue(logType.LogType)
+	if !strings.HasPrefix(name, models.CustomLogTypePrefix) {
+		return nil, errors.Errorf("custom log type %q does not start with %q", name, models.CustomLogTypePrefix)
+	}
+	if len(logType.Fields) == 0 {
+		return nil, errors.Errorf("custom log type %q has no fields", name)
+	}
+
+	p := &Parser{
+		logType: name,
+	}
+	timestampFieldName := aws.StringValue(logType.TimestampField)
+	seen := make(map[string]struct{}, len(logType.Fields))
+	structFields := make([]reflect.StructField, 0, len(logType.Fields)+1)
+	hasRequired := false
+	for i, schemaField := range logType.Fields {
+		f, err := newField(schemaField)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid field %d of custom log type %q", i, name)
+		}
+		// Glue column names are case insensitive
+		if _, duplicate := seen[strings.ToLower(f.name)]; duplicate {
+			return nil, errors.Errorf("duplicate field %q in custom log type %q", f.name, name)
+		}
+		seen[strings.ToLower(f.name)] = struct{}{}
+
+		if f.name == timestampFieldName {
+			if f.fieldType != models.CustomFieldTypeTimestamp {
+				return nil, errors.Errorf("timestamp field %q of custom log type %q is not of type %s",
+					f.name, name, models.CustomFieldTypeTimestamp)
+			}
+			f.required = true // we cannot set the event time without it
+			p.timestampField = f
+		}
+		if f.fieldType == models.CustomFieldTypeTimestamp {
+			f.timestampFormat = models.CustomTimestampRFC3339
+			if f == p.timestampField && logType.TimestampFormat != nil {
+				f.timestampFormat = *logType.TimestampFormat
+			}
+		}
+		hasRequired = hasRequired || f.required
+
+		f.index = len(structFields)
+		p.fields = append(p.fields, f)
+		structFields = append(structFields, f.structField(schemaField.Description))
+	}
+	if timestampFieldName != "" && p.timestampField == nil {
+		return nil, errors.Errorf("timestamp field %q is not a field of custom log type %q", timestampFieldName, name)
+	}
+	// without any required fields every JSON object would be classified as this log type
+	if !hasRequired {
+		return nil, errors.Errorf("custom log type %q must have at least one required field", name)
+	}
+
+	structFields = append(structFields, reflect.StructField{
+		Name:      pantherLogType.Name(),
+		Type:      pantherLogType,
+		Anonymous: true,
+	})
+	p.eventType = reflect.StructOf(structFields)
+	return p, nil
+}
+
+func newField(schemaField *models.CustomLogTypeField) (*field, error) {
+	f := &field{
+		name:      aws.StringValue(schemaField.Name),
+		fieldType: aws.StringValue(schemaField.Type),
+		required:  aws.BoolValue(schemaField.Required),
+	}
+	if f.name == "" {
+		return nil, errors.New("missing name")
+	}
+	if strings.HasPrefix(f.name, parsers.PantherFieldPrefix) {
+		return nil, errors.Errorf("field %q uses the reserved prefix %q", f.name, parsers.PantherFieldPrefix)
+	}
+	if _, ok := fieldTypes[f.fieldType]; !ok {
+		return nil, errors.Errorf("field %q has unsupported type %q", f.name, f.fieldType)
+	}
+	for _, indicator := range schemaField.Indicators {
+		switch aws.StringValue(indicator) {
+		case models.CustomIndicatorIP, models.CustomIndicatorDomain,
+			models.CustomIndicatorMD5, models.CustomIndicatorSHA1, models.CustomIndicatorSHA256:
+		default:
+			return nil, errors.Errorf("field %q has unsupported indicator %q", f.name, aws.StringValue(indicator))
+		}
+		if f.fieldType != models.CustomFieldTypeString {
+			return nil, errors.Errorf("field %q must be of type %s to be used as indicator", f.name, models.CustomFieldTypeString)
+		}
+		f.indicators = append(f.indicators, *indicator)
+	}
+	return f, nil
+}
+
+func (f *field) structField(description *string) reflect.StructField {
+	tag := `json:"` + f.name + `,omitempty"`
+	if f.required {
+		tag += ` validate:"required"`
+	}
+	comment := aws.StringValue(description)
+	if strings.TrimSpace(comment) == "" {
+		comment = f.name // descriptions are required for Glue columns
+	}
+	tag += ` description:` + strconv.Quote(comment)
+	return reflect.StructField{
+		Name: "Field" + strconv.Itoa(f.index),
+		Type: fieldTypes[f.fieldType],
+		Tag:  reflect.StructTag(tag),
+	}
+}
+
+// EventStruct returns a pointer to a zero value of the generated event struct, used to generate the Glue schema
+func (p *Parser) EventStruct() interface{} {
+	return reflect.New(p.eventType).Interface()
+}
+
+// New returns the parser itself since it holds no state
+func (p *Parser) New() parsers.LogParser {
+	return p
+}
+
+// LogType returns the log type supported by this parser
+func (p *Parser) LogType() string {
+	return p.logType
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *Parser) Parse(log string) ([]*parsers.PantherLog, error) {
+	var rawFields map[string]jsoniter.RawMessage
+	if err := jsoniter.UnmarshalFromString(log, &rawFields); err != nil {
+		return nil, err
+	}
+
+	event := reflect.New(p.eventType)
+	for _, f := range p.fields {
+		rawValue, ok := rawFields[f.name]
+		if !ok || string(rawValue) == "null" {
+			continue // missing required fields are reported by the validator below
+		}
+		value, err := f.decode(rawValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode field %q", f.name)
+		}
+		event.Elem().Field(f.index).Set(value)
+	}
+
+	pantherLog := event.Elem().Field(len(p.fields)).Addr().Interface().(*parsers.PantherLog)
+	var eventTime *timestamp.RFC3339
+	if p.timestampField != nil {
+		eventTime, _ = event.Elem().Field(p.timestampField.index).Interface().(*timestamp.RFC3339)
+	}
+	pantherLog.SetCoreFields(p.logType, eventTime, event.Interface())
+	p.appendIndicators(pantherLog, event.Elem())
+
+	if err := parsers.Validator.Struct(event.Interface()); err != nil {
+		return nil, err
+	}
+
+	return pantherLog.Logs(), nil
+}
+
+// decode returns a pointer to the value of the field
+func (f *field) decode(rawValue jsoniter.RawMessage) (reflect.Value, error) {
+	if f.fieldType == models.CustomFieldTypeTimestamp {
+		ts, err := parseTimestamp(f.timestampFormat, rawValue)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(&ts), nil
+	}
+	value := reflect.New(fieldTypes[f.fieldType].Elem())
+	if err := jsoniter.Unmarshal(rawValue, value.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return value, nil
+}
+
+func parseTimestamp(format string, rawValue jsoniter.RawMessage) (timestamp.RFC3339, error) {
+	switch format {
+	case models.CustomTimestampUnix, models.CustomTimestampUnixMillisecond:
+		var value numerics.Int64
+		if err := jsoniter.Unmarshal(rawValue, &value); err != nil {
+			return timestamp.RFC3339{}, err
+		}
+		if format == models.CustomTimestampUnixMillisecond {
+			return timestamp.Unix(0, int64(value)*int64(time.Millisecond)), nil
+		}
+		return timestamp.Unix(int64(value), 0), nil
+	case models.CustomTimestampRFC3339:
+		format = time.RFC3339Nano
+	}
+	// any other format is a Go time layout
+	var value string
+	if err := jsoniter.Unmarshal(rawValue, &value); err != nil {
+		return timestamp.RFC3339{}, err
+	}
+	return timestamp.Parse(format, value)
+}
+
+func (p *Parser) appendIndicators(pantherLog *parsers.PantherLog, event reflect.Value) {
+	for _, f := range p.fields {
+		if len(f.indicators) == 0 {
+			continue
+		}
+		value, _ := event.Field(f.index).Interface().(*string)
+		if value == nil {
+			continue
+		}
+		for _, indicator := range f.indicators {
+			switch indicator {
+			case models.CustomIndicatorIP:
+				pantherLog.AppendAnyIPAddress(*value)
+			case models.CustomIndicatorDomain:
+				pantherLog.AppendAnyDomainNames(*value)
+			case models.CustomIndicatorMD5:
+				pantherLog.AppendAnyMD5Hashes(*value)
+			case models.CustomIndicatorSHA1:
+				pantherLog.AppendAnySHA1Hashes(*value)
+			case models.CustomIndicatorSHA256:
+				pantherLog.AppendAnySHA256Hashes(*value)
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package customlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+)
+
+func testLogType() *models.CustomLogType {
+	return &models.CustomLogType{
+		LogType:         aws.String("Custom.MyService"),
+		Description:     aws.String("My service logs"),
+		TimestampField:  aws.String("ts"),
+		TimestampFormat: aws.String(models.CustomTimestampUnixMillisecond),
+		Fields: []*models.CustomLogTypeField{
+			{
+				Name: aws.String("ts"),
+				Type: aws.String(models.CustomFieldTypeTimestamp),
+			},
+			{
+				Name:        aws.String("client"),
+				Type:        aws.String(models.CustomFieldTypeString),
+				Description: aws.String("The client address"),
+				Required:    aws.Bool(true),
+				Indicators:  aws.StringSlice([]string{models.CustomIndicatorIP}),
+			},
+			{
+				Name:       aws.String("host"),
+				Type:       aws.String(models.CustomFieldTypeString),
+				Indicators: aws.StringSlice([]string{models.CustomIndicatorDomain}),
+			},
+			{
+				Name: aws.String("bytes"),
+				Type: aws.String(models.CustomFieldTypeBigInt),
+			},
+			{
+				Name: aws.String("ratio"),
+				Type: aws.String(models.CustomFieldTypeDouble),
+			},
+			{
+				Name: aws.String("ok"),
+				Type: aws.String(models.CustomFieldTypeBoolean),
+			},
+			{
+				Name: aws.String("details"),
+				Type: aws.String(models.CustomFieldTypeJSON),
+			},
+		},
+	}
+}
+
+func TestCustomLogParser(t *testing.T) {
+	parser, err := NewParser(testLogType())
+	require.NoError(t, err)
+	require.Equal(t, "Custom.MyService", parser.LogType())
+
+	log := `{"ts":1577836800123,"client":"192.168.1.1","host":"example.com","bytes":"42","ratio":0.5,` +
+		`"ok":true,"details":{"a":[1,2]},"extra":"ignored"}`
+	events, err := parser.Parse(log)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	event := events[0]
+	require.Equal(t, "Custom.MyService", *event.PantherLogType)
+	require.Equal(t, "2020-01-01 00:00:00.123 +0000 UTC", event.PantherEventTime.String())
+
+	eventJSON, err := jsoniter.MarshalToString(event.Event())
+	require.NoError(t, err)
+	var actual map[string]interface{}
+	require.NoError(t, jsoniter.UnmarshalFromString(eventJSON, &actual))
+	require.Equal(t, "2020-01-01 00:00:00.123000000", actual["ts"])
+	require.Equal(t, "192.168.1.1", actual["client"])
+	require.Equal(t, float64(42), actual["bytes"])
+	require.Equal(t, 0.5, actual["ratio"])
+	require.Equal(t, true, actual["ok"])
+	require.Equal(t, map[string]interface{}{"a": []interface{}{float64(1), float64(2)}}, actual["details"])
+	require.Equal(t, []interface{}{"192.168.1.1"}, actual["p_any_ip_addresses"])
+	require.Equal(t, []interface{}{"example.com"}, actual["p_any_domain_names"])
+	require.NotContains(t, actual, "extra")
+}
+
+func TestCustomLogParserGoLayout(t *testing.T) {
+	logType := testLogType()
+	logType.TimestampFormat = aws.String("2006/01/02 15:04:05")
+	parser, err := NewParser(logType)
+	require.NoError(t, err)
+
+	events, err := parser.Parse(`{"ts":"2020/01/01 10:00:00","client":"10.0.0.1"}`)
+	require.NoError(t, err)
+	require.Equal(t, "2020-01-01 10:00:00 +0000 UTC", events[0].PantherEventTime.String())
+}
+
+func TestCustomLogParserFails(t *testing.T) {
+	parser, err := NewParser(testLogType())
+	require.NoError(t, err)
+
+	// missing required field
+	_, err = parser.Parse(`{"ts":1577836800123}`)
+	require.Error(t, err)
+	// missing timestamp field
+	_, err = parser.Parse(`{"client":"10.0.0.1"}`)
+	require.Error(t, err)
+	// wrong type
+	_, err = parser.Parse(`{"ts":1577836800123,"client":"10.0.0.1","ok":"yes"}`)
+	require.Error(t, err)
+	// not an object
+	_, err = parser.Parse(`["a"]`)
+	require.Error(t, err)
+	_, err = parser.Parse(`not json`)
+	require.Error(t, err)
+}
+
+func TestNewParserInvalid(t *testing.T) {
+	for name, update := range map[string]func(*models.CustomLogType){
+		"prefix":            func(lt *models.CustomLogType) { lt.LogType = aws.String("MyService") },
+		"no fields":         func(lt *models.CustomLogType) { lt.Fields = nil },
+		"unknown timestamp": func(lt *models.CustomLogType) { lt.TimestampField = aws.String("nope") },
+		"timestamp type":    func(lt *models.CustomLogType) { lt.TimestampField = aws.String("client") },
+		"reserved":          func(lt *models.CustomLogType) { lt.Fields[1].Name = aws.String("p_client") },
+		"duplicate":         func(lt *models.CustomLogType) { lt.Fields[2].Name = aws.String("Client") },
+		"field type":        func(lt *models.CustomLogType) { lt.Fields[1].Type = aws.String("varchar") },
+		"indicator":         func(lt *models.CustomLogType) { lt.Fields[1].Indicators = aws.StringSlice([]string{"email"}) },
+		"indicator type":    func(lt *models.CustomLogType) { lt.Fields[3].Indicators = aws.StringSlice([]string{"ip"}) },
+		"no required": func(lt *models.CustomLogType) {
+			lt.TimestampField = nil
+			lt.Fields[1].Required = nil
+		},
+	} {
+		logType := testLogType()
+		update(logType)
+		_, err := NewParser(logType)
+		require.Error(t, err, name)
+	}
+}
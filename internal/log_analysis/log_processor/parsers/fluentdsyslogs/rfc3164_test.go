@@ -48,6 +48,7 @@ func TestRFC3164(t *testing.T) {
 	// panther fields
 	expectedRFC3164.PantherLogType = aws.String("Fluentd.Syslog3164")
 	expectedRFC3164.AppendAnyDomainNamePtrs(expectedRFC3164.Hostname)
+	expectedRFC3164.AppendAnyUsernames("root")
 	expectedRFC3164.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
 
 	checkRFC3164(t, log, expectedRFC3164)
@@ -92,6 +93,7 @@ func TestRFC3164SSHMessage(t *testing.T) {
 	expectedEvent.PantherLogType = aws.String("Fluentd.Syslog3164")
 	expectedEvent.AppendAnyDomainNamePtrs(expectedEvent.Hostname)
 	expectedEvent.AppendAnyIPAddressInFieldPtr(expectedEvent.Message)
+	expectedEvent.AppendAnyUsernames("ubuntu")
 	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
 
 	checkRFC3164(t, log, expectedEvent)
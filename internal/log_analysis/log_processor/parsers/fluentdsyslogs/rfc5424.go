@@ -86,4 +86,5 @@ func (event *RFC5424) updatePantherFields(p *RFC5424Parser) {
 	}
 
 	event.AppendAnyIPAddressInFieldPtr(event.Message)
+	event.AppendAnyUsernamesInFieldPtr(event.Message)
 }
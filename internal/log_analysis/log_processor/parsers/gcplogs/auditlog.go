@@ -80,12 +80,7 @@ func (p *AuditLogParser) Parse(log string) ([]*parsers.PantherLog, error) {
 			AuditLogSystemLogID,
 		})
 	}
-	ts := entry.Timestamp
-	if ts == nil {
-		// Fallback to ReceiveTimestamp which is a required field to get a timestamp hopefully closer to the actual event timestamp.
-		ts = entry.ReceiveTimestamp
-	}
-	entry.SetCoreFields(TypeAuditLog, ts, &entry)
+	entry.SetCoreFields(TypeAuditLog, entry.EventTime(), &entry)
 	if entry.HTTPRequest != nil {
 		entry.AppendAnyIPAddressPtr(entry.HTTPRequest.RemoteIP)
 		entry.AppendAnyIPAddressPtr(entry.HTTPRequest.ServerIP)
@@ -93,6 +88,9 @@ func (p *AuditLogParser) Parse(log string) ([]*parsers.PantherLog, error) {
 	if meta := entry.Payload.RequestMetadata; meta != nil {
 		entry.AppendAnyIPAddressPtr(meta.CallerIP)
 	}
+	if auth := entry.Payload.AuthenticationInfo; auth != nil {
+		entry.AppendAnyEmailPtrs(auth.PrincipalEmail)
+	}
 	if err := parsers.Validator.Struct(entry); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,106 @@
+package gcplogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+)
+
+type LogEntryDNS struct {
+	LogEntry
+	Payload DNSQuery `json:"jsonPayload" validate:"required" description:"The DNS query log payload"`
+
+	parsers.PantherLog
+}
+
+const (
+	TypeDNS = "GCP.DNS"
+
+	// nolint:lll
+	DNSDesc = `Cloud DNS query logs record the queries that the name server resolves for VPC networks, as well as queries from external entities directly to a public zone.
+
+Reference: https://cloud.google.com/dns/docs/monitoring
+`
+	DNSLogID = "dns.googleapis.com%2Fdns_queries"
+)
+
+type DNSParser struct{}
+
+var _ parsers.LogParser = (*DNSParser)(nil)
+
+func NewDNSParser() parsers.LogParser {
+	return &DNSParser{}
+}
+
+func (p *DNSParser) LogType() string {
+	return TypeDNS
+}
+
+// New creates a new log parser instance
+func (p *DNSParser) New() parsers.LogParser {
+	return &DNSParser{}
+}
+
+// Parse implements parsers.LogParser interface
+func (p *DNSParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	entry := LogEntryDNS{}
+	if err := jsoniter.UnmarshalFromString(log, &entry); err != nil {
+		return nil, err
+	}
+	if id := entry.LogID(); id != DNSLogID {
+		return nil, errors.Errorf("invalid LogID %q != %s", id, DNSLogID)
+	}
+	entry.SetCoreFields(TypeDNS, entry.EventTime(), &entry)
+	entry.AppendAnyIPAddressPtr(entry.Payload.SourceIP)
+	entry.AppendAnyIPAddressPtr(entry.Payload.DestinationIP)
+	if entry.Payload.QueryName != nil {
+		entry.AppendAnyDomainNames(strings.TrimSuffix(*entry.Payload.QueryName, "."))
+	}
+	if err := parsers.Validator.Struct(entry); err != nil {
+		return nil, err
+	}
+	return entry.Logs(), nil
+}
+
+// nolint:lll
+// Reference https://cloud.google.com/dns/docs/monitoring#dns-log-record-format
+type DNSQuery struct {
+	QueryName      *string           `json:"queryName" validate:"required" description:"The DNS query name."`
+	QueryType      *string           `json:"queryType,omitempty" description:"The DNS query type (e.g. A, AAAA, MX)."`
+	ResponseCode   *string           `json:"responseCode,omitempty" description:"The response code (e.g. NOERROR, NXDOMAIN, SERVFAIL)."`
+	RData          *string           `json:"rdata,omitempty" description:"The DNS answers in presentation format, truncated to 260 bytes."`
+	AuthAnswer     *bool             `json:"authAnswer,omitempty" description:"Whether the answer is authoritative."`
+	Protocol       *string           `json:"protocol,omitempty" description:"The protocol of the query (TCP or UDP)."`
+	SourceIP       *string           `json:"sourceIP,omitempty" description:"The IP address originating the query."`
+	SourceNetwork  *string           `json:"sourceNetwork,omitempty" description:"The network from which the query reached the resolver."`
+	DestinationIP  *string           `json:"destinationIP,omitempty" description:"The target IP address of forwarded queries (only applicable for forwarding)."`
+	TargetType     *string           `json:"targetType,omitempty" description:"The type of target resolving the DNS query (e.g. private-zone, forwarding-zone, peering-zone, internal, external)."`
+	EgressError    *string           `json:"egressError,omitempty" description:"The egress proxy error reported by the forwarding target."`
+	ServerLatency  *numerics.Integer `json:"serverLatency,omitempty" description:"The latency of the query in milliseconds."`
+	VMInstanceID   *numerics.Int64   `json:"vmInstanceId,omitempty" description:"The Compute Engine VM instance ID (only applicable to queries initiated by Compute Engine VMs)."`
+	VMInstanceName *string           `json:"vmInstanceName,omitempty" description:"The Compute Engine VM instance name (only applicable to queries initiated by Compute Engine VMs)."`
+	VMProjectID    *string           `json:"vmProjectId,omitempty" description:"The Google Cloud project ID of the network from which the query was sent."`
+	VMZoneName     *string           `json:"vmZoneName,omitempty" description:"The name of the VM zone from which the query was sent."`
+}
@@ -0,0 +1,111 @@
+package gcplogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestDNSParser(t *testing.T) {
+	log := `{
+		"insertId": "fmavnxe5yxb4",
+		"jsonPayload": {
+			"authAnswer": false,
+			"protocol": "UDP",
+			"queryName": "evil.example.com.",
+			"queryType": "A",
+			"rdata": "evil.example.com.\t300\tIN\ta\t192.0.2.10",
+			"responseCode": "NOERROR",
+			"serverLatency": 14,
+			"sourceIP": "10.128.0.2",
+			"sourceNetwork": "default",
+			"targetType": "external",
+			"vmInstanceId": 4203210987654321,
+			"vmInstanceName": "1234567890.instance-1",
+			"vmProjectId": "some-project-id",
+			"vmZoneName": "us-central1-a"
+		},
+		"logName": "projects/some-project-id/logs/dns.googleapis.com%2Fdns_queries",
+		"receiveTimestamp": "2020-05-06T12:00:01.548390311Z",
+		"resource": {
+			"type": "dns_query",
+			"labels": {
+				"location": "us-central1",
+				"project_id": "some-project-id",
+				"source_type": "gce-vm",
+				"target_name": "",
+				"target_type": "external"
+			}
+		},
+		"severity": "INFO",
+		"timestamp": "2020-05-06T12:00:00.529813405Z"
+	}`
+
+	ts := mustParseTime(t, "2020-05-06T12:00:00.529813405Z")
+	tsReceive := mustParseTime(t, "2020-05-06T12:00:01.548390311Z")
+	latency := numerics.Integer(14)
+	instanceID := numerics.Int64(4203210987654321)
+
+	entry := &LogEntryDNS{
+		LogEntry: LogEntry{
+			LogName:          aws.String("projects/some-project-id/logs/dns.googleapis.com%2Fdns_queries"),
+			Severity:         aws.String("INFO"),
+			Timestamp:        (*timestamp.RFC3339)(&ts),
+			ReceiveTimestamp: (*timestamp.RFC3339)(&tsReceive),
+			InsertID:         aws.String("fmavnxe5yxb4"),
+			Resource: MonitoredResource{
+				Type: aws.String("dns_query"),
+				Labels: Labels{
+					"location":    "us-central1",
+					"project_id":  "some-project-id",
+					"source_type": "gce-vm",
+					"target_name": "",
+					"target_type": "external",
+				},
+			},
+		},
+		Payload: DNSQuery{
+			QueryName:      aws.String("evil.example.com."),
+			QueryType:      aws.String("A"),
+			ResponseCode:   aws.String("NOERROR"),
+			RData:          aws.String("evil.example.com.\t300\tIN\ta\t192.0.2.10"),
+			AuthAnswer:     aws.Bool(false),
+			Protocol:       aws.String("UDP"),
+			SourceIP:       aws.String("10.128.0.2"),
+			SourceNetwork:  aws.String("default"),
+			TargetType:     aws.String("external"),
+			ServerLatency:  &latency,
+			VMInstanceID:   &instanceID,
+			VMInstanceName: aws.String("1234567890.instance-1"),
+			VMProjectID:    aws.String("some-project-id"),
+			VMZoneName:     aws.String("us-central1-a"),
+		},
+	}
+
+	entry.SetCoreFields(TypeDNS, entry.Timestamp, entry)
+	entry.AppendAnyIPAddress("10.128.0.2")
+	entry.AppendAnyDomainNames("evil.example.com")
+	testutil.CheckPantherParser(t, log, NewDNSParser(), &entry.PantherLog)
+}
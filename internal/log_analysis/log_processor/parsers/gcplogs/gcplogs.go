@@ -62,6 +62,24 @@ func (entry *LogEntry) LogID() string {
 	return ""
 }
 
+// ResourceType returns the type of the monitored resource that produced the log entry.
+// GCP logs are aggregated and some log types are identified by the resource type rather than the log id.
+func (entry *LogEntry) ResourceType() string {
+	if entry == nil || entry.Resource.Type == nil {
+		return ""
+	}
+	return *entry.Resource.Type
+}
+
+// EventTime returns the timestamp of the log entry.
+// It falls back to ReceiveTimestamp which is a required field to get a timestamp hopefully closer to the actual event timestamp.
+func (entry *LogEntry) EventTime() *timestamp.RFC3339 {
+	if entry.Timestamp != nil {
+		return entry.Timestamp
+	}
+	return entry.ReceiveTimestamp
+}
+
 // nolint:lll
 type MonitoredResource struct {
 	Type   *string `json:"type" validate:"required" description:"Type of resource that produced this log entry"`
@@ -0,0 +1,92 @@
+package gcplogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+type LogEntryGKEContainer struct {
+	LogEntry
+	TextPayload *string              `json:"textPayload,omitempty" description:"The log line written by the container, if it was not JSON."`
+	JSONPayload *jsoniter.RawMessage `json:"jsonPayload,omitempty" description:"The JSON object written by the container, if the log line was JSON."`
+
+	parsers.PantherLog
+}
+
+const (
+	TypeGKEContainer = "GCP.GKEContainer"
+
+	// nolint:lll
+	GKEContainerDesc = `GKE container logs are the stdout and stderr streams of the containers running in Google Kubernetes Engine clusters.
+
+Reference: https://cloud.google.com/kubernetes-engine/docs/how-to/logging
+`
+	GKEContainerResourceType       = "k8s_container"
+	GKEContainerLegacyResourceType = "container"
+)
+
+type GKEContainerParser struct{}
+
+var _ parsers.LogParser = (*GKEContainerParser)(nil)
+
+func NewGKEContainerParser() parsers.LogParser {
+	return &GKEContainerParser{}
+}
+
+func (p *GKEContainerParser) LogType() string {
+	return TypeGKEContainer
+}
+
+// New creates a new log parser instance
+func (p *GKEContainerParser) New() parsers.LogParser {
+	return &GKEContainerParser{}
+}
+
+// Parse implements parsers.LogParser interface
+func (p *GKEContainerParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	entry := LogEntryGKEContainer{}
+	if err := jsoniter.UnmarshalFromString(log, &entry); err != nil {
+		return nil, err
+	}
+	// The log id is the container stream (e.g. stdout, stderr) so we dispatch on the resource type
+	switch resourceType := entry.ResourceType(); resourceType {
+	case GKEContainerResourceType, GKEContainerLegacyResourceType:
+	default:
+		return nil, errors.Errorf("invalid resource type %q != %s", resourceType, []string{
+			GKEContainerResourceType,
+			GKEContainerLegacyResourceType,
+		})
+	}
+	if entry.TextPayload == nil && entry.JSONPayload == nil {
+		return nil, errors.New("missing textPayload or jsonPayload")
+	}
+	entry.SetCoreFields(TypeGKEContainer, entry.EventTime(), &entry)
+	if entry.HTTPRequest != nil {
+		entry.AppendAnyIPAddressPtr(entry.HTTPRequest.RemoteIP)
+		entry.AppendAnyIPAddressPtr(entry.HTTPRequest.ServerIP)
+	}
+	if err := parsers.Validator.Struct(entry); err != nil {
+		return nil, err
+	}
+	return entry.Logs(), nil
+}
@@ -0,0 +1,144 @@
+package gcplogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestGKEContainerParserText(t *testing.T) {
+	log := `{
+		"insertId": "9o1vnhfy0tpr8rn5",
+		"labels": {
+			"k8s-pod/app": "nginx"
+		},
+		"logName": "projects/some-project-id/logs/stderr",
+		"receiveTimestamp": "2020-05-06T13:00:05.223436374Z",
+		"resource": {
+			"type": "k8s_container",
+			"labels": {
+				"cluster_name": "the-cluster",
+				"container_name": "nginx",
+				"location": "us-central1-f",
+				"namespace_name": "default",
+				"pod_name": "nginx-5c7588df-x2k4t",
+				"project_id": "some-project-id"
+			}
+		},
+		"severity": "ERROR",
+		"textPayload": "2020/05/06 13:00:00 [error] 6#6: *1 open() \"/usr/share/nginx/html/wp-login.php\" failed (2: No such file or directory)",
+		"timestamp": "2020-05-06T13:00:00.493515129Z"
+	}`
+
+	ts := mustParseTime(t, "2020-05-06T13:00:00.493515129Z")
+	tsReceive := mustParseTime(t, "2020-05-06T13:00:05.223436374Z")
+
+	entry := &LogEntryGKEContainer{
+		LogEntry: LogEntry{
+			LogName:          aws.String("projects/some-project-id/logs/stderr"),
+			Severity:         aws.String("ERROR"),
+			Timestamp:        (*timestamp.RFC3339)(&ts),
+			ReceiveTimestamp: (*timestamp.RFC3339)(&tsReceive),
+			InsertID:         aws.String("9o1vnhfy0tpr8rn5"),
+			Labels: Labels{
+				"k8s-pod/app": "nginx",
+			},
+			Resource: MonitoredResource{
+				Type: aws.String("k8s_container"),
+				Labels: Labels{
+					"cluster_name":   "the-cluster",
+					"container_name": "nginx",
+					"location":       "us-central1-f",
+					"namespace_name": "default",
+					"pod_name":       "nginx-5c7588df-x2k4t",
+					"project_id":     "some-project-id",
+				},
+			},
+		},
+		TextPayload: aws.String(`2020/05/06 13:00:00 [error] 6#6: *1 open() "/usr/share/nginx/html/wp-login.php" failed (2: No such file or directory)`),
+	}
+
+	entry.SetCoreFields(TypeGKEContainer, entry.Timestamp, entry)
+	testutil.CheckPantherParser(t, log, NewGKEContainerParser(), &entry.PantherLog)
+}
+
+func TestGKEContainerParserJSON(t *testing.T) {
+	log := `{
+		"insertId": "1x2y3z",
+		"jsonPayload": {"level": "info", "msg": "user logged in", "user": "alice"},
+		"logName": "projects/some-project-id/logs/stdout",
+		"receiveTimestamp": "2020-05-06T13:00:05.223436374Z",
+		"resource": {
+			"type": "container",
+			"labels": {
+				"cluster_name": "the-cluster",
+				"container_name": "api",
+				"namespace_id": "default",
+				"pod_id": "api-7d9b8c6f5-abcde",
+				"project_id": "some-project-id",
+				"zone": "us-central1-f"
+			}
+		}
+	}`
+
+	tsReceive := mustParseTime(t, "2020-05-06T13:00:05.223436374Z")
+	payload := jsoniter.RawMessage(`{"level": "info", "msg": "user logged in", "user": "alice"}`)
+
+	entry := &LogEntryGKEContainer{
+		LogEntry: LogEntry{
+			LogName:          aws.String("projects/some-project-id/logs/stdout"),
+			ReceiveTimestamp: (*timestamp.RFC3339)(&tsReceive),
+			InsertID:         aws.String("1x2y3z"),
+			Resource: MonitoredResource{
+				Type: aws.String("container"),
+				Labels: Labels{
+					"cluster_name":   "the-cluster",
+					"container_name": "api",
+					"namespace_id":   "default",
+					"pod_id":         "api-7d9b8c6f5-abcde",
+					"project_id":     "some-project-id",
+					"zone":           "us-central1-f",
+				},
+			},
+		},
+		JSONPayload: &payload,
+	}
+
+	entry.SetCoreFields(TypeGKEContainer, entry.ReceiveTimestamp, entry)
+	testutil.CheckPantherParser(t, log, NewGKEContainerParser(), &entry.PantherLog)
+}
+
+func TestGKEContainerParserInvalidResourceType(t *testing.T) {
+	log := `{
+		"textPayload": "hello",
+		"logName": "projects/some-project-id/logs/stdout",
+		"receiveTimestamp": "2020-05-06T13:00:05.223436374Z",
+		"resource": {"type": "gce_instance", "labels": {}}
+	}`
+	results, err := NewGKEContainerParser().Parse(log)
+	require.Error(t, err)
+	require.Nil(t, results)
+}
@@ -0,0 +1,115 @@
+package gcplogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"net/url"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+)
+
+type LogEntryHTTPLoadBalancer struct {
+	LogEntry
+	Payload HTTPLoadBalancer `json:"jsonPayload" validate:"required" description:"The HTTP(S) load balancer log payload"`
+
+	parsers.PantherLog
+}
+
+const (
+	TypeHTTPLoadBalancer = "GCP.HTTPLoadBalancer"
+
+	// nolint:lll
+	HTTPLoadBalancerDesc = `HTTP(S) Load Balancing request logs record each request served by an external HTTP(S) load balancer, including the Cloud Armor security policy decisions.
+
+Reference: https://cloud.google.com/load-balancing/docs/https/https-logging-monitoring
+`
+	HTTPLoadBalancerLogID        = "requests"
+	HTTPLoadBalancerResourceType = "http_load_balancer"
+)
+
+type HTTPLoadBalancerParser struct{}
+
+var _ parsers.LogParser = (*HTTPLoadBalancerParser)(nil)
+
+func NewHTTPLoadBalancerParser() parsers.LogParser {
+	return &HTTPLoadBalancerParser{}
+}
+
+func (p *HTTPLoadBalancerParser) LogType() string {
+	return TypeHTTPLoadBalancer
+}
+
+// New creates a new log parser instance
+func (p *HTTPLoadBalancerParser) New() parsers.LogParser {
+	return &HTTPLoadBalancerParser{}
+}
+
+// Parse implements parsers.LogParser interface
+func (p *HTTPLoadBalancerParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	entry := LogEntryHTTPLoadBalancer{}
+	if err := jsoniter.UnmarshalFromString(log, &entry); err != nil {
+		return nil, err
+	}
+	// Other resources also write to the `requests` log so we check the resource type too
+	if id := entry.LogID(); id != HTTPLoadBalancerLogID {
+		return nil, errors.Errorf("invalid LogID %q != %s", id, HTTPLoadBalancerLogID)
+	}
+	if resourceType := entry.ResourceType(); resourceType != HTTPLoadBalancerResourceType {
+		return nil, errors.Errorf("invalid resource type %q != %s", resourceType, HTTPLoadBalancerResourceType)
+	}
+	entry.SetCoreFields(TypeHTTPLoadBalancer, entry.EventTime(), &entry)
+	if req := entry.HTTPRequest; req != nil {
+		entry.AppendAnyIPAddressPtr(req.RemoteIP)
+		entry.AppendAnyIPAddressPtr(req.ServerIP)
+		if req.RequestURL != nil {
+			if u, err := url.Parse(*req.RequestURL); err == nil && u.Hostname() != "" {
+				if !entry.AppendAnyIPAddress(u.Hostname()) {
+					entry.AppendAnyDomainNames(u.Hostname())
+				}
+			}
+		}
+	}
+	if err := parsers.Validator.Struct(entry); err != nil {
+		return nil, err
+	}
+	return entry.Logs(), nil
+}
+
+// nolint:lll
+// Reference https://cloud.google.com/load-balancing/docs/https/https-logging-monitoring#what_is_logged
+type HTTPLoadBalancer struct {
+	PayloadType            *string         `json:"@type" validate:"required,eq=type.googleapis.com/google.cloud.loadbalancing.type.LoadBalancerLogEntry" description:"The type of payload"`
+	StatusDetails          *string         `json:"statusDetails,omitempty" description:"A textual description of the response code (e.g. response_sent_by_backend, denied_by_security_policy)."`
+	CacheID                *string         `json:"cacheId,omitempty" description:"The location and cache instance that the cache response was served from."`
+	BackendTargetProjectID *string         `json:"backendTargetProjectNumber,omitempty" description:"The project number of the backend target."`
+	EnforcedSecurityPolicy *SecurityPolicy `json:"enforcedSecurityPolicy,omitempty" description:"The Cloud Armor security policy rule that was enforced."`
+	PreviewSecurityPolicy  *SecurityPolicy `json:"previewSecurityPolicy,omitempty" description:"The Cloud Armor security policy rule that would have been enforced if it was not in preview mode."`
+}
+
+// nolint:lll
+type SecurityPolicy struct {
+	Name             *string           `json:"name,omitempty" description:"The name of the security policy."`
+	Priority         *numerics.Integer `json:"priority,omitempty" description:"The priority of the matching rule in the security policy."`
+	ConfiguredAction *string           `json:"configuredAction,omitempty" description:"The name of the configured action in the matching rule (e.g. ALLOW, DENY, RATE_BASED_BAN)."`
+	Outcome          *string           `json:"outcome,omitempty" description:"The outcome of executing the configured action (ACCEPT or DENY)."`
+}
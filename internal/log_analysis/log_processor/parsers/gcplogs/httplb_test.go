@@ -0,0 +1,137 @@
+package gcplogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestHTTPLoadBalancerParser(t *testing.T) {
+	log := `{
+		"insertId": "1m7qz5ufk5pny",
+		"jsonPayload": {
+			"@type": "type.googleapis.com/google.cloud.loadbalancing.type.LoadBalancerLogEntry",
+			"statusDetails": "denied_by_security_policy",
+			"enforcedSecurityPolicy": {
+				"name": "block-bad-ips",
+				"priority": 1000,
+				"configuredAction": "DENY",
+				"outcome": "DENY"
+			}
+		},
+		"httpRequest": {
+			"requestMethod": "GET",
+			"requestUrl": "https://www.example.com/login",
+			"requestSize": "87",
+			"status": 403,
+			"responseSize": "215",
+			"userAgent": "curl/7.64.1",
+			"remoteIp": "198.51.100.7",
+			"latency": "0.001425s"
+		},
+		"resource": {
+			"type": "http_load_balancer",
+			"labels": {
+				"project_id": "some-project-id",
+				"url_map_name": "web-map",
+				"forwarding_rule_name": "web-rule",
+				"backend_service_name": "",
+				"zone": "global",
+				"target_proxy_name": "web-target-proxy"
+			}
+		},
+		"timestamp": "2020-05-06T11:02:13.311042Z",
+		"severity": "WARNING",
+		"logName": "projects/some-project-id/logs/requests",
+		"trace": "projects/some-project-id/traces/8b6f0b2d2a",
+		"receiveTimestamp": "2020-05-06T11:02:14.049917113Z"
+	}`
+
+	ts := mustParseTime(t, "2020-05-06T11:02:13.311042Z")
+	tsReceive := mustParseTime(t, "2020-05-06T11:02:14.049917113Z")
+	priority := numerics.Integer(1000)
+	requestSize, responseSize := numerics.Int64(87), numerics.Int64(215)
+	status := int16(403)
+
+	entry := &LogEntryHTTPLoadBalancer{
+		LogEntry: LogEntry{
+			LogName:          aws.String("projects/some-project-id/logs/requests"),
+			Severity:         aws.String("WARNING"),
+			Timestamp:        (*timestamp.RFC3339)(&ts),
+			ReceiveTimestamp: (*timestamp.RFC3339)(&tsReceive),
+			InsertID:         aws.String("1m7qz5ufk5pny"),
+			Trace:            aws.String("projects/some-project-id/traces/8b6f0b2d2a"),
+			Resource: MonitoredResource{
+				Type: aws.String("http_load_balancer"),
+				Labels: Labels{
+					"project_id":           "some-project-id",
+					"url_map_name":         "web-map",
+					"forwarding_rule_name": "web-rule",
+					"backend_service_name": "",
+					"zone":                 "global",
+					"target_proxy_name":    "web-target-proxy",
+				},
+			},
+			HTTPRequest: &HTTPRequest{
+				RequestMethod: aws.String("GET"),
+				RequestURL:    aws.String("https://www.example.com/login"),
+				RequestSize:   &requestSize,
+				Status:        &status,
+				ResponseSize:  &responseSize,
+				UserAgent:     aws.String("curl/7.64.1"),
+				RemoteIP:      aws.String("198.51.100.7"),
+				Latency:       aws.String("0.001425s"),
+			},
+		},
+		Payload: HTTPLoadBalancer{
+			PayloadType:   aws.String("type.googleapis.com/google.cloud.loadbalancing.type.LoadBalancerLogEntry"),
+			StatusDetails: aws.String("denied_by_security_policy"),
+			EnforcedSecurityPolicy: &SecurityPolicy{
+				Name:             aws.String("block-bad-ips"),
+				Priority:         &priority,
+				ConfiguredAction: aws.String("DENY"),
+				Outcome:          aws.String("DENY"),
+			},
+		},
+	}
+
+	entry.SetCoreFields(TypeHTTPLoadBalancer, entry.Timestamp, entry)
+	entry.AppendAnyIPAddress("198.51.100.7")
+	entry.AppendAnyDomainNames("www.example.com")
+	testutil.CheckPantherParser(t, log, NewHTTPLoadBalancerParser(), &entry.PantherLog)
+}
+
+func TestHTTPLoadBalancerParserInvalidResourceType(t *testing.T) {
+	log := `{
+		"jsonPayload": {"@type": "type.googleapis.com/google.cloud.loadbalancing.type.LoadBalancerLogEntry"},
+		"logName": "projects/some-project-id/logs/requests",
+		"receiveTimestamp": "2020-05-06T11:02:14.049917113Z",
+		"resource": {"type": "cloud_run_revision", "labels": {}}
+	}`
+	results, err := NewHTTPLoadBalancerParser().Parse(log)
+	require.Error(t, err)
+	require.Nil(t, results)
+}
@@ -0,0 +1,164 @@
+package gcplogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestVPCFlowParser(t *testing.T) {
+	log := `{
+		"insertId": "1oc7ik2g5rkz8w4",
+		"jsonPayload": {
+			"bytes_sent": "756",
+			"connection": {
+				"dest_ip": "10.128.0.2",
+				"dest_port": 22,
+				"protocol": 6,
+				"src_ip": "203.0.113.45",
+				"src_port": 53452
+			},
+			"dest_instance": {
+				"project_id": "some-project-id",
+				"region": "us-central1",
+				"vm_name": "instance-1",
+				"zone": "us-central1-a"
+			},
+			"dest_vpc": {
+				"project_id": "some-project-id",
+				"subnetwork_name": "default",
+				"vpc_name": "default"
+			},
+			"end_time": "2020-05-06T10:20:31.519937587Z",
+			"packets_sent": "8",
+			"reporter": "DEST",
+			"rtt_msec": "94",
+			"src_location": {
+				"asn": 15169,
+				"city": "Mountain View",
+				"continent": "America",
+				"country": "usa",
+				"region": "California"
+			},
+			"start_time": "2020-05-06T10:20:30.470011423Z"
+		},
+		"logName": "projects/some-project-id/logs/compute.googleapis.com%2Fvpc_flows",
+		"receiveTimestamp": "2020-05-06T10:20:40.195187585Z",
+		"resource": {
+			"labels": {
+				"location": "us-central1-a",
+				"project_id": "some-project-id",
+				"subnetwork_id": "8210123456789",
+				"subnetwork_name": "default"
+			},
+			"type": "gce_subnetwork"
+		},
+		"timestamp": "2020-05-06T10:20:40.195187585Z"
+	}`
+
+	ts := mustParseTime(t, "2020-05-06T10:20:40.195187585Z")
+	start := mustParseTime(t, "2020-05-06T10:20:30.470011423Z")
+	end := mustParseTime(t, "2020-05-06T10:20:31.519937587Z")
+	destPort, srcPort, protocol, asn := numerics.Integer(22), numerics.Integer(53452), numerics.Integer(6), numerics.Integer(15169)
+	bytesSent, packetsSent, rtt := numerics.Int64(756), numerics.Int64(8), numerics.Int64(94)
+
+	entry := &LogEntryVPCFlow{
+		LogEntry: LogEntry{
+			LogName:          aws.String("projects/some-project-id/logs/compute.googleapis.com%2Fvpc_flows"),
+			Timestamp:        (*timestamp.RFC3339)(&ts),
+			ReceiveTimestamp: (*timestamp.RFC3339)(&ts),
+			InsertID:         aws.String("1oc7ik2g5rkz8w4"),
+			Resource: MonitoredResource{
+				Type: aws.String("gce_subnetwork"),
+				Labels: Labels{
+					"location":        "us-central1-a",
+					"project_id":      "some-project-id",
+					"subnetwork_id":   "8210123456789",
+					"subnetwork_name": "default",
+				},
+			},
+		},
+		Payload: VPCFlow{
+			Connection: &IPConnection{
+				SrcIP:    aws.String("203.0.113.45"),
+				SrcPort:  &srcPort,
+				DestIP:   aws.String("10.128.0.2"),
+				DestPort: &destPort,
+				Protocol: &protocol,
+			},
+			Reporter: aws.String("DEST"),
+			DestInstance: &InstanceDetails{
+				ProjectID: aws.String("some-project-id"),
+				VMName:    aws.String("instance-1"),
+				Region:    aws.String("us-central1"),
+				Zone:      aws.String("us-central1-a"),
+			},
+			DestVPC: &VPCDetails{
+				ProjectID:      aws.String("some-project-id"),
+				VPCName:        aws.String("default"),
+				SubnetworkName: aws.String("default"),
+			},
+			SrcLocation: &GeographicDetails{
+				Continent: aws.String("America"),
+				Country:   aws.String("usa"),
+				Region:    aws.String("California"),
+				City:      aws.String("Mountain View"),
+				ASN:       &asn,
+			},
+			StartTime:       (*timestamp.RFC3339)(&start),
+			EndTime:         (*timestamp.RFC3339)(&end),
+			BytesSent:       &bytesSent,
+			PacketsSent:     &packetsSent,
+			RoundTripTimeMs: &rtt,
+		},
+	}
+
+	entry.SetCoreFields(TypeVPCFlow, entry.Timestamp, entry)
+	entry.AppendAnyIPAddress("203.0.113.45")
+	entry.AppendAnyIPAddress("10.128.0.2")
+	testutil.CheckPantherParser(t, log, NewVPCFlowParser(), &entry.PantherLog)
+}
+
+func TestVPCFlowParserInvalidLogID(t *testing.T) {
+	log := `{
+		"jsonPayload": {"connection": {"src_ip": "10.0.0.1"}},
+		"logName": "projects/some-project-id/logs/cloudaudit.googleapis.com%2Factivity",
+		"receiveTimestamp": "2020-05-06T10:20:40.195187585Z",
+		"resource": {"type": "gce_subnetwork", "labels": {}}
+	}`
+	results, err := NewVPCFlowParser().Parse(log)
+	require.Error(t, err)
+	require.Nil(t, results)
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	ts, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
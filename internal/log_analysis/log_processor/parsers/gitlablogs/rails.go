@@ -105,4 +105,5 @@ func (p *RailsParser) LogType() string {
 func (event *Rails) updatePantherFields(p *RailsParser) {
 	event.SetCoreFields(p.LogType(), event.Time, event)
 	event.AppendAnyIPAddressPtr(event.RemoteIP)
+	event.AppendAnyUsernamePtrs(event.UserName)
 }
@@ -77,6 +77,7 @@ func TestGitLabRails(t *testing.T) {
 	// panther fields
 	expectedEvent.PantherLogType = aws.String("GitLab.Rails")
 	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.RemoteIP)
+	expectedEvent.AppendAnyUsernamePtrs(expectedEvent.UserName)
 	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
 	checkGitLabRails(t, log, expectedEvent)
 }
@@ -151,6 +152,7 @@ func TestGitLabRailsException(t *testing.T) {
 	// panther fields
 	expectedEvent.PantherLogType = aws.String("GitLab.Rails")
 	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.RemoteIP)
+	expectedEvent.AppendAnyUsernamePtrs(expectedEvent.UserName)
 	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
 	checkGitLabRails(t, log, expectedEvent)
 }
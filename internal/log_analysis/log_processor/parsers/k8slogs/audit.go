@@ -0,0 +1,158 @@
+package k8slogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+// TypeAudit is the log type of Kubernetes API server audit events
+const TypeAudit = PantherPrefix + ".Audit"
+
+// AuditDesc describes the Kubernetes audit event
+var AuditDesc = `Kubernetes API server audit events (audit.k8s.io/v1), including EKS control plane audit logs exported from CloudWatch Logs.
+Reference: https://kubernetes.io/docs/tasks/debug-application-cluster/audit/`
+
+// Audit is a Kubernetes API server audit event
+// nolint:lll
+type Audit struct {
+	Kind                     *string              `json:"kind" validate:"required,eq=Event" description:"The kind of the object (always Event)."`
+	APIVersion               *string              `json:"apiVersion" validate:"required" description:"The versioned schema of the object (audit.k8s.io/v1)."`
+	Level                    *string              `json:"level,omitempty" description:"The audit level at which the event was generated (None, Metadata, Request or RequestResponse)."`
+	AuditID                  *string              `json:"auditID" validate:"required" description:"The unique audit ID, generated for each request."`
+	Stage                    *string              `json:"stage,omitempty" description:"The stage of the request handling when this event instance was generated (RequestReceived, ResponseStarted, ResponseComplete or Panic)."`
+	RequestURI               *string              `json:"requestURI,omitempty" description:"The request URI as sent by the client to a server."`
+	Verb                     *string              `json:"verb,omitempty" description:"The kubernetes verb associated with the request (e.g. get, list, watch, create, update, patch, delete). For non-resource requests, this is the lower-cased HTTP method."`
+	User                     *UserInfo            `json:"user,omitempty" description:"The authenticated user information."`
+	ImpersonatedUser         *UserInfo            `json:"impersonatedUser,omitempty" description:"The impersonated user information."`
+	SourceIPs                []string             `json:"sourceIPs,omitempty" description:"The source IPs, from where the request originated and intermediate proxies."`
+	UserAgent                *string              `json:"userAgent,omitempty" description:"The user agent string reported by the client."`
+	ObjectRef                *ObjectReference     `json:"objectRef,omitempty" description:"The object reference this request is targeted at. Does not apply for List-type requests, or non-resource requests."`
+	ResponseStatus           *ResponseStatus      `json:"responseStatus,omitempty" description:"The response status, populated even when the ResponseObject is not a Status type."`
+	RequestObject            *jsoniter.RawMessage `json:"requestObject,omitempty" description:"The API object from the request, in JSON format. Only logged at Request level and higher."`
+	ResponseObject           *jsoniter.RawMessage `json:"responseObject,omitempty" description:"The API object returned in the response, in JSON. Only logged at RequestResponse level."`
+	RequestReceivedTimestamp *timestamp.RFC3339   `json:"requestReceivedTimestamp" validate:"required" description:"The time the request reached the apiserver."`
+	StageTimestamp           *timestamp.RFC3339   `json:"stageTimestamp,omitempty" description:"The time the request reached the current audit stage."`
+	Annotations              *map[string]string   `json:"annotations,omitempty" description:"An unstructured key value map stored with an audit event that may be set by plugins invoked in the request serving chain."`
+
+	parsers.PantherLog
+}
+
+// UserInfo holds the information about the user needed to implement the user.Info interface
+// nolint:lll
+type UserInfo struct {
+	Username *string              `json:"username,omitempty" description:"The name that uniquely identifies this user among all active users."`
+	UID      *string              `json:"uid,omitempty" description:"A unique value that identifies this user across time."`
+	Groups   []string             `json:"groups,omitempty" description:"The names of groups this user is a part of."`
+	Extra    *jsoniter.RawMessage `json:"extra,omitempty" description:"Any additional information provided by the authenticator (e.g. the IAM ARN for EKS)."`
+}
+
+// ObjectReference contains enough information to let you inspect or modify the referred object
+// nolint:lll
+type ObjectReference struct {
+	Resource        *string `json:"resource,omitempty" description:"The resource type."`
+	Namespace       *string `json:"namespace,omitempty" description:"The namespace of the object."`
+	Name            *string `json:"name,omitempty" description:"The name of the object."`
+	UID             *string `json:"uid,omitempty" description:"The UID of the object."`
+	APIGroup        *string `json:"apiGroup,omitempty" description:"The name of the API group that contains the referred object. The empty string represents the core API group."`
+	APIVersion      *string `json:"apiVersion,omitempty" description:"The version of the API group that contains the referred object."`
+	ResourceVersion *string `json:"resourceVersion,omitempty" description:"The resource version of the object."`
+	Subresource     *string `json:"subresource,omitempty" description:"The subresource (e.g. status, exec, log)."`
+}
+
+// ResponseStatus is the status of the API response
+// nolint:lll
+type ResponseStatus struct {
+	Status  *string              `json:"status,omitempty" description:"The status of the operation (Success or Failure)."`
+	Message *string              `json:"message,omitempty" description:"A human-readable description of the status of this operation."`
+	Reason  *string              `json:"reason,omitempty" description:"A machine-readable description of why this operation is in the Failure status."`
+	Details *jsoniter.RawMessage `json:"details,omitempty" description:"Extended data associated with the reason."`
+	Code    *int                 `json:"code,omitempty" description:"The suggested HTTP return code for this status."`
+}
+
+// AuditParser parses Kubernetes API server audit events
+type AuditParser struct{}
+
+var _ parsers.LogParser = (*AuditParser)(nil)
+
+// New creates a new parser
+func (p *AuditParser) New() parsers.LogParser {
+	return &AuditParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *AuditParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	event := &Audit{}
+
+	err := jsoniter.UnmarshalFromString(trimExportTimestamp(log), event)
+	if err != nil {
+		return nil, err
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *AuditParser) LogType() string {
+	return TypeAudit
+}
+
+func (event *Audit) updatePantherFields(p *AuditParser) {
+	event.SetCoreFields(p.LogType(), event.RequestReceivedTimestamp, event)
+	for _, sourceIP := range event.SourceIPs {
+		event.AppendAnyIPAddress(sourceIP)
+	}
+	for _, user := range []*UserInfo{event.User, event.ImpersonatedUser} {
+		if user == nil {
+			continue
+		}
+		// users authenticated by an identity provider (e.g. OIDC, GKE) are usually named by their email address
+		event.AppendAnyUsernamePtrs(user.Username)
+		event.AppendAnyEmailPtrs(user.Username)
+	}
+}
+
+// trimExportTimestamp removes the timestamp that a CloudWatch Logs export to S3 (e.g. of EKS control plane logs)
+// prepends to each log event, e.g. `2020-05-05T19:47:11.118Z {"kind":"Event",...}`
+func trimExportTimestamp(log string) string {
+	log = strings.TrimSpace(log)
+	if strings.HasPrefix(log, "{") {
+		return log
+	}
+	i := strings.IndexByte(log, ' ')
+	if i < 0 {
+		return log
+	}
+	if _, err := time.Parse(time.RFC3339Nano, log[:i]); err != nil {
+		return log
+	}
+	return strings.TrimSpace(log[i+1:])
+}
@@ -0,0 +1,128 @@
+package k8slogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+//nolint:lll
+const testAuditLog = `{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"RequestResponse","auditID":"5a6bd0a5-3a4b-4bb4-8b4e-0fa6f0c1e7b0","stage":"ResponseComplete","requestURI":"/api/v1/namespaces/default/pods/nginx/exec?command=sh","verb":"create","user":{"username":"kubernetes-admin","uid":"heptio-authenticator-aws:123456789012:AIDAEXAMPLE","groups":["system:masters","system:authenticated"],"extra":{"accessKeyId":["AKIAEXAMPLE"],"arn":["arn:aws:iam::123456789012:user/admin"]}},"impersonatedUser":{"username":"system:serviceaccount:default:builder"},"sourceIPs":["10.0.0.10","192.168.1.1"],"userAgent":"kubectl/v1.17.0 (linux/amd64) kubernetes/70132b0","objectRef":{"resource":"pods","namespace":"default","name":"nginx","apiVersion":"v1","subresource":"exec"},"responseStatus":{"metadata":{},"status":"Failure","message":"pods \"nginx\" is forbidden","reason":"Forbidden","details":{"name":"nginx","kind":"pods"},"code":403},"requestReceivedTimestamp":"2020-05-05T19:47:11.118375Z","stageTimestamp":"2020-05-05T19:47:11.120167Z"}`
+
+func TestAuditParser(t *testing.T) {
+	checkAudit(t, testAuditLog, expectedAudit())
+}
+
+func TestAuditParserCloudWatchLogsExport(t *testing.T) {
+	// CloudWatch Logs exports of EKS control plane logs prefix each event with the ingestion time
+	checkAudit(t, "2020-05-05T19:47:12.000Z "+testAuditLog, expectedAudit())
+}
+
+func TestAuditParserAnnotations(t *testing.T) {
+	log := `{"kind":"Event","apiVersion":"audit.k8s.io/v1","auditID":"1","requestReceivedTimestamp":"2020-05-05T19:47:11Z",` +
+		`"annotations":{"authorization.k8s.io/decision":"forbid","authorization.k8s.io/reason":""}}`
+
+	parser := (&AuditParser{}).New()
+	events, err := parser.Parse(log)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	event := events[0].Event().(*Audit)
+	require.Equal(t, &map[string]string{
+		"authorization.k8s.io/decision": "forbid",
+		"authorization.k8s.io/reason":   "",
+	}, event.Annotations)
+}
+
+func TestAuditParserInvalidKind(t *testing.T) {
+	parser := (&AuditParser{}).New()
+	events, err := parser.Parse(`{"kind":"Policy","apiVersion":"audit.k8s.io/v1","auditID":"1","requestReceivedTimestamp":"2020-05-05T19:47:11Z"}`)
+	require.Error(t, err)
+	require.Nil(t, events)
+}
+
+func TestAuditType(t *testing.T) {
+	parser := (&AuditParser{}).New()
+	require.Equal(t, "Kubernetes.Audit", parser.LogType())
+}
+
+func expectedAudit() *Audit {
+	requestTime := time.Date(2020, 5, 5, 19, 47, 11, 118375000, time.UTC)
+	stageTime := time.Date(2020, 5, 5, 19, 47, 11, 120167000, time.UTC)
+	extra := jsoniter.RawMessage(`{"accessKeyId":["AKIAEXAMPLE"],"arn":["arn:aws:iam::123456789012:user/admin"]}`)
+	details := jsoniter.RawMessage(`{"name":"nginx","kind":"pods"}`)
+	expectedEvent := &Audit{
+		Kind:       aws.String("Event"),
+		APIVersion: aws.String("audit.k8s.io/v1"),
+		Level:      aws.String("RequestResponse"),
+		AuditID:    aws.String("5a6bd0a5-3a4b-4bb4-8b4e-0fa6f0c1e7b0"),
+		Stage:      aws.String("ResponseComplete"),
+		RequestURI: aws.String("/api/v1/namespaces/default/pods/nginx/exec?command=sh"),
+		Verb:       aws.String("create"),
+		User: &UserInfo{
+			Username: aws.String("kubernetes-admin"),
+			UID:      aws.String("heptio-authenticator-aws:123456789012:AIDAEXAMPLE"),
+			Groups:   []string{"system:masters", "system:authenticated"},
+			Extra:    &extra,
+		},
+		ImpersonatedUser: &UserInfo{
+			Username: aws.String("system:serviceaccount:default:builder"),
+		},
+		SourceIPs: []string{"10.0.0.10", "192.168.1.1"},
+		UserAgent: aws.String("kubectl/v1.17.0 (linux/amd64) kubernetes/70132b0"),
+		ObjectRef: &ObjectReference{
+			Resource:    aws.String("pods"),
+			Namespace:   aws.String("default"),
+			Name:        aws.String("nginx"),
+			APIVersion:  aws.String("v1"),
+			Subresource: aws.String("exec"),
+		},
+		ResponseStatus: &ResponseStatus{
+			Status:  aws.String("Failure"),
+			Message: aws.String(`pods "nginx" is forbidden`),
+			Reason:  aws.String("Forbidden"),
+			Details: &details,
+			Code:    aws.Int(403),
+		},
+		RequestReceivedTimestamp: (*timestamp.RFC3339)(&requestTime),
+		StageTimestamp:           (*timestamp.RFC3339)(&stageTime),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Kubernetes.Audit")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&requestTime)
+	expectedEvent.AppendAnyIPAddress("10.0.0.10")
+	expectedEvent.AppendAnyIPAddress("192.168.1.1")
+	expectedEvent.AppendAnyUsernames("kubernetes-admin", "system:serviceaccount:default:builder")
+	return expectedEvent
+}
+
+func checkAudit(t *testing.T, log string, expectedEvent *Audit) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := (&AuditParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
@@ -0,0 +1,192 @@
+package kvlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var KeyValueDesc = `Events made only of space separated key=value pairs (e.g., firewall logs)
+Values with spaces must be double quoted. CEF, LEEF and syslog events are not key=value events.`
+
+// The keys holding IP addresses, host names, users or MAC addresses, following the CEF dictionary and common variants
+var (
+	ipAddressKeys  = []string{"src", "dst", "srcip", "dstip", "src_ip", "dst_ip", "dvc"}
+	hostNameKeys   = []string{"shost", "dhost", "dvchost", "host", "hostname"}
+	userKeys       = []string{"suser", "duser", "user", "username", "srcuser", "dstuser"}
+	macAddressKeys = []string{"smac", "dmac", "dvcmac", "mac", "srcmac", "dstmac"}
+)
+
+// nolint:lll
+type KeyValue struct {
+	Timestamp *timestamp.RFC3339 `json:"timestamp,omitempty" description:"The time of the event in UTC, from the timestamp, time or date and time keys."`
+	Fields    *map[string]string `json:"fields" validate:"required" description:"The key value pairs of the event."`
+
+	// NOTE: added to end of struct to allow expansion later
+	parsers.PantherLog
+}
+
+// KeyValueParser parses key=value events
+type KeyValueParser struct{}
+
+var _ parsers.LogParser = (*KeyValueParser)(nil)
+
+// New returns an initialized LogParser for key=value events
+func (p *KeyValueParser) New() parsers.LogParser {
+	return &KeyValueParser{}
+}
+
+var (
+	// the keys of the pairs
+	keyRegex = regexp.MustCompile(`^[\w.\-]+$`)
+	// the syslog priority header, e.g. <134>
+	syslogPriorityRegex = regexp.MustCompile(`^<\d{1,3}>`)
+)
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *KeyValueParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	log = strings.TrimSpace(log)
+	// CEF, LEEF and syslog events have their own parsers and would otherwise be classified as key=value events too
+	if strings.HasPrefix(log, "CEF:") || strings.HasPrefix(log, "LEEF:") {
+		return nil, errors.New("CEF and LEEF events are not key=value events")
+	}
+	if syslogPriorityRegex.MatchString(log) {
+		return nil, errors.New("syslog events are not key=value events")
+	}
+
+	fields, err := splitPairs(log)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("no key=value pair found")
+	}
+	event := &KeyValue{
+		Fields: &fields,
+	}
+	event.Timestamp = eventTimestamp(fields)
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *KeyValueParser) LogType() string {
+	return "KeyValue.Event"
+}
+
+func (event *KeyValue) updatePantherFields(p *KeyValueParser) {
+	event.SetCoreFields(p.LogType(), event.Timestamp, event)
+
+	fields := *event.Fields
+	for _, key := range ipAddressKeys {
+		if value, found := fields[key]; found {
+			event.AppendAnyIPAddress(value)
+		}
+	}
+	for _, key := range hostNameKeys {
+		if value, found := fields[key]; found && !event.AppendAnyIPAddress(value) {
+			event.AppendAnyDomainNames(value)
+		}
+	}
+	for _, key := range userKeys {
+		if value, found := fields[key]; found {
+			// user names can be email addresses, those that are not are ignored by AppendAnyEmails
+			event.AppendAnyUsernames(value)
+			event.AppendAnyEmails(value)
+		}
+	}
+	for _, key := range macAddressKeys {
+		if value, found := fields[key]; found {
+			event.AppendAnyMACAddresses(value)
+		}
+	}
+}
+
+// splitPairs splits space separated key=value pairs, values can be double quoted and escape quotes with a backslash
+func splitPairs(log string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for log = strings.TrimSpace(log); log != ""; log = strings.TrimLeft(log, " \t") {
+		equal := strings.IndexByte(log, '=')
+		if equal <= 0 || !keyRegex.MatchString(log[:equal]) {
+			return nil, errors.Errorf("expected a key=value pair at %q", log)
+		}
+		key := log[:equal]
+		log = log[equal+1:]
+
+		var value string
+		if strings.HasPrefix(log, `"`) {
+			var builder strings.Builder
+			end := -1
+			for i := 1; i < len(log); i++ {
+				if log[i] == '\\' && i+1 < len(log) && (log[i+1] == '"' || log[i+1] == '\\') {
+					i++
+				} else if log[i] == '"' {
+					end = i
+					break
+				}
+				builder.WriteByte(log[i])
+			}
+			if end < 0 {
+				return nil, errors.Errorf("unterminated quoted value for key %q", key)
+			}
+			value, log = builder.String(), log[end+1:]
+			if log != "" && log[0] != ' ' && log[0] != '\t' {
+				return nil, errors.Errorf("expected a space after the quoted value of key %q", key)
+			}
+		} else {
+			end := strings.IndexAny(log, " \t")
+			if end < 0 {
+				end = len(log)
+			}
+			value, log = log[:end], log[end:]
+		}
+		fields[key] = value
+	}
+	return fields, nil
+}
+
+// eventTimestamp returns the time of the event, from a single key or from separate date and time keys
+func eventTimestamp(fields map[string]string) *timestamp.RFC3339 {
+	for _, key := range []string{"timestamp", "time", "eventtime"} {
+		if t, err := time.Parse(time.RFC3339Nano, fields[key]); err == nil {
+			ts := timestamp.RFC3339(t.UTC())
+			return &ts
+		}
+	}
+	if date, found := fields["date"]; found {
+		if t, err := time.Parse("2006-01-02 15:04:05", date+" "+fields["time"]); err == nil {
+			ts := timestamp.RFC3339(t)
+			return &ts
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package kvlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestKeyValue(t *testing.T) {
+	// nolint:lll
+	log := `date=2020-01-14 time=10:11:12 devname="FG 100" type="traffic" srcip=10.1.100.11 dstip=172.16.200.55 dstport=80 srcmac=00:0C:29:8E:6A:1F user="Jane.Doe@example.com" action="deny" msg="quoted \"value\""`
+
+	expectedTime := time.Date(2020, 1, 14, 10, 11, 12, 0, time.UTC)
+	expectedEvent := &KeyValue{
+		Timestamp: (*timestamp.RFC3339)(&expectedTime),
+		Fields: &map[string]string{
+			"date":    "2020-01-14",
+			"time":    "10:11:12",
+			"devname": "FG 100",
+			"type":    "traffic",
+			"srcip":   "10.1.100.11",
+			"dstip":   "172.16.200.55",
+			"dstport": "80",
+			"srcmac":  "00:0C:29:8E:6A:1F",
+			"user":    "Jane.Doe@example.com",
+			"action":  "deny",
+			"msg":     `quoted "value"`,
+		},
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("KeyValue.Event")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("10.1.100.11")
+	expectedEvent.AppendAnyIPAddress("172.16.200.55")
+	expectedEvent.AppendAnyUsernames("Jane.Doe@example.com")
+	expectedEvent.AppendAnyEmails("jane.doe@example.com")
+	expectedEvent.AppendAnyMACAddresses("00:0c:29:8e:6a:1f")
+
+	checkKeyValue(t, log, expectedEvent)
+}
+
+func TestKeyValueQuotedTimestamp(t *testing.T) {
+	log := ` timestamp="2020-01-14T10:11:11.5Z" src=10.0.0.1 dst=10.0.0.2 dhost=www.example.com `
+
+	expectedTime := time.Date(2020, 1, 14, 10, 11, 11, 500000000, time.UTC)
+	expectedEvent := &KeyValue{
+		Timestamp: (*timestamp.RFC3339)(&expectedTime),
+		Fields: &map[string]string{
+			"timestamp": "2020-01-14T10:11:11.5Z",
+			"src":       "10.0.0.1",
+			"dst":       "10.0.0.2",
+			"dhost":     "www.example.com",
+		},
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("KeyValue.Event")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("10.0.0.1")
+	expectedEvent.AppendAnyIPAddress("10.0.0.2")
+	expectedEvent.AppendAnyDomainNames("www.example.com")
+
+	checkKeyValue(t, log, expectedEvent)
+}
+
+func TestKeyValueInvalid(t *testing.T) {
+	parser := (&KeyValueParser{}).New()
+	for _, log := range []string{
+		`no pairs here`,
+		`a=b free text c=d`,
+		`a="unterminated`,
+		`a="quoted"b=c`,
+		`{"json":"a=b"}`,
+		``,
+		// free text before the pairs
+		`connection closed user=root result=failure`,
+		// the formats with their own parsers
+		`CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2`,
+		"LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0\tdst=172.50.123.1",
+		`<14>1 2020-01-14T10:11:12Z fw01 proxy - - - src=10.0.0.1 dst=10.0.0.2`,
+		`<134>Feb 14 19:04:54 host01 sshd[42]: user=root result=failure`,
+		`<134>user=root result=failure`,
+	} {
+		_, err := parser.Parse(log)
+		require.Error(t, err, log)
+	}
+}
+
+func TestKeyValueType(t *testing.T) {
+	parser := &KeyValueParser{}
+	require.Equal(t, "KeyValue.Event", parser.LogType())
+}
+
+// the fields are compared without serializing the event to JSON
+func checkKeyValue(t *testing.T, log string, expectedEvent *KeyValue) {
+	parser := (&KeyValueParser{}).New()
+	logs, err := parser.Parse(log)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	event := logs[0].Event().(*KeyValue)
+
+	require.NotNil(t, event.PantherRowID)
+	require.NotNil(t, event.PantherParseTime)
+	expectedEvent.PantherRowID = event.PantherRowID
+	expectedEvent.PantherParseTime = event.PantherParseTime
+	if expectedEvent.PantherEventTime == nil {
+		expectedEvent.PantherEventTime = event.PantherParseTime
+	}
+	expectedEvent.SetEvent(expectedEvent)
+	require.Equal(t, expectedEvent, event)
+}
@@ -0,0 +1,398 @@
+package leeflogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/sysloglogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var LEEFDesc = `IBM QRadar Log Event Extended Format (LEEF) events, optionally sent with a syslog header
+Reference: https://www.ibm.com/support/knowledgecenter/SS42VS_DSM/com.ibm.dsm.doc/c_LEEF_Format_Guide_intro.html`
+
+const (
+	leefPrefix = "LEEF:"
+	// version, vendor, product, product version, event id
+	leefHeaderFields = 5
+	// LEEF 1.0 attributes are separated by tabs, LEEF 2.0 can set the delimiter in the header
+	defaultDelimiter = "\t"
+	// the default format of devTime, https://www.ibm.com/support/knowledgecenter/SS42VS_DSM/com.ibm.dsm.doc/r_LEEF_predefined_attributes.html
+	defaultDevTimeLayout = "Jan 2 2006 15:04:05"
+)
+
+// nolint:lll
+type LEEF struct {
+	Syslog         *sysloglogs.Header `json:"syslog,omitempty" description:"The syslog header the event was sent with."`
+	Version        *string            `json:"version" validate:"required" description:"The version of the LEEF format, 1.0 or 2.0."`
+	Vendor         *string            `json:"vendor" validate:"required" description:"The vendor of the sending device."`
+	ProductName    *string            `json:"productName" validate:"required" description:"The product name of the sending device."`
+	ProductVersion *string            `json:"productVersion" validate:"required" description:"The version of the sending device."`
+	EventID        *string            `json:"eventId" validate:"required" description:"The unique identifier of the event type."`
+
+	// Predefined attributes
+	Category       *string            `json:"cat,omitempty" description:"The category of the event."`
+	DevTime        *timestamp.RFC3339 `json:"devTime,omitempty" description:"The time of the event in UTC."`
+	DevTimeFormat  *string            `json:"devTimeFormat,omitempty" description:"The Java date format of devTime."`
+	Protocol       *string            `json:"proto,omitempty" description:"The transport protocol of the event."`
+	Severity       *int               `json:"sev,omitempty" description:"The severity of the event, from 1 to 10."`
+	Src            *string            `json:"src,omitempty" description:"The source IP address."`
+	Dst            *string            `json:"dst,omitempty" description:"The destination IP address."`
+	SrcPort        *uint16            `json:"srcPort,omitempty" description:"The source port."`
+	DstPort        *uint16            `json:"dstPort,omitempty" description:"The destination port."`
+	SrcPreNAT      *string            `json:"srcPreNAT,omitempty" description:"The source IP address before network address translation."`
+	DstPreNAT      *string            `json:"dstPreNAT,omitempty" description:"The destination IP address before network address translation."`
+	SrcPostNAT     *string            `json:"srcPostNAT,omitempty" description:"The source IP address after network address translation."`
+	DstPostNAT     *string            `json:"dstPostNAT,omitempty" description:"The destination IP address after network address translation."`
+	SrcPreNATPort  *uint16            `json:"srcPreNATPort,omitempty" description:"The source port before network address translation."`
+	DstPreNATPort  *uint16            `json:"dstPreNATPort,omitempty" description:"The destination port before network address translation."`
+	SrcPostNATPort *uint16            `json:"srcPostNATPort,omitempty" description:"The source port after network address translation."`
+	DstPostNATPort *uint16            `json:"dstPostNATPort,omitempty" description:"The destination port after network address translation."`
+	SrcMAC         *string            `json:"srcMAC,omitempty" description:"The source MAC address."`
+	DstMAC         *string            `json:"dstMAC,omitempty" description:"The destination MAC address."`
+	SrcBytes       *int64             `json:"srcBytes,omitempty" description:"The number of bytes sent by the source."`
+	DstBytes       *int64             `json:"dstBytes,omitempty" description:"The number of bytes sent by the destination."`
+	SrcPackets     *int64             `json:"srcPackets,omitempty" description:"The number of packets sent by the source."`
+	DstPackets     *int64             `json:"dstPackets,omitempty" description:"The number of packets sent by the destination."`
+	TotalPackets   *int64             `json:"totalPackets,omitempty" description:"The total number of packets of the event."`
+	UserName       *string            `json:"usrName,omitempty" description:"The user associated with the event."`
+	AccountName    *string            `json:"accountName,omitempty" description:"The account associated with the event."`
+	Role           *string            `json:"role,omitempty" description:"The role of the user."`
+	Realm          *string            `json:"realm,omitempty" description:"The realm of the event."`
+	Policy         *string            `json:"policy,omitempty" description:"The policy associated with the event."`
+	Resource       *string            `json:"resource,omitempty" description:"The resource associated with the event."`
+	URL            *string            `json:"url,omitempty" description:"The URL associated with the event."`
+	GroupID        *string            `json:"groupID,omitempty" description:"The group associated with the event."`
+	Domain         *string            `json:"domain,omitempty" description:"The domain associated with the event."`
+	IdentSrc       *string            `json:"identSrc,omitempty" description:"The IP address of the identity the event refers to."`
+	IdentHostName  *string            `json:"identHostName,omitempty" description:"The host name of the identity the event refers to."`
+	IdentNetBios   *string            `json:"identNetBios,omitempty" description:"The NetBIOS name of the identity the event refers to."`
+	IdentGroupName *string            `json:"identGrpName,omitempty" description:"The group name of the identity the event refers to."`
+	IdentMAC       *string            `json:"identMAC,omitempty" description:"The MAC address of the identity the event refers to."`
+	IsLoginEvent   *bool              `json:"isLoginEvent,omitempty" description:"The event is a login."`
+	IsLogoutEvent  *bool              `json:"isLogoutEvent,omitempty" description:"The event is a logout."`
+	VirtualSrc     *string            `json:"vSrc,omitempty" description:"The virtual source IP address of the event."`
+	VirtualSrcName *string            `json:"vSrcName,omitempty" description:"The virtual source name of the event."`
+	Attributes     *map[string]string `json:"attributes,omitempty" description:"The custom attributes of the event (and predefined attributes with invalid values)."`
+
+	// NOTE: added to end of struct to allow expansion later
+	parsers.PantherLog
+}
+
+// LEEFParser parses LEEF events, with or without a syslog header
+type LEEFParser struct {
+	syslogParser *sysloglogs.HeaderParser
+}
+
+var _ parsers.LogParser = (*LEEFParser)(nil)
+
+// New returns an initialized LogParser for LEEF events
+func (p *LEEFParser) New() parsers.LogParser {
+	return &LEEFParser{
+		syslogParser: sysloglogs.NewHeaderParser(),
+	}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *LEEFParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	if p.syslogParser == nil {
+		return nil, errors.New("parser can not be nil")
+	}
+
+	start := strings.Index(log, leefPrefix)
+	if start < 0 {
+		return nil, errors.New("LEEF prefix not found")
+	}
+	event := &LEEF{}
+	if header := log[:start]; strings.TrimSpace(header) != "" {
+		syslogHeader, err := p.syslogParser.Parse(header)
+		if err != nil {
+			return nil, err
+		}
+		event.Syslog = syslogHeader
+	}
+
+	fields := strings.SplitN(log[start+len(leefPrefix):], "|", leefHeaderFields+1)
+	if len(fields) != leefHeaderFields+1 {
+		return nil, errors.Errorf("expected %d LEEF header fields, found %d", leefHeaderFields, len(fields)-1)
+	}
+	event.Version = &fields[0]
+	event.Vendor = &fields[1]
+	event.ProductName = &fields[2]
+	event.ProductVersion = &fields[3]
+	event.EventID = &fields[4]
+
+	attributes := fields[leefHeaderFields]
+	delimiter := defaultDelimiter
+	if strings.HasPrefix(*event.Version, "2") {
+		// LEEF 2.0 adds the delimiter of the attributes to the header, the field is optional
+		if end := strings.IndexByte(attributes, '|'); end >= 0 && !strings.Contains(attributes[:end], "=") {
+			var err error
+			if delimiter, err = parseDelimiter(attributes[:end]); err != nil {
+				return nil, err
+			}
+			attributes = attributes[end+1:]
+		}
+	}
+
+	var devTime string
+	for _, attribute := range strings.Split(attributes, delimiter) {
+		if attribute == "" {
+			continue
+		}
+		keyValue := strings.SplitN(attribute, "=", 2)
+		if len(keyValue) != 2 {
+			return nil, errors.Errorf("invalid LEEF attribute %q", attribute)
+		}
+		key, value := keyValue[0], keyValue[1]
+		if key == "devTime" {
+			// the format can follow the time
+			devTime = value
+			continue
+		}
+		if setField, found := attributeFields[key]; found && setField(event, value) {
+			continue
+		}
+		event.addAttribute(key, value)
+	}
+	if devTime != "" {
+		if t, err := parseDevTime(devTime, event.DevTimeFormat); err == nil {
+			event.DevTime = &t
+		} else {
+			event.addAttribute("devTime", devTime)
+		}
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *LEEFParser) LogType() string {
+	return "LEEF.Event"
+}
+
+func (event *LEEF) addAttribute(key, value string) {
+	if event.Attributes == nil {
+		event.Attributes = &map[string]string{}
+	}
+	(*event.Attributes)[key] = value
+}
+
+func (event *LEEF) updatePantherFields(p *LEEFParser) {
+	eventTime := event.DevTime
+	if eventTime == nil && event.Syslog != nil {
+		eventTime = event.Syslog.Timestamp
+	}
+	event.SetCoreFields(p.LogType(), eventTime, event)
+
+	if event.Syslog != nil {
+		event.Syslog.UpdatePantherFields(&event.PantherLog)
+	}
+	for _, address := range []*string{
+		event.Src,
+		event.Dst,
+		event.SrcPreNAT,
+		event.DstPreNAT,
+		event.SrcPostNAT,
+		event.DstPostNAT,
+		event.IdentSrc,
+		event.VirtualSrc,
+	} {
+		event.AppendAnyIPAddressPtr(address)
+	}
+	if !event.AppendAnyIPAddressPtr(event.IdentHostName) {
+		event.AppendAnyDomainNamePtrs(event.IdentHostName)
+	}
+	event.AppendAnyUsernamePtrs(event.UserName)
+	event.AppendAnyEmailPtrs(event.UserName)
+	event.AppendAnyMACAddressPtrs(event.SrcMAC, event.DstMAC, event.IdentMAC)
+}
+
+// parseDelimiter parses the LEEF 2.0 delimiter, a single character or its hex code (e.g., x09 or 0x09)
+func parseDelimiter(value string) (string, error) {
+	if len(value) == 1 {
+		return value, nil
+	}
+	hex := strings.TrimPrefix(strings.TrimPrefix(value, "0"), "x")
+	if hex != value {
+		if code, err := strconv.ParseUint(hex, 16, 8); err == nil {
+			return string(rune(code)), nil
+		}
+	}
+	return "", errors.Errorf("invalid LEEF delimiter %q", value)
+}
+
+// parseDevTime parses the time of the event in the given Java format, or the default format or milliseconds since epoch
+func parseDevTime(value string, javaFormat *string) (timestamp.RFC3339, error) {
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return timestamp.RFC3339(time.Unix(0, millis*int64(time.Millisecond)).UTC()), nil
+	}
+	layout := defaultDevTimeLayout
+	if javaFormat != nil {
+		layout = javaTimeLayout(*javaFormat)
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return timestamp.RFC3339{}, err
+	}
+	if t.Year() == 0 { // the year is not part of the format
+		t = timestamp.InferYear(t, time.Now().UTC())
+	}
+	return timestamp.RFC3339(t.UTC()), nil
+}
+
+// Converts the letters of the Java SimpleDateFormat patterns used in devTimeFormat, the longer patterns come first
+var javaTimeLayoutReplacer = strings.NewReplacer(
+	"'T'", "T",
+	"yyyy", "2006",
+	"yy", "06",
+	"MMMM", "January",
+	"MMM", "Jan",
+	"MM", "01",
+	"dd", "02",
+	"d", "2",
+	"EEEE", "Monday",
+	"EEE", "Mon",
+	"HH", "15",
+	"hh", "03",
+	"mm", "04",
+	"ss", "05",
+	"SSS", "000",
+	"a", "PM",
+	"zzz", "MST",
+	"z", "MST",
+	"XXX", "-07:00",
+	"Z", "-0700",
+)
+
+func javaTimeLayout(javaFormat string) string {
+	return javaTimeLayoutReplacer.Replace(javaFormat)
+}
+
+// attributeSetter sets the field of a predefined attribute, it returns false if the value is invalid for the field
+type attributeSetter func(event *LEEF, value string) bool
+
+// nolint:lll
+var attributeFields = map[string]attributeSetter{
+	"cat":            stringField(func(e *LEEF) **string { return &e.Category }),
+	"devTimeFormat":  stringField(func(e *LEEF) **string { return &e.DevTimeFormat }),
+	"proto":          stringField(func(e *LEEF) **string { return &e.Protocol }),
+	"sev":            intField(func(e *LEEF) **int { return &e.Severity }),
+	"src":            stringField(func(e *LEEF) **string { return &e.Src }),
+	"dst":            stringField(func(e *LEEF) **string { return &e.Dst }),
+	"srcPort":        portField(func(e *LEEF) **uint16 { return &e.SrcPort }),
+	"dstPort":        portField(func(e *LEEF) **uint16 { return &e.DstPort }),
+	"srcPreNAT":      stringField(func(e *LEEF) **string { return &e.SrcPreNAT }),
+	"dstPreNAT":      stringField(func(e *LEEF) **string { return &e.DstPreNAT }),
+	"srcPostNAT":     stringField(func(e *LEEF) **string { return &e.SrcPostNAT }),
+	"dstPostNAT":     stringField(func(e *LEEF) **string { return &e.DstPostNAT }),
+	"srcPreNATPort":  portField(func(e *LEEF) **uint16 { return &e.SrcPreNATPort }),
+	"dstPreNATPort":  portField(func(e *LEEF) **uint16 { return &e.DstPreNATPort }),
+	"srcPostNATPort": portField(func(e *LEEF) **uint16 { return &e.SrcPostNATPort }),
+	"dstPostNATPort": portField(func(e *LEEF) **uint16 { return &e.DstPostNATPort }),
+	"srcMAC":         stringField(func(e *LEEF) **string { return &e.SrcMAC }),
+	"dstMAC":         stringField(func(e *LEEF) **string { return &e.DstMAC }),
+	"srcBytes":       int64Field(func(e *LEEF) **int64 { return &e.SrcBytes }),
+	"dstBytes":       int64Field(func(e *LEEF) **int64 { return &e.DstBytes }),
+	"srcPackets":     int64Field(func(e *LEEF) **int64 { return &e.SrcPackets }),
+	"dstPackets":     int64Field(func(e *LEEF) **int64 { return &e.DstPackets }),
+	"totalPackets":   int64Field(func(e *LEEF) **int64 { return &e.TotalPackets }),
+	"usrName":        stringField(func(e *LEEF) **string { return &e.UserName }),
+	"accountName":    stringField(func(e *LEEF) **string { return &e.AccountName }),
+	"role":           stringField(func(e *LEEF) **string { return &e.Role }),
+	"realm":          stringField(func(e *LEEF) **string { return &e.Realm }),
+	"policy":         stringField(func(e *LEEF) **string { return &e.Policy }),
+	"resource":       stringField(func(e *LEEF) **string { return &e.Resource }),
+	"url":            stringField(func(e *LEEF) **string { return &e.URL }),
+	"groupID":        stringField(func(e *LEEF) **string { return &e.GroupID }),
+	"domain":         stringField(func(e *LEEF) **string { return &e.Domain }),
+	"identSrc":       stringField(func(e *LEEF) **string { return &e.IdentSrc }),
+	"identHostName":  stringField(func(e *LEEF) **string { return &e.IdentHostName }),
+	"identNetBios":   stringField(func(e *LEEF) **string { return &e.IdentNetBios }),
+	"identGrpName":   stringField(func(e *LEEF) **string { return &e.IdentGroupName }),
+	"identMAC":       stringField(func(e *LEEF) **string { return &e.IdentMAC }),
+	"isLoginEvent":   boolField(func(e *LEEF) **bool { return &e.IsLoginEvent }),
+	"isLogoutEvent":  boolField(func(e *LEEF) **bool { return &e.IsLogoutEvent }),
+	"vSrc":           stringField(func(e *LEEF) **string { return &e.VirtualSrc }),
+	"vSrcName":       stringField(func(e *LEEF) **string { return &e.VirtualSrcName }),
+}
+
+func stringField(field func(event *LEEF) **string) attributeSetter {
+	return func(event *LEEF, value string) bool {
+		*field(event) = &value
+		return true
+	}
+}
+
+func intField(field func(event *LEEF) **int) attributeSetter {
+	return func(event *LEEF, value string) bool {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return false
+		}
+		*field(event) = &n
+		return true
+	}
+}
+
+func int64Field(field func(event *LEEF) **int64) attributeSetter {
+	return func(event *LEEF, value string) bool {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		*field(event) = &n
+		return true
+	}
+}
+
+func portField(field func(event *LEEF) **uint16) attributeSetter {
+	return func(event *LEEF, value string) bool {
+		n, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return false
+		}
+		port := uint16(n)
+		*field(event) = &port
+		return true
+	}
+}
+
+func boolField(field func(event *LEEF) **bool) attributeSetter {
+	return func(event *LEEF, value string) bool {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return false
+		}
+		*field(event) = &b
+		return true
+	}
+}
@@ -0,0 +1,166 @@
+package leeflogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/sysloglogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestLEEF(t *testing.T) {
+	// nolint:lll
+	log := "LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0\tdst=172.50.123.1\tsev=5\tcat=anomaly\tsrcPort=81\tdstPort=21\tusrName=joe.black\tdevTime=Jan 14 2020 10:11:12"
+
+	expectedTime := time.Date(2020, 1, 14, 10, 11, 12, 0, time.UTC)
+	expectedEvent := &LEEF{
+		Version:        aws.String("1.0"),
+		Vendor:         aws.String("Microsoft"),
+		ProductName:    aws.String("MSExchange"),
+		ProductVersion: aws.String("4.0 SP1"),
+		EventID:        aws.String("15345"),
+		Src:            aws.String("192.0.2.0"),
+		Dst:            aws.String("172.50.123.1"),
+		Severity:       aws.Int(5),
+		Category:       aws.String("anomaly"),
+		SrcPort:        aws.Uint16(81),
+		DstPort:        aws.Uint16(21),
+		UserName:       aws.String("joe.black"),
+		DevTime:        (*timestamp.RFC3339)(&expectedTime),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("LEEF.Event")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("192.0.2.0")
+	expectedEvent.AppendAnyIPAddress("172.50.123.1")
+	expectedEvent.AppendAnyUsernames("joe.black")
+
+	checkLEEF(t, log, expectedEvent)
+}
+
+func TestLEEF2WithDelimiterAndRFC3164Header(t *testing.T) {
+	// nolint:lll
+	log := `<13>Jan 18 11:07:53 192.168.1.1 LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dst=10.0.0.5^sev=5^srcPort=81^dstPort=21^devTimeFormat=yyyy-MM-dd'T'HH:mm:ss.SSSZ^devTime=2020-01-18T11:07:52.000+0100^identHostName=host.example.com`
+
+	syslogTime := time.Date(time.Now().UTC().Year(), 1, 18, 11, 7, 53, 0, time.UTC)
+	expectedTime := time.Date(2020, 1, 18, 10, 7, 52, 0, time.UTC)
+	expectedEvent := &LEEF{
+		Syslog: &sysloglogs.Header{
+			Priority:  aws.Uint8(13),
+			Facility:  aws.Uint8(1),
+			Severity:  aws.Uint8(5),
+			Timestamp: (*timestamp.RFC3339)(&syslogTime),
+			Hostname:  aws.String("192.168.1.1"),
+		},
+		Version:        aws.String("2.0"),
+		Vendor:         aws.String("Lancope"),
+		ProductName:    aws.String("StealthWatch"),
+		ProductVersion: aws.String("1.0"),
+		EventID:        aws.String("41"),
+		Src:            aws.String("10.0.1.8"),
+		Dst:            aws.String("10.0.0.5"),
+		Severity:       aws.Int(5),
+		SrcPort:        aws.Uint16(81),
+		DstPort:        aws.Uint16(21),
+		DevTimeFormat:  aws.String("yyyy-MM-dd'T'HH:mm:ss.SSSZ"),
+		DevTime:        (*timestamp.RFC3339)(&expectedTime),
+		IdentHostName:  aws.String("host.example.com"),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("LEEF.Event")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("192.168.1.1")
+	expectedEvent.AppendAnyIPAddress("10.0.1.8")
+	expectedEvent.AppendAnyIPAddress("10.0.0.5")
+	expectedEvent.AppendAnyDomainNames("host.example.com")
+
+	checkLEEF(t, log, expectedEvent)
+}
+
+func TestLEEF2WithHexDelimiter(t *testing.T) {
+	log := "LEEF:2.0|Vendor|Product|1.0|100|x09|src=10.0.0.1\tdevTime=1578960000000"
+
+	expectedTime := time.Date(2020, 1, 14, 0, 0, 0, 0, time.UTC)
+	expectedEvent := &LEEF{
+		Version:        aws.String("2.0"),
+		Vendor:         aws.String("Vendor"),
+		ProductName:    aws.String("Product"),
+		ProductVersion: aws.String("1.0"),
+		EventID:        aws.String("100"),
+		Src:            aws.String("10.0.0.1"),
+		DevTime:        (*timestamp.RFC3339)(&expectedTime),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("LEEF.Event")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("10.0.0.1")
+
+	checkLEEF(t, log, expectedEvent)
+}
+
+func TestLEEFCustomAttributes(t *testing.T) {
+	log := "LEEF:1.0|Vendor|Product|1.0|100|srcPort=http\tcustomKey=a=b\tdevTime=yesterday"
+
+	parser := (&LEEFParser{}).New()
+	events, err := parser.Parse(log)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	event := events[0].Event().(*LEEF)
+	require.Nil(t, event.SrcPort)
+	require.Nil(t, event.DevTime)
+	require.Equal(t, &map[string]string{
+		"srcPort":   "http",
+		"customKey": "a=b",
+		"devTime":   "yesterday",
+	}, event.Attributes)
+}
+
+func TestLEEFInvalid(t *testing.T) {
+	parser := (&LEEFParser{}).New()
+	for _, log := range []string{
+		`CEF:0|Vendor|Product|1.0|100|name|5|src=10.0.0.1`,
+		`LEEF:1.0|Vendor|Product|1.0`,
+		"LEEF:1.0|Vendor|Product|1.0|100|src=10.0.0.1\tinvalid",
+		`LEEF:2.0|Vendor|Product|1.0|100|xZZ|src=10.0.0.1`,
+	} {
+		_, err := parser.Parse(log)
+		require.Error(t, err, log)
+	}
+}
+
+func TestLEEFType(t *testing.T) {
+	parser := &LEEFParser{}
+	require.Equal(t, "LEEF.Event", parser.LogType())
+}
+
+func checkLEEF(t *testing.T, log string, expectedEvent *LEEF) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := (&LEEFParser{}).New()
+	logs, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), logs, err)
+}
@@ -126,4 +126,5 @@ func (p *AccessParser) LogType() string {
 func (event *Access) updatePantherFields(p *AccessParser) {
 	event.SetCoreFields(p.LogType(), event.Time, event)
 	event.AppendAnyIPAddressPtr(event.RemoteAddress)
+	event.AppendAnyUsernamePtrs(event.RemoteUser)
 }
@@ -76,6 +76,31 @@ func TestAccessLogWithoutReferer(t *testing.T) {
 	checkAccessLog(t, log, expectedEvent)
 }
 
+func TestAccessLogWithRemoteUser(t *testing.T) {
+	//nolint:lll
+	log := `180.76.15.143 - alice [06/Feb/2019:00:00:38 +0000] "GET /admin HTTP/1.1" 200 193 "-" "curl/7.64.1"`
+
+	expectedTime := time.Unix(1549411238, 0).UTC()
+
+	expectedEvent := &Access{
+		RemoteAddress: aws.String("180.76.15.143"),
+		RemoteUser:    aws.String("alice"),
+		Time:          (*timestamp.RFC3339)(&expectedTime),
+		Request:       aws.String("GET /admin HTTP/1.1"),
+		Status:        aws.Int16(200),
+		BodyBytesSent: aws.Int(193),
+		HTTPUserAgent: aws.String("curl/7.64.1"),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Nginx.Access")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyIPAddress("180.76.15.143")
+	expectedEvent.AppendAnyUsernames("alice")
+
+	checkAccessLog(t, log, expectedEvent)
+}
+
 func TestAccessLogType(t *testing.T) {
 	parser := &AccessParser{}
 	require.Equal(t, "Nginx.Access", parser.LogType())
@@ -91,4 +91,7 @@ func (event *Differential) updatePantherFields(p *DifferentialParser) {
 
 	event.AppendAnyIPAddress(event.Columns["local_address"])
 	event.AppendAnyIPAddress(event.Columns["remote_address"])
+	// e.g. the users, logged_in_users, last and interface_details tables
+	event.AppendAnyUsernames(event.Columns["username"], event.Columns["user"])
+	event.AppendAnyMACAddresses(event.Columns["mac"])
 }
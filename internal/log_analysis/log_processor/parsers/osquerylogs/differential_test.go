@@ -96,6 +96,41 @@ func TestDifferentialLogWithExtraIps(t *testing.T) {
 	checkOsQueryDifferentialLog(t, log, expectedEvent)
 }
 
+func TestDifferentialLogWithUsernameAndMAC(t *testing.T) {
+	//nolint:lll
+	log := `{"name":"pack_incident-response_logged_in_users","hostIdentifier":"Quans-MacBook-Pro-2.local","calendarTime":"Tue Nov 5 06:08:26 2018 UTC","unixTime":"1572934106","epoch":"0","counter":"62","logNumericsAsNumbers":"false","decorations":{"host_uuid":"F919E9BF-0BF1-5456-8F6C-335243AEA537"},"columns":{"user":"quan","host":"192.168.1.2","mac":"8C:85:90:0B:4F:21"},"action":"added","log_type":"result"}`
+
+	expectedTime := time.Unix(1541398106, 0).UTC()
+	expectedEvent := &Differential{
+		Action:               aws.String("added"),
+		Name:                 aws.String("pack_incident-response_logged_in_users"),
+		Epoch:                (*numerics.Integer)(aws.Int(0)),
+		HostIdentifier:       aws.String(("Quans-MacBook-Pro-2.local")),
+		UnixTime:             (*numerics.Integer)(aws.Int(1572934106)),
+		LogNumericsAsNumbers: aws.Bool(false),
+		LogType:              aws.String("result"),
+		CalendarTime:         (*timestamp.ANSICwithTZ)(&expectedTime),
+		Columns: map[string]string{
+			"user": "quan",
+			"host": "192.168.1.2",
+			"mac":  "8C:85:90:0B:4F:21",
+		},
+		Counter: (*numerics.Integer)(aws.Int(62)),
+		Decorations: map[string]string{
+			"host_uuid": "F919E9BF-0BF1-5456-8F6C-335243AEA537",
+		},
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Osquery.Differential")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyDomainNames("Quans-MacBook-Pro-2.local")
+	expectedEvent.AppendAnyUsernames("quan")
+	expectedEvent.AppendAnyMACAddresses("8c:85:90:0b:4f:21")
+
+	checkOsQueryDifferentialLog(t, log, expectedEvent)
+}
+
 func TestDifferentialLogWithoutLogNumericAsNumbers(t *testing.T) {
 	//nolint:lll
 	log := `{"action":"added","calendarTime":"Tue Nov 5 06:08:26 2018 UTC","columns":{"build_distro":"10.12"},"counter":"255","decorations":{"host_uuid":"37821E12-CC8A-5AA3-A90C-FAB28A5BF8F9" },"epoch":"0","hostIdentifier":"host.lan","log_type":"result","name":"pack_osquery-monitoring_osquery_info","unixTime":"1536682461"}`
@@ -149,6 +149,7 @@ func (event *EventInfo) updatePantherFields(p *EventInfoParser) {
 	event.SetCoreFields(p.LogType(), (*timestamp.RFC3339)(event.Timestamp), event)
 	event.AppendAnyIPAddressPtr(event.SrcIP)
 	event.AppendAnyIPAddressPtr(event.DstIP)
+	event.AppendAnyUsernamePtrs(event.SrcUser, event.DstUser)
 	if event.SyscheckFile != nil {
 		event.AppendAnyMD5HashPtrs(event.SyscheckFile.MD5Before, event.SyscheckFile.MD5After)
 		event.AppendAnySHA1HashPtrs(event.SyscheckFile.SHA1Before, event.SyscheckFile.SHA1After)
@@ -95,6 +95,37 @@ func TestEventInfoWithSyscheckFile(t *testing.T) {
 	checkEventInfo(t, log, expectedEvent)
 }
 
+func TestEventInfoWithUsers(t *testing.T) {
+	//nolint:lll
+	log := `{"rule":{"level":3,"comment":"Successful sudo to ROOT executed","sidid":5402,"group":"syslog,sudo,"},"id":"1510376401.1","TimeStamp":1510376401000,"location":"/var/log/secure","full_log":"Nov 11 00:00:01 ix sudo: alice : TTY=pts/0 ; PWD=/home/alice ; USER=root ; COMMAND=/bin/sh","hostname":"ix","program_name":"sudo","srcuser":"alice","dstuser":"root"}`
+
+	expectedTime := time.Unix(1510376401, 0).UTC()
+
+	expectedEvent := &EventInfo{
+		Rule: &Rule{
+			Level:   aws.Int(3),
+			Comment: aws.String("Successful sudo to ROOT executed"),
+			SIDID:   aws.Int(5402),
+			Group:   aws.String("syslog,sudo,"),
+		},
+		ID:          aws.String("1510376401.1"),
+		Timestamp:   (*timestamp.UnixMillisecond)(&expectedTime),
+		Location:    aws.String("/var/log/secure"),
+		FullLog:     aws.String("Nov 11 00:00:01 ix sudo: alice : TTY=pts/0 ; PWD=/home/alice ; USER=root ; COMMAND=/bin/sh"),
+		Hostname:    aws.String("ix"),
+		ProgramName: aws.String("sudo"),
+		SrcUser:     aws.String("alice"),
+		DstUser:     aws.String("root"),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("OSSEC.EventInfo")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyUsernames("alice", "root")
+
+	checkEventInfo(t, log, expectedEvent)
+}
+
 func TestEventInfoType(t *testing.T) {
 	parser := &EventInfoParser{}
 	require.Equal(t, "OSSEC.EventInfo", parser.LogType())
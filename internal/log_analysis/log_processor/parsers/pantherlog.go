@@ -22,6 +22,7 @@ import (
 	"net"
 	"regexp"
 	"sort"
+	"strings"
 
 	jsoniter "github.com/json-iterator/go"
 
@@ -33,7 +34,16 @@ const (
 )
 
 var (
-	ipv4Regex  = regexp.MustCompile(`(([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\.){3}([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])*`)
+	ipv4Regex = regexp.MustCompile(`(([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\.){3}([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])*`)
+	// matches IPv6 address candidates, including IPv4 suffixed (e.g. ::ffff:192.0.2.1) and zone suffixed (e.g. fe80::1%eth0) forms
+	ipv6Regex = regexp.MustCompile(`([0-9A-Fa-f]{0,4}:){2,7}(([0-9]{1,3}\.){3}[0-9]{1,3}|[0-9A-Fa-f]{0,4})(%[0-9A-Za-z_.\-]+)?`)
+	// a pragmatic check of the form of email addresses, not of RFC 5322
+	emailRegex = regexp.MustCompile(`^[a-z0-9!#$%&'*+/=?^_{|}~.-]+@[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`)
+	// matches the users in the authentication messages of sshd, PAM and sudo, e.g.
+	// `Failed password for invalid user admin from ...`, `session opened for user root by ...`, `ruser=alice`,
+	// `alice : TTY=pts/0 ; PWD=/home/alice ; USER=root ; COMMAND=/bin/sh`
+	usernameInFieldRegex = regexp.MustCompile(
+		`\bfor (?:invalid user |illegal user )?([^\s;,'"()]+) from\b|\b(?:for|[Ii]nvalid|[Ii]llegal) user ([^\s;,'"()]+)|\b(?:r?user|USER|logname)=([^\s;,'"()]+)|^\s*([^\s;,'"()]+) : TTY=`)
 	rowCounter RowID // number of rows generated in this lambda execution (used to generate p_row_id)
 )
 
@@ -56,6 +66,53 @@ type PantherLog struct {
 	PantherAnySHA1Hashes   *PantherAnyString `json:"p_any_sha1_hashes,omitempty" description:"Panther added field with collection of SHA1 hashes associated with the row"`
 	PantherAnyMD5Hashes    *PantherAnyString `json:"p_any_md5_hashes,omitempty" description:"Panther added field with collection of MD5 hashes associated with the row"`
 	PantherAnySHA256Hashes *PantherAnyString `json:"p_any_sha256_hashes,omitempty" description:"Panther added field with collection of SHA256 hashes of any algorithm associated with the row"`
+
+	// NOTE: new fields are added to PantherLogExtension
+	PantherLogExtension
+}
+
+// PantherLogExtension has the Panther fields added after log types extended PantherLog with their own fields (e.g., p_any_aws_*).
+// Their columns are placed at the end of every table (see glueschema.InferJSONColumns), after the columns of the extensions.
+// NOTE: It is VERY important that fields are added to END of the structure to avoid needed to re-build existing Glue partitions.
+// nolint(lll)
+type PantherLogExtension struct {
+	// optional (CloudWatch Logs subscriptions)
+	PantherCloudWatchLogGroup  *string `json:"p_cloudwatch_log_group,omitempty" description:"Panther added field with the CloudWatch Logs log group the event was delivered from"`
+	PantherCloudWatchLogStream *string `json:"p_cloudwatch_log_stream,omitempty" description:"Panther added field with the CloudWatch Logs log stream the event was delivered from"`
+	PantherCloudWatchOwner     *string `json:"p_cloudwatch_owner,omitempty" description:"Panther added field with the AWS account id that owns the CloudWatch Logs log group"`
+
+	// optional (GeoIP enrichment of p_any_ip_addresses)
+	PantherAnyIPCountries *PantherAnyString      `json:"p_any_ip_countries,omitempty" description:"Panther added field with collection of ISO country codes of the public ip addresses associated with the row"`
+	PantherAnyIPASNs      *PantherAnyString      `json:"p_any_ip_asns,omitempty" description:"Panther added field with collection of autonomous system numbers of the public ip addresses associated with the row"`
+	PantherIPGeolocations []PantherIPGeolocation `json:"p_ip_geolocations,omitempty" description:"Panther added field with the geolocation and autonomous system of each public ip address associated with the row"`
+
+	// optional (threat intel indicators matching the p_any fields)
+	PantherIOCMatches []PantherIOCMatch `json:"p_ioc_matches,omitempty" description:"Panther added field with the threat intel indicators of compromise associated with the row and their feeds"`
+
+	// optional (any, identities and devices)
+	PantherAnyUsernames    *PantherAnyString `json:"p_any_usernames,omitempty" description:"Panther added field with collection of usernames associated with the row"`
+	PantherAnyEmails       *PantherAnyString `json:"p_any_emails,omitempty" description:"Panther added field with collection of email addresses (lower case) associated with the row"`
+	PantherAnyMACAddresses *PantherAnyString `json:"p_any_mac_addresses,omitempty" description:"Panther added field with collection of MAC addresses (lower case, colon separated) associated with the row"`
+}
+
+// PantherIOCMatch is a threat intel indicator of compromise found in the Panther fields of an event
+type PantherIOCMatch struct {
+	Indicator *string `json:"indicator" description:"The matched indicator."`
+	Type      *string `json:"type" description:"The type of the indicator (ip, domain, md5, sha1 or sha256)."`
+	Feed      *string `json:"feed" description:"The threat intel feed of the indicator."`
+}
+
+// PantherIPGeolocation is the geolocation and autonomous system of an ip address
+// nolint(lll)
+type PantherIPGeolocation struct {
+	IP             *string  `json:"ip" description:"The ip address."`
+	CountryCode    *string  `json:"country_code,omitempty" description:"The ISO 3166-1 alpha-2 code of the country."`
+	Country        *string  `json:"country,omitempty" description:"The English name of the country."`
+	City           *string  `json:"city,omitempty" description:"The English name of the city."`
+	Latitude       *float64 `json:"latitude,omitempty" description:"The approximate latitude of the location."`
+	Longitude      *float64 `json:"longitude,omitempty" description:"The approximate longitude of the location."`
+	ASN            *int64   `json:"asn,omitempty" description:"The autonomous system number."`
+	ASOrganization *string  `json:"as_organization,omitempty" description:"The organization of the autonomous system."`
 }
 
 type PantherAnyString struct { // needed to declare as struct (rather than map) for CF generation
@@ -69,19 +126,27 @@ func NewPantherAnyString() *PantherAnyString {
 }
 
 func (any *PantherAnyString) MarshalJSON() ([]byte, error) {
-	if any != nil { // copy to slice
-		values := make([]string, len(any.set))
-		i := 0
-		for k := range any.set {
-			values[i] = k
-			i++
-		}
-		sort.Strings(values) // sort for consistency and to improve compression when stored
-		return jsoniter.Marshal(values)
+	if any != nil {
+		return jsoniter.Marshal(any.Values())
 	}
 	return []byte{}, nil
 }
 
+// Values returns the sorted values of the set
+func (any *PantherAnyString) Values() []string {
+	if any == nil {
+		return nil
+	}
+	values := make([]string, len(any.set))
+	i := 0
+	for k := range any.set {
+		values[i] = k
+		i++
+	}
+	sort.Strings(values) // sort for consistency and to improve compression when stored
+	return values
+}
+
 func (any *PantherAnyString) UnmarshalJSON(jsonBytes []byte) error {
 	var values []string
 	err := jsoniter.Unmarshal(jsonBytes, &values)
@@ -147,29 +212,82 @@ func (pl *PantherLog) AppendAnyIPAddressInFieldPtr(value *string) bool {
 	return pl.AppendAnyIPAddressInField(*value)
 }
 
-// AppendAnyIPAddressInField extracts all IPs from the value using a regexp
+// AppendAnyIPAddressInField extracts all IPs from the value using regular expressions, returns true if any IP was found.
+// IPv6 addresses may be bracketed (e.g. [2001:db8::1]:443) or have a zone (e.g. fe80::1%eth0). Extracted addresses are
+// normalized: IPv6 addresses are compressed, zones are dropped and IPv4-mapped IPv6 addresses are collapsed to IPv4.
 func (pl *PantherLog) AppendAnyIPAddressInField(value string) bool {
-	matchedIPs := ipv4Regex.FindAllString(value, -1)
-	if len(matchedIPs) == 0 {
-		return false
+	found := false
+	remaining := []byte(value)
+	for _, match := range ipv6Regex.FindAllStringIndex(value, -1) {
+		start, end := match[0], match[1]
+		if !isIPv6Boundary(value, start, end) {
+			continue
+		}
+		if pl.AppendAnyIPAddress(value[start:end]) {
+			found = true
+			// blank out the address so that an IPv4 suffix is not extracted again
+			for i := start; i < end; i++ {
+				remaining[i] = ' '
+			}
+		}
 	}
-	for _, match := range matchedIPs {
-		if !pl.AppendAnyIPAddress(match) {
-			return false
+	for _, match := range ipv4Regex.FindAll(remaining, -1) {
+		if pl.AppendAnyIPAddress(string(match)) {
+			found = true
 		}
 	}
-	return true
+	return found
+}
+
+// isIPv6Boundary returns true if the IPv6 candidate at value[start:end] is not part of a longer word (e.g. std::string)
+func isIPv6Boundary(value string, start, end int) bool {
+	isWordChar := func(c byte) bool {
+		return c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+	}
+	if start > 0 && (isWordChar(value[start-1]) || value[start-1] == ':' || value[start-1] == '.') {
+		return false
+	}
+	if end < len(value) && isWordChar(value[end]) {
+		return false
+	}
+	// at least one hex digit, so that e.g. `::` in prose is not an address
+	return strings.IndexFunc(value[start:end], func(r rune) bool {
+		return ('0' <= r && r <= '9') || ('a' <= r && r <= 'f') || ('A' <= r && r <= 'F')
+	}) >= 0
+}
+
+// normalizeIP returns the canonical form of an IP address or the empty string if the value is not an IP address
+func normalizeIP(value string) string {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	if zone := strings.IndexByte(value, '%'); zone >= 0 {
+		value = value[:zone]
+	}
+	ip := net.ParseIP(value)
+	if ip == nil && strings.HasSuffix(value, ":") && !strings.HasSuffix(value, "::") {
+		// a trailing separator, e.g. `from 2001:db8::1: connection closed`
+		ip = net.ParseIP(strings.TrimSuffix(value, ":"))
+	}
+	if ip == nil {
+		return ""
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return ipv4.String()
+	}
+	return ip.String()
 }
 
+// AppendAnyIPAddress adds the ip address normalized like the ones of AppendAnyIPAddressInField, so each address is stored
+// in a single form. It returns false if the value is not an ip address.
 func (pl *PantherLog) AppendAnyIPAddress(value string) bool {
-	if net.ParseIP(value) != nil {
-		if pl.PantherAnyIPAddresses == nil { // lazy create
-			pl.PantherAnyIPAddresses = NewPantherAnyString()
-		}
-		AppendAnyString(pl.PantherAnyIPAddresses, value)
-		return true
+	ip := normalizeIP(value)
+	if ip == "" {
+		return false
+	}
+	if pl.PantherAnyIPAddresses == nil { // lazy create
+		pl.PantherAnyIPAddresses = NewPantherAnyString()
 	}
-	return false
+	AppendAnyString(pl.PantherAnyIPAddresses, ip)
+	return true
 }
 
 func (pl *PantherLog) AppendAnyDomainNamePtrs(values ...*string) {
@@ -232,6 +350,125 @@ func (pl *PantherLog) AppendAnySHA256HashesPtr(values ...*string) {
 	}
 }
 
+func (pl *PantherLog) AppendAnyUsernamePtrs(values ...*string) {
+	for _, value := range values {
+		if value != nil {
+			pl.AppendAnyUsernames(*value)
+		}
+	}
+}
+
+// AppendAnyUsernames adds the usernames as they are, "-" (used by many logs for no user) is ignored
+func (pl *PantherLog) AppendAnyUsernames(values ...string) {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || value == "-" {
+			continue
+		}
+		if pl.PantherAnyUsernames == nil { // lazy create
+			pl.PantherAnyUsernames = NewPantherAnyString()
+		}
+		AppendAnyString(pl.PantherAnyUsernames, value)
+	}
+}
+
+// AppendAnyUsernamesInFieldPtr makes sure the value passed is not nil before
+// passing into AppendAnyUsernamesInField
+func (pl *PantherLog) AppendAnyUsernamesInFieldPtr(value *string) bool {
+	if value == nil {
+		return false
+	}
+	return pl.AppendAnyUsernamesInField(*value)
+}
+
+// AppendAnyUsernamesInField extracts the users of the authentication messages of sshd, PAM and sudo from free form text
+// (e.g. syslog messages), returns true if any user was found.
+func (pl *PantherLog) AppendAnyUsernamesInField(value string) bool {
+	found := false
+	for _, match := range usernameInFieldRegex.FindAllStringSubmatch(value, -1) {
+		for _, username := range match[1:] {
+			if username != "" && username != "-" {
+				pl.AppendAnyUsernames(username)
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+func (pl *PantherLog) AppendAnyEmailPtrs(values ...*string) {
+	for _, value := range values {
+		if value != nil {
+			pl.AppendAnyEmails(*value)
+		}
+	}
+}
+
+// AppendAnyEmails adds the email addresses in lower case, values that are not email addresses are ignored.
+// Addresses in angle brackets (e.g., `Jane Doe <jane@example.com>`) and mailto: URLs are accepted.
+func (pl *PantherLog) AppendAnyEmails(values ...string) {
+	for _, value := range values {
+		email := normalizeEmail(value)
+		if email == "" {
+			continue
+		}
+		if pl.PantherAnyEmails == nil { // lazy create
+			pl.PantherAnyEmails = NewPantherAnyString()
+		}
+		AppendAnyString(pl.PantherAnyEmails, email)
+	}
+}
+
+// normalizeEmail returns the email address in lower case or the empty string if the value is not an email address
+func normalizeEmail(value string) string {
+	value = strings.TrimSpace(value)
+	if start := strings.LastIndexByte(value, '<'); start >= 0 && strings.HasSuffix(value, ">") {
+		value = value[start+1 : len(value)-1]
+	}
+	value = strings.ToLower(strings.TrimPrefix(value, "mailto:"))
+	if !emailRegex.MatchString(value) {
+		return ""
+	}
+	return value
+}
+
+func (pl *PantherLog) AppendAnyMACAddressPtrs(values ...*string) {
+	for _, value := range values {
+		if value != nil {
+			pl.AppendAnyMACAddresses(*value)
+		}
+	}
+}
+
+// AppendAnyMACAddresses adds the MAC addresses in their canonical form (lower case, colon separated),
+// values that are not MAC addresses are ignored.
+func (pl *PantherLog) AppendAnyMACAddresses(values ...string) {
+	for _, value := range values {
+		mac, err := net.ParseMAC(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		if pl.PantherAnyMACAddresses == nil { // lazy create
+			pl.PantherAnyMACAddresses = NewPantherAnyString()
+		}
+		AppendAnyString(pl.PantherAnyMACAddresses, mac.String())
+	}
+}
+
+func (pl *PantherLog) AppendAnyIPCountries(values ...string) {
+	if pl.PantherAnyIPCountries == nil { // lazy create
+		pl.PantherAnyIPCountries = NewPantherAnyString()
+	}
+	AppendAnyString(pl.PantherAnyIPCountries, values...)
+}
+
+func (pl *PantherLog) AppendAnyIPASNs(values ...string) {
+	if pl.PantherAnyIPASNs == nil { // lazy create
+		pl.PantherAnyIPASNs = NewPantherAnyString()
+	}
+	AppendAnyString(pl.PantherAnyIPASNs, values...)
+}
+
 func AppendAnyString(any *PantherAnyString, values ...string) {
 	// add new if not present
 	for _, v := range values {
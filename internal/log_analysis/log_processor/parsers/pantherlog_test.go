@@ -172,6 +172,42 @@ func TestAppendAnyIPsInFieldMultiple(t *testing.T) {
 	require.Equal(t, expectedAny, event.PantherAnyIPAddresses)
 }
 
+func TestAppendAnyIPsInFieldIPv6(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{"none", "connection established", nil},
+		{"ipv6", "connection established from 2001:db8:85a3::8a2e:370:7334", []string{"2001:db8:85a3::8a2e:370:7334"}},
+		{"ipv6 normalized", "client=2001:DB8:0:0:0:0:0:1 ok", []string{"2001:db8::1"}},
+		{"ipv6 loopback", "listening on ::1", []string{"::1"}},
+		{"ipv6 bracketed with port", "GET / from [2001:db8::1]:443", []string{"2001:db8::1"}},
+		{"ipv6 zone", "neighbor fe80::1ff:fe23:4567:890a%eth0 reachable", []string{"fe80::1ff:fe23:4567:890a"}},
+		{"ipv6 trailing separator", "from 2001:db8::7: connection closed", []string{"2001:db8::7"}},
+		{"ipv4 mapped", "Accepted password from ::ffff:192.0.2.128 port 22", []string{"192.0.2.128"}},
+		{"ipv4 mapped hex", "peer ::ffff:c000:280 reset", []string{"192.0.2.128"}},
+		{"x-forwarded-for", "2001:db8::2, 198.51.100.7, ::ffff:203.0.113.9", []string{"2001:db8::2", "198.51.100.7", "203.0.113.9"}},
+		{"mac address", "dhcp ack 00:1a:2b:3c:4d:5e", nil},
+		{"time", "Dec 13 12:30:45 host sshd", nil},
+		{"scope operator", "threw std::runtime_error in Foo::bar", nil},
+		{"ipv4 in invalid ipv6", "a:b:c:10.0.0.1 up", []string{"10.0.0.1"}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			event := PantherLog{}
+			require.Equal(t, len(tc.expected) > 0, event.AppendAnyIPAddressInField(tc.value))
+			if tc.expected == nil {
+				require.Nil(t, event.PantherAnyIPAddresses)
+				return
+			}
+			expectedAny := NewPantherAnyString()
+			AppendAnyString(expectedAny, tc.expected...)
+			require.Equal(t, expectedAny, event.PantherAnyIPAddresses)
+		})
+	}
+}
+
 func TestAppendAnyIPV4(t *testing.T) {
 	event := PantherLog{}
 	require.True(t, event.AppendAnyIPAddressPtr(aws.String("192.168.1.1")))
@@ -195,13 +231,40 @@ func TestAppendAnyIPV6(t *testing.T) {
 
 	expectedAny := &PantherAnyString{
 		set: map[string]struct{}{
-			"2001:db8:85a3:0:0:8a2e:370:7334": {},
-			"::ffff:192.0.2.128":              {},
+			"2001:db8:85a3::8a2e:370:7334": {},
+			"192.0.2.128":                  {},
 		},
 	}
 	require.Equal(t, expectedAny, event.PantherAnyIPAddresses)
 }
 
+func TestAppendAnyIPAddressNormalized(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected string
+	}{
+		{"192.0.2.1", "192.0.2.1"},
+		{"::ffff:192.0.2.1", "192.0.2.1"},
+		{"2001:DB8:0::1", "2001:db8::1"},
+		{"2001:0db8:0000:0000:0000:0000:0000:0001", "2001:db8::1"},
+		{"[2001:db8::1]", "2001:db8::1"},
+		{"fe80::1%eth0", "fe80::1"},
+		{"[fe80::1%eth0]", "fe80::1"},
+		{"192.0.2.256", ""},
+		{"", ""},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			event := PantherLog{}
+			require.Equal(t, tc.expected != "", event.AppendAnyIPAddress(tc.value))
+			if tc.expected == "" {
+				require.Nil(t, event.PantherAnyIPAddresses)
+				return
+			}
+			require.Equal(t, []string{tc.expected}, event.PantherAnyIPAddresses.Values())
+		})
+	}
+}
+
 func TestAppendAnyDomainNames(t *testing.T) {
 	event := PantherLog{}
 	value := "a"
@@ -249,3 +312,81 @@ func TestAppendAnyMD5Hashes(t *testing.T) {
 	event.AppendAnyMD5HashPtrs(&value)
 	require.Equal(t, expectedAny, event.PantherAnyMD5Hashes)
 }
+
+func TestAppendAnyUsernames(t *testing.T) {
+	event := PantherLog{}
+	value := "jdoe"
+	expectedAny := &PantherAnyString{
+		set: map[string]struct{}{
+			value: {},
+		},
+	}
+	event.AppendAnyUsernames(value, "-", " ")
+	require.Equal(t, expectedAny, event.PantherAnyUsernames)
+
+	event = PantherLog{}
+	event.AppendAnyUsernamePtrs(&value, nil)
+	require.Equal(t, expectedAny, event.PantherAnyUsernames)
+
+	event = PantherLog{}
+	event.AppendAnyUsernames("-")
+	require.Nil(t, event.PantherAnyUsernames)
+}
+
+func TestAppendAnyUsernamesInField(t *testing.T) {
+	for _, tc := range []struct {
+		message   string
+		usernames []string
+	}{
+		{"Accepted publickey for alice from 192.0.2.1 port 51234 ssh2: RSA SHA256:abc", []string{"alice"}},
+		{"Failed password for invalid user admin from 192.0.2.1 port 22 ssh2", []string{"admin"}},
+		{"Invalid user oracle from 192.0.2.1 port 40000", []string{"oracle"}},
+		{"pam_unix(sshd:session): session opened for user root by (uid=0)", []string{"root"}},
+		{"pam_unix(sshd:auth): authentication failure; logname= uid=0 euid=0 tty=ssh ruser= rhost=192.0.2.1  user=bob", []string{"bob"}},
+		{"  alice : TTY=pts/0 ; PWD=/home/alice ; USER=root ; COMMAND=/bin/sh", []string{"alice", "root"}},
+		{"Connection closed for reason unknown", nil},
+		{"Received disconnect from 192.0.2.1 port 22:11: disconnected by user", nil},
+	} {
+		event := PantherLog{}
+		require.Equal(t, len(tc.usernames) > 0, event.AppendAnyUsernamesInField(tc.message), tc.message)
+		if len(tc.usernames) == 0 {
+			require.Nil(t, event.PantherAnyUsernames, tc.message)
+			continue
+		}
+		expected := PantherLog{}
+		expected.AppendAnyUsernames(tc.usernames...)
+		require.Equal(t, expected.PantherAnyUsernames, event.PantherAnyUsernames, tc.message)
+	}
+
+	event := PantherLog{}
+	require.False(t, event.AppendAnyUsernamesInFieldPtr(nil))
+}
+
+func TestAppendAnyEmails(t *testing.T) {
+	event := PantherLog{}
+	event.AppendAnyEmails(
+		"Jane.Doe@Example.com",
+		"Jane Doe <jane.doe@example.com>",
+		"mailto:john+spam@mail.example.co.uk",
+		"not an email",
+		"jdoe",
+		"@example.com",
+		"jdoe@localhost",
+	)
+	require.Equal(t, []string{"jane.doe@example.com", "john+spam@mail.example.co.uk"}, event.PantherAnyEmails.Values())
+
+	event = PantherLog{}
+	event.AppendAnyEmailPtrs(nil)
+	require.Nil(t, event.PantherAnyEmails)
+}
+
+func TestAppendAnyMACAddresses(t *testing.T) {
+	event := PantherLog{}
+	event.AppendAnyMACAddresses("00:1A:2B:3C:4D:5E", "00-1a-2b-3c-4d-5e", "001a.2b3c.4d5f", "00:1a:2b", "-")
+	require.Equal(t, []string{"00:1a:2b:3c:4d:5e", "00:1a:2b:3c:4d:5f"}, event.PantherAnyMACAddresses.Values())
+
+	event = PantherLog{}
+	value := "00:1a:2b:3c:4d:5e"
+	event.AppendAnyMACAddressPtrs(&value, nil)
+	require.Equal(t, []string{value}, event.PantherAnyMACAddresses.Values())
+}
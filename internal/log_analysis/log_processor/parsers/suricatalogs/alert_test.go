@@ -0,0 +1,99 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestAlert(t *testing.T) {
+	//nolint:lll
+	log := `{"timestamp": "2015-10-22T06:58:41.823566+0000", "flow_id": 1133520599513328, "pcap_cnt": 95411, "event_type": "alert", "src_ip": "192.168.88.25", "src_port": 34062, "dest_ip": "104.16.41.2", "dest_port": 80, "proto": "006", "community_id": "1:Ug5gpw5jfyUnvAvv+ZBhhTGzlTg=", "tx_id": 0, "alert": {"action": "allowed", "gid": 1, "signature_id": 2013028, "rev": 4, "signature": "ET POLICY curl User-Agent Outbound", "category": "Attempted Information Leak", "severity": 2}, "http": {"hostname": "pypi.python.org", "url": "/simple/", "http_user_agent": "curl/7.35.0", "http_method": "GET", "protocol": "HTTP/1.1", "status": 200, "length": 0}, "app_proto": "http", "flow": {"pkts_toserver": 4, "pkts_toclient": 2, "bytes_toserver": 372, "bytes_toclient": 148, "start": "2015-10-22T06:58:41.711376+0000"}, "pcap_filename": "/pcaps/4SICS-GeekLounge-151022.pcap"}`
+
+	expectedTime := time.Date(2015, 10, 22, 6, 58, 41, 823566000, time.UTC)
+	expectedFlowStart := time.Date(2015, 10, 22, 6, 58, 41, 711376000, time.UTC)
+	expectedEvent := &Alert{
+		Timestamp:   (*timestamp.SuricataTimestamp)(&expectedTime),
+		FlowID:      aws.Int(1133520599513328),
+		PcapCnt:     aws.Int(95411),
+		EventType:   aws.String("alert"),
+		SrcIP:       aws.String("192.168.88.25"),
+		SrcPort:     aws.Uint16(34062),
+		DestIP:      aws.String("104.16.41.2"),
+		DestPort:    aws.Uint16(80),
+		Proto:       (*numerics.Integer)(aws.Int(6)),
+		CommunityID: aws.String("1:Ug5gpw5jfyUnvAvv+ZBhhTGzlTg="),
+		TxID:        aws.Int(0),
+		Alert: &AlertDetails{
+			Action:      aws.String("allowed"),
+			GID:         aws.Int(1),
+			SignatureID: aws.Int(2013028),
+			Rev:         aws.Int(4),
+			Signature:   aws.String("ET POLICY curl User-Agent Outbound"),
+			Category:    aws.String("Attempted Information Leak"),
+			Severity:    aws.Int(2),
+		},
+		HTTP: &HTTPDetails{
+			Hostname:      aws.String("pypi.python.org"),
+			URL:           aws.String("/simple/"),
+			HTTPUserAgent: aws.String("curl/7.35.0"),
+			HTTPMethod:    aws.String("GET"),
+			Protocol:      aws.String("HTTP/1.1"),
+			Status:        aws.Int(200),
+			Length:        aws.Int(0),
+		},
+		AppProto: aws.String("http"),
+		Flow: &FlowDetails{
+			PktsToserver:  aws.Int(4),
+			PktsToclient:  aws.Int(2),
+			BytesToserver: aws.Int(372),
+			BytesToclient: aws.Int(148),
+			Start:         (*timestamp.SuricataTimestamp)(&expectedFlowStart),
+		},
+		PcapFilename: aws.String("/pcaps/4SICS-GeekLounge-151022.pcap"),
+	}
+	expectedEvent.SetCoreFields("Suricata.Alert", (*timestamp.RFC3339)(&expectedTime), expectedEvent)
+	expectedEvent.AppendAnyIPAddress("192.168.88.25")
+	expectedEvent.AppendAnyIPAddress("104.16.41.2")
+	expectedEvent.AppendAnyDomainNames("pypi.python.org")
+	parser := (&AlertParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
+
+func TestAlertMissingSignatureID(t *testing.T) {
+	//nolint:lll
+	log := `{"timestamp": "2015-10-22T06:58:41.823566+0000", "event_type": "alert", "src_ip": "192.168.88.25", "dest_ip": "104.16.41.2", "proto": "006", "alert": {"action": "allowed", "signature": "ET POLICY curl User-Agent Outbound", "severity": 2}}`
+	parser := (&AlertParser{}).New()
+	_, err := parser.Parse(log)
+	require.Error(t, err)
+}
+
+func TestAlertType(t *testing.T) {
+	parser := &AlertParser{}
+	require.Equal(t, "Suricata.Alert", parser.LogType())
+}
@@ -0,0 +1,84 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestFileInfo(t *testing.T) {
+	//nolint:lll
+	log := `{"timestamp": "2015-10-22T06:58:42.011298+0000", "flow_id": 1133520599513328, "pcap_cnt": 95420, "event_type": "fileinfo", "src_ip": "104.16.41.2", "src_port": 80, "dest_ip": "192.168.88.25", "dest_port": 34062, "proto": "006", "http": {"hostname": "pypi.python.org", "url": "/simple/", "http_method": "GET", "status": 200}, "app_proto": "http", "fileinfo": {"filename": "/simple/", "magic": "HTML document, ASCII text", "gaps": false, "state": "CLOSED", "md5": "8d6a2b8f1a6b7e6c3f0d12a1b14c5e9d", "sha1": "2c5d9f8b0b6e0c7d3a9a17f4c0e5e5a3d8a9b1c2", "sha256": "5f1b2d0e9c7a4b3e6d8f0a1c2b3d4e5f60718293a4b5c6d7e8f9012345678901", "stored": false, "size": 1228, "tx_id": 0}, "pcap_filename": "/pcaps/4SICS-GeekLounge-151022.pcap"}`
+
+	expectedTime := time.Date(2015, 10, 22, 6, 58, 42, 11298000, time.UTC)
+	expectedEvent := &FileInfo{
+		Timestamp: (*timestamp.SuricataTimestamp)(&expectedTime),
+		FlowID:    aws.Int(1133520599513328),
+		PcapCnt:   aws.Int(95420),
+		EventType: aws.String("fileinfo"),
+		SrcIP:     aws.String("104.16.41.2"),
+		SrcPort:   aws.Uint16(80),
+		DestIP:    aws.String("192.168.88.25"),
+		DestPort:  aws.Uint16(34062),
+		Proto:     (*numerics.Integer)(aws.Int(6)),
+		HTTP: &HTTPDetails{
+			Hostname:   aws.String("pypi.python.org"),
+			URL:        aws.String("/simple/"),
+			HTTPMethod: aws.String("GET"),
+			Status:     aws.Int(200),
+		},
+		AppProto: aws.String("http"),
+		FileInfo: &FileInfoDetails{
+			Filename: aws.String("/simple/"),
+			Magic:    aws.String("HTML document, ASCII text"),
+			Gaps:     aws.Bool(false),
+			State:    aws.String("CLOSED"),
+			Md5:      aws.String("8d6a2b8f1a6b7e6c3f0d12a1b14c5e9d"),
+			Sha1:     aws.String("2c5d9f8b0b6e0c7d3a9a17f4c0e5e5a3d8a9b1c2"),
+			Sha256:   aws.String("5f1b2d0e9c7a4b3e6d8f0a1c2b3d4e5f60718293a4b5c6d7e8f9012345678901"),
+			Stored:   aws.Bool(false),
+			Size:     aws.Int(1228),
+			TxID:     aws.Int(0),
+		},
+		PcapFilename: aws.String("/pcaps/4SICS-GeekLounge-151022.pcap"),
+	}
+	expectedEvent.SetCoreFields("Suricata.FileInfo", (*timestamp.RFC3339)(&expectedTime), expectedEvent)
+	expectedEvent.AppendAnyIPAddress("104.16.41.2")
+	expectedEvent.AppendAnyIPAddress("192.168.88.25")
+	expectedEvent.AppendAnyDomainNames("pypi.python.org")
+	expectedEvent.AppendAnyMD5Hashes("8d6a2b8f1a6b7e6c3f0d12a1b14c5e9d")
+	expectedEvent.AppendAnySHA1Hashes("2c5d9f8b0b6e0c7d3a9a17f4c0e5e5a3d8a9b1c2")
+	expectedEvent.AppendAnySHA256Hashes("5f1b2d0e9c7a4b3e6d8f0a1c2b3d4e5f60718293a4b5c6d7e8f9012345678901")
+	parser := (&FileInfoParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
+
+func TestFileInfoType(t *testing.T) {
+	parser := &FileInfoParser{}
+	require.Equal(t, "Suricata.FileInfo", parser.LogType())
+}
@@ -0,0 +1,121 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var FlowDesc = `Suricata parser for the Flow event type in the EVE JSON output.
+Reference: https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-flow`
+
+//nolint:lll
+type Flow struct {
+	AppProto     *string                      `json:"app_proto,omitempty" description:"Suricata Flow AppProto"`
+	CommunityID  *string                      `json:"community_id,omitempty" description:"Suricata Flow CommunityID"`
+	DestIP       *string                      `json:"dest_ip" validate:"required" description:"Suricata Flow DestIP"`
+	DestPort     *uint16                      `json:"dest_port,omitempty" description:"Suricata Flow DestPort"`
+	EventType    *string                      `json:"event_type" validate:"required,eq=flow" description:"Suricata Flow EventType"`
+	Flow         *FlowDetails                 `json:"flow" validate:"required,dive" description:"Suricata Flow Flow"`
+	FlowID       *int                         `json:"flow_id,omitempty" description:"Suricata Flow FlowID"`
+	IcmpCode     *int                         `json:"icmp_code,omitempty" description:"Suricata Flow IcmpCode"`
+	IcmpType     *int                         `json:"icmp_type,omitempty" description:"Suricata Flow IcmpType"`
+	InIface      *string                      `json:"in_iface,omitempty" description:"Suricata Flow InIface"`
+	PcapFilename *string                      `json:"pcap_filename,omitempty" description:"Suricata Flow PcapFilename"`
+	Proto        *numerics.Integer            `json:"proto" validate:"required" description:"Suricata Flow Proto"`
+	SrcIP        *string                      `json:"src_ip" validate:"required" description:"Suricata Flow SrcIP"`
+	SrcPort      *uint16                      `json:"src_port,omitempty" description:"Suricata Flow SrcPort"`
+	TCP          *TCPDetails                  `json:"tcp,omitempty" validate:"omitempty,dive" description:"Suricata Flow TCP"`
+	Timestamp    *timestamp.SuricataTimestamp `json:"timestamp" validate:"required" description:"Suricata Flow Timestamp"`
+	Vlan         []int                        `json:"vlan,omitempty" description:"Suricata Flow Vlan"`
+
+	parsers.PantherLog
+}
+
+//nolint:lll
+type FlowDetails struct {
+	Age           *int                         `json:"age,omitempty" description:"Suricata FlowDetails Age"`
+	Alerted       *bool                        `json:"alerted,omitempty" description:"Suricata FlowDetails Alerted"`
+	BytesToclient *int                         `json:"bytes_toclient,omitempty" description:"Suricata FlowDetails BytesToclient"`
+	BytesToserver *int                         `json:"bytes_toserver,omitempty" description:"Suricata FlowDetails BytesToserver"`
+	End           *timestamp.SuricataTimestamp `json:"end,omitempty" description:"Suricata FlowDetails End"`
+	PktsToclient  *int                         `json:"pkts_toclient,omitempty" description:"Suricata FlowDetails PktsToclient"`
+	PktsToserver  *int                         `json:"pkts_toserver,omitempty" description:"Suricata FlowDetails PktsToserver"`
+	Reason        *string                      `json:"reason,omitempty" description:"Suricata FlowDetails Reason"`
+	Start         *timestamp.SuricataTimestamp `json:"start,omitempty" description:"Suricata FlowDetails Start"`
+	State         *string                      `json:"state,omitempty" description:"Suricata FlowDetails State"`
+}
+
+//nolint:lll
+type TCPDetails struct {
+	Ack        *bool   `json:"ack,omitempty" description:"Suricata TCPDetails Ack"`
+	Cwr        *bool   `json:"cwr,omitempty" description:"Suricata TCPDetails Cwr"`
+	Ecn        *bool   `json:"ecn,omitempty" description:"Suricata TCPDetails Ecn"`
+	Fin        *bool   `json:"fin,omitempty" description:"Suricata TCPDetails Fin"`
+	Psh        *bool   `json:"psh,omitempty" description:"Suricata TCPDetails Psh"`
+	Rst        *bool   `json:"rst,omitempty" description:"Suricata TCPDetails Rst"`
+	State      *string `json:"state,omitempty" description:"Suricata TCPDetails State"`
+	Syn        *bool   `json:"syn,omitempty" description:"Suricata TCPDetails Syn"`
+	TCPFlags   *string `json:"tcp_flags,omitempty" description:"Suricata TCPDetails TCPFlags"`
+	TCPFlagsTc *string `json:"tcp_flags_tc,omitempty" description:"Suricata TCPDetails TCPFlagsTc"`
+	TCPFlagsTs *string `json:"tcp_flags_ts,omitempty" description:"Suricata TCPDetails TCPFlagsTs"`
+	Urg        *bool   `json:"urg,omitempty" description:"Suricata TCPDetails Urg"`
+}
+
+// FlowParser parses Suricata Flow events in the JSON format
+type FlowParser struct{}
+
+var _ parsers.LogParser = (*FlowParser)(nil)
+
+func (p *FlowParser) New() parsers.LogParser {
+	return &FlowParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *FlowParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	event := &Flow{}
+
+	err := jsoniter.UnmarshalFromString(log, event)
+	if err != nil {
+		return nil, err
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *FlowParser) LogType() string {
+	return "Suricata.Flow"
+}
+
+func (event *Flow) updatePantherFields(p *FlowParser) {
+	event.SetCoreFields(p.LogType(), (*timestamp.RFC3339)(event.Timestamp), event)
+	event.AppendAnyIPAddressPtr(event.SrcIP)
+	event.AppendAnyIPAddressPtr(event.DestIP)
+}
@@ -0,0 +1,129 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var HTTPDesc = `Suricata parser for the HTTP event type in the EVE JSON output.
+Reference: https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-http`
+
+//nolint:lll
+type HTTP struct {
+	AppProto     *string                      `json:"app_proto,omitempty" description:"Suricata HTTP AppProto"`
+	CommunityID  *string                      `json:"community_id,omitempty" description:"Suricata HTTP CommunityID"`
+	DestIP       *string                      `json:"dest_ip" validate:"required" description:"Suricata HTTP DestIP"`
+	DestPort     *uint16                      `json:"dest_port,omitempty" description:"Suricata HTTP DestPort"`
+	EventType    *string                      `json:"event_type" validate:"required,eq=http" description:"Suricata HTTP EventType"`
+	FlowID       *int                         `json:"flow_id,omitempty" description:"Suricata HTTP FlowID"`
+	HTTP         *HTTPDetails                 `json:"http" validate:"required,dive" description:"Suricata HTTP HTTP"`
+	InIface      *string                      `json:"in_iface,omitempty" description:"Suricata HTTP InIface"`
+	PcapCnt      *int                         `json:"pcap_cnt,omitempty" description:"Suricata HTTP PcapCnt"`
+	PcapFilename *string                      `json:"pcap_filename,omitempty" description:"Suricata HTTP PcapFilename"`
+	Proto        *numerics.Integer            `json:"proto" validate:"required" description:"Suricata HTTP Proto"`
+	SrcIP        *string                      `json:"src_ip" validate:"required" description:"Suricata HTTP SrcIP"`
+	SrcPort      *uint16                      `json:"src_port,omitempty" description:"Suricata HTTP SrcPort"`
+	Timestamp    *timestamp.SuricataTimestamp `json:"timestamp" validate:"required" description:"Suricata HTTP Timestamp"`
+	TxID         *int                         `json:"tx_id,omitempty" description:"Suricata HTTP TxID"`
+	Vlan         []int                        `json:"vlan,omitempty" description:"Suricata HTTP Vlan"`
+
+	parsers.PantherLog
+}
+
+//nolint:lll
+type HTTPDetails struct {
+	Hostname         *string             `json:"hostname,omitempty" description:"Suricata HTTPDetails Hostname"`
+	HTTPContentType  *string             `json:"http_content_type,omitempty" description:"Suricata HTTPDetails HTTPContentType"`
+	HTTPMethod       *string             `json:"http_method,omitempty" description:"Suricata HTTPDetails HTTPMethod"`
+	HTTPPort         *int                `json:"http_port,omitempty" description:"Suricata HTTPDetails HTTPPort"`
+	HTTPRefer        *string             `json:"http_refer,omitempty" description:"Suricata HTTPDetails HTTPRefer"`
+	HTTPRequestBody  *string             `json:"http_request_body,omitempty" description:"Suricata HTTPDetails HTTPRequestBody"`
+	HTTPResponseBody *string             `json:"http_response_body,omitempty" description:"Suricata HTTPDetails HTTPResponseBody"`
+	HTTPUserAgent    *string             `json:"http_user_agent,omitempty" description:"Suricata HTTPDetails HTTPUserAgent"`
+	Length           *int                `json:"length,omitempty" description:"Suricata HTTPDetails Length"`
+	Protocol         *string             `json:"protocol,omitempty" description:"Suricata HTTPDetails Protocol"`
+	Redirect         *string             `json:"redirect,omitempty" description:"Suricata HTTPDetails Redirect"`
+	RequestHeaders   []HTTPDetailsHeader `json:"request_headers,omitempty" description:"Suricata HTTPDetails RequestHeaders"`
+	ResponseHeaders  []HTTPDetailsHeader `json:"response_headers,omitempty" description:"Suricata HTTPDetails ResponseHeaders"`
+	Status           *int                `json:"status,omitempty" description:"Suricata HTTPDetails Status"`
+	URL              *string             `json:"url,omitempty" description:"Suricata HTTPDetails URL"`
+	XFF              *string             `json:"xff,omitempty" description:"Suricata HTTPDetails XFF"`
+}
+
+//nolint:lll
+type HTTPDetailsHeader struct {
+	Name  *string `json:"name,omitempty" description:"Suricata HTTPDetailsHeader Name"`
+	Value *string `json:"value,omitempty" description:"Suricata HTTPDetailsHeader Value"`
+}
+
+// HTTPParser parses Suricata HTTP events in the JSON format
+type HTTPParser struct{}
+
+var _ parsers.LogParser = (*HTTPParser)(nil)
+
+func (p *HTTPParser) New() parsers.LogParser {
+	return &HTTPParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *HTTPParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	event := &HTTP{}
+
+	err := jsoniter.UnmarshalFromString(log, event)
+	if err != nil {
+		return nil, err
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *HTTPParser) LogType() string {
+	return "Suricata.HTTP"
+}
+
+func (event *HTTP) updatePantherFields(p *HTTPParser) {
+	event.SetCoreFields(p.LogType(), (*timestamp.RFC3339)(event.Timestamp), event)
+	event.AppendAnyIPAddressPtr(event.SrcIP)
+	event.AppendAnyIPAddressPtr(event.DestIP)
+	event.HTTP.appendPantherFields(&event.PantherLog)
+}
+
+// appendPantherFields adds the HTTP hostname and the client address in X-Forwarded-For to the panther fields
+func (details *HTTPDetails) appendPantherFields(event *parsers.PantherLog) {
+	if details == nil {
+		return
+	}
+	// The hostname is taken from the Host header and might be an IP address
+	if !event.AppendAnyIPAddressPtr(details.Hostname) {
+		event.AppendAnyDomainNamePtrs(details.Hostname)
+	}
+	event.AppendAnyIPAddressInFieldPtr(details.XFF)
+}
@@ -0,0 +1,79 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestHTTP(t *testing.T) {
+	//nolint:lll
+	log := `{"timestamp": "2015-10-22T06:58:41.823566+0000", "flow_id": 1133520599513328, "pcap_cnt": 95411, "event_type": "http", "src_ip": "192.168.88.25", "src_port": 34062, "dest_ip": "104.16.41.2", "dest_port": 80, "proto": "006", "tx_id": 0, "http": {"hostname": "pypi.python.org", "url": "/simple/", "http_user_agent": "curl/7.35.0", "http_content_type": "text/html", "http_method": "GET", "protocol": "HTTP/1.1", "status": 301, "redirect": "https://pypi.python.org/simple/", "length": 122, "xff": "10.0.0.1", "request_headers": [{"name": "Host", "value": "pypi.python.org"}]}, "pcap_filename": "/pcaps/4SICS-GeekLounge-151022.pcap"}`
+
+	expectedTime := time.Date(2015, 10, 22, 6, 58, 41, 823566000, time.UTC)
+	expectedEvent := &HTTP{
+		Timestamp: (*timestamp.SuricataTimestamp)(&expectedTime),
+		FlowID:    aws.Int(1133520599513328),
+		PcapCnt:   aws.Int(95411),
+		EventType: aws.String("http"),
+		SrcIP:     aws.String("192.168.88.25"),
+		SrcPort:   aws.Uint16(34062),
+		DestIP:    aws.String("104.16.41.2"),
+		DestPort:  aws.Uint16(80),
+		Proto:     (*numerics.Integer)(aws.Int(6)),
+		TxID:      aws.Int(0),
+		HTTP: &HTTPDetails{
+			Hostname:        aws.String("pypi.python.org"),
+			URL:             aws.String("/simple/"),
+			HTTPUserAgent:   aws.String("curl/7.35.0"),
+			HTTPContentType: aws.String("text/html"),
+			HTTPMethod:      aws.String("GET"),
+			Protocol:        aws.String("HTTP/1.1"),
+			Status:          aws.Int(301),
+			Redirect:        aws.String("https://pypi.python.org/simple/"),
+			Length:          aws.Int(122),
+			XFF:             aws.String("10.0.0.1"),
+			RequestHeaders: []HTTPDetailsHeader{
+				{Name: aws.String("Host"), Value: aws.String("pypi.python.org")},
+			},
+		},
+		PcapFilename: aws.String("/pcaps/4SICS-GeekLounge-151022.pcap"),
+	}
+	expectedEvent.SetCoreFields("Suricata.HTTP", (*timestamp.RFC3339)(&expectedTime), expectedEvent)
+	expectedEvent.AppendAnyIPAddress("192.168.88.25")
+	expectedEvent.AppendAnyIPAddress("104.16.41.2")
+	expectedEvent.AppendAnyIPAddress("10.0.0.1")
+	expectedEvent.AppendAnyDomainNames("pypi.python.org")
+	parser := (&HTTPParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
+
+func TestHTTPType(t *testing.T) {
+	parser := &HTTPParser{}
+	require.Equal(t, "Suricata.HTTP", parser.LogType())
+}
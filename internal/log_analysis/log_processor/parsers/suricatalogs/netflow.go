@@ -0,0 +1,102 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var NetflowDesc = `Suricata parser for the Netflow event type in the EVE JSON output.
+Reference: https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-output.html#netflow`
+
+//nolint:lll
+type Netflow struct {
+	AppProto     *string                      `json:"app_proto,omitempty" description:"Suricata Netflow AppProto"`
+	CommunityID  *string                      `json:"community_id,omitempty" description:"Suricata Netflow CommunityID"`
+	DestIP       *string                      `json:"dest_ip" validate:"required" description:"Suricata Netflow DestIP"`
+	DestPort     *uint16                      `json:"dest_port,omitempty" description:"Suricata Netflow DestPort"`
+	EventType    *string                      `json:"event_type" validate:"required,eq=netflow" description:"Suricata Netflow EventType"`
+	FlowID       *int                         `json:"flow_id,omitempty" description:"Suricata Netflow FlowID"`
+	IcmpCode     *int                         `json:"icmp_code,omitempty" description:"Suricata Netflow IcmpCode"`
+	IcmpType     *int                         `json:"icmp_type,omitempty" description:"Suricata Netflow IcmpType"`
+	InIface      *string                      `json:"in_iface,omitempty" description:"Suricata Netflow InIface"`
+	Netflow      *NetflowDetails              `json:"netflow" validate:"required,dive" description:"Suricata Netflow Netflow"`
+	PcapFilename *string                      `json:"pcap_filename,omitempty" description:"Suricata Netflow PcapFilename"`
+	Proto        *numerics.Integer            `json:"proto" validate:"required" description:"Suricata Netflow Proto"`
+	SrcIP        *string                      `json:"src_ip" validate:"required" description:"Suricata Netflow SrcIP"`
+	SrcPort      *uint16                      `json:"src_port,omitempty" description:"Suricata Netflow SrcPort"`
+	TCP          *TCPDetails                  `json:"tcp,omitempty" validate:"omitempty,dive" description:"Suricata Netflow TCP"`
+	Timestamp    *timestamp.SuricataTimestamp `json:"timestamp" validate:"required" description:"Suricata Netflow Timestamp"`
+	Vlan         []int                        `json:"vlan,omitempty" description:"Suricata Netflow Vlan"`
+
+	parsers.PantherLog
+}
+
+//nolint:lll
+type NetflowDetails struct {
+	Age    *int                         `json:"age,omitempty" description:"Suricata NetflowDetails Age"`
+	Bytes  *int                         `json:"bytes,omitempty" description:"Suricata NetflowDetails Bytes"`
+	End    *timestamp.SuricataTimestamp `json:"end,omitempty" description:"Suricata NetflowDetails End"`
+	MaxTTL *int                         `json:"max_ttl,omitempty" description:"Suricata NetflowDetails MaxTTL"`
+	MinTTL *int                         `json:"min_ttl,omitempty" description:"Suricata NetflowDetails MinTTL"`
+	Pkts   *int                         `json:"pkts,omitempty" description:"Suricata NetflowDetails Pkts"`
+	Start  *timestamp.SuricataTimestamp `json:"start,omitempty" description:"Suricata NetflowDetails Start"`
+}
+
+// NetflowParser parses Suricata Netflow events in the JSON format
+type NetflowParser struct{}
+
+var _ parsers.LogParser = (*NetflowParser)(nil)
+
+func (p *NetflowParser) New() parsers.LogParser {
+	return &NetflowParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *NetflowParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	event := &Netflow{}
+
+	err := jsoniter.UnmarshalFromString(log, event)
+	if err != nil {
+		return nil, err
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *NetflowParser) LogType() string {
+	return "Suricata.Netflow"
+}
+
+func (event *Netflow) updatePantherFields(p *NetflowParser) {
+	event.SetCoreFields(p.LogType(), (*timestamp.RFC3339)(event.Timestamp), event)
+	event.AppendAnyIPAddressPtr(event.SrcIP)
+	event.AppendAnyIPAddressPtr(event.DestIP)
+}
@@ -0,0 +1,78 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestNetflow(t *testing.T) {
+	//nolint:lll
+	log := `{"timestamp": "2015-10-22T06:40:06.591493+0000", "flow_id": 1004478225493232, "event_type": "netflow", "src_ip": "192.168.88.61", "src_port": 44378, "dest_ip": "192.168.88.1", "dest_port": 80, "proto": "006", "app_proto": "http", "netflow": {"pkts": 6, "bytes": 525, "start": "2015-10-22T06:39:06.591493+0000", "end": "2015-10-22T06:39:07.104128+0000", "age": 1, "min_ttl": 64, "max_ttl": 64}, "tcp": {"tcp_flags": "1b", "syn": true, "fin": true, "psh": true, "ack": true}}`
+
+	expectedTime := time.Date(2015, 10, 22, 6, 40, 6, 591493000, time.UTC)
+	expectedStart := time.Date(2015, 10, 22, 6, 39, 6, 591493000, time.UTC)
+	expectedEnd := time.Date(2015, 10, 22, 6, 39, 7, 104128000, time.UTC)
+	expectedEvent := &Netflow{
+		Timestamp: (*timestamp.SuricataTimestamp)(&expectedTime),
+		FlowID:    aws.Int(1004478225493232),
+		EventType: aws.String("netflow"),
+		SrcIP:     aws.String("192.168.88.61"),
+		SrcPort:   aws.Uint16(44378),
+		DestIP:    aws.String("192.168.88.1"),
+		DestPort:  aws.Uint16(80),
+		Proto:     (*numerics.Integer)(aws.Int(6)),
+		AppProto:  aws.String("http"),
+		Netflow: &NetflowDetails{
+			Pkts:   aws.Int(6),
+			Bytes:  aws.Int(525),
+			Start:  (*timestamp.SuricataTimestamp)(&expectedStart),
+			End:    (*timestamp.SuricataTimestamp)(&expectedEnd),
+			Age:    aws.Int(1),
+			MinTTL: aws.Int(64),
+			MaxTTL: aws.Int(64),
+		},
+		TCP: &TCPDetails{
+			TCPFlags: aws.String("1b"),
+			Syn:      aws.Bool(true),
+			Fin:      aws.Bool(true),
+			Psh:      aws.Bool(true),
+			Ack:      aws.Bool(true),
+		},
+	}
+	expectedEvent.SetCoreFields("Suricata.Netflow", (*timestamp.RFC3339)(&expectedTime), expectedEvent)
+	expectedEvent.AppendAnyIPAddress("192.168.88.61")
+	expectedEvent.AppendAnyIPAddress("192.168.88.1")
+	parser := (&NetflowParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
+
+func TestNetflowType(t *testing.T) {
+	parser := &NetflowParser{}
+	require.Equal(t, "Suricata.Netflow", parser.LogType())
+}
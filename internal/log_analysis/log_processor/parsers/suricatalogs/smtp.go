@@ -0,0 +1,133 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var SMTPDesc = `Suricata parser for the SMTP event type in the EVE JSON output.
+Reference: https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-smtp`
+
+//nolint:lll
+type SMTP struct {
+	AppProto     *string                      `json:"app_proto,omitempty" description:"Suricata SMTP AppProto"`
+	CommunityID  *string                      `json:"community_id,omitempty" description:"Suricata SMTP CommunityID"`
+	DestIP       *string                      `json:"dest_ip" validate:"required" description:"Suricata SMTP DestIP"`
+	DestPort     *uint16                      `json:"dest_port,omitempty" description:"Suricata SMTP DestPort"`
+	Email        *EmailDetails                `json:"email,omitempty" validate:"omitempty,dive" description:"Suricata SMTP Email"`
+	EventType    *string                      `json:"event_type" validate:"required,eq=smtp" description:"Suricata SMTP EventType"`
+	FlowID       *int                         `json:"flow_id,omitempty" description:"Suricata SMTP FlowID"`
+	InIface      *string                      `json:"in_iface,omitempty" description:"Suricata SMTP InIface"`
+	PcapCnt      *int                         `json:"pcap_cnt,omitempty" description:"Suricata SMTP PcapCnt"`
+	PcapFilename *string                      `json:"pcap_filename,omitempty" description:"Suricata SMTP PcapFilename"`
+	Proto        *numerics.Integer            `json:"proto" validate:"required" description:"Suricata SMTP Proto"`
+	SMTP         *SMTPDetails                 `json:"smtp" validate:"required,dive" description:"Suricata SMTP SMTP"`
+	SrcIP        *string                      `json:"src_ip" validate:"required" description:"Suricata SMTP SrcIP"`
+	SrcPort      *uint16                      `json:"src_port,omitempty" description:"Suricata SMTP SrcPort"`
+	Timestamp    *timestamp.SuricataTimestamp `json:"timestamp" validate:"required" description:"Suricata SMTP Timestamp"`
+	TxID         *int                         `json:"tx_id,omitempty" description:"Suricata SMTP TxID"`
+	Vlan         []int                        `json:"vlan,omitempty" description:"Suricata SMTP Vlan"`
+
+	parsers.PantherLog
+}
+
+//nolint:lll
+type SMTPDetails struct {
+	Helo     *string  `json:"helo,omitempty" description:"Suricata SMTPDetails Helo"`
+	MailFrom *string  `json:"mail_from,omitempty" description:"Suricata SMTPDetails MailFrom"`
+	RcptTo   []string `json:"rcpt_to,omitempty" description:"Suricata SMTPDetails RcptTo"`
+}
+
+//nolint:lll
+type EmailDetails struct {
+	Attachment []string `json:"attachment,omitempty" description:"Suricata EmailDetails Attachment"`
+	Cc         []string `json:"cc,omitempty" description:"Suricata EmailDetails Cc"`
+	From       *string  `json:"from,omitempty" description:"Suricata EmailDetails From"`
+	Status     *string  `json:"status,omitempty" description:"Suricata EmailDetails Status"`
+	Subject    *string  `json:"subject,omitempty" description:"Suricata EmailDetails Subject"`
+	To         []string `json:"to,omitempty" description:"Suricata EmailDetails To"`
+	URL        []string `json:"url,omitempty" description:"Suricata EmailDetails URL"`
+}
+
+// SMTPParser parses Suricata SMTP events in the JSON format
+type SMTPParser struct{}
+
+var _ parsers.LogParser = (*SMTPParser)(nil)
+
+func (p *SMTPParser) New() parsers.LogParser {
+	return &SMTPParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *SMTPParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	event := &SMTP{}
+
+	err := jsoniter.UnmarshalFromString(log, event)
+	if err != nil {
+		return nil, err
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *SMTPParser) LogType() string {
+	return "Suricata.SMTP"
+}
+
+func (event *SMTP) updatePantherFields(p *SMTPParser) {
+	event.SetCoreFields(p.LogType(), (*timestamp.RFC3339)(event.Timestamp), event)
+	event.AppendAnyIPAddressPtr(event.SrcIP)
+	event.AppendAnyIPAddressPtr(event.DestIP)
+	if event.SMTP != nil && !event.AppendAnyIPAddressPtr(event.SMTP.Helo) {
+		event.AppendAnyDomainNamePtrs(event.SMTP.Helo)
+	}
+	event.SMTP.appendPantherFields(&event.PantherLog)
+	event.Email.appendPantherFields(&event.PantherLog)
+}
+
+// appendPantherFields adds the envelope sender and recipients to the panther fields
+func (details *SMTPDetails) appendPantherFields(event *parsers.PantherLog) {
+	if details == nil {
+		return
+	}
+	event.AppendAnyEmailPtrs(details.MailFrom)
+	event.AppendAnyEmails(details.RcptTo...)
+}
+
+// appendPantherFields adds the sender and recipients of the message to the panther fields
+func (details *EmailDetails) appendPantherFields(event *parsers.PantherLog) {
+	if details == nil {
+		return
+	}
+	event.AppendAnyEmailPtrs(details.From)
+	event.AppendAnyEmails(details.To...)
+	event.AppendAnyEmails(details.Cc...)
+}
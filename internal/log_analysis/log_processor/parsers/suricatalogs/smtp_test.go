@@ -0,0 +1,75 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestSMTP(t *testing.T) {
+	//nolint:lll
+	log := `{"timestamp": "2015-10-22T07:18:09.154219+0000", "flow_id": 1418284474434104, "pcap_cnt": 170153, "event_type": "smtp", "src_ip": "192.168.88.61", "src_port": 49164, "dest_ip": "192.168.88.1", "dest_port": 25, "proto": "006", "tx_id": 0, "smtp": {"helo": "mail.example.com", "mail_from": "<alice@example.com>", "rcpt_to": ["<bob@example.org>"]}, "email": {"status": "PARSE_DONE", "from": "alice@example.com", "to": ["bob@example.org"], "attachment": ["report.pdf"]}, "pcap_filename": "/pcaps/4SICS-GeekLounge-151022.pcap"}`
+
+	expectedTime := time.Date(2015, 10, 22, 7, 18, 9, 154219000, time.UTC)
+	expectedEvent := &SMTP{
+		Timestamp: (*timestamp.SuricataTimestamp)(&expectedTime),
+		FlowID:    aws.Int(1418284474434104),
+		PcapCnt:   aws.Int(170153),
+		EventType: aws.String("smtp"),
+		SrcIP:     aws.String("192.168.88.61"),
+		SrcPort:   aws.Uint16(49164),
+		DestIP:    aws.String("192.168.88.1"),
+		DestPort:  aws.Uint16(25),
+		Proto:     (*numerics.Integer)(aws.Int(6)),
+		TxID:      aws.Int(0),
+		SMTP: &SMTPDetails{
+			Helo:     aws.String("mail.example.com"),
+			MailFrom: aws.String("<alice@example.com>"),
+			RcptTo:   []string{"<bob@example.org>"},
+		},
+		Email: &EmailDetails{
+			Status:     aws.String("PARSE_DONE"),
+			From:       aws.String("alice@example.com"),
+			To:         []string{"bob@example.org"},
+			Attachment: []string{"report.pdf"},
+		},
+		PcapFilename: aws.String("/pcaps/4SICS-GeekLounge-151022.pcap"),
+	}
+	expectedEvent.SetCoreFields("Suricata.SMTP", (*timestamp.RFC3339)(&expectedTime), expectedEvent)
+	expectedEvent.AppendAnyIPAddress("192.168.88.61")
+	expectedEvent.AppendAnyIPAddress("192.168.88.1")
+	expectedEvent.AppendAnyDomainNames("mail.example.com")
+	expectedEvent.AppendAnyEmails("alice@example.com", "bob@example.org")
+	parser := (&SMTPParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
+
+func TestSMTPType(t *testing.T) {
+	parser := &SMTPParser{}
+	require.Equal(t, "Suricata.SMTP", parser.LogType())
+}
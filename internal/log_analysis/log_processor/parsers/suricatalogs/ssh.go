@@ -0,0 +1,108 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var SSHDesc = `Suricata parser for the SSH event type in the EVE JSON output.
+Reference: https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-ssh`
+
+//nolint:lll
+type SSH struct {
+	AppProto     *string                      `json:"app_proto,omitempty" description:"Suricata SSH AppProto"`
+	CommunityID  *string                      `json:"community_id,omitempty" description:"Suricata SSH CommunityID"`
+	DestIP       *string                      `json:"dest_ip" validate:"required" description:"Suricata SSH DestIP"`
+	DestPort     *uint16                      `json:"dest_port,omitempty" description:"Suricata SSH DestPort"`
+	EventType    *string                      `json:"event_type" validate:"required,eq=ssh" description:"Suricata SSH EventType"`
+	FlowID       *int                         `json:"flow_id,omitempty" description:"Suricata SSH FlowID"`
+	InIface      *string                      `json:"in_iface,omitempty" description:"Suricata SSH InIface"`
+	PcapCnt      *int                         `json:"pcap_cnt,omitempty" description:"Suricata SSH PcapCnt"`
+	PcapFilename *string                      `json:"pcap_filename,omitempty" description:"Suricata SSH PcapFilename"`
+	Proto        *numerics.Integer            `json:"proto" validate:"required" description:"Suricata SSH Proto"`
+	SrcIP        *string                      `json:"src_ip" validate:"required" description:"Suricata SSH SrcIP"`
+	SrcPort      *uint16                      `json:"src_port,omitempty" description:"Suricata SSH SrcPort"`
+	SSH          *SSHDetails                  `json:"ssh" validate:"required,dive" description:"Suricata SSH SSH"`
+	Timestamp    *timestamp.SuricataTimestamp `json:"timestamp" validate:"required" description:"Suricata SSH Timestamp"`
+	Vlan         []int                        `json:"vlan,omitempty" description:"Suricata SSH Vlan"`
+
+	parsers.PantherLog
+}
+
+//nolint:lll
+type SSHDetails struct {
+	Client *SSHDetailsHost `json:"client,omitempty" validate:"omitempty,dive" description:"Suricata SSHDetails Client"`
+	Server *SSHDetailsHost `json:"server,omitempty" validate:"omitempty,dive" description:"Suricata SSHDetails Server"`
+}
+
+//nolint:lll
+type SSHDetailsHost struct {
+	Hassh           *SSHDetailsHassh `json:"hassh,omitempty" validate:"omitempty,dive" description:"Suricata SSHDetailsHost Hassh"`
+	ProtoVersion    *string          `json:"proto_version,omitempty" description:"Suricata SSHDetailsHost ProtoVersion"`
+	SoftwareVersion *string          `json:"software_version,omitempty" description:"Suricata SSHDetailsHost SoftwareVersion"`
+}
+
+//nolint:lll
+type SSHDetailsHassh struct {
+	Hash   *string `json:"hash,omitempty" description:"Suricata SSHDetailsHassh Hash"`
+	String *string `json:"string,omitempty" description:"Suricata SSHDetailsHassh String"`
+}
+
+// SSHParser parses Suricata SSH events in the JSON format
+type SSHParser struct{}
+
+var _ parsers.LogParser = (*SSHParser)(nil)
+
+func (p *SSHParser) New() parsers.LogParser {
+	return &SSHParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *SSHParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	event := &SSH{}
+
+	err := jsoniter.UnmarshalFromString(log, event)
+	if err != nil {
+		return nil, err
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *SSHParser) LogType() string {
+	return "Suricata.SSH"
+}
+
+func (event *SSH) updatePantherFields(p *SSHParser) {
+	event.SetCoreFields(p.LogType(), (*timestamp.RFC3339)(event.Timestamp), event)
+	event.AppendAnyIPAddressPtr(event.SrcIP)
+	event.AppendAnyIPAddressPtr(event.DestIP)
+}
@@ -0,0 +1,75 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestSSH(t *testing.T) {
+	//nolint:lll
+	log := `{"timestamp": "2015-10-22T07:48:31.427815+0000", "flow_id": 1874637549513048, "pcap_cnt": 382017, "event_type": "ssh", "src_ip": "192.168.88.61", "src_port": 49362, "dest_ip": "192.168.88.1", "dest_port": 22, "proto": "006", "ssh": {"client": {"proto_version": "2.0", "software_version": "OpenSSH_6.7p1", "hassh": {"hash": "ec7378c1a92f5a8dde7e8b7a1ddf33d1", "string": "curve25519-sha256,diffie-hellman-group14-sha1"}}, "server": {"proto_version": "2.0", "software_version": "OpenSSH_6.6.1p1"}}, "pcap_filename": "/pcaps/4SICS-GeekLounge-151022.pcap"}`
+
+	expectedTime := time.Date(2015, 10, 22, 7, 48, 31, 427815000, time.UTC)
+	expectedEvent := &SSH{
+		Timestamp: (*timestamp.SuricataTimestamp)(&expectedTime),
+		FlowID:    aws.Int(1874637549513048),
+		PcapCnt:   aws.Int(382017),
+		EventType: aws.String("ssh"),
+		SrcIP:     aws.String("192.168.88.61"),
+		SrcPort:   aws.Uint16(49362),
+		DestIP:    aws.String("192.168.88.1"),
+		DestPort:  aws.Uint16(22),
+		Proto:     (*numerics.Integer)(aws.Int(6)),
+		SSH: &SSHDetails{
+			Client: &SSHDetailsHost{
+				ProtoVersion:    aws.String("2.0"),
+				SoftwareVersion: aws.String("OpenSSH_6.7p1"),
+				Hassh: &SSHDetailsHassh{
+					Hash:   aws.String("ec7378c1a92f5a8dde7e8b7a1ddf33d1"),
+					String: aws.String("curve25519-sha256,diffie-hellman-group14-sha1"),
+				},
+			},
+			Server: &SSHDetailsHost{
+				ProtoVersion:    aws.String("2.0"),
+				SoftwareVersion: aws.String("OpenSSH_6.6.1p1"),
+			},
+		},
+		PcapFilename: aws.String("/pcaps/4SICS-GeekLounge-151022.pcap"),
+	}
+	expectedEvent.SetCoreFields("Suricata.SSH", (*timestamp.RFC3339)(&expectedTime), expectedEvent)
+	expectedEvent.AppendAnyIPAddress("192.168.88.61")
+	expectedEvent.AppendAnyIPAddress("192.168.88.1")
+	parser := (&SSHParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
+
+func TestSSHType(t *testing.T) {
+	parser := &SSHParser{}
+	require.Equal(t, "Suricata.SSH", parser.LogType())
+}
@@ -0,0 +1,76 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestTLS(t *testing.T) {
+	//nolint:lll
+	log := `{"timestamp": "2015-10-22T07:05:32.185325+0000", "flow_id": 1740391925633612, "pcap_cnt": 122883, "event_type": "tls", "src_ip": "192.168.88.25", "src_port": 41932, "dest_ip": "172.217.21.14", "dest_port": 443, "proto": "006", "tls": {"subject": "C=US, ST=California, L=Mountain View, O=Google Inc, CN=*.google.com", "issuerdn": "C=US, O=Google Inc, CN=Google Internet Authority G2", "serial": "3E:83:8F:6E:AE:4A:DE:24", "fingerprint": "a2:56:9e:1b:17:8f:31:8c:04:a3:4c:0e:e6:39:26:f5:1d:0f:2f:0a", "sni": "www.google.com", "version": "TLS 1.2", "notbefore": "2015-10-07T12:56:00", "notafter": "2016-01-05T00:00:00", "ja3": {"hash": "e7d705a3286e19ea42f587b344ee6865", "string": "771,49195-49199,0-10-11,23-24,0"}}, "pcap_filename": "/pcaps/4SICS-GeekLounge-151022.pcap"}`
+
+	expectedTime := time.Date(2015, 10, 22, 7, 5, 32, 185325000, time.UTC)
+	expectedEvent := &TLS{
+		Timestamp: (*timestamp.SuricataTimestamp)(&expectedTime),
+		FlowID:    aws.Int(1740391925633612),
+		PcapCnt:   aws.Int(122883),
+		EventType: aws.String("tls"),
+		SrcIP:     aws.String("192.168.88.25"),
+		SrcPort:   aws.Uint16(41932),
+		DestIP:    aws.String("172.217.21.14"),
+		DestPort:  aws.Uint16(443),
+		Proto:     (*numerics.Integer)(aws.Int(6)),
+		TLS: &TLSDetails{
+			Subject:     aws.String("C=US, ST=California, L=Mountain View, O=Google Inc, CN=*.google.com"),
+			Issuerdn:    aws.String("C=US, O=Google Inc, CN=Google Internet Authority G2"),
+			Serial:      aws.String("3E:83:8F:6E:AE:4A:DE:24"),
+			Fingerprint: aws.String("a2:56:9e:1b:17:8f:31:8c:04:a3:4c:0e:e6:39:26:f5:1d:0f:2f:0a"),
+			Sni:         aws.String("www.google.com"),
+			Version:     aws.String("TLS 1.2"),
+			Notbefore:   aws.String("2015-10-07T12:56:00"),
+			Notafter:    aws.String("2016-01-05T00:00:00"),
+			JA3: &TLSDetailsJA3{
+				Hash:   aws.String("e7d705a3286e19ea42f587b344ee6865"),
+				String: aws.String("771,49195-49199,0-10-11,23-24,0"),
+			},
+		},
+		PcapFilename: aws.String("/pcaps/4SICS-GeekLounge-151022.pcap"),
+	}
+	expectedEvent.SetCoreFields("Suricata.TLS", (*timestamp.RFC3339)(&expectedTime), expectedEvent)
+	expectedEvent.AppendAnyIPAddress("192.168.88.25")
+	expectedEvent.AppendAnyIPAddress("172.217.21.14")
+	expectedEvent.AppendAnyDomainNames("www.google.com")
+	parser := (&TLSParser{}).New()
+	events, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), events, err)
+}
+
+func TestTLSType(t *testing.T) {
+	parser := &TLSParser{}
+	require.Equal(t, "Suricata.TLS", parser.LogType())
+}
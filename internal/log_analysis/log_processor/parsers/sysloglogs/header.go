@@ -0,0 +1,117 @@
+package sysloglogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/influxdata/go-syslog/v3"
+	"github.com/influxdata/go-syslog/v3/rfc3164"
+	"github.com/influxdata/go-syslog/v3/rfc5424"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+// nolint:lll
+// Header is the syslog header of messages carrying another format (e.g., CEF sent over syslog)
+type Header struct {
+	Priority  *uint8             `json:"priority,omitempty" description:"Priority is calculated by (Facility * 8 + Severity). The lower this value, the higher importance of the log message."`
+	Facility  *uint8             `json:"facility,omitempty" description:"Facility value helps determine which process created the message. Eg: 0 = kernel messages, 3 = system daemons."`
+	Severity  *uint8             `json:"severity,omitempty" description:"Severity indicates how severe the message is. Eg: 0=Emergency to 7=Debug."`
+	Timestamp *timestamp.RFC3339 `json:"timestamp,omitempty" description:"Timestamp of the syslog message in UTC."`
+	Hostname  *string            `json:"hostname,omitempty" description:"Hostname identifies the machine that originally sent the syslog message."`
+	Appname   *string            `json:"appname,omitempty" description:"Appname identifies the device or application that originated the syslog message."`
+	ProcID    *string            `json:"procid,omitempty" description:"ProcID is often the process ID, but can be any value used to enable log analyzers to detect discontinuities in syslog reporting."`
+	MsgID     *string            `json:"msgid,omitempty" description:"MsgID identifies the type of message. For example, a firewall might use the MsgID 'TCPIN' for incoming TCP traffic."`
+}
+
+// RFC5424 headers start with the priority followed by a non-zero version
+var rfc5424HeaderRegex = regexp.MustCompile(`^<\d{1,3}>[1-9]\d{0,2} `)
+
+// HeaderParser parses syslog headers in the RFC3164 or RFC5424 format
+type HeaderParser struct {
+	rfc3164 syslog.Machine
+	rfc5424 syslog.Machine
+}
+
+// NewHeaderParser returns a parser of syslog headers
+func NewHeaderParser() *HeaderParser {
+	return &HeaderParser{
+		rfc3164: rfc3164.NewParser(
+			rfc3164.WithBestEffort(),
+			rfc3164.WithTimezone(time.UTC),
+			rfc3164.WithYear(rfc3164.CurrentYear{}),
+			rfc3164.WithRFC3339(),
+		),
+		rfc5424: rfc5424.NewParser(rfc5424.WithBestEffort()),
+	}
+}
+
+// Parse parses a syslog header without the message that follows it
+func (p *HeaderParser) Parse(header string) (*Header, error) {
+	// the parsers expect a space after the last field of the header
+	header = strings.TrimSpace(header) + " "
+	if rfc5424HeaderRegex.MatchString(header) {
+		// the message is optional in RFC5424, a header with all the fields parses without errors
+		msg, _ := p.rfc5424.Parse([]byte(header))
+		if msg == nil || msg.(*rfc5424.SyslogMessage).Priority == nil {
+			return nil, errors.Errorf("invalid RFC5424 syslog header %q", header)
+		}
+		internalRFC5424 := msg.(*rfc5424.SyslogMessage)
+		return &Header{
+			Priority:  internalRFC5424.Priority,
+			Facility:  internalRFC5424.Facility,
+			Severity:  internalRFC5424.Severity,
+			Timestamp: (*timestamp.RFC3339)(internalRFC5424.Timestamp),
+			Hostname:  internalRFC5424.Hostname,
+			Appname:   internalRFC5424.Appname,
+			ProcID:    internalRFC5424.ProcID,
+			MsgID:     internalRFC5424.MsgID,
+		}, nil
+	}
+
+	// Best effort parsing returns the fields found before an error, the message (and often the tag) is missing here
+	msg, _ := p.rfc3164.Parse([]byte(header))
+	if msg == nil || msg.(*rfc3164.SyslogMessage).Priority == nil || msg.(*rfc3164.SyslogMessage).Timestamp == nil {
+		return nil, errors.Errorf("invalid RFC3164 syslog header %q", header)
+	}
+	internalRFC3164 := msg.(*rfc3164.SyslogMessage)
+	return &Header{
+		Priority:  internalRFC3164.Priority,
+		Facility:  internalRFC3164.Facility,
+		Severity:  internalRFC3164.Severity,
+		Timestamp: (*timestamp.RFC3339)(internalRFC3164.Timestamp),
+		Hostname:  internalRFC3164.Hostname,
+		Appname:   internalRFC3164.Appname,
+		ProcID:    internalRFC3164.ProcID,
+		MsgID:     internalRFC3164.MsgID,
+	}, nil
+}
+
+// UpdatePantherFields adds the host of the header to the panther fields of the event
+func (header *Header) UpdatePantherFields(event *parsers.PantherLog) {
+	// The hostname should be a FQDN, but may also be an IP address.
+	if !event.AppendAnyIPAddressPtr(header.Hostname) {
+		event.AppendAnyDomainNamePtrs(header.Hostname)
+	}
+}
@@ -0,0 +1,84 @@
+package sysloglogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestHeaderRFC3164(t *testing.T) {
+	expectedTime := time.Date(time.Now().UTC().Year(), 10, 11, 22, 14, 15, 0, time.UTC)
+	header, err := NewHeaderParser().Parse(`<34>Oct 11 22:14:15 mymachine su[42]: `)
+	require.NoError(t, err)
+	require.Equal(t, &Header{
+		Priority:  aws.Uint8(34),
+		Facility:  aws.Uint8(4),
+		Severity:  aws.Uint8(2),
+		Timestamp: (*timestamp.RFC3339)(&expectedTime),
+		Hostname:  aws.String("mymachine"),
+		Appname:   aws.String("su"),
+		ProcID:    aws.String("42"),
+	}, header)
+}
+
+func TestHeaderRFC3164WithoutTag(t *testing.T) {
+	expectedTime := time.Date(time.Now().UTC().Year(), 10, 11, 22, 14, 15, 0, time.UTC)
+	header, err := NewHeaderParser().Parse(`<34>Oct 11 22:14:15 10.0.0.1`)
+	require.NoError(t, err)
+	require.Equal(t, &Header{
+		Priority:  aws.Uint8(34),
+		Facility:  aws.Uint8(4),
+		Severity:  aws.Uint8(2),
+		Timestamp: (*timestamp.RFC3339)(&expectedTime),
+		Hostname:  aws.String("10.0.0.1"),
+	}, header)
+}
+
+func TestHeaderRFC5424(t *testing.T) {
+	expectedTime := time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC)
+	header, err := NewHeaderParser().Parse(`<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 -`)
+	require.NoError(t, err)
+	require.Equal(t, &Header{
+		Priority:  aws.Uint8(165),
+		Facility:  aws.Uint8(20),
+		Severity:  aws.Uint8(5),
+		Timestamp: (*timestamp.RFC3339)(&expectedTime),
+		Hostname:  aws.String("mymachine.example.com"),
+		Appname:   aws.String("evntslog"),
+		MsgID:     aws.String("ID47"),
+	}, header)
+}
+
+func TestHeaderInvalid(t *testing.T) {
+	parser := NewHeaderParser()
+	for _, header := range []string{
+		`Oct 11 22:14:15 mymachine`,
+		`<34>`,
+		`not a header`,
+	} {
+		_, err := parser.Parse(header)
+		require.Error(t, err, header)
+	}
+}
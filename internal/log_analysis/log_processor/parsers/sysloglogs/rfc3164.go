@@ -114,4 +114,5 @@ func (event *RFC3164) updatePantherFields(p *RFC3164Parser) {
 	}
 
 	event.AppendAnyIPAddressInFieldPtr(event.Message)
+	event.AppendAnyUsernamesInFieldPtr(event.Message)
 }
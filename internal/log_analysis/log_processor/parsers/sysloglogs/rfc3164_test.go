@@ -44,6 +44,7 @@ func TestRFC3164(t *testing.T) {
 	t.Run("Example1", testRFC3164Example1)
 	t.Run("Example2", testRFC3164Example2)
 	t.Run("Example3", testRFC3164Example3)
+	t.Run("SSHFailedPassword", testRFC3164SSHFailedPassword)
 }
 
 func testRFC3164Simple(t *testing.T) {
@@ -184,6 +185,35 @@ func testRFC3164Example3(t *testing.T) {
 	checkRFC3164(t, log, expectedEvent)
 }
 
+func testRFC3164SSHFailedPassword(t *testing.T) {
+	//nolint:lll
+	log := `<38>Oct 11 22:14:15 bastion sshd[4721]: Failed password for invalid user admin from 203.0.113.9 port 41022 ssh2`
+
+	expectedTime := time.Date(time.Now().UTC().Year(), 10, 11, 22, 14, 15, 0, time.UTC)
+
+	expectedEvent := &RFC3164{
+		Priority:  aws.Uint8(38),
+		Facility:  aws.Uint8(4),
+		Severity:  aws.Uint8(6),
+		Timestamp: (*timestamp.RFC3339)(&expectedTime),
+		Hostname:  aws.String("bastion"),
+		Appname:   aws.String("sshd"),
+		ProcID:    aws.String("4721"),
+		MsgID:     nil,
+		Message:   aws.String("Failed password for invalid user admin from 203.0.113.9 port 41022 ssh2"),
+	}
+
+	expectedEvent.AppendAnyDomainNamePtrs(expectedEvent.Hostname)
+	expectedEvent.AppendAnyIPAddress("203.0.113.9")
+	expectedEvent.AppendAnyUsernames("admin")
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Syslog.RFC3164")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+
+	checkRFC3164(t, log, expectedEvent)
+}
+
 func TestRFC3164Type(t *testing.T) {
 	parser := &RFC3164Parser{}
 	require.Equal(t, "Syslog.RFC3164", parser.LogType())
@@ -55,6 +55,16 @@ func Now() RFC3339 {
 	return (RFC3339)(time.Now().UTC())
 }
 
+// InferYear sets the year of a time parsed without one to the year of now. The previous year is used if the time
+// would be more than a day after now, e.g. for an event of December 31st received on January 1st.
+func InferYear(t, now time.Time) time.Time {
+	t = t.AddDate(now.Year()-t.Year(), 0, 0)
+	if t.Sub(now) > 24*time.Hour {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t
+}
+
 type RFC3339 time.Time
 
 func (ts *RFC3339) String() string {
@@ -52,6 +52,27 @@ func TestTimestampRFC3339Unmarshal(t *testing.T) {
 	assert.Equal(t, (RFC3339)(expectedTime), ts)
 }
 
+func TestInferYear(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		value    string
+		expected time.Time
+	}{
+		{"past", "Jan 1 00:00:00", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"previous year", "Dec 31 23:59:59", time.Date(2019, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{"clock skew", "Jan 1 23:00:00", time.Date(2020, 1, 1, 23, 0, 0, 0, time.UTC)},
+		{"more than a day in the future", "Jan 3 00:00:00", time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC)},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := time.Parse("Jan 2 15:04:05", tc.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, InferYear(parsed, now))
+		})
+	}
+}
+
 func TestTimestampANSICwithTZString(t *testing.T) {
 	ts := (ANSICwithTZ)(expectedTime)
 	assert.Equal(t, expectedString, ts.String())
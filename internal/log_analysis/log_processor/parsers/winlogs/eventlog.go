@@ -0,0 +1,469 @@
+package winlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+// TypeEventLog is the log type of Windows event log records
+const TypeEventLog = PantherPrefix + ".EventLog"
+
+// EventLogDesc describes the Windows event log record
+var EventLogDesc = `Windows event log records (e.g. Security, Sysmon and PowerShell channels) forwarded as JSON by Winlogbeat or NXLog (im_msvistalog with to_json).
+Reference: https://docs.microsoft.com/en-us/windows/win32/wes/eventschema-systempropertiestype-complextype`
+
+const (
+	agentWinlogbeat = "winlogbeat"
+	agentNXLog      = "nxlog"
+
+	// NXLog writes EventTime in the local time of the agent, without a zone, unless configured otherwise
+	nxlogTimeLayout = "2006-01-02 15:04:05"
+)
+
+// EventLog is a Windows event log record
+// nolint:lll
+type EventLog struct {
+	EventID     *numerics.Integer  `json:"EventID" validate:"required" description:"The identifier that the provider used to identify the event."`
+	Channel     *string            `json:"Channel" validate:"required" description:"The channel to which the event was logged (e.g. Security, Microsoft-Windows-Sysmon/Operational)."`
+	Computer    *string            `json:"Computer" validate:"required" description:"The name of the computer on which the event occurred."`
+	TimeCreated *timestamp.RFC3339 `json:"TimeCreated" validate:"required" description:"The time the event was logged."`
+	Provider    *Provider          `json:"Provider,omitempty" description:"The provider that logged the event."`
+	RecordID    *numerics.Int64    `json:"EventRecordID,omitempty" description:"The record number assigned to the event when it was logged."`
+	Level       *string            `json:"Level,omitempty" description:"The severity level of the event (e.g. Information, Warning, Error)."`
+	Task        *string            `json:"Task,omitempty" description:"The task (category) of the event."`
+	Opcode      *string            `json:"Opcode,omitempty" description:"The activity or a point within an activity that the application was performing when it raised the event."`
+	Keywords    []string           `json:"Keywords,omitempty" description:"The keywords used to classify the event (e.g. Audit Success, Audit Failure)."`
+	ProcessID   *numerics.Int64    `json:"ProcessID,omitempty" description:"The id of the process that logged the event."`
+	ThreadID    *numerics.Int64    `json:"ThreadID,omitempty" description:"The id of the thread that logged the event."`
+	User        *User              `json:"User,omitempty" description:"The user the event was logged for (the security identifier in the System properties)."`
+	Message     *string            `json:"Message,omitempty" description:"The rendered message of the event."`
+	EventData   *map[string]string `json:"EventData,omitempty" description:"The event specific data, keyed by name (includes UserData for events that log it)."`
+	Agent       *string            `json:"Agent" validate:"required,oneof=winlogbeat nxlog" description:"The agent that forwarded the event (winlogbeat or nxlog)."`
+
+	WindowsPantherLog
+}
+
+// Provider identifies the provider that logged the event
+// nolint:lll
+type Provider struct {
+	Name *string `json:"Name,omitempty" description:"The name of the event provider (e.g. Microsoft-Windows-Security-Auditing)."`
+	GUID *string `json:"Guid,omitempty" description:"The globally unique identifier of the event provider."`
+}
+
+// User is the account an event was logged for
+// nolint:lll
+type User struct {
+	Identifier *string `json:"Identifier,omitempty" description:"The security identifier (SID) of the account."`
+	Name       *string `json:"Name,omitempty" description:"The name of the account."`
+	Domain     *string `json:"Domain,omitempty" description:"The domain of the account."`
+	Type       *string `json:"Type,omitempty" description:"The type of the account (e.g. User, Well Known Group)."`
+}
+
+// EventLogParser parses Windows event log records forwarded by Winlogbeat or NXLog
+type EventLogParser struct{}
+
+var _ parsers.LogParser = (*EventLogParser)(nil)
+
+// New creates a new parser
+func (p *EventLogParser) New() parsers.LogParser {
+	return &EventLogParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *EventLogParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	fields := map[string]jsoniter.RawMessage{}
+	if err := jsoniter.UnmarshalFromString(log, &fields); err != nil {
+		return nil, err
+	}
+
+	var event *EventLog
+	var err error
+	switch {
+	case fields["winlog"] != nil || fields["event_id"] != nil:
+		event, err = parseWinlogbeat(log)
+	case fields["EventID"] != nil && fields["Hostname"] != nil:
+		event, err = parseNXLog(fields)
+	default:
+		return nil, errors.New("not a Winlogbeat or NXLog Windows event")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(event); err != nil {
+		return nil, err
+	}
+
+	return event.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *EventLogParser) LogType() string {
+	return TypeEventLog
+}
+
+// winlogbeatEvent is the event published by Winlogbeat, 7.x nests the event under `winlog` while 6.x has it at the top level
+type winlogbeatEvent struct {
+	Timestamp *timestamp.RFC3339 `json:"@timestamp"`
+	Message   *string            `json:"message"`
+	Log       *struct {
+		Level *string `json:"level"`
+	} `json:"log"`
+	Winlog *winlogbeatFields `json:"winlog"`
+
+	winlogbeatFields
+	Level        *string         `json:"level"`
+	LogName      *string         `json:"log_name"`
+	SourceName   *string         `json:"source_name"`
+	RecordNumber *numerics.Int64 `json:"record_number"`
+	ProcessID    *numerics.Int64 `json:"process_id"`
+	ThreadID     *numerics.Int64 `json:"thread_id"`
+}
+
+type winlogbeatFields struct {
+	EventID      *numerics.Integer              `json:"event_id"`
+	Channel      *string                        `json:"channel"`
+	ComputerName *string                        `json:"computer_name"`
+	ProviderName *string                        `json:"provider_name"`
+	ProviderGUID *string                        `json:"provider_guid"`
+	RecordID     *numerics.Int64                `json:"record_id"`
+	Task         *string                        `json:"task"`
+	Opcode       *string                        `json:"opcode"`
+	Keywords     []string                       `json:"keywords"`
+	EventData    map[string]jsoniter.RawMessage `json:"event_data"`
+	UserData     map[string]jsoniter.RawMessage `json:"user_data"`
+	User         *winlogbeatUser                `json:"user"`
+	Process      *struct {
+		PID    *numerics.Int64 `json:"pid"`
+		Thread *struct {
+			ID *numerics.Int64 `json:"id"`
+		} `json:"thread"`
+	} `json:"process"`
+}
+
+type winlogbeatUser struct {
+	Identifier *string `json:"identifier"`
+	Name       *string `json:"name"`
+	Domain     *string `json:"domain"`
+	Type       *string `json:"type"`
+}
+
+func parseWinlogbeat(log string) (*EventLog, error) {
+	input := winlogbeatEvent{}
+	if err := jsoniter.UnmarshalFromString(log, &input); err != nil {
+		return nil, err
+	}
+	fields := &input.winlogbeatFields
+	if input.Winlog != nil {
+		fields = input.Winlog
+	}
+
+	event := &EventLog{
+		EventID:     fields.EventID,
+		Channel:     firstString(fields.Channel, input.LogName),
+		Computer:    fields.ComputerName,
+		TimeCreated: input.Timestamp,
+		RecordID:    fields.RecordID,
+		Level:       input.Level,
+		Task:        fields.Task,
+		Opcode:      fields.Opcode,
+		Keywords:    fields.Keywords,
+		ProcessID:   input.ProcessID,
+		ThreadID:    input.ThreadID,
+		Message:     input.Message,
+		Agent:       stringPtr(agentWinlogbeat),
+	}
+	if event.RecordID == nil {
+		event.RecordID = input.RecordNumber
+	}
+	if input.Log != nil && input.Log.Level != nil {
+		event.Level = input.Log.Level
+	}
+	if name := firstString(fields.ProviderName, input.SourceName); name != nil || fields.ProviderGUID != nil {
+		event.Provider = &Provider{
+			Name: name,
+			GUID: fields.ProviderGUID,
+		}
+	}
+	if fields.Process != nil {
+		event.ProcessID = fields.Process.PID
+		if fields.Process.Thread != nil {
+			event.ThreadID = fields.Process.Thread.ID
+		}
+	}
+	if user := fields.User; user != nil {
+		event.User = &User{
+			Identifier: user.Identifier,
+			Name:       user.Name,
+			Domain:     user.Domain,
+			Type:       user.Type,
+		}
+	}
+	event.addEventData(fields.EventData)
+	event.addEventData(fields.UserData)
+	return event, nil
+}
+
+// nxlogSystemFields are the fields im_msvistalog adds to each record, all other fields are EventData
+var nxlogSystemFields = map[string]bool{
+	"EventTime":         true,
+	"EventReceivedTime": true,
+	"Hostname":          true,
+	"Keywords":          true,
+	"EventType":         true,
+	"SeverityValue":     true,
+	"Severity":          true,
+	"EventID":           true,
+	"SourceName":        true,
+	"ProviderGuid":      true,
+	"Version":           true,
+	"Task":              true,
+	"OpcodeValue":       true,
+	"RecordNumber":      true,
+	"ActivityID":        true,
+	"RelatedActivityID": true,
+	"ProcessID":         true,
+	"ThreadID":          true,
+	"Channel":           true,
+	"Domain":            true,
+	"AccountName":       true,
+	"UserID":            true,
+	"AccountType":       true,
+	"Category":          true,
+	"Opcode":            true,
+	"Message":           true,
+	"SourceModuleName":  true,
+	"SourceModuleType":  true,
+}
+
+// nxlogKeywords maps the NXLog EventType of audit events to the keyword names used by Windows
+var nxlogKeywords = map[string]string{
+	"AUDIT_SUCCESS": "Audit Success",
+	"AUDIT_FAILURE": "Audit Failure",
+}
+
+func parseNXLog(fields map[string]jsoniter.RawMessage) (*EventLog, error) {
+	event := &EventLog{
+		Channel:  rawString(fields["Channel"]),
+		Computer: rawString(fields["Hostname"]),
+		Level:    rawString(fields["Severity"]),
+		Task:     rawString(fields["Category"]),
+		Opcode:   rawString(fields["Opcode"]),
+		Message:  rawString(fields["Message"]),
+		Agent:    stringPtr(agentNXLog),
+	}
+	for name, value := range map[string]interface{}{
+		"EventID":      &event.EventID,
+		"RecordNumber": &event.RecordID,
+		"ProcessID":    &event.ProcessID,
+		"ThreadID":     &event.ThreadID,
+	} {
+		if raw := fields[name]; raw != nil {
+			if err := jsoniter.Unmarshal(raw, value); err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", name)
+			}
+		}
+	}
+	if eventTime := rawString(fields["EventTime"]); eventTime != nil {
+		timeCreated, err := parseNXLogTime(*eventTime)
+		if err != nil {
+			return nil, err
+		}
+		event.TimeCreated = &timeCreated
+	}
+	if name, guid := rawString(fields["SourceName"]), rawString(fields["ProviderGuid"]); name != nil || guid != nil {
+		event.Provider = &Provider{
+			Name: name,
+			GUID: guid,
+		}
+	}
+	if eventType := rawString(fields["EventType"]); eventType != nil {
+		if keyword, ok := nxlogKeywords[*eventType]; ok {
+			event.Keywords = []string{keyword}
+		}
+	}
+	if sid := rawString(fields["UserID"]); sid != nil {
+		event.User = &User{
+			Identifier: sid,
+			Name:       rawString(fields["AccountName"]),
+			Domain:     rawString(fields["Domain"]),
+			Type:       rawString(fields["AccountType"]),
+		}
+	}
+	eventData := make(map[string]jsoniter.RawMessage, len(fields))
+	for name, value := range fields {
+		if !nxlogSystemFields[name] {
+			eventData[name] = value
+		}
+	}
+	event.addEventData(eventData)
+	return event, nil
+}
+
+func parseNXLogTime(value string) (timestamp.RFC3339, error) {
+	if ts, err := timestamp.Parse(time.RFC3339Nano, value); err == nil {
+		return ts, nil
+	}
+	ts, err := timestamp.Parse(nxlogTimeLayout, value)
+	if err != nil {
+		return ts, errors.Errorf("invalid EventTime %q", value)
+	}
+	return ts, nil
+}
+
+// addEventData adds the fields to EventData, keeping non string values as JSON text
+func (event *EventLog) addEventData(fields map[string]jsoniter.RawMessage) {
+	for name, raw := range fields {
+		value := rawString(raw)
+		if value == nil {
+			continue
+		}
+		if event.EventData == nil {
+			event.EventData = &map[string]string{}
+		}
+		(*event.EventData)[name] = *value
+	}
+}
+
+// EventData fields holding IP addresses (Security auditing, Windows Filtering Platform and Sysmon events)
+var ipAddressFields = []string{
+	"IpAddress",
+	"ClientAddress",
+	"SourceAddress",
+	"DestAddress",
+	"SourceIp",
+	"DestinationIp",
+}
+
+// EventData fields holding host or domain names (Sysmon network connection and DNS query events)
+var domainNameFields = []string{
+	"SourceHostname",
+	"DestinationHostname",
+	"QueryName",
+}
+
+// EventData fields holding account names, Sysmon logs `DOMAIN\name` in User
+var accountFields = []string{
+	"SubjectUserName",
+	"TargetUserName",
+	"TargetOutboundUserName",
+	"SamAccountName",
+	"AccountName",
+	"User",
+}
+
+// EventData fields holding the Sysmon file hashes, e.g. `SHA1=...,MD5=...,SHA256=...,IMPHASH=...`
+var hashesFields = []string{
+	"Hashes",
+	"Hash",
+}
+
+func (event *EventLog) updatePantherFields(p *EventLogParser) {
+	event.SetCoreFields(p.LogType(), event.TimeCreated, event)
+
+	if event.User != nil {
+		event.AppendAnyWindowsAccountPtrs(event.User.Name)
+	}
+	if event.EventData == nil {
+		return
+	}
+	eventData := *event.EventData
+	for _, name := range ipAddressFields {
+		if value, ok := eventData[name]; ok {
+			event.AppendAnyIPAddress(value)
+		}
+	}
+	for _, name := range domainNameFields {
+		if value, ok := eventData[name]; ok && value != "-" && !event.AppendAnyIPAddress(value) {
+			event.AppendAnyDomainNames(strings.TrimSuffix(value, "."))
+		}
+	}
+	for _, name := range accountFields {
+		if value, ok := eventData[name]; ok {
+			event.AppendAnyWindowsAccounts(value)
+		}
+	}
+	for _, name := range hashesFields {
+		if value, ok := eventData[name]; ok {
+			event.appendHashes(value)
+		}
+	}
+}
+
+// appendHashes splits the Sysmon hashes field into the hashes of each algorithm
+func (event *EventLog) appendHashes(hashes string) {
+	for _, hash := range strings.Split(hashes, ",") {
+		separator := strings.IndexByte(hash, '=')
+		if separator < 0 {
+			continue
+		}
+		algorithm, value := strings.TrimSpace(hash[:separator]), strings.TrimSpace(hash[separator+1:])
+		if value == "" {
+			continue
+		}
+		switch strings.ToUpper(algorithm) {
+		case "MD5":
+			event.AppendAnyMD5Hashes(value)
+		case "SHA1":
+			event.AppendAnySHA1Hashes(value)
+		case "SHA256":
+			event.AppendAnySHA256Hashes(value)
+		}
+	}
+}
+
+// rawString returns the value of a JSON string, or the JSON text of any other non null value
+func rawString(raw jsoniter.RawMessage) *string {
+	if raw == nil {
+		return nil
+	}
+	var value string
+	if err := jsoniter.Unmarshal(raw, &value); err == nil {
+		return &value
+	}
+	text := strings.TrimSpace(string(raw))
+	if text == "null" {
+		return nil
+	}
+	return &text
+}
+
+func firstString(values ...*string) *string {
+	for _, value := range values {
+		if value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,312 @@
+package winlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestEventLogWinlogbeatSysmon(t *testing.T) {
+	// nolint:lll
+	log := `{
+		"@timestamp": "2020-05-06T14:12:37.152Z",
+		"agent": {"type": "winlogbeat", "version": "7.6.2"},
+		"event": {"code": 1, "kind": "event", "module": "sysmon"},
+		"host": {"name": "WKS-042"},
+		"log": {"level": "information"},
+		"message": "Process Create:\nImage: C:\\Windows\\System32\\WindowsPowerShell\\v1.0\\powershell.exe",
+		"winlog": {
+			"api": "wineventlog",
+			"channel": "Microsoft-Windows-Sysmon/Operational",
+			"computer_name": "WKS-042.corp.example.com",
+			"event_data": {
+				"CommandLine": "powershell.exe -nop -w hidden -enc SQBFAFgA",
+				"Hashes": "SHA1=6CBCE4A295C163791B60FC23D285E6D84F28EE4C,MD5=7353F60B1739074EB17C5F4DDDEFE239,SHA256=DE96A6E69944335375DC1AC238336066889D9FFC7D73628EF4FE1B1B160AB32C,IMPHASH=741776AACCFC5B71FF59832DCDCACE0F",
+				"Image": "C:\\Windows\\System32\\WindowsPowerShell\\v1.0\\powershell.exe",
+				"LogonId": "0x3e7a2b",
+				"ProcessId": "4312",
+				"User": "CORP\\alice"
+			},
+			"event_id": 1,
+			"opcode": "Info",
+			"process": {"pid": 2484, "thread": {"id": 3640}},
+			"provider_guid": "{5770385F-C22A-43E0-BF4C-06F5698FFBD9}",
+			"provider_name": "Microsoft-Windows-Sysmon",
+			"record_id": 178293,
+			"task": "Process Create (rule: ProcessCreate)",
+			"user": {"domain": "NT AUTHORITY", "identifier": "S-1-5-18", "name": "SYSTEM", "type": "User"},
+			"version": 5
+		}
+	}`
+
+	expected := &EventLog{
+		EventID:     integer(1),
+		Channel:     aws.String("Microsoft-Windows-Sysmon/Operational"),
+		Computer:    aws.String("WKS-042.corp.example.com"),
+		TimeCreated: mustParseTime(t, "2020-05-06T14:12:37.152Z"),
+		Provider: &Provider{
+			Name: aws.String("Microsoft-Windows-Sysmon"),
+			GUID: aws.String("{5770385F-C22A-43E0-BF4C-06F5698FFBD9}"),
+		},
+		RecordID:  int64Ptr(178293),
+		Level:     aws.String("information"),
+		Task:      aws.String("Process Create (rule: ProcessCreate)"),
+		Opcode:    aws.String("Info"),
+		ProcessID: int64Ptr(2484),
+		ThreadID:  int64Ptr(3640),
+		User: &User{
+			Identifier: aws.String("S-1-5-18"),
+			Name:       aws.String("SYSTEM"),
+			Domain:     aws.String("NT AUTHORITY"),
+			Type:       aws.String("User"),
+		},
+		Message: aws.String("Process Create:\nImage: C:\\Windows\\System32\\WindowsPowerShell\\v1.0\\powershell.exe"),
+		// nolint:lll
+		EventData: &map[string]string{
+			"CommandLine": "powershell.exe -nop -w hidden -enc SQBFAFgA",
+			"Hashes":      "SHA1=6CBCE4A295C163791B60FC23D285E6D84F28EE4C,MD5=7353F60B1739074EB17C5F4DDDEFE239,SHA256=DE96A6E69944335375DC1AC238336066889D9FFC7D73628EF4FE1B1B160AB32C,IMPHASH=741776AACCFC5B71FF59832DCDCACE0F",
+			"Image":       "C:\\Windows\\System32\\WindowsPowerShell\\v1.0\\powershell.exe",
+			"LogonId":     "0x3e7a2b",
+			"ProcessId":   "4312",
+			"User":        "CORP\\alice",
+		},
+		Agent: aws.String("winlogbeat"),
+	}
+	expected.AppendAnyWindowsAccounts("system", "alice")
+	expected.AppendAnySHA1Hashes("6CBCE4A295C163791B60FC23D285E6D84F28EE4C")
+	expected.AppendAnyMD5Hashes("7353F60B1739074EB17C5F4DDDEFE239")
+	expected.AppendAnySHA256Hashes("DE96A6E69944335375DC1AC238336066889D9FFC7D73628EF4FE1B1B160AB32C")
+
+	checkEventLog(t, log, expected)
+}
+
+func TestEventLogWinlogbeat6Security(t *testing.T) {
+	log := `{
+		"@timestamp": "2020-05-06T09:41:02.000Z",
+		"beat": {"name": "DC-01", "version": "6.8.0"},
+		"computer_name": "DC-01.corp.example.com",
+		"event_data": {
+			"IpAddress": "203.0.113.45",
+			"IpPort": "50432",
+			"LogonType": "10",
+			"SubjectUserName": "-",
+			"TargetDomainName": "CORP",
+			"TargetUserName": "Administrator",
+			"WorkstationName": "ATTACKER-PC"
+		},
+		"event_id": 4624,
+		"keywords": ["Audit Success"],
+		"level": "Information",
+		"log_name": "Security",
+		"message": "An account was successfully logged on.",
+		"opcode": "Info",
+		"process_id": 636,
+		"provider_guid": "{54849625-5478-4994-A5BA-3E3B0328C30D}",
+		"record_number": "9125503",
+		"source_name": "Microsoft-Windows-Security-Auditing",
+		"task": "Logon",
+		"thread_id": 4420,
+		"type": "wineventlog"
+	}`
+
+	expected := &EventLog{
+		EventID:     integer(4624),
+		Channel:     aws.String("Security"),
+		Computer:    aws.String("DC-01.corp.example.com"),
+		TimeCreated: mustParseTime(t, "2020-05-06T09:41:02.000Z"),
+		Provider: &Provider{
+			Name: aws.String("Microsoft-Windows-Security-Auditing"),
+			GUID: aws.String("{54849625-5478-4994-A5BA-3E3B0328C30D}"),
+		},
+		RecordID:  int64Ptr(9125503),
+		Level:     aws.String("Information"),
+		Task:      aws.String("Logon"),
+		Opcode:    aws.String("Info"),
+		Keywords:  []string{"Audit Success"},
+		ProcessID: int64Ptr(636),
+		ThreadID:  int64Ptr(4420),
+		Message:   aws.String("An account was successfully logged on."),
+		EventData: &map[string]string{
+			"IpAddress":        "203.0.113.45",
+			"IpPort":           "50432",
+			"LogonType":        "10",
+			"SubjectUserName":  "-",
+			"TargetDomainName": "CORP",
+			"TargetUserName":   "Administrator",
+			"WorkstationName":  "ATTACKER-PC",
+		},
+		Agent: aws.String("winlogbeat"),
+	}
+	expected.AppendAnyIPAddress("203.0.113.45")
+	expected.AppendAnyWindowsAccounts("administrator")
+
+	checkEventLog(t, log, expected)
+}
+
+func TestEventLogNXLog(t *testing.T) {
+	log := `{
+		"EventTime": "2020-05-06 10:15:48",
+		"Hostname": "WEB-07.corp.example.com",
+		"Keywords": -9218868437227405312,
+		"EventType": "AUDIT_FAILURE",
+		"SeverityValue": 4,
+		"Severity": "ERROR",
+		"EventID": 4625,
+		"SourceName": "Microsoft-Windows-Security-Auditing",
+		"ProviderGuid": "{54849625-5478-4994-A5BA-3E3B0328C30D}",
+		"Version": 0,
+		"Task": 12544,
+		"OpcodeValue": 0,
+		"RecordNumber": 88210,
+		"ProcessID": 612,
+		"ThreadID": 2316,
+		"Channel": "Security",
+		"Category": "Logon",
+		"Opcode": "Info",
+		"Message": "An account failed to log on.",
+		"SubjectUserSid": "S-1-0-0",
+		"TargetUserName": "CORP\\bob",
+		"FailureReason": "%%2313",
+		"Status": "0xc000006d",
+		"LogonType": 3,
+		"IpAddress": "198.51.100.23",
+		"IpPort": "0",
+		"EventReceivedTime": "2020-05-06 10:15:49",
+		"SourceModuleName": "eventlog",
+		"SourceModuleType": "im_msvistalog"
+	}`
+
+	expected := &EventLog{
+		EventID:     integer(4625),
+		Channel:     aws.String("Security"),
+		Computer:    aws.String("WEB-07.corp.example.com"),
+		TimeCreated: mustParseTime(t, "2020-05-06T10:15:48Z"),
+		Provider: &Provider{
+			Name: aws.String("Microsoft-Windows-Security-Auditing"),
+			GUID: aws.String("{54849625-5478-4994-A5BA-3E3B0328C30D}"),
+		},
+		RecordID:  int64Ptr(88210),
+		Level:     aws.String("ERROR"),
+		Task:      aws.String("Logon"),
+		Opcode:    aws.String("Info"),
+		Keywords:  []string{"Audit Failure"},
+		ProcessID: int64Ptr(612),
+		ThreadID:  int64Ptr(2316),
+		Message:   aws.String("An account failed to log on."),
+		EventData: &map[string]string{
+			"SubjectUserSid": "S-1-0-0",
+			"TargetUserName": "CORP\\bob",
+			"FailureReason":  "%%2313",
+			"Status":         "0xc000006d",
+			"LogonType":      "3",
+			"IpAddress":      "198.51.100.23",
+			"IpPort":         "0",
+		},
+		Agent: aws.String("nxlog"),
+	}
+	expected.AppendAnyIPAddress("198.51.100.23")
+	expected.AppendAnyWindowsAccounts("bob")
+
+	checkEventLog(t, log, expected)
+}
+
+func TestEventLogSysmonDNSQuery(t *testing.T) {
+	log := `{
+		"@timestamp": "2020-05-06T16:03:11.480Z",
+		"winlog": {
+			"channel": "Microsoft-Windows-Sysmon/Operational",
+			"computer_name": "WKS-042.corp.example.com",
+			"event_id": "22",
+			"event_data": {
+				"QueryName": "c2.example.net",
+				"QueryResults": "::ffff:192.0.2.44;",
+				"QueryStatus": "0",
+				"Image": "C:\\Users\\alice\\AppData\\Local\\Temp\\update.exe"
+			}
+		}
+	}`
+
+	event := parseEventLog(t, log)
+	require.Equal(t, 22, int(*event.EventID))
+	require.Equal(t, "c2.example.net", (*event.EventData)["QueryName"])
+	expected := parsers.PantherLog{}
+	expected.AppendAnyDomainNames("c2.example.net")
+	require.Equal(t, expected.PantherAnyDomainNames, event.PantherAnyDomainNames)
+	require.Nil(t, event.PantherAnyWindowsAccounts)
+}
+
+func TestEventLogInvalid(t *testing.T) {
+	parser := (&EventLogParser{}).New()
+	for _, log := range []string{
+		`not json`,
+		`{"EventID": 4624}`, // not a known agent format
+		`{"winlog": {"event_id": 4624, "channel": "Security", "computer_name": "DC-01"}}`,                      // missing @timestamp
+		`{"EventTime": "06/05/2020", "Hostname": "DC-01", "EventID": 4624, "Channel": "Security"}`,             // invalid time
+		`{"EventTime": "2020-05-06 10:15:48", "Hostname": "DC-01", "EventID": "Logon", "Channel": "Security"}`, // invalid event id
+		`{"@timestamp": "2020-05-06T14:12:37Z", "winlog": {"channel": "Security", "computer_name": "DC-01"}}`,  // missing event id
+	} {
+		events, err := parser.Parse(log)
+		require.Error(t, err, log)
+		require.Nil(t, events)
+	}
+}
+
+func TestEventLogType(t *testing.T) {
+	parser := &EventLogParser{}
+	require.Equal(t, "Windows.EventLog", parser.LogType())
+}
+
+func checkEventLog(t *testing.T, log string, expected *EventLog) {
+	event := parseEventLog(t, log)
+	expected.SetCoreFields(TypeEventLog, expected.TimeCreated, expected)
+	// copy the fields that differ on each parse
+	expected.PantherRowID = event.PantherRowID
+	expected.PantherParseTime = event.PantherParseTime
+	require.Equal(t, expected, event)
+}
+
+func parseEventLog(t *testing.T, log string) *EventLog {
+	parser := (&EventLogParser{}).New()
+	events, err := parser.Parse(log)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	return events[0].Event().(*EventLog)
+}
+
+func mustParseTime(t *testing.T, value string) *timestamp.RFC3339 {
+	ts, err := time.Parse(time.RFC3339Nano, value)
+	require.NoError(t, err)
+	return (*timestamp.RFC3339)(&ts)
+}
+
+func integer(value int) *numerics.Integer {
+	return (*numerics.Integer)(&value)
+}
+
+func int64Ptr(value int64) *numerics.Int64 {
+	return (*numerics.Int64)(&value)
+}
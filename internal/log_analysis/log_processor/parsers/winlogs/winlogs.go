@@ -0,0 +1,64 @@
+package winlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package winlogs parses Windows event logs forwarded as JSON by agents like Winlogbeat and NXLog.
+
+import (
+	"strings"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// PantherPrefix is the prefix of all logs parsed by this package
+const PantherPrefix = "Windows"
+
+// nolint(lll)
+type WindowsPantherLog struct {
+	parsers.PantherLog
+
+	PantherAnyWindowsAccounts *parsers.PantherAnyString `json:"p_any_windows_accounts,omitempty" description:"Panther added field with collection of windows account names associated with the row"`
+}
+
+func (pl *WindowsPantherLog) AppendAnyWindowsAccountPtrs(values ...*string) {
+	for _, value := range values {
+		if value != nil {
+			pl.AppendAnyWindowsAccounts(*value)
+		}
+	}
+}
+
+// NOTE: account names are case insensitive, they are stored lower case and without the domain (e.g. CORP\alice is alice).
+// They are also added to the usernames.
+func (pl *WindowsPantherLog) AppendAnyWindowsAccounts(values ...string) {
+	for _, value := range values {
+		if i := strings.LastIndexByte(value, '\\'); i >= 0 {
+			value = value[i+1:]
+		}
+		if value == "" || value == "-" {
+			continue
+		}
+		if pl.PantherAnyWindowsAccounts == nil { // lazy create
+			pl.PantherAnyWindowsAccounts = parsers.NewPantherAnyString()
+		}
+		account := strings.ToLower(value)
+		parsers.AppendAnyString(pl.PantherAnyWindowsAccounts, account)
+		pl.AppendAnyUsernames(account)
+	}
+}
@@ -0,0 +1,79 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestZeekConn(t *testing.T) {
+	// nolint:lll
+	log := `{"ts":1591367999.5,"uid":"CMdzit1AMNsmfAIiQc","id.orig_h":"192.168.4.76","id.orig_p":36844,"id.resp_h":"192.168.4.1","id.resp_p":53,"proto":"udp","service":"dns","duration":0.25,"orig_bytes":62,"resp_bytes":141,"conn_state":"SF","missed_bytes":0,"history":"Dd","orig_pkts":2,"orig_ip_bytes":118,"resp_pkts":2,"resp_ip_bytes":197,"tunnel_parents":[],"orig_l2_addr":"00:0c:29:8e:6a:1f","resp_l2_addr":"f0:9f:c2:1a:2b:3c"}`
+
+	expectedTime := time.Date(2020, 6, 5, 14, 39, 59, 500000000, time.UTC)
+	expectedEvent := &ZeekConn{
+		Ts:          (*timestamp.UnixFloat)(&expectedTime),
+		UID:         aws.String("CMdzit1AMNsmfAIiQc"),
+		IDOrigH:     aws.String("192.168.4.76"),
+		IDOrigP:     aws.Uint16(36844),
+		IDRespH:     aws.String("192.168.4.1"),
+		IDRespP:     aws.Uint16(53),
+		Proto:       aws.String("udp"),
+		Service:     aws.String("dns"),
+		Duration:    aws.Float64(0.25),
+		OrigBytes:   aws.Uint64(62),
+		RespBytes:   aws.Uint64(141),
+		ConnState:   aws.String("SF"),
+		MissedBytes: aws.Uint64(0),
+		History:     aws.String("Dd"),
+		OrigPkts:    aws.Uint64(2),
+		OrigIPBytes: aws.Uint64(118),
+		RespPkts:    aws.Uint64(2),
+		RespIPBytes: aws.Uint64(197),
+		OrigL2Addr:  aws.String("00:0c:29:8e:6a:1f"),
+		RespL2Addr:  aws.String("f0:9f:c2:1a:2b:3c"),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Zeek.Conn")
+	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.IDOrigH)
+	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.IDRespH)
+	expectedEvent.AppendAnyMACAddresses("00:0c:29:8e:6a:1f", "f0:9f:c2:1a:2b:3c")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	checkZeekConn(t, log, expectedEvent)
+}
+
+func TestZeekConnType(t *testing.T) {
+	parser := &ZeekConnParser{}
+	require.Equal(t, "Zeek.Conn", parser.LogType())
+}
+
+func checkZeekConn(t *testing.T, log string, expectedEvent *ZeekConn) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := &ZeekConnParser{}
+	logs, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), logs, err)
+}
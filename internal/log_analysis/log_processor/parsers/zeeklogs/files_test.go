@@ -0,0 +1,80 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestZeekFiles(t *testing.T) {
+	// nolint:lll
+	log := `{"ts":1591367999.5,"fuid":"FEEsZS1w0Z0VJIb5x4","tx_hosts":["31.3.245.133"],"rx_hosts":["192.168.4.76"],"conn_uids":["CHhAvVGS1DHFjwGM9"],"source":"HTTP","depth":0,"analyzers":["MD5","SHA1","SHA256"],"mime_type":"text/plain","duration":0.0,"is_orig":false,"seen_bytes":39,"total_bytes":39,"missing_bytes":0,"overflow_bytes":0,"timedout":false,"md5":"2ab1d4d1a0be0ee7d23b3cbc2d1b0a7e","sha1":"33bf88d5b82df3723d5863c7d23445e345828904","sha256":"2b2a1a77b9e2bd43b5bd1d1c9a8e7e7b4a2f1a4c1b7e3c3d5f4e2a1b0c9d8e7f"}`
+
+	expectedTime := time.Date(2020, 6, 5, 14, 39, 59, 500000000, time.UTC)
+	expectedEvent := &ZeekFiles{
+		Ts:            (*timestamp.UnixFloat)(&expectedTime),
+		FUID:          aws.String("FEEsZS1w0Z0VJIb5x4"),
+		TxHosts:       []string{"31.3.245.133"},
+		RxHosts:       []string{"192.168.4.76"},
+		ConnUIDs:      []string{"CHhAvVGS1DHFjwGM9"},
+		Source:        aws.String("HTTP"),
+		Depth:         aws.Int(0),
+		Analyzers:     []string{"MD5", "SHA1", "SHA256"},
+		MIMEType:      aws.String("text/plain"),
+		Duration:      aws.Float64(0),
+		IsOrig:        aws.Bool(false),
+		SeenBytes:     aws.Uint64(39),
+		TotalBytes:    aws.Uint64(39),
+		MissingBytes:  aws.Uint64(0),
+		OverflowBytes: aws.Uint64(0),
+		Timedout:      aws.Bool(false),
+		MD5:           aws.String("2ab1d4d1a0be0ee7d23b3cbc2d1b0a7e"),
+		SHA1:          aws.String("33bf88d5b82df3723d5863c7d23445e345828904"),
+		SHA256:        aws.String("2b2a1a77b9e2bd43b5bd1d1c9a8e7e7b4a2f1a4c1b7e3c3d5f4e2a1b0c9d8e7f"),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Zeek.Files")
+	expectedEvent.AppendAnyIPAddress("31.3.245.133")
+	expectedEvent.AppendAnyIPAddress("192.168.4.76")
+	expectedEvent.AppendAnyMD5HashPtrs(expectedEvent.MD5)
+	expectedEvent.AppendAnySHA1HashPtrs(expectedEvent.SHA1)
+	expectedEvent.AppendAnySHA256HashesPtr(expectedEvent.SHA256)
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	checkZeekFiles(t, log, expectedEvent)
+}
+
+func TestZeekFilesType(t *testing.T) {
+	parser := &ZeekFilesParser{}
+	require.Equal(t, "Zeek.Files", parser.LogType())
+}
+
+func checkZeekFiles(t *testing.T, log string, expectedEvent *ZeekFiles) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := &ZeekFilesParser{}
+	logs, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), logs, err)
+}
@@ -0,0 +1,102 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestZeekHTTP(t *testing.T) {
+	// nolint:lll
+	log := `{"ts":1591367999.25,"uid":"CHhAvVGS1DHFjwGM9","id.orig_h":"192.168.4.76","id.orig_p":46378,"id.resp_h":"31.3.245.133","id.resp_p":80,"trans_depth":1,"method":"GET","host":"testmyids.com:80","uri":"/","version":"1.1","user_agent":"curl/7.47.0","request_body_len":0,"response_body_len":39,"status_code":200,"status_msg":"OK","tags":[],"resp_fuids":["FEEsZS1w0Z0VJIb5x4"],"resp_mime_types":["text/plain"]}`
+
+	expectedTime := time.Date(2020, 6, 5, 14, 39, 59, 250000000, time.UTC)
+	expectedEvent := &ZeekHTTP{
+		Ts:              (*timestamp.UnixFloat)(&expectedTime),
+		UID:             aws.String("CHhAvVGS1DHFjwGM9"),
+		IDOrigH:         aws.String("192.168.4.76"),
+		IDOrigP:         aws.Uint16(46378),
+		IDRespH:         aws.String("31.3.245.133"),
+		IDRespP:         aws.Uint16(80),
+		TransDepth:      aws.Int(1),
+		Method:          aws.String("GET"),
+		Host:            aws.String("testmyids.com:80"),
+		URI:             aws.String("/"),
+		Version:         aws.String("1.1"),
+		UserAgent:       aws.String("curl/7.47.0"),
+		RequestBodyLen:  aws.Uint64(0),
+		ResponseBodyLen: aws.Uint64(39),
+		StatusCode:      aws.Int(200),
+		StatusMsg:       aws.String("OK"),
+		Tags:            []string{},
+		RespFUIDs:       []string{"FEEsZS1w0Z0VJIb5x4"},
+		RespMIMETypes:   []string{"text/plain"},
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Zeek.HTTP")
+	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.IDOrigH)
+	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.IDRespH)
+	expectedEvent.AppendAnyDomainNames("testmyids.com")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	checkZeekHTTP(t, log, expectedEvent)
+}
+
+func TestZeekHTTPIPHost(t *testing.T) {
+	// nolint:lll
+	log := `{"ts":1591367999.25,"uid":"CHhAvVGS1DHFjwGM9","id.orig_h":"192.168.4.76","id.orig_p":46378,"id.resp_h":"31.3.245.133","id.resp_p":80,"method":"GET","host":"31.3.245.133"}`
+
+	expectedTime := time.Date(2020, 6, 5, 14, 39, 59, 250000000, time.UTC)
+	expectedEvent := &ZeekHTTP{
+		Ts:      (*timestamp.UnixFloat)(&expectedTime),
+		UID:     aws.String("CHhAvVGS1DHFjwGM9"),
+		IDOrigH: aws.String("192.168.4.76"),
+		IDOrigP: aws.Uint16(46378),
+		IDRespH: aws.String("31.3.245.133"),
+		IDRespP: aws.Uint16(80),
+		Method:  aws.String("GET"),
+		Host:    aws.String("31.3.245.133"),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Zeek.HTTP")
+	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.IDOrigH)
+	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.IDRespH)
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	checkZeekHTTP(t, log, expectedEvent)
+}
+
+func TestZeekHTTPType(t *testing.T) {
+	parser := &ZeekHTTPParser{}
+	require.Equal(t, "Zeek.HTTP", parser.LogType())
+}
+
+func checkZeekHTTP(t *testing.T, log string, expectedEvent *ZeekHTTP) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := &ZeekHTTPParser{}
+	logs, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), logs, err)
+}
@@ -0,0 +1,101 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var ZeekNoticeDesc = `Zeek notices raised by the notice framework
+Reference: https://docs.zeek.org/en/current/scripts/base/frameworks/notice/main.zeek.html#type-Notice::Info`
+
+// nolint:lll
+type ZeekNotice struct {
+	Ts                        *timestamp.UnixFloat `json:"ts,omitempty" validate:"required" description:"An absolute time indicating when the notice occurred."`
+	UID                       *string              `json:"uid,omitempty" description:"A connection UID which uniquely identifies the endpoints concerned with the notice."`
+	IDOrigH                   *string              `json:"id.orig_h,omitempty" description:"The originator’s IP address."`
+	IDOrigP                   *uint16              `json:"id.orig_p,omitempty" description:"The originator’s port number."`
+	IDRespH                   *string              `json:"id.resp_h,omitempty" description:"The responder’s IP address."`
+	IDRespP                   *uint16              `json:"id.resp_p,omitempty" description:"The responder’s port number."`
+	FUID                      *string              `json:"fuid,omitempty" description:"A file unique ID if this notice is related to a file."`
+	FileMIMEType              *string              `json:"file_mime_type,omitempty" description:"A mime type if the notice is related to a file."`
+	FileDesc                  *string              `json:"file_desc,omitempty" description:"Frequently files can be “described” to give a bit more context."`
+	Proto                     *string              `json:"proto,omitempty" description:"The transport protocol."`
+	Note                      *string              `json:"note,omitempty" validate:"required" description:"The type of the notice."`
+	Msg                       *string              `json:"msg,omitempty" description:"The human readable message for the notice."`
+	Sub                       *string              `json:"sub,omitempty" description:"The human readable sub-message."`
+	Src                       *string              `json:"src,omitempty" description:"Source address, if we don’t have a conn_id."`
+	Dst                       *string              `json:"dst,omitempty" description:"Destination address."`
+	P                         *uint16              `json:"p,omitempty" description:"Associated port, if we don’t have a conn_id."`
+	N                         *uint64              `json:"n,omitempty" description:"Associated count, or perhaps a status code."`
+	PeerDescr                 *string              `json:"peer_descr,omitempty" description:"Textual description for the peer that raised this notice."`
+	Actions                   []string             `json:"actions,omitempty" description:"The actions which have been applied to this notice."`
+	SuppressFor               *float64             `json:"suppress_for,omitempty" description:"This field indicates the length of time in seconds that this unique notice should be suppressed."`
+	RemoteLocationCountryCode *string              `json:"remote_location.country_code,omitempty" description:"The country code of the remote host."`
+	RemoteLocationRegion      *string              `json:"remote_location.region,omitempty" description:"The region of the remote host."`
+	RemoteLocationCity        *string              `json:"remote_location.city,omitempty" description:"The city of the remote host."`
+	RemoteLocationLatitude    *float64             `json:"remote_location.latitude,omitempty" description:"The latitude of the remote host."`
+	RemoteLocationLongitude   *float64             `json:"remote_location.longitude,omitempty" description:"The longitude of the remote host."`
+	Dropped                   *bool                `json:"dropped,omitempty" description:"Indicate if the $src IP address was dropped and denied network access."`
+	parsers.PantherLog
+}
+
+// ZeekNoticeParser parses zeek notice logs
+type ZeekNoticeParser struct{}
+
+var _ parsers.LogParser = (*ZeekNoticeParser)(nil)
+
+func (p *ZeekNoticeParser) New() parsers.LogParser {
+	return &ZeekNoticeParser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *ZeekNoticeParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	zeekNotice := &ZeekNotice{}
+
+	err := jsoniter.UnmarshalFromString(log, zeekNotice)
+	if err != nil {
+		return nil, err
+	}
+
+	zeekNotice.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(zeekNotice); err != nil {
+		return nil, err
+	}
+
+	return zeekNotice.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *ZeekNoticeParser) LogType() string {
+	return "Zeek.Notice"
+}
+
+func (event *ZeekNotice) updatePantherFields(p *ZeekNoticeParser) {
+	event.SetCoreFields(p.LogType(), (*timestamp.RFC3339)(event.Ts), event)
+
+	event.AppendAnyIPAddressPtr(event.IDOrigH)
+	event.AppendAnyIPAddressPtr(event.IDRespH)
+	event.AppendAnyIPAddressPtr(event.Src)
+	event.AppendAnyIPAddressPtr(event.Dst)
+}
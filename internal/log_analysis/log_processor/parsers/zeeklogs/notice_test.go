@@ -0,0 +1,76 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestZeekNotice(t *testing.T) {
+	// nolint:lll
+	log := `{"ts":1591367999.5,"uid":"CsukF91Bx9mrqdEaH9","id.orig_h":"192.168.4.49","id.orig_p":56718,"id.resp_h":"13.32.202.10","id.resp_p":443,"proto":"tcp","note":"SSL::Invalid_Server_Cert","msg":"SSL certificate validation failed with (unable to get local issuer certificate)","sub":"CN=www.taosecurity.com","src":"192.168.4.49","dst":"13.32.202.10","p":443,"peer_descr":"worker-1","actions":["Notice::ACTION_LOG"],"suppress_for":3600.0,"remote_location.country_code":"US","dropped":false}`
+
+	expectedTime := time.Date(2020, 6, 5, 14, 39, 59, 500000000, time.UTC)
+	expectedEvent := &ZeekNotice{
+		Ts:                        (*timestamp.UnixFloat)(&expectedTime),
+		UID:                       aws.String("CsukF91Bx9mrqdEaH9"),
+		IDOrigH:                   aws.String("192.168.4.49"),
+		IDOrigP:                   aws.Uint16(56718),
+		IDRespH:                   aws.String("13.32.202.10"),
+		IDRespP:                   aws.Uint16(443),
+		Proto:                     aws.String("tcp"),
+		Note:                      aws.String("SSL::Invalid_Server_Cert"),
+		Msg:                       aws.String("SSL certificate validation failed with (unable to get local issuer certificate)"),
+		Sub:                       aws.String("CN=www.taosecurity.com"),
+		Src:                       aws.String("192.168.4.49"),
+		Dst:                       aws.String("13.32.202.10"),
+		P:                         aws.Uint16(443),
+		PeerDescr:                 aws.String("worker-1"),
+		Actions:                   []string{"Notice::ACTION_LOG"},
+		SuppressFor:               aws.Float64(3600),
+		RemoteLocationCountryCode: aws.String("US"),
+		Dropped:                   aws.Bool(false),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Zeek.Notice")
+	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.IDOrigH)
+	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.IDRespH)
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	checkZeekNotice(t, log, expectedEvent)
+}
+
+func TestZeekNoticeType(t *testing.T) {
+	parser := &ZeekNoticeParser{}
+	require.Equal(t, "Zeek.Notice", parser.LogType())
+}
+
+func checkZeekNotice(t *testing.T, log string, expectedEvent *ZeekNotice) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := &ZeekNoticeParser{}
+	logs, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), logs, err)
+}
@@ -0,0 +1,68 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestZeekWeird(t *testing.T) {
+	// nolint:lll
+	log := `{"ts":1591367999.25,"uid":"CQ5MUu3f4XtFBT1Tjg","id.orig_h":"192.168.4.76","id.orig_p":53378,"id.resp_h":"192.168.4.1","id.resp_p":53,"name":"dns_unmatched_reply","notice":false,"peer":"worker-1","source":"DNS"}`
+
+	expectedTime := time.Date(2020, 6, 5, 14, 39, 59, 250000000, time.UTC)
+	expectedEvent := &ZeekWeird{
+		Ts:      (*timestamp.UnixFloat)(&expectedTime),
+		UID:     aws.String("CQ5MUu3f4XtFBT1Tjg"),
+		IDOrigH: aws.String("192.168.4.76"),
+		IDOrigP: aws.Uint16(53378),
+		IDRespH: aws.String("192.168.4.1"),
+		IDRespP: aws.Uint16(53),
+		Name:    aws.String("dns_unmatched_reply"),
+		Notice:  aws.Bool(false),
+		Peer:    aws.String("worker-1"),
+		Source:  aws.String("DNS"),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Zeek.Weird")
+	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.IDOrigH)
+	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.IDRespH)
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	checkZeekWeird(t, log, expectedEvent)
+}
+
+func TestZeekWeirdType(t *testing.T) {
+	parser := &ZeekWeirdParser{}
+	require.Equal(t, "Zeek.Weird", parser.LogType())
+}
+
+func checkZeekWeird(t *testing.T, log string, expectedEvent *ZeekWeird) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := &ZeekWeirdParser{}
+	logs, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), logs, err)
+}
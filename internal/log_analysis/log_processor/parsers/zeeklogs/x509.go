@@ -0,0 +1,101 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+var ZeekX509Desc = `Zeek X.509 certificate info
+Reference: https://docs.zeek.org/en/current/scripts/base/files/x509/main.zeek.html#type-X509::Info`
+
+// nolint:lll
+type ZeekX509 struct {
+	Ts                        *timestamp.UnixFloat `json:"ts,omitempty" validate:"required" description:"Current timestamp."`
+	ID                        *string              `json:"id,omitempty" validate:"required" description:"File id of this certificate."`
+	CertificateVersion        *int                 `json:"certificate.version,omitempty" description:"Version number."`
+	CertificateSerial         *string              `json:"certificate.serial,omitempty" description:"Serial number."`
+	CertificateSubject        *string              `json:"certificate.subject,omitempty" description:"Subject."`
+	CertificateIssuer         *string              `json:"certificate.issuer,omitempty" description:"Issuer."`
+	CertificateNotValidBefore *timestamp.UnixFloat `json:"certificate.not_valid_before,omitempty" description:"Timestamp before when certificate is not valid."`
+	CertificateNotValidAfter  *timestamp.UnixFloat `json:"certificate.not_valid_after,omitempty" description:"Timestamp after when certificate is not valid."`
+	CertificateKeyAlg         *string              `json:"certificate.key_alg,omitempty" description:"Name of the key algorithm."`
+	CertificateSigAlg         *string              `json:"certificate.sig_alg,omitempty" description:"Name of the signature algorithm."`
+	CertificateKeyType        *string              `json:"certificate.key_type,omitempty" description:"Key type, if key parseable by openssl (either rsa, dsa or ec)."`
+	CertificateKeyLength      *int                 `json:"certificate.key_length,omitempty" description:"Key length in bits."`
+	CertificateExponent       *string              `json:"certificate.exponent,omitempty" description:"Exponent, if RSA-certificate."`
+	CertificateCurve          *string              `json:"certificate.curve,omitempty" description:"Curve, if EC-certificate."`
+	SANDNS                    []string             `json:"san.dns,omitempty" description:"List of DNS entries in SAN."`
+	SANURI                    []string             `json:"san.uri,omitempty" description:"List of URI entries in SAN."`
+	SANEmail                  []string             `json:"san.email,omitempty" description:"List of email entries in SAN."`
+	SANIP                     []string             `json:"san.ip,omitempty" description:"List of IP entries in SAN."`
+	BasicConstraintsCA        *bool                `json:"basic_constraints.ca,omitempty" description:"CA flag set?"`
+	BasicConstraintsPathLen   *int                 `json:"basic_constraints.path_len,omitempty" description:"Maximum path length."`
+	parsers.PantherLog
+}
+
+// ZeekX509Parser parses zeek x509 logs
+type ZeekX509Parser struct{}
+
+var _ parsers.LogParser = (*ZeekX509Parser)(nil)
+
+func (p *ZeekX509Parser) New() parsers.LogParser {
+	return &ZeekX509Parser{}
+}
+
+// Parse returns the parsed events or nil if parsing failed
+func (p *ZeekX509Parser) Parse(log string) ([]*parsers.PantherLog, error) {
+	zeekX509 := &ZeekX509{}
+
+	err := jsoniter.UnmarshalFromString(log, zeekX509)
+	if err != nil {
+		return nil, err
+	}
+
+	zeekX509.updatePantherFields(p)
+
+	if err := parsers.Validator.Struct(zeekX509); err != nil {
+		return nil, err
+	}
+
+	return zeekX509.Logs(), nil
+}
+
+// LogType returns the log type supported by this parser
+func (p *ZeekX509Parser) LogType() string {
+	return "Zeek.X509"
+}
+
+func (event *ZeekX509) updatePantherFields(p *ZeekX509Parser) {
+	event.SetCoreFields(p.LogType(), (*timestamp.RFC3339)(event.Ts), event)
+
+	for _, ip := range event.SANIP {
+		event.AppendAnyIPAddress(ip)
+	}
+	for _, name := range event.SANDNS {
+		// Wildcard entries are not useful for matching, strip the leading label
+		event.AppendAnyDomainNames(strings.TrimPrefix(name, "*."))
+	}
+	event.AppendAnyEmails(event.SANEmail...)
+}
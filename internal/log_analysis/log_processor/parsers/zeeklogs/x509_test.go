@@ -0,0 +1,76 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+)
+
+func TestZeekX509(t *testing.T) {
+	// nolint:lll
+	log := `{"ts":1591367999.75,"id":"F2XEvj1CahhdhtfvT4","certificate.version":3,"certificate.serial":"0A7F3E2B7B5C1E52E2F4F3B1C1B6E6A1","certificate.subject":"CN=www.taosecurity.com","certificate.issuer":"CN=Amazon,OU=Server CA 1B,O=Amazon,C=US","certificate.not_valid_before":1588291200.0,"certificate.not_valid_after":1622505600.0,"certificate.key_alg":"rsaEncryption","certificate.sig_alg":"sha256WithRSAEncryption","certificate.key_type":"rsa","certificate.key_length":2048,"certificate.exponent":"65537","san.dns":["www.taosecurity.com","*.taosecurity.com"],"san.ip":["13.32.202.10"],"basic_constraints.ca":false}`
+
+	expectedTime := time.Date(2020, 6, 5, 14, 39, 59, 750000000, time.UTC)
+	notValidBefore := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	notValidAfter := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	expectedEvent := &ZeekX509{
+		Ts:                        (*timestamp.UnixFloat)(&expectedTime),
+		ID:                        aws.String("F2XEvj1CahhdhtfvT4"),
+		CertificateVersion:        aws.Int(3),
+		CertificateSerial:         aws.String("0A7F3E2B7B5C1E52E2F4F3B1C1B6E6A1"),
+		CertificateSubject:        aws.String("CN=www.taosecurity.com"),
+		CertificateIssuer:         aws.String("CN=Amazon,OU=Server CA 1B,O=Amazon,C=US"),
+		CertificateNotValidBefore: (*timestamp.UnixFloat)(&notValidBefore),
+		CertificateNotValidAfter:  (*timestamp.UnixFloat)(&notValidAfter),
+		CertificateKeyAlg:         aws.String("rsaEncryption"),
+		CertificateSigAlg:         aws.String("sha256WithRSAEncryption"),
+		CertificateKeyType:        aws.String("rsa"),
+		CertificateKeyLength:      aws.Int(2048),
+		CertificateExponent:       aws.String("65537"),
+		SANDNS:                    []string{"www.taosecurity.com", "*.taosecurity.com"},
+		SANIP:                     []string{"13.32.202.10"},
+		BasicConstraintsCA:        aws.Bool(false),
+	}
+
+	// panther fields
+	expectedEvent.PantherLogType = aws.String("Zeek.X509")
+	expectedEvent.AppendAnyIPAddress("13.32.202.10")
+	expectedEvent.AppendAnyDomainNames("www.taosecurity.com", "taosecurity.com")
+	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	checkZeekX509(t, log, expectedEvent)
+}
+
+func TestZeekX509Type(t *testing.T) {
+	parser := &ZeekX509Parser{}
+	require.Equal(t, "Zeek.X509", parser.LogType())
+}
+
+func checkZeekX509(t *testing.T, log string, expectedEvent *ZeekX509) {
+	expectedEvent.SetEvent(expectedEvent)
+	parser := &ZeekX509Parser{}
+	logs, err := parser.Parse(log)
+	testutil.EqualPantherLog(t, expectedEvent.Log(), logs, err)
+}
@@ -0,0 +1,63 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// The logs of the different Zeek types share fields (e.g., ts, uid and id.*), each log must be classified as only its type
+func TestZeekLogTypesAreExclusive(t *testing.T) {
+	zeekParsers := []parsers.LogParser{
+		&ZeekConnParser{},
+		&ZeekDNSParser{},
+		&ZeekFilesParser{},
+		&ZeekHTTPParser{},
+		&ZeekNoticeParser{},
+		&ZeekSSLParser{},
+		&ZeekWeirdParser{},
+		&ZeekX509Parser{},
+	}
+	// nolint:lll
+	samples := map[string]string{
+		"Zeek.Conn":   `{"ts":1591367999.5,"uid":"CMdzit1AMNsmfAIiQc","id.orig_h":"192.168.4.76","id.orig_p":36844,"id.resp_h":"192.168.4.1","id.resp_p":53,"proto":"udp","service":"dns","duration":0.25,"orig_bytes":62,"resp_bytes":141,"conn_state":"SF","missed_bytes":0,"history":"Dd","orig_pkts":2,"orig_ip_bytes":118,"resp_pkts":2,"resp_ip_bytes":197,"tunnel_parents":[],"orig_l2_addr":"00:0c:29:8e:6a:1f","resp_l2_addr":"f0:9f:c2:1a:2b:3c"}`,
+		"Zeek.DNS":    `{"ts":1541001600.580233,"uid":"CpR9AY39cUCZ0t5qq6","id.orig_h":"172.16.2.16","id.orig_p":43720,"id.resp_h":"172.16.0.2","id.resp_p":53,"proto":"udp","trans_id":27282,"query":"16.2.16.172.in-addr.arpa", "qtype":1,"rcode":0,"rcode_name":"NOERROR","AA":false,"TC":false,"RD":false,"RA":true,"Z":0,"answers":["ip-172-16-2-16.us-west-2.compute.internal"],"TTLs":[60.0],"rejected":false}`,
+		"Zeek.Files":  `{"ts":1591367999.5,"fuid":"FEEsZS1w0Z0VJIb5x4","tx_hosts":["31.3.245.133"],"rx_hosts":["192.168.4.76"],"conn_uids":["CHhAvVGS1DHFjwGM9"],"source":"HTTP","depth":0,"analyzers":["MD5","SHA1","SHA256"],"mime_type":"text/plain","duration":0.0,"is_orig":false,"seen_bytes":39,"total_bytes":39,"missing_bytes":0,"overflow_bytes":0,"timedout":false,"md5":"2ab1d4d1a0be0ee7d23b3cbc2d1b0a7e","sha1":"33bf88d5b82df3723d5863c7d23445e345828904","sha256":"2b2a1a77b9e2bd43b5bd1d1c9a8e7e7b4a2f1a4c1b7e3c3d5f4e2a1b0c9d8e7f"}`,
+		"Zeek.HTTP":   `{"ts":1591367999.25,"uid":"CHhAvVGS1DHFjwGM9","id.orig_h":"192.168.4.76","id.orig_p":46378,"id.resp_h":"31.3.245.133","id.resp_p":80,"trans_depth":1,"method":"GET","host":"testmyids.com:80","uri":"/","version":"1.1","user_agent":"curl/7.47.0","request_body_len":0,"response_body_len":39,"status_code":200,"status_msg":"OK","tags":[],"resp_fuids":["FEEsZS1w0Z0VJIb5x4"],"resp_mime_types":["text/plain"]}`,
+		"Zeek.Notice": `{"ts":1591367999.5,"uid":"CsukF91Bx9mrqdEaH9","id.orig_h":"192.168.4.49","id.orig_p":56718,"id.resp_h":"13.32.202.10","id.resp_p":443,"proto":"tcp","note":"SSL::Invalid_Server_Cert","msg":"SSL certificate validation failed with (unable to get local issuer certificate)","sub":"CN=www.taosecurity.com","src":"192.168.4.49","dst":"13.32.202.10","p":443,"peer_descr":"worker-1","actions":["Notice::ACTION_LOG"],"suppress_for":3600.0,"remote_location.country_code":"US","dropped":false}`,
+		"Zeek.SSL":    `{"ts":1591367999.75,"uid":"CsukF91Bx9mrqdEaH9","id.orig_h":"192.168.4.49","id.orig_p":56718,"id.resp_h":"13.32.202.10","id.resp_p":443,"version":"TLSv12","cipher":"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256","curve":"secp256r1","server_name":"www.taosecurity.com","resumed":false,"next_protocol":"h2","established":true,"cert_chain_fuids":["F2XEvj1CahhdhtfvT4","FZ7ygD3ERPfEVVohG9"],"client_cert_chain_fuids":[],"subject":"CN=www.taosecurity.com","issuer":"CN=Amazon,OU=Server CA 1B,O=Amazon,C=US","validation_status":"ok"}`,
+		"Zeek.Weird":  `{"ts":1591367999.25,"uid":"CQ5MUu3f4XtFBT1Tjg","id.orig_h":"192.168.4.76","id.orig_p":53378,"id.resp_h":"192.168.4.1","id.resp_p":53,"name":"dns_unmatched_reply","notice":false,"peer":"worker-1","source":"DNS"}`,
+		"Zeek.X509":   `{"ts":1591367999.75,"id":"F2XEvj1CahhdhtfvT4","certificate.version":3,"certificate.serial":"0A7F3E2B7B5C1E52E2F4F3B1C1B6E6A1","certificate.subject":"CN=www.taosecurity.com","certificate.issuer":"CN=Amazon,OU=Server CA 1B,O=Amazon,C=US","certificate.not_valid_before":1588291200.0,"certificate.not_valid_after":1622505600.0,"certificate.key_alg":"rsaEncryption","certificate.sig_alg":"sha256WithRSAEncryption","certificate.key_type":"rsa","certificate.key_length":2048,"certificate.exponent":"65537","san.dns":["www.taosecurity.com","*.taosecurity.com"],"san.ip":["13.32.202.10"],"basic_constraints.ca":false}`,
+	}
+	require.Len(t, samples, len(zeekParsers))
+
+	for logType, log := range samples {
+		var matches []string
+		for _, parser := range zeekParsers {
+			if _, err := parser.New().Parse(log); err == nil {
+				matches = append(matches, parser.LogType())
+			}
+		}
+		require.Equal(t, []string{logType}, matches, log)
+	}
+}
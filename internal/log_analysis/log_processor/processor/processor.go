@@ -21,16 +21,27 @@ package processor
 import (
 	"bufio"
 	"io"
+	"regexp"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/geoip"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/multiline"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/redaction"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/threatintel"
 	"github.com/panther-labs/panther/pkg/oplog"
 )
 
@@ -38,9 +49,24 @@ const (
 	// oplog keys
 	operationName = "parse"
 	statsKey      = "stats"
+
+	// JSON documents with all events in a top level array (e.g., CloudTrail) are streamed one record at a time
+	recordsField = "Records"
+	// how much of the stream we look at to detect a records document
+	recordsEnvelopePeekSize = 64
+	// the size of the read buffer of the streaming JSON decoder
+	recordsStreamBufferSize = 64 * 1024
+
+	// CloudWatch Logs subscriptions deliver batches of log events wrapped in an envelope
+	cloudWatchLogsMessageTypeField = "messageType"
+	// envelopes with other message types (i.e., CONTROL_MESSAGE) do not carry log events
+	cloudWatchLogsDataMessage = "DATA_MESSAGE"
 )
 
 var (
+	recordsEnvelopeRegex        = regexp.MustCompile(`^\s*\{\s*"` + recordsField + `"\s*:\s*\[`)
+	cloudWatchLogsEnvelopeRegex = regexp.MustCompile(`^\s*\{\s*"` + cloudWatchLogsMessageTypeField + `"\s*:`)
+
 	// ParsedEventBufferSize is the size of the buffer of the Go channel containing the parsed events.
 	// Since there are different goroutines writing and reading from that channel each with different I/O characteristics,
 	// we are specifying this buffer to avoid blocking the goroutines that write to the channel if the reader goroutine is
@@ -48,8 +74,33 @@ var (
 	// to avoid using up lot of memory.
 	// see also: https://golang.org/doc/effective_go.html#channels
 	ParsedEventBufferSize = 1000
+
+	// enriches the events with the geolocation of their ip addresses, nil unless EnableGeoIP() is called
+	geoIPEnricher *geoip.Enricher
+	// matches the events against threat intel indicators, set by EnableThreatIntel()
+	threatIntelMatcher *threatintel.Matcher
+	// applies the redaction rules of the log types to their events, set by EnableRedaction()
+	redactor *redaction.Redactor
 )
 
+// EnableGeoIP enriches the parsed events with the geolocation and autonomous system of their public ip addresses.
+// It is not safe for concurrent use, call it before processing any data.
+func EnableGeoIP(enricher *geoip.Enricher) {
+	geoIPEnricher = enricher
+}
+
+// EnableThreatIntel adds the threat intel indicators found in the Panther fields of the parsed events to p_ioc_matches.
+// It is not safe for concurrent use, call it between invocations to replace the indicators.
+func EnableThreatIntel(matcher *threatintel.Matcher) {
+	threatIntelMatcher = matcher
+}
+
+// EnableRedaction applies the redaction rules of the log types to the parsed events before they are stored.
+// It is not safe for concurrent use, call it between invocations to replace the rules.
+func EnableRedaction(r *redaction.Redactor) {
+	redactor = r
+}
+
 // Process orchestrates the tasks of parsing logs, classification, normalization
 // and forwarding the logs to the appropriate destination. Any errors will cause Lambda invocation to fail
 func Process(dataStreams chan *common.DataStream, destination destinations.Destination) error {
@@ -82,13 +133,25 @@ func process(dataStreams chan *common.DataStream, destination destinations.Desti
 	}()
 
 	// it is important to process the streams serially to manage memory!
+	stats := newSourceStats()
 	for dataStream := range dataStreams {
 		processor := newProcessorFunc(dataStream)
 		err := processor.run(parsedEventChannel)
+		closeDataStream(dataStream)
+		// data found to be unsupported while it is read fails the same way when retried, it would block the whole batch
+		if err != nil && errors.Cause(err) == sources.ErrUnsupportedData {
+			zap.L().Warn("skipping the rest of unsupported data", zap.Any("hints", dataStream.Hints), zap.Error(err))
+			err = nil
+		}
 		if err != nil {
 			errorChannel <- err
 			break
 		}
+		stats.add(dataStream, processor.classifier, time.Now())
+	}
+	// on failure the streams left are not processed, they are closed so the temporary files they read are released
+	for dataStream := range dataStreams {
+		closeDataStream(dataStream)
 	}
 
 	// Close the channel after all goroutines have finished writing to it.
@@ -100,16 +163,50 @@ func process(dataStreams chan *common.DataStream, destination destinations.Desti
 	errorsWg.Wait()           // wait for err chan loop to finish
 	zap.L().Debug("data processing goroutines finished")
 
+	if err == nil { // on failure the data is processed again
+		stats.store(common.LambdaClient)
+	}
 	return err
 }
 
+func closeDataStream(dataStream *common.DataStream) {
+	if err := dataStream.Close(); err != nil {
+		zap.L().Warn("failed to close data stream", zap.Error(err))
+	}
+}
+
 // processStream reads the data from an S3 the dataStream, parses it and writes events to the output channel
 func (p *Processor) run(outputChan chan *parsers.PantherLog) error {
 	var err error
 	stream := bufio.NewReader(p.input.Reader)
+	if isRecordsDocument(stream) {
+		err = p.processRecords(stream, outputChan)
+	} else if isCloudWatchLogsDocument(stream) {
+		err = p.processCloudWatchLogs(stream, outputChan)
+	} else {
+		err = p.processLines(stream, outputChan)
+	}
+	p.logStats(err) // emit log line describing the processing of the file and any errors
+	return err
+}
+
+// processLines classifies each line of the stream, or each event joined from multiple lines if configured
+func (p *Processor) processLines(stream *bufio.Reader, outputChan chan *parsers.PantherLog) (err error) {
+	readEvent := func() (string, error) {
+		return stream.ReadString(common.EventDelimiter)
+	}
+	var multilineReader *multiline.Reader
+	if config := p.multilineConfig(); config != nil {
+		multilineReader, err = multiline.NewReader(stream, config)
+		if err != nil { // configs are validated by the source API, in case one is not, read the lines as they are
+			p.operation.LogWarn(errors.Wrap(err, "invalid multiline configuration"))
+		} else {
+			readEvent = multilineReader.ReadEvent
+		}
+	}
 	for {
 		var line string
-		line, err = stream.ReadString(common.EventDelimiter)
+		line, err = readEvent()
 		if err != nil {
 			if err == io.EOF { // we are done
 				err = nil // not really an error
@@ -122,18 +219,162 @@ func (p *Processor) run(outputChan chan *parsers.PantherLog) error {
 	if err != nil {
 		err = errors.Wrap(err, "failed to ReadString()")
 	}
-	p.logStats(err) // emit log line describing the processing of the file and any errors
+	if multilineReader != nil && multilineReader.Truncated() > 0 {
+		p.operation.LogWarn(errors.New("truncated events larger than the multiline size limit"),
+			zap.Uint64("truncatedEvents", multilineReader.Truncated()))
+	}
 	return err
 }
 
+// multilineConfig returns how the lines of the stream are joined into events, nil if each line is an event.
+// The configuration of the source takes precedence, the one of the log type applies only if the data
+// can be of just that log type.
+func (p *Processor) multilineConfig() *sourcemodels.MultilineConfig {
+	if p.input.Multiline != nil {
+		return p.input.Multiline
+	}
+	var logType string
+	switch {
+	case p.input.LogType != nil:
+		logType = *p.input.LogType
+	case len(p.input.LogTypes) == 1:
+		logType = p.input.LogTypes[0]
+	default:
+		return nil
+	}
+	if lpm, found := registry.AvailableParsers()[logType]; found {
+		return lpm.Multiline
+	}
+	return nil
+}
+
+// processRecords decodes a `{"Records":[...]}` document one record at a time so memory use is bounded
+// by the size of a single record rather than the whole file. Each record is classified as a document
+// containing just that record, so parsers see the same structure as the original file.
+func (p *Processor) processRecords(stream io.Reader, outputChan chan *parsers.PantherLog) error {
+	iter := jsoniter.Parse(jsoniter.ConfigDefault, stream, recordsStreamBufferSize)
+	next := iter.WhatIsNext()
+	for ; next == jsoniter.ObjectValue; next = iter.WhatIsNext() { // multiple documents, e.g. one per line, are allowed
+		for field := iter.ReadObject(); field != ""; field = iter.ReadObject() {
+			if field != recordsField {
+				iter.Skip()
+				continue
+			}
+			for iter.ReadArray() {
+				record := iter.SkipAndReturnBytes()
+				if iter.Error != nil {
+					break
+				}
+				p.processLogLine(`{"`+recordsField+`":[`+string(record)+`]}`, outputChan)
+			}
+		}
+		if iter.Error != nil {
+			break
+		}
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return errors.Wrap(iter.Error, "failed to decode records")
+	}
+	if next != jsoniter.InvalidValue { // the stream must end after the last document
+		return errors.New("failed to decode records: unexpected data after document")
+	}
+	return nil
+}
+
+// isRecordsDocument peeks at the start of the stream to detect a `{"Records":[...]}` document
+func isRecordsDocument(stream *bufio.Reader) bool {
+	start, _ := stream.Peek(recordsEnvelopePeekSize) // returns what is available on errors (e.g., short files)
+	return recordsEnvelopeRegex.Match(start)
+}
+
+// cloudWatchLogsEnvelope is the payload of a CloudWatch Logs subscription
+// See https://docs.aws.amazon.com/AmazonCloudWatch/latest/logs/SubscriptionFilters.html
+type cloudWatchLogsEnvelope struct {
+	MessageType string `json:"messageType"`
+	Owner       string `json:"owner"`
+	LogGroup    string `json:"logGroup"`
+	LogStream   string `json:"logStream"`
+	LogEvents   []struct {
+		Message string `json:"message"`
+	} `json:"logEvents"`
+}
+
+// processCloudWatchLogs unwraps the log events of CloudWatch Logs subscription envelopes and classifies each message.
+// Firehose concatenates the envelopes of a delivery so there can be many documents, not necessarily separated by new lines.
+func (p *Processor) processCloudWatchLogs(stream io.Reader, outputChan chan *parsers.PantherLog) error {
+	iter := jsoniter.Parse(jsoniter.ConfigDefault, stream, recordsStreamBufferSize)
+	next := iter.WhatIsNext()
+	for ; next == jsoniter.ObjectValue; next = iter.WhatIsNext() {
+		envelope := &cloudWatchLogsEnvelope{}
+		iter.ReadVal(envelope)
+		if iter.Error != nil {
+			break
+		}
+		if envelope.MessageType != cloudWatchLogsDataMessage {
+			continue
+		}
+		p.envelope = envelope
+		for _, logEvent := range envelope.LogEvents {
+			p.processLogLine(logEvent.Message, outputChan)
+		}
+		p.envelope = nil
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return errors.Wrap(iter.Error, "failed to decode CloudWatch Logs envelope")
+	}
+	if next != jsoniter.InvalidValue { // the stream must end after the last document
+		return errors.New("failed to decode CloudWatch Logs envelope: unexpected data after document")
+	}
+	return nil
+}
+
+// isCloudWatchLogsDocument peeks at the start of the stream to detect a CloudWatch Logs subscription envelope
+func isCloudWatchLogsDocument(stream *bufio.Reader) bool {
+	start, _ := stream.Peek(recordsEnvelopePeekSize) // returns what is available on errors (e.g., short files)
+	return cloudWatchLogsEnvelopeRegex.Match(start)
+}
+
 func (p *Processor) processLogLine(line string, outputChan chan *parsers.PantherLog) {
 	classificationResult := p.classifyLogLine(line)
+	if len(classificationResult.ParserPanics) > 0 ||
+		(classificationResult.LogType == nil && len(strings.TrimSpace(line)) != 0) {
+
+		// the redaction rules apply to the fields of the events, a line that failed to parse cannot be redacted
+		if !p.dropsFailures() {
+			outputChan <- p.newFailure(line, classificationResult).Log() // store so we can see what we drop and replay it
+		}
+	}
 	if classificationResult.LogType == nil { // unable to classify, no error, keep parsing (best effort, will be logged)
 		return
 	}
 	p.sendEvents(classificationResult, outputChan)
 }
 
+func (p *Processor) newFailure(line string, result *classification.ClassifierResult) *classification.Failure {
+	failure := classification.NewFailure(strings.TrimSpace(line), p.classifier.Stats().LogLineCount, result)
+	if p.input.SourceID != "" {
+		failure.SourceID = &p.input.SourceID
+		failure.SourceLabel = &p.input.SourceLabel
+	}
+	if p.input.Hints.S3 != nil {
+		failure.S3Bucket = &p.input.Hints.S3.Bucket
+		failure.S3Key = &p.input.Hints.S3.Key
+	}
+	return failure
+}
+
+// dropsFailures returns true if the data can be of log types with redaction rules, the lines that fail
+// classification are not stored since they may hold the values the rules remove
+func (p *Processor) dropsFailures() bool {
+	if redactor == nil {
+		return false
+	}
+	if p.input.LogType != nil {
+		return redactor.Applies([]string{*p.input.LogType})
+	}
+	return redactor.Applies(p.input.LogTypes)
+}
+
 func (p *Processor) classifyLogLine(line string) *classification.ClassifierResult {
 	result := p.classifier.Classify(line)
 	if result.LogType == nil && len(strings.TrimSpace(line)) != 0 { // only if line is not empty do we log (often we get trailing \n's)
@@ -149,10 +390,40 @@ func (p *Processor) classifyLogLine(line string) *classification.ClassifierResul
 
 func (p *Processor) sendEvents(result *classification.ClassifierResult, outputChan chan *parsers.PantherLog) {
 	for _, event := range result.Events {
+		if p.envelope != nil { // attach where the event came from
+			event.PantherCloudWatchLogGroup = &p.envelope.LogGroup
+			event.PantherCloudWatchLogStream = &p.envelope.LogStream
+			event.PantherCloudWatchOwner = &p.envelope.Owner
+		}
+		if geoIPEnricher != nil {
+			geoIPEnricher.Enrich(event)
+		}
+		if threatIntelMatcher != nil {
+			threatIntelMatcher.Match(event)
+		}
+		if redactor != nil && !p.redact(event) {
+			continue
+		}
 		outputChan <- event
 	}
 }
 
+// redact applies the redaction rules of the log type of the event, it returns false if the event cannot be stored
+func (p *Processor) redact(event *parsers.PantherLog) bool {
+	count, err := redactor.Redact(event)
+	if err != nil { // never store an event without its rules applied
+		p.operation.LogError(err, zap.String("logType", aws.StringValue(event.PantherLogType)))
+		return false
+	}
+	if count > 0 {
+		p.classifier.Stats().RedactedValueCount += uint64(count)
+		if parserStats, ok := p.classifier.ParserStats()[aws.StringValue(event.PantherLogType)]; ok {
+			parserStats.RedactedValueCount += uint64(count)
+		}
+	}
+	return true
+}
+
 func (p *Processor) logStats(err error) {
 	p.operation.Stop()
 	p.operation.Log(err, zap.Any(statsKey, *p.classifier.Stats()))
@@ -165,12 +436,13 @@ type Processor struct {
 	input      *common.DataStream
 	classifier classification.ClassifierAPI
 	operation  *oplog.Operation
+	envelope   *cloudWatchLogsEnvelope // set while the log events of a CloudWatch Logs envelope are processed
 }
 
 func NewProcessor(input *common.DataStream) *Processor {
 	return &Processor{
 		input:      input,
-		classifier: classification.NewClassifier(),
+		classifier: classification.NewClassifierForLogTypes(input.LogTypes),
 		operation:  common.OpLogManager.Start(operationName),
 	}
 }
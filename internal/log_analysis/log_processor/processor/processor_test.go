@@ -19,11 +19,17 @@ package processor
  */
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -32,12 +38,18 @@ import (
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/geoip"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/redaction"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/threatintel"
 	"github.com/panther-labs/panther/pkg/oplog"
+	"github.com/panther-labs/panther/pkg/testutils"
 )
 
 var (
@@ -163,6 +175,96 @@ func TestProcessDataStreamError(t *testing.T) {
 	assertLogEqual(t, expectedLog, actualLog)
 }
 
+func TestProcessClosesDataStreams(t *testing.T) {
+	destination := (&testDestination{}).standardMock()
+
+	// the first stream fails, the second one is never processed
+	var closed []int
+	badDataStream := makeBadDataStream()
+	badDataStream.Closer = closerFunc(func() error {
+		closed = append(closed, 1)
+		return nil
+	})
+	dataStream := makeDataStream()
+	dataStream.Closer = closerFunc(func() error {
+		closed = append(closed, 2)
+		return nil
+	})
+	p := NewProcessor(badDataStream)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	mockClassifier.standardMocks(&classification.ClassifierStats{}, map[string]*classification.ParserStats{})
+
+	newProcessorFunc := func(*common.DataStream) *Processor { return p }
+	streamChan := make(chan *common.DataStream, 2)
+	streamChan <- badDataStream
+	streamChan <- dataStream
+	close(streamChan)
+	err := process(streamChan, destination, newProcessorFunc)
+	require.Error(t, err)
+	require.Equal(t, []int{1, 2}, closed)
+}
+
+func TestProcessSkipsUnsupportedArchiveFiles(t *testing.T) {
+	var inner bytes.Buffer
+	zipWriter := zip.NewWriter(&inner)
+	zipFile, err := zipWriter.Create("inner.log")
+	require.NoError(t, err)
+	_, err = zipFile.Write([]byte(testLogLine))
+	require.NoError(t, err)
+	require.NoError(t, zipWriter.Close())
+
+	// the nested archive fails when it is read, the file after it is still processed
+	var archive bytes.Buffer
+	tarWriter := tar.NewWriter(&archive)
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{"inner.zip", inner.Bytes()},
+		{"a.log", []byte(testLogLine)},
+	} {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: file.name, Mode: 0600, Size: int64(len(file.data))}))
+		_, err = tarWriter.Write(file.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+
+	s3Mock := &testutils.S3Mock{}
+	s3Mock.On("GetObject", mock.Anything).Return(&s3.GetObjectOutput{Body: ioutil.NopCloser(&archive)}, nil).Once()
+	dataStreams, err := sources.ReadS3Object(s3Mock, &sources.S3ObjectInfo{S3Bucket: testBucket, S3ObjectKey: testKey})
+	require.NoError(t, err)
+	require.Len(t, dataStreams, 2)
+
+	newProcessorFunc := func(dataStream *common.DataStream) *Processor {
+		p := NewProcessor(dataStream)
+		mockClassifier := &testClassifier{}
+		p.classifier = mockClassifier
+		mockClassifier.On("Classify", testLogLine).Return(&classification.ClassifierResult{
+			Events:  []*parsers.PantherLog{newTestLog()},
+			LogType: &testLogType,
+		})
+		mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{})
+		mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{})
+		return p
+	}
+	destination := (&testDestination{}).standardMock()
+	streamChan := make(chan *common.DataStream, len(dataStreams))
+	for _, dataStream := range dataStreams {
+		streamChan <- dataStream
+	}
+	close(streamChan)
+	require.NoError(t, process(streamChan, destination, newProcessorFunc))
+	assert.Equal(t, uint64(1), destination.nEvents)
+	s3Mock.AssertExpectations(t)
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
 func TestProcessDataStreamErrorNoChannelBuffers(t *testing.T) {
 	ParsedEventBufferSize = 0 // ensure we work when event channel is blocking
 	TestProcessDataStreamError(t)
@@ -438,3 +540,303 @@ func mockLogger() *observer.ObservedLogs {
 	zap.ReplaceGlobals(zap.New(core))
 	return mockLog
 }
+
+func TestProcessRecordsDocument(t *testing.T) {
+	records := `{"Records":[{"eventID":"1"},{"eventID":"2","nested":{"Records":[]}}],"other":"ignored"}
+ { "Records" : [ {"eventID":"3"} ] }
+`
+	dataStream := &common.DataStream{
+		Reader: strings.NewReader(records),
+		Hints:  common.DataStreamHints{S3: s3Hint},
+	}
+	p := NewProcessor(dataStream)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	for _, expected := range []string{
+		`{"Records":[{"eventID":"1"}]}`,
+		`{"Records":[{"eventID":"2","nested":{"Records":[]}}]}`,
+		`{"Records":[{"eventID":"3"}]}`,
+	} {
+		mockClassifier.On("Classify", expected).Return(&classification.ClassifierResult{
+			Events:  []*parsers.PantherLog{newTestLog()},
+			LogType: &testLogType,
+		}).Once()
+	}
+	mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{})
+	mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{})
+
+	outputChan := make(chan *parsers.PantherLog, 10)
+	require.NoError(t, p.run(outputChan))
+	assert.Len(t, outputChan, 3)
+	mockClassifier.AssertExpectations(t)
+}
+
+func TestProcessRecordsDocumentTruncated(t *testing.T) {
+	dataStream := &common.DataStream{
+		Reader: strings.NewReader(`{"Records":[{"eventID":"1"},{"eventID":`),
+		Hints:  common.DataStreamHints{S3: s3Hint},
+	}
+	p := NewProcessor(dataStream)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	mockClassifier.On("Classify", `{"Records":[{"eventID":"1"}]}`).Return(&classification.ClassifierResult{
+		Events:  []*parsers.PantherLog{newTestLog()},
+		LogType: &testLogType,
+	}).Once()
+	mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{})
+	mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{})
+
+	outputChan := make(chan *parsers.PantherLog, 10)
+	require.Error(t, p.run(outputChan))
+	assert.Len(t, outputChan, 1)
+	mockClassifier.AssertExpectations(t)
+}
+
+func TestProcessMultiline(t *testing.T) {
+	lines := "2020-06-01 ERROR failed\n  at main.go:10\n  at main.go:20\n2020-06-01 INFO done\n"
+	dataStream := &common.DataStream{
+		Reader:    strings.NewReader(lines),
+		Hints:     common.DataStreamHints{S3: s3Hint},
+		Multiline: &sourcemodels.MultilineConfig{StartPattern: aws.String(`^\d{4}-`)},
+	}
+	p := NewProcessor(dataStream)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	for _, expected := range []string{
+		"2020-06-01 ERROR failed\n  at main.go:10\n  at main.go:20\n",
+		"2020-06-01 INFO done\n",
+	} {
+		mockClassifier.On("Classify", expected).Return(&classification.ClassifierResult{
+			Events:  []*parsers.PantherLog{newTestLog()},
+			LogType: &testLogType,
+		}).Once()
+	}
+	mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{})
+	mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{})
+
+	outputChan := make(chan *parsers.PantherLog, 10)
+	require.NoError(t, p.run(outputChan))
+	assert.Len(t, outputChan, 2)
+	mockClassifier.AssertExpectations(t)
+}
+
+func TestProcessCloudWatchLogsEnvelope(t *testing.T) {
+	// Firehose concatenates envelopes without a delimiter, control messages carry no log events
+	//nolint:lll
+	envelopes := `{"messageType":"CONTROL_MESSAGE","owner":"CloudwatchLogs","logGroup":"","logStream":"","subscriptionFilters":[],"logEvents":[{"id":"","timestamp":1432826855000,"message":"CWL CONTROL MESSAGE: Checking health of destination Firehose."}]}` +
+		`{"messageType":"DATA_MESSAGE","owner":"123456789012","logGroup":"/aws/eks/cluster/audit","logStream":"kube-apiserver-audit","subscriptionFilters":["panther"],"logEvents":[{"id":"1","timestamp":1432826855000,"message":"{\"event\":1}"},{"id":"2","timestamp":1432826855001,"message":"{\"event\":2}"}]}`
+	dataStream := &common.DataStream{
+		Reader: strings.NewReader(envelopes),
+		Hints:  common.DataStreamHints{S3: s3Hint},
+	}
+	p := NewProcessor(dataStream)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	for _, expected := range []string{`{"event":1}`, `{"event":2}`} {
+		mockClassifier.On("Classify", expected).Return(&classification.ClassifierResult{
+			Events:  []*parsers.PantherLog{newTestLog()},
+			LogType: &testLogType,
+		}).Once()
+	}
+	mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{})
+	mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{})
+
+	outputChan := make(chan *parsers.PantherLog, 10)
+	require.NoError(t, p.run(outputChan))
+	require.Len(t, outputChan, 2)
+	mockClassifier.AssertExpectations(t)
+	for i := 0; i < 2; i++ {
+		event := <-outputChan
+		assert.Equal(t, "/aws/eks/cluster/audit", *event.PantherCloudWatchLogGroup)
+		assert.Equal(t, "kube-apiserver-audit", *event.PantherCloudWatchLogStream)
+		assert.Equal(t, "123456789012", *event.PantherCloudWatchOwner)
+	}
+}
+
+func TestProcessGeoIP(t *testing.T) {
+	enricher, err := geoip.Open("../geoip/testdata/GeoLite2-City-Test.mmdb", "../geoip/testdata/GeoLite2-ASN-Test.mmdb")
+	require.NoError(t, err)
+	EnableGeoIP(enricher)
+	defer EnableGeoIP(nil)
+
+	dataStream := &common.DataStream{
+		Reader: strings.NewReader(testLogLine),
+		Hints:  common.DataStreamHints{S3: s3Hint},
+	}
+	p := NewProcessor(dataStream)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	event := newTestLog()
+	event.AppendAnyIPAddress("81.2.69.160")
+	mockClassifier.On("Classify", testLogLine).Return(&classification.ClassifierResult{
+		Events:  []*parsers.PantherLog{event},
+		LogType: &testLogType,
+	}).Once()
+	mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{})
+	mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{})
+
+	outputChan := make(chan *parsers.PantherLog, 10)
+	require.NoError(t, p.run(outputChan))
+	require.Len(t, outputChan, 1)
+	mockClassifier.AssertExpectations(t)
+	event = <-outputChan
+	assert.Equal(t, []string{"GB"}, event.PantherAnyIPCountries.Values())
+	assert.Equal(t, []string{"20712"}, event.PantherAnyIPASNs.Values())
+	require.Len(t, event.PantherIPGeolocations, 1)
+}
+
+func TestProcessThreatIntel(t *testing.T) {
+	EnableThreatIntel(threatintel.NewMatcher([]*sourcemodels.ThreatIntelIndicator{
+		{Feed: aws.String("tor"), Type: aws.String(sourcemodels.ThreatIntelIndicatorIP), Value: aws.String("192.0.2.1")},
+	}))
+	defer EnableThreatIntel(nil)
+
+	dataStream := &common.DataStream{
+		Reader: strings.NewReader(testLogLine),
+		Hints:  common.DataStreamHints{S3: s3Hint},
+	}
+	p := NewProcessor(dataStream)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	event := newTestLog()
+	event.AppendAnyIPAddress("192.0.2.1")
+	mockClassifier.On("Classify", testLogLine).Return(&classification.ClassifierResult{
+		Events:  []*parsers.PantherLog{event},
+		LogType: &testLogType,
+	}).Once()
+	mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{})
+	mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{})
+
+	outputChan := make(chan *parsers.PantherLog, 10)
+	require.NoError(t, p.run(outputChan))
+	require.Len(t, outputChan, 1)
+	mockClassifier.AssertExpectations(t)
+	event = <-outputChan
+	require.Len(t, event.PantherIOCMatches, 1)
+	assert.Equal(t, "tor", *event.PantherIOCMatches[0].Feed)
+}
+
+func TestProcessRedaction(t *testing.T) {
+	r, err := redaction.NewRedactor([]*sourcemodels.RedactionRules{
+		{
+			LogType: aws.String(testLogType),
+			Rules: []*sourcemodels.RedactionRule{
+				{Field: aws.String("p_any_ip_addresses"), Action: aws.String(sourcemodels.RedactionDrop)},
+			},
+		},
+	})
+	require.NoError(t, err)
+	EnableRedaction(r)
+	defer EnableRedaction(nil)
+
+	dataStream := &common.DataStream{
+		Reader: strings.NewReader(testLogLine),
+		Hints:  common.DataStreamHints{S3: s3Hint},
+	}
+	p := NewProcessor(dataStream)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	event := newTestLog()
+	event.AppendAnyIPAddress("192.0.2.1")
+	mockClassifier.On("Classify", testLogLine).Return(&classification.ClassifierResult{
+		Events:  []*parsers.PantherLog{event},
+		LogType: &testLogType,
+	}).Once()
+	stats := &classification.ClassifierStats{}
+	mockClassifier.On("Stats", mock.Anything).Return(stats)
+	parserStats := &classification.ParserStats{LogType: testLogType}
+	mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{testLogType: parserStats})
+
+	outputChan := make(chan *parsers.PantherLog, 10)
+	require.NoError(t, p.run(outputChan))
+	require.Len(t, outputChan, 1)
+	mockClassifier.AssertExpectations(t)
+	event = <-outputChan
+	data, err := parsers.JSON.Marshal(event.Event())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "192.0.2.1")
+	assert.Contains(t, string(data), `"p_log_type":"`+testLogType+`"`)
+	assert.Equal(t, uint64(1), stats.RedactedValueCount)
+	assert.Equal(t, uint64(1), parserStats.RedactedValueCount)
+}
+
+func TestProcessCloudWatchLogsEnvelopeTruncated(t *testing.T) {
+	dataStream := &common.DataStream{
+		Reader: strings.NewReader(`{"messageType":"DATA_MESSAGE","owner":"123456789012","logEvents":[{"id":"1","message":`),
+		Hints:  common.DataStreamHints{S3: s3Hint},
+	}
+	p := NewProcessor(dataStream)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{})
+	mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{})
+
+	outputChan := make(chan *parsers.PantherLog, 10)
+	require.Error(t, p.run(outputChan))
+	assert.Len(t, outputChan, 0)
+	mockClassifier.AssertExpectations(t)
+}
+
+func TestProcessClassifyFailureStored(t *testing.T) {
+	dataStream := &common.DataStream{
+		Reader:      strings.NewReader("bad line\n\n"),
+		Hints:       common.DataStreamHints{S3: s3Hint},
+		SourceID:    "testSourceId",
+		SourceLabel: "testSourceLabel",
+	}
+	p := NewProcessor(dataStream)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	mockClassifier.On("Classify", mock.Anything).Return(&classification.ClassifierResult{
+		ParsersTried: []string{testLogType},
+	})
+	mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{LogLineCount: 1})
+	mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{})
+
+	outputChan := make(chan *parsers.PantherLog, 10)
+	require.NoError(t, p.run(outputChan))
+	require.Len(t, outputChan, 1) // blank lines are not stored
+
+	event := <-outputChan
+	assert.Equal(t, classification.FailureLogType, *event.PantherLogType)
+	failure := event.Event().(*classification.Failure)
+	assert.Equal(t, "bad line", *failure.Line)
+	assert.Equal(t, uint64(1), *failure.LineNum)
+	assert.Equal(t, "testSourceId", *failure.SourceID)
+	assert.Equal(t, "testSourceLabel", *failure.SourceLabel)
+	assert.Equal(t, testBucket, *failure.S3Bucket)
+	assert.Equal(t, testKey, *failure.S3Key)
+	assert.Equal(t, []string{testLogType}, failure.ParsersTried)
+}
+
+func TestProcessClassifyFailureRedacted(t *testing.T) {
+	r, err := redaction.NewRedactor([]*sourcemodels.RedactionRules{
+		{
+			LogType: aws.String(testLogType),
+			Rules: []*sourcemodels.RedactionRule{
+				{Field: aws.String("email"), Action: aws.String(sourcemodels.RedactionDrop)},
+			},
+		},
+	})
+	require.NoError(t, err)
+	EnableRedaction(r)
+	defer EnableRedaction(nil)
+
+	dataStream := &common.DataStream{
+		Reader:   strings.NewReader("bad line with alice@example.com\n"),
+		Hints:    common.DataStreamHints{S3: s3Hint},
+		LogTypes: []string{testLogType},
+	}
+	p := NewProcessor(dataStream)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	mockClassifier.On("Classify", mock.Anything).Return(&classification.ClassifierResult{
+		ParsersTried: []string{testLogType},
+	})
+	mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{LogLineCount: 1})
+	mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{})
+
+	outputChan := make(chan *parsers.PantherLog, 10)
+	require.NoError(t, p.run(outputChan))
+	assert.Len(t, outputChan, 0) // the line may hold values the rules remove
+}
@@ -0,0 +1,114 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"go.uber.org/zap"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+const sourceAPIFunctionName = "panther-source-api"
+
+// sourceStats aggregates the classification stats of the data streams of each source integration by the hour
+// they are processed in, so they are sent to the source API once per invocation
+type sourceStats struct {
+	items map[string]*sourcemodels.SourceStats // by integration id, hour and log type
+}
+
+func newSourceStats() *sourceStats {
+	return &sourceStats{
+		items: make(map[string]*sourcemodels.SourceStats),
+	}
+}
+
+func (s *sourceStats) add(dataStream *common.DataStream, classifier classification.ClassifierAPI, now time.Time) {
+	// data replayed under a prefix was received before, it does not count
+	if dataStream.SourceID == "" || dataStream.DestinationPrefix != "" {
+		return
+	}
+
+	classifierStats := classifier.Stats()
+	allLogTypes := s.item(dataStream.SourceID, "", now)
+	allLogTypes.LogLineCount += classifierStats.LogLineCount
+	allLogTypes.BytesProcessedCount += classifierStats.BytesProcessedCount
+	allLogTypes.EventCount += classifierStats.EventCount
+	allLogTypes.ClassificationFailureCount += classifierStats.ClassificationFailureCount
+
+	for _, parserStats := range classifier.ParserStats() {
+		logType := s.item(dataStream.SourceID, parserStats.LogType, now)
+		logType.LogLineCount += parserStats.LogLineCount
+		logType.BytesProcessedCount += parserStats.BytesProcessedCount
+		logType.EventCount += parserStats.EventCount
+		logType.LastEventTime = latestEventTime(logType.LastEventTime, parserStats.LastEventTime)
+		allLogTypes.LastEventTime = latestEventTime(allLogTypes.LastEventTime, parserStats.LastEventTime)
+	}
+}
+
+func (s *sourceStats) item(sourceID, logType string, now time.Time) *sourcemodels.SourceStats {
+	key := sourceID + "/" + now.UTC().Truncate(time.Hour).Format(time.RFC3339) + "/" + logType
+	item, ok := s.items[key]
+	if !ok {
+		item = &sourcemodels.SourceStats{
+			IntegrationID: aws.String(sourceID),
+		}
+		if logType != "" {
+			item.LogType = aws.String(logType)
+		}
+		s.items[key] = item
+	}
+	item.ReceivedTime = aws.Time(now)
+	return item
+}
+
+func latestEventTime(current *time.Time, eventTime time.Time) *time.Time {
+	if eventTime.IsZero() || (current != nil && !eventTime.After(*current)) {
+		return current
+	}
+	return aws.Time(eventTime)
+}
+
+// store sends the stats to the source API, failures are logged since the data has been processed
+func (s *sourceStats) store(lambdaClient lambdaiface.LambdaAPI) {
+	if len(s.items) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(s.items))
+	for key := range s.items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	input := &sourcemodels.LambdaInput{
+		AddSourceStats: &sourcemodels.AddSourceStatsInput{},
+	}
+	for _, key := range keys {
+		input.AddSourceStats.Stats = append(input.AddSourceStats.Stats, s.items[key])
+	}
+	if err := genericapi.Invoke(lambdaClient, sourceAPIFunctionName, input, nil); err != nil {
+		zap.L().Error("failed to store source stats", zap.Int("count", len(keys)), zap.Error(err))
+	}
+}
@@ -0,0 +1,102 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func TestSourceStats(t *testing.T) {
+	now := time.Date(2020, 1, 1, 10, 30, 0, 0, time.UTC)
+	eventTime := now.Add(-time.Minute)
+	classifier := &testClassifier{}
+	classifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{
+		BytesProcessedCount:        10,
+		LogLineCount:               3,
+		EventCount:                 2,
+		ClassificationFailureCount: 1,
+	})
+	classifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{
+		testLogType: {
+			BytesProcessedCount: 8,
+			LogLineCount:        2,
+			EventCount:          2,
+			LogType:             testLogType,
+			LastEventTime:       eventTime,
+		},
+	})
+
+	stats := newSourceStats()
+	stats.add(&common.DataStream{SourceID: "source"}, classifier, now)
+	stats.add(&common.DataStream{SourceID: "source"}, classifier, now)
+	stats.add(&common.DataStream{}, classifier, now)                                                 // unknown source
+	stats.add(&common.DataStream{SourceID: "source", DestinationPrefix: "replay/"}, classifier, now) // replayed
+
+	expectedStats := []*sourcemodels.SourceStats{
+		{
+			IntegrationID:              aws.String("source"),
+			ReceivedTime:               &now,
+			LastEventTime:              &eventTime,
+			LogLineCount:               6,
+			BytesProcessedCount:        20,
+			EventCount:                 4,
+			ClassificationFailureCount: 2,
+		},
+		{
+			IntegrationID:       aws.String("source"),
+			LogType:             aws.String(testLogType),
+			ReceivedTime:        &now,
+			LastEventTime:       &eventTime,
+			LogLineCount:        4,
+			BytesProcessedCount: 16,
+			EventCount:          4,
+		},
+	}
+
+	// the stats are sent to the source API in a single call
+	lambdaClient := &testutils.LambdaMock{}
+	lambdaClient.On("Invoke", mock.Anything).Return(&lambda.InvokeOutput{}, nil).Once()
+	stats.store(lambdaClient)
+	lambdaClient.AssertExpectations(t)
+	input := lambdaClient.Calls[0].Arguments.Get(0).(*lambda.InvokeInput)
+	assert.Equal(t, sourceAPIFunctionName, *input.FunctionName)
+	var payload sourcemodels.LambdaInput
+	require.NoError(t, jsoniter.Unmarshal(input.Payload, &payload))
+	require.NotNil(t, payload.AddSourceStats)
+	assert.Equal(t, expectedStats, payload.AddSourceStats.Stats)
+}
+
+func TestSourceStatsNoData(t *testing.T) {
+	lambdaClient := &testutils.LambdaMock{}
+	newSourceStats().store(lambdaClient)
+	lambdaClient.AssertNotCalled(t, "Invoke", mock.Anything)
+}
@@ -61,11 +61,29 @@ func streamEvents(sqsClient sqsiface.SQSAPI, deadlineTime time.Time, event event
 	var sqsMessageCount int
 	var err error
 
+	destination, err := destinations.CreateDestination()
+	if err != nil {
+		return 0, err
+	}
+
 	streamChan := make(chan *common.DataStream, 2*sqsMaxBatchSize) // use small buffer to pipeline events
 	processingDeadlineTime := deadlineTime.Add(-time.Duration(float32(time.Since(deadlineTime)) * processingTimeLimitScalar))
 
 	var accumulatedMessageReceipts []*string // accumulate message receipts for delete at the end
 
+	// replayed data is written under its own prefix so it is processed separately, after the rest of the data
+	// so that only one destination buffers events at a time (see destinations.maxS3BufferMemUsageBytes)
+	var replayDataStreams []*common.DataStream // only used by the go routine below until it is done
+	sendDataStreams := func(dataStreams []*common.DataStream) {
+		for _, dataStream := range dataStreams {
+			if dataStream.DestinationPrefix != "" {
+				replayDataStreams = append(replayDataStreams, dataStream)
+				continue
+			}
+			streamChan <- dataStream
+		}
+	}
+
 	readEventErrorChan := make(chan error, 1) // below go routine closes over this for errors, 1 deep buffer
 	go func() {
 		defer func() {
@@ -82,9 +100,7 @@ func streamEvents(sqsClient sqsiface.SQSAPI, deadlineTime time.Time, event event
 
 		// process lambda events
 		sqsMessageCount += len(dataStreams)
-		for _, dataStream := range dataStreams {
-			streamChan <- dataStream
-		}
+		sendDataStreams(dataStreams)
 
 		// continue to read until either there are no sqs messages or we have exceeded the processing time limit
 		for isProcessingTimeRemaining(processingDeadlineTime) {
@@ -121,20 +137,26 @@ func streamEvents(sqsClient sqsiface.SQSAPI, deadlineTime time.Time, event event
 
 			// process sqs messages
 			sqsMessageCount += len(dataStreams)
-			for _, dataStream := range dataStreams {
-				streamChan <- dataStream
-			}
+			sendDataStreams(dataStreams)
 		}
 	}()
 
 	// process streamChan until closed (blocks)
-	err = processFunc(streamChan, destinations.CreateS3Destination())
-	if err != nil { // prefer Process() error to readEventError
-		return 0, err
+	err = processFunc(streamChan, destination)
+	for dataStream := range streamChan { // in case processing stopped early, the go routine must not block
+		closeDataStream(dataStream)
 	}
+	// the go routine is done once it closes the error channel
 	readEventError := <-readEventErrorChan
-	if readEventError != nil {
-		return 0, readEventError
+	if err == nil { // prefer Process() error to readEventError
+		err = readEventError
+	}
+	if err != nil {
+		sources.CloseDataStreams(replayDataStreams)
+		return 0, err
+	}
+	if err = processReplayDataStreams(replayDataStreams, processFunc); err != nil {
+		return 0, err
 	}
 
 	// delete messages from sqs q on success (best effort)
@@ -142,6 +164,41 @@ func streamEvents(sqsClient sqsiface.SQSAPI, deadlineTime time.Time, event event
 	return sqsMessageCount, nil
 }
 
+// processReplayDataStreams processes the replayed data streams with one destination per destination prefix,
+// one prefix at a time. On failure the streams left are closed.
+func processReplayDataStreams(dataStreams []*common.DataStream,
+	processFunc func(chan *common.DataStream, destinations.Destination) error) error {
+
+	var prefixes []string // keep the order the streams were received
+	prefixDataStreams := make(map[string][]*common.DataStream)
+	for _, dataStream := range dataStreams {
+		prefix := dataStream.DestinationPrefix
+		if _, ok := prefixDataStreams[prefix]; !ok {
+			prefixes = append(prefixes, prefix)
+		}
+		prefixDataStreams[prefix] = append(prefixDataStreams[prefix], dataStream)
+	}
+
+	for i, prefix := range prefixes {
+		streamChan := make(chan *common.DataStream, len(prefixDataStreams[prefix]))
+		for _, dataStream := range prefixDataStreams[prefix] {
+			streamChan <- dataStream
+		}
+		close(streamChan)
+		err := processFunc(streamChan, destinations.CreateS3ReplayDestination(prefix))
+		for dataStream := range streamChan { // in case processing stopped early
+			closeDataStream(dataStream)
+		}
+		if err != nil {
+			for _, unprocessed := range prefixes[i+1:] {
+				sources.CloseDataStreams(prefixDataStreams[unprocessed])
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 func lambdaDataStreams(event events.SQSEvent,
 	readSnsMessagesFunc func([]string) ([]*common.DataStream, error)) ([]*common.DataStream, error) {
 
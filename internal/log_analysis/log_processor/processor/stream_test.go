@@ -21,6 +21,7 @@ package processor
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -123,6 +124,85 @@ func TestStreamEventsProcessingTimeLimitExceeded(t *testing.T) {
 	streamTestSqsClient.AssertExpectations(t)
 }
 
+func TestStreamEventsReplay(t *testing.T) {
+	initTest()
+
+	// this one has no messages, which breaks the loop
+	streamTestSqsClient.On("GetQueueAttributes", mock.Anything).Return(streamTestMessagesBelowThreshold, nil).Once()
+
+	lambdaEvent := events.SQSEvent{
+		Records: []events.SQSMessage{{Body: snsMessage}, {Body: snsMessage}, {Body: snsMessage}},
+	}
+	readSnsMessagesFunc := func(messages []string) ([]*common.DataStream, error) {
+		return []*common.DataStream{
+			{DestinationPrefix: "replay/a/"},
+			{},
+			{DestinationPrefix: "replay/a/"},
+		}, nil
+	}
+	var lock sync.Mutex
+	var processedDataStreams []int // number of streams per call of the process function
+	processFunc := func(streamChan chan *common.DataStream, dest destinations.Destination) error {
+		count := 0
+		for range streamChan {
+			count++
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		processedDataStreams = append(processedDataStreams, count)
+		return nil
+	}
+
+	sqsMessageCount, err := streamEvents(streamTestSqsClient, streamTestDeadline, lambdaEvent,
+		processFunc, readSnsMessagesFunc)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sqsMessageCount)
+	// replayed streams are processed together with their own destination, after the rest is processed
+	assert.Equal(t, []int{1, 2}, processedDataStreams)
+	streamTestSqsClient.AssertExpectations(t)
+}
+
+func TestStreamEventsReplayErrorClosesDataStreams(t *testing.T) {
+	initTest()
+
+	// this one has no messages, which breaks the loop
+	streamTestSqsClient.On("GetQueueAttributes", mock.Anything).Return(streamTestMessagesBelowThreshold, nil).Once()
+
+	var lock sync.Mutex
+	var closed []string
+	dataStream := func(prefix string) *common.DataStream {
+		return &common.DataStream{
+			DestinationPrefix: prefix,
+			Closer: closerFunc(func() error {
+				lock.Lock()
+				defer lock.Unlock()
+				closed = append(closed, prefix)
+				return nil
+			}),
+		}
+	}
+	readSnsMessagesFunc := func(messages []string) ([]*common.DataStream, error) {
+		return []*common.DataStream{dataStream("replay/a/"), dataStream("replay/b/"), dataStream("replay/a/")}, nil
+	}
+	calls := 0
+	processFunc := func(streamChan chan *common.DataStream, dest destinations.Destination) error {
+		calls++
+		if calls > 1 { // the first replay prefix fails without reading its streams
+			return fmt.Errorf("processError")
+		}
+		for range streamChan {
+		}
+		return nil
+	}
+
+	_, err := streamEvents(streamTestSqsClient, streamTestDeadline, streamTestLambdaEvent,
+		processFunc, readSnsMessagesFunc)
+	require.Error(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []string{"replay/a/", "replay/a/", "replay/b/"}, closed)
+	streamTestSqsClient.AssertExpectations(t)
+}
+
 func TestStreamEventsReadEventError(t *testing.T) {
 	initTest()
 
@@ -235,7 +315,11 @@ func failProcessorFunc(streamChan chan *common.DataStream, dest destinations.Des
 }
 
 func noopReadSnsMessagesFunc(messages []string) ([]*common.DataStream, error) {
-	return make([]*common.DataStream, len(messages)), nil
+	dataStreams := make([]*common.DataStream, len(messages))
+	for i := range dataStreams {
+		dataStreams[i] = &common.DataStream{}
+	}
+	return dataStreams, nil
 }
 
 // simulated error parsing sqs message or reading s3 object
@@ -0,0 +1,558 @@
+package redaction
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package redaction removes or obfuscates sensitive values (e.g., emails, card numbers and tokens) of the parsed events
+// with the redaction rules of their log type, managed through the source API, before the events are stored.
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+const (
+	sourceAPIFunctionName = "panther-source-api"
+
+	// the separator of the names of nested fields in the field paths of the rules
+	fieldSeparator = "."
+	// the number of trailing characters left visible by the mask action
+	maskVisibleSuffix = 4
+	maskCharacter     = "*"
+
+	// the Panther fields that hold values of the event fields, see redaction.pantherFields
+	pantherAnyFieldPrefix      = "p_any_"
+	pantherIOCMatchesField     = "p_ioc_matches"
+	pantherIOCIndicatorField   = "indicator"
+	pantherGeolocationsField   = "p_ip_geolocations"
+	pantherGeolocationIPField  = "ip"
+	pantherAnyIPCountriesField = "p_any_ip_countries"
+	pantherAnyIPASNsField      = "p_any_ip_asns"
+)
+
+// Rules are the compiled redaction rules of a log type
+type Rules struct {
+	root *node
+	salt []byte
+}
+
+// node is a field of the events, the root node is the event itself
+type node struct {
+	rules  []*rule
+	fields map[string]*node
+}
+
+type rule struct {
+	action      string
+	pattern     *regexp.Regexp // nil if the rule applies to whole values
+	replacement string
+}
+
+// Compile checks and compiles the redaction rules of a log type, the salt is required by hash rules
+func Compile(rules []*sourcemodels.RedactionRule, salt []byte) (*Rules, error) {
+	result := &Rules{
+		root: &node{},
+		salt: salt,
+	}
+	for i, r := range rules {
+		compiled := &rule{
+			action:      aws.StringValue(r.Action),
+			replacement: aws.StringValue(r.Replacement),
+		}
+		switch compiled.action {
+		case sourcemodels.RedactionDrop, sourcemodels.RedactionMask, sourcemodels.RedactionReplace:
+		case sourcemodels.RedactionHash:
+			if len(salt) == 0 {
+				return nil, errors.Errorf("rule %d: hash rules require a salt", i)
+			}
+		default:
+			return nil, errors.Errorf("rule %d: unknown action %q", i, compiled.action)
+		}
+		if r.Pattern != nil {
+			pattern, err := regexp.Compile(*r.Pattern)
+			if err != nil {
+				return nil, errors.Wrapf(err, "rule %d: invalid pattern", i)
+			}
+			compiled.pattern = pattern
+		}
+		field := result.root
+		for _, name := range strings.Split(aws.StringValue(r.Field), fieldSeparator) {
+			if name == "" {
+				return nil, errors.Errorf("rule %d: invalid field %q", i, aws.StringValue(r.Field))
+			}
+			child, ok := field.fields[name]
+			if !ok {
+				if field.fields == nil {
+					field.fields = make(map[string]*node)
+				}
+				child = &node{}
+				field.fields[name] = child
+			}
+			field = child
+		}
+		field.rules = append(field.rules, compiled)
+	}
+	return result, nil
+}
+
+// RedactJSON applies the rules to the JSON of an event, it returns the redacted JSON and the number of values
+// that were redacted. The JSON is returned as it is if no values were redacted.
+//
+// The values of the Panther fields (e.g., p_any_emails) that were taken from redacted values are redacted
+// with the same rules, they are not counted.
+func (r *Rules) RedactJSON(data []byte) ([]byte, int, error) {
+	redaction := &redaction{salt: r.salt}
+	result, _ := redaction.value(r.root, data)
+	if len(redaction.redacted) > 0 && redaction.err == nil {
+		result = redaction.pantherFields(result)
+	}
+	if redaction.err != nil {
+		return nil, 0, redaction.err
+	}
+	return result, redaction.count, nil
+}
+
+// redaction rewrites the JSON of an event, re-encoding only the values that the rules apply to
+type redaction struct {
+	salt     []byte
+	count    int
+	redacted []*redactedValue
+	err      error
+}
+
+// redactedValue is a value of the event that the rules applied to
+type redactedValue struct {
+	original string // the JSON of values that are not strings
+	result   string
+	dropped  bool
+	rules    []*rule
+}
+
+// value returns the redacted JSON value, false if the value is dropped
+func (r *redaction) value(n *node, data []byte) ([]byte, bool) {
+	data = bytes.TrimSpace(data)
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	switch iter.WhatIsNext() {
+	case jsoniter.ArrayValue: // the rules of a field apply to each element of the array
+		result := []byte{'['}
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			element, keep := r.value(n, iter.SkipAndReturnBytes())
+			if keep {
+				if len(result) > 1 {
+					result = append(result, ',')
+				}
+				result = append(result, element...)
+			}
+			return r.err == nil
+		})
+		r.setError(iter.Error)
+		return append(result, ']'), true
+	case jsoniter.StringValue:
+		value := iter.ReadString()
+		if r.setError(iter.Error) {
+			return nil, false
+		}
+		redacted, keep := r.string(n.rules, value)
+		if !keep {
+			return nil, false
+		}
+		if redacted == value {
+			return data, true
+		}
+		return r.marshal(redacted), true
+	case jsoniter.NilValue:
+		return data, true
+	case jsoniter.ObjectValue:
+		if r.drop(n.rules, data) {
+			return nil, false
+		}
+		if len(n.fields) == 0 {
+			return data, true
+		}
+		return r.object(n, iter), true
+	default: // numbers and booleans cannot hold the redacted values, they are dropped
+		if r.drop(n.rules, data) {
+			return nil, false
+		}
+		return data, true
+	}
+}
+
+func (r *redaction) object(n *node, iter *jsoniter.Iterator) []byte {
+	result := []byte{'{'}
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, name string) bool {
+		value := iter.SkipAndReturnBytes()
+		if field, ok := n.fields[name]; ok {
+			var keep bool
+			if value, keep = r.value(field, value); !keep {
+				return r.err == nil
+			}
+		}
+		if len(result) > 1 {
+			result = append(result, ',')
+		}
+		result = append(result, r.marshal(name)...)
+		result = append(result, ':')
+		result = append(result, value...)
+		return r.err == nil
+	})
+	r.setError(iter.Error)
+	return append(result, '}')
+}
+
+// string applies the rules to a string value, it returns false if the value is dropped
+func (r *redaction) string(rules []*rule, value string) (string, bool) {
+	result, keep, redacted := applyRules(rules, value, r.salt)
+	if redacted {
+		r.count++
+		r.redacted = append(r.redacted, &redactedValue{
+			original: value,
+			result:   result,
+			dropped:  !keep,
+			rules:    rules,
+		})
+	}
+	return result, keep
+}
+
+// applyRules returns the redacted value, false if it is dropped, and whether any rule applied to it
+func applyRules(rules []*rule, value string, salt []byte) (string, bool, bool) {
+	redacted := false
+	for _, rule := range rules {
+		if rule.pattern != nil && !rule.pattern.MatchString(value) {
+			continue
+		}
+		redacted = true
+		if rule.action == sourcemodels.RedactionDrop {
+			return "", false, true
+		}
+		value = rule.apply(value, salt)
+	}
+	return value, true, redacted
+}
+
+// drop returns true if any rule applies to a value that is not a string
+func (r *redaction) drop(rules []*rule, data []byte) bool {
+	for _, rule := range rules {
+		if rule.pattern == nil || rule.pattern.Match(data) {
+			r.count++
+			r.redacted = append(r.redacted, &redactedValue{
+				original: string(data),
+				dropped:  true,
+				rules:    rules,
+			})
+			return true
+		}
+	}
+	return false
+}
+
+// pantherFields redacts the values of the Panther fields that were taken from redacted values of the event
+// (e.g., an email of a hashed field in p_any_emails), so they do not keep what the rules removed.
+//
+// The p_any_ip_countries and p_any_ip_asns fields are left as they are, they do not hold values of the event.
+func (r *redaction) pantherFields(data []byte) []byte {
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	if iter.WhatIsNext() != jsoniter.ObjectValue {
+		return data
+	}
+	result := []byte{'{'}
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, name string) bool {
+		value := iter.SkipAndReturnBytes()
+		switch {
+		case name == pantherIOCMatchesField:
+			value = r.pantherObjects(value, pantherIOCIndicatorField)
+		case name == pantherGeolocationsField:
+			value = r.pantherObjects(value, pantherGeolocationIPField)
+		case name == pantherAnyIPCountriesField || name == pantherAnyIPASNsField:
+		case strings.HasPrefix(name, pantherAnyFieldPrefix):
+			value = r.pantherValues(value)
+		}
+		if len(result) > 1 {
+			result = append(result, ',')
+		}
+		result = append(result, r.marshal(name)...)
+		result = append(result, ':')
+		result = append(result, value...)
+		return r.err == nil
+	})
+	r.setError(iter.Error)
+	return append(result, '}')
+}
+
+// pantherValues redacts the strings of a p_any field, the values left are unique
+func (r *redaction) pantherValues(data []byte) []byte {
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	if iter.WhatIsNext() != jsoniter.ArrayValue {
+		return data
+	}
+	result := []byte{'['}
+	seen := make(map[string]struct{})
+	iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+		if iter.WhatIsNext() != jsoniter.StringValue {
+			iter.Skip()
+			return iter.Error == nil
+		}
+		value, keep := r.pantherValue(iter.ReadString())
+		if _, duplicate := seen[value]; !keep || duplicate {
+			return iter.Error == nil
+		}
+		seen[value] = struct{}{}
+		if len(result) > 1 {
+			result = append(result, ',')
+		}
+		result = append(result, r.marshal(value)...)
+		return r.err == nil && iter.Error == nil
+	})
+	r.setError(iter.Error)
+	return append(result, ']')
+}
+
+// pantherObjects redacts the field of each object of a Panther field, objects with a dropped value are dropped
+func (r *redaction) pantherObjects(data []byte, field string) []byte {
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	if iter.WhatIsNext() != jsoniter.ArrayValue {
+		return data
+	}
+	result := []byte{'['}
+	iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+		element, keep := r.pantherObject(iter.SkipAndReturnBytes(), field)
+		if keep {
+			if len(result) > 1 {
+				result = append(result, ',')
+			}
+			result = append(result, element...)
+		}
+		return r.err == nil && iter.Error == nil
+	})
+	r.setError(iter.Error)
+	return append(result, ']')
+}
+
+func (r *redaction) pantherObject(data []byte, field string) ([]byte, bool) {
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	if iter.WhatIsNext() != jsoniter.ObjectValue {
+		return data, true
+	}
+	result := []byte{'{'}
+	keep := true
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, name string) bool {
+		value := iter.SkipAndReturnBytes()
+		if name == field {
+			var s string
+			if err := jsoniter.ConfigDefault.Unmarshal(value, &s); err == nil {
+				if s, keep = r.pantherValue(s); !keep {
+					return false
+				}
+				value = r.marshal(s)
+			}
+		}
+		if len(result) > 1 {
+			result = append(result, ',')
+		}
+		result = append(result, r.marshal(name)...)
+		result = append(result, ':')
+		result = append(result, value...)
+		return r.err == nil
+	})
+	r.setError(iter.Error)
+	return append(result, '}'), keep
+}
+
+// pantherValue applies the rules of the redacted value a Panther value was taken from, it returns false if it is dropped.
+//
+// A Panther value was taken from a redacted value if it is the value, or part of it but not of its result, ignoring
+// case since the Panther fields are normalized. Values that the rules do not change are dropped.
+func (r *redaction) pantherValue(value string) (string, bool) {
+	if value == "" {
+		return value, true
+	}
+	lower := strings.ToLower(value)
+	source := r.findRedacted(func(original string) bool { return original == lower })
+	if source == nil {
+		source = r.findRedacted(func(original string) bool { return strings.Contains(original, lower) })
+	}
+	if source == nil || (!source.dropped && strings.Contains(strings.ToLower(source.result), lower)) {
+		return value, true
+	}
+	result, keep, _ := applyRules(source.rules, value, r.salt)
+	if !keep || result == value {
+		return "", false
+	}
+	return result, true
+}
+
+// findRedacted returns the first redacted value whose lower case original matches
+func (r *redaction) findRedacted(match func(original string) bool) *redactedValue {
+	for _, redacted := range r.redacted {
+		if match(strings.ToLower(redacted.original)) {
+			return redacted
+		}
+	}
+	return nil
+}
+
+func (r *redaction) marshal(value string) []byte {
+	data, err := jsoniter.ConfigDefault.Marshal(value)
+	r.setError(err)
+	return data
+}
+
+// setError records the first error, it returns true if there is one
+func (r *redaction) setError(err error) bool {
+	if err != nil && r.err == nil {
+		r.err = errors.Wrap(err, "failed to redact event")
+	}
+	return r.err != nil
+}
+
+// apply returns the value with the matches of the pattern, or all of it, hashed, masked or replaced
+func (rule *rule) apply(value string, salt []byte) string {
+	var redact func(string) string
+	switch rule.action {
+	case sourcemodels.RedactionHash:
+		redact = func(value string) string {
+			mac := hmac.New(sha256.New, salt)
+			mac.Write([]byte(value)) // never returns an error
+			return hex.EncodeToString(mac.Sum(nil))
+		}
+	case sourcemodels.RedactionMask:
+		redact = mask
+	default:
+		if rule.pattern != nil { // the replacement can reference the groups of the pattern
+			return rule.pattern.ReplaceAllString(value, rule.replacement)
+		}
+		return rule.replacement
+	}
+	if rule.pattern != nil {
+		return rule.pattern.ReplaceAllStringFunc(value, redact)
+	}
+	return redact(value)
+}
+
+// mask replaces all characters of the value with '*' but the last 4, short values are masked completely
+func mask(value string) string {
+	runes := []rune(value)
+	visible := 0
+	if len(runes) > 2*maskVisibleSuffix {
+		visible = maskVisibleSuffix
+	}
+	return strings.Repeat(maskCharacter, len(runes)-visible) + string(runes[len(runes)-visible:])
+}
+
+// Redactor applies the redaction rules of the log types to their events
+type Redactor struct {
+	logTypes map[string]*Rules
+}
+
+// NewRedactor compiles the redaction rules of the log types.
+//
+// It fails if the rules of any log type are invalid, so that events are never stored without their rules applied.
+func NewRedactor(rules []*sourcemodels.RedactionRules) (*Redactor, error) {
+	redactor := &Redactor{
+		logTypes: make(map[string]*Rules, len(rules)),
+	}
+	for _, logTypeRules := range rules {
+		logType := aws.StringValue(logTypeRules.LogType)
+		salt, err := base64.StdEncoding.DecodeString(aws.StringValue(logTypeRules.Salt))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid redaction salt of %s", logType)
+		}
+		compiled, err := Compile(logTypeRules.Rules, salt)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid redaction rules of %s", logType)
+		}
+		redactor.logTypes[logType] = compiled
+	}
+	return redactor, nil
+}
+
+// Load fetches the redaction rules of all log types, with the salts of their hashes, from the source API
+func Load(lambdaClient lambdaiface.LambdaAPI) (*Redactor, error) {
+	input := &sourcemodels.LambdaInput{
+		ListRedactionRules: &sourcemodels.ListRedactionRulesInput{
+			IncludeSalts: true,
+		},
+	}
+	var output []*sourcemodels.RedactionRules
+	if err := genericapi.Invoke(lambdaClient, sourceAPIFunctionName, input, &output); err != nil {
+		return nil, errors.Wrap(err, "failed to list redaction rules")
+	}
+	return NewRedactor(output)
+}
+
+// Len returns the number of log types with redaction rules
+func (r *Redactor) Len() int {
+	return len(r.logTypes)
+}
+
+// Applies returns true if any of the log types has redaction rules, all log types are checked if none are given
+func (r *Redactor) Applies(logTypes []string) bool {
+	if len(logTypes) == 0 {
+		return len(r.logTypes) > 0
+	}
+	for _, logType := range logTypes {
+		if _, ok := r.logTypes[logType]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact applies the rules of the log type of the event, it returns the number of values that were redacted.
+//
+// The rules apply to the fields of the event as they are stored (i.e., after the renaming of the fields by
+// parsers.JSON), including the Panther fields. If any values are redacted, the event is replaced by its redacted JSON,
+// in which the Panther fields taken from the redacted values are redacted as well.
+func (r *Redactor) Redact(event *parsers.PantherLog) (int, error) {
+	rules, ok := r.logTypes[aws.StringValue(event.PantherLogType)]
+	if !ok {
+		return 0, nil
+	}
+	data, err := parsers.JSON.Marshal(event.Event())
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to marshal event")
+	}
+	redacted, count, err := rules.RedactJSON(data)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		event.SetEvent(jsoniter.RawMessage(redacted))
+	}
+	return count, nil
+}
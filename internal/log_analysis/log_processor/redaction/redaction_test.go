@@ -0,0 +1,264 @@
+package redaction
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+var testSalt = []byte("salt")
+
+func redactionRule(field, action string) *sourcemodels.RedactionRule {
+	return &sourcemodels.RedactionRule{
+		Field:  aws.String(field),
+		Action: aws.String(action),
+	}
+}
+
+func testHash(value string) string {
+	mac := hmac.New(sha256.New, testSalt)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestRedactJSON(t *testing.T) {
+	cardRule := redactionRule("message", sourcemodels.RedactionMask)
+	cardRule.Pattern = aws.String(`\b\d{16}\b`)
+	tokenRule := redactionRule("request.headers.authorization", sourcemodels.RedactionReplace)
+	tokenRule.Pattern = aws.String(`^(Bearer) .*$`)
+	tokenRule.Replacement = aws.String("$1 <redacted>")
+	adminRule := redactionRule("user.role", sourcemodels.RedactionDrop)
+	adminRule.Pattern = aws.String(`^admin$`)
+	rules, err := Compile([]*sourcemodels.RedactionRule{
+		redactionRule("user.email", sourcemodels.RedactionHash),
+		redactionRule("user.password", sourcemodels.RedactionDrop),
+		redactionRule("user.pin", sourcemodels.RedactionDrop),
+		redactionRule("user.address", sourcemodels.RedactionHash),
+		adminRule,
+		cardRule,
+		tokenRule,
+		redactionRule("missing.field", sourcemodels.RedactionDrop),
+	}, testSalt)
+	require.NoError(t, err)
+
+	input := `{
+  "user": [
+    {"email": "alice@example.com", "password": "secret", "pin": 1234, "role": "admin", "address": {"city": "Athens"}},
+    {"email": null, "role": "viewer"}
+  ],
+  "message": "paid with 4111111111111111",
+  "request": {"headers": {"authorization": "Bearer eyJhbGciOi", "host": "example.com"}},
+  "p_log_type": "Test.Log"
+}`
+	expected := `{
+  "user": [
+    {"email": "` + testHash("alice@example.com") + `"},
+    {"email": null, "role": "viewer"}
+  ],
+  "message": "paid with ************1111",
+  "request": {"headers": {"authorization": "Bearer <redacted>", "host": "example.com"}},
+  "p_log_type": "Test.Log"
+}`
+	redacted, count, err := rules.RedactJSON([]byte(input))
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(redacted))
+	assert.Equal(t, 7, count)
+}
+
+func TestRedactJSONUnchanged(t *testing.T) {
+	rules, err := Compile([]*sourcemodels.RedactionRule{redactionRule("password", sourcemodels.RedactionDrop)}, nil)
+	require.NoError(t, err)
+	input := `{"user":"alice","nested":{"password":"not redacted"}}`
+	redacted, count, err := rules.RedactJSON([]byte(input))
+	require.NoError(t, err)
+	assert.Equal(t, input, string(redacted))
+	assert.Equal(t, 0, count)
+}
+
+func TestRedactJSONPantherFields(t *testing.T) {
+	cardRule := redactionRule("message", sourcemodels.RedactionMask)
+	cardRule.Pattern = aws.String(`\b\d{16}\b`)
+	rules, err := Compile([]*sourcemodels.RedactionRule{
+		redactionRule("user.email", sourcemodels.RedactionHash),
+		redactionRule("user.name", sourcemodels.RedactionDrop),
+		redactionRule("client", sourcemodels.RedactionDrop),
+		cardRule,
+	}, testSalt)
+	require.NoError(t, err)
+
+	input := `{
+  "user": {"email": "Alice@Example.com", "name": "alice"},
+  "client": {"ip": "192.0.2.1"},
+  "message": "paid with 4111111111111111 from 198.51.100.1",
+  "p_any_emails": ["alice@example.com"],
+  "p_any_usernames": ["alice", "bob"],
+  "p_any_ip_addresses": ["192.0.2.1", "198.51.100.1"],
+  "p_any_ip_countries": ["US"],
+  "p_any_card_numbers": ["4111111111111111"],
+  "p_ioc_matches": [
+    {"indicator": "192.0.2.1", "type": "ip", "feed": "tor"},
+    {"indicator": "198.51.100.1", "type": "ip", "feed": "tor"}
+  ],
+  "p_ip_geolocations": [{"ip": "192.0.2.1", "country_code": "US"}]
+}`
+	expected := `{
+  "user": {"email": "` + testHash("Alice@Example.com") + `"},
+  "message": "paid with ************1111 from 198.51.100.1",
+  "p_any_emails": ["` + testHash("alice@example.com") + `"],
+  "p_any_usernames": ["bob"],
+  "p_any_ip_addresses": ["198.51.100.1"],
+  "p_any_ip_countries": ["US"],
+  "p_any_card_numbers": ["************1111"],
+  "p_ioc_matches": [{"indicator": "198.51.100.1", "type": "ip", "feed": "tor"}],
+  "p_ip_geolocations": []
+}`
+	redacted, count, err := rules.RedactJSON([]byte(input))
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(redacted))
+	assert.Equal(t, 4, count)
+}
+
+func TestRedactJSONInvalid(t *testing.T) {
+	rules, err := Compile([]*sourcemodels.RedactionRule{redactionRule("user.password", sourcemodels.RedactionDrop)}, nil)
+	require.NoError(t, err)
+	_, _, err = rules.RedactJSON([]byte(`{"user":{"password":`))
+	require.Error(t, err)
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "", mask(""))
+	assert.Equal(t, "********", mask("12345678"))
+	assert.Equal(t, "*****6789", mask("123456789"))
+	assert.Equal(t, "**********όσμε", mask("Καλημέρα κόσμε")) // characters, not bytes
+}
+
+func TestCompileInvalid(t *testing.T) {
+	for _, rule := range []*sourcemodels.RedactionRule{
+		redactionRule("user..email", sourcemodels.RedactionDrop),
+		redactionRule("user.email", "encrypt"),
+		redactionRule("user.email", sourcemodels.RedactionHash), // no salt
+		{Field: aws.String("user.email"), Action: aws.String(sourcemodels.RedactionMask), Pattern: aws.String("(")},
+	} {
+		_, err := Compile([]*sourcemodels.RedactionRule{rule}, nil)
+		assert.Error(t, err, *rule.Field)
+	}
+}
+
+type testEvent struct {
+	parsers.PantherLog
+	Email *string `json:"email,omitempty"`
+}
+
+func TestRedactor(t *testing.T) {
+	redactor, err := NewRedactor([]*sourcemodels.RedactionRules{
+		{
+			LogType: aws.String("Test.Log"),
+			Rules:   []*sourcemodels.RedactionRule{redactionRule("email", sourcemodels.RedactionHash)},
+			Salt:    aws.String(base64.StdEncoding.EncodeToString(testSalt)),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, redactor.Len())
+
+	event := &testEvent{Email: aws.String("alice@example.com")}
+	event.SetCoreFields("Test.Log", nil, event)
+	count, err := redactor.Redact(event.Log())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	data, err := parsers.JSON.Marshal(event.Event())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"email":"`+testHash("alice@example.com")+`"`)
+	assert.Contains(t, string(data), `"p_log_type":"Test.Log"`)
+
+	other := &testEvent{Email: aws.String("alice@example.com")}
+	other.SetCoreFields("Other.Log", nil, other)
+	count, err = redactor.Redact(other.Log())
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, other, other.Event())
+}
+
+func TestRedactorApplies(t *testing.T) {
+	redactor, err := NewRedactor([]*sourcemodels.RedactionRules{
+		{
+			LogType: aws.String("Test.Log"),
+			Rules:   []*sourcemodels.RedactionRule{redactionRule("email", sourcemodels.RedactionDrop)},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, redactor.Applies([]string{"Other.Log", "Test.Log"}))
+	assert.True(t, redactor.Applies(nil))
+	assert.False(t, redactor.Applies([]string{"Other.Log"}))
+
+	none, err := NewRedactor(nil)
+	require.NoError(t, err)
+	assert.False(t, none.Applies(nil))
+}
+
+func TestNewRedactorInvalid(t *testing.T) {
+	_, err := NewRedactor([]*sourcemodels.RedactionRules{
+		{
+			LogType: aws.String("Test.Log"),
+			Rules:   []*sourcemodels.RedactionRule{redactionRule("email", sourcemodels.RedactionHash)},
+		},
+	})
+	require.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	lambdaMock := &testutils.LambdaMock{}
+	payload, err := jsoniter.Marshal([]*sourcemodels.RedactionRules{
+		{
+			LogType: aws.String("Test.Log"),
+			Rules:   []*sourcemodels.RedactionRule{redactionRule("email", sourcemodels.RedactionDrop)},
+		},
+	})
+	require.NoError(t, err)
+	lambdaMock.On("Invoke", mock.Anything).Return(&lambda.InvokeOutput{Payload: payload}, nil).Once()
+
+	redactor, err := Load(lambdaMock)
+	require.NoError(t, err)
+	assert.Equal(t, 1, redactor.Len())
+	lambdaMock.AssertExpectations(t)
+}
+
+func TestLoadError(t *testing.T) {
+	lambdaMock := &testutils.LambdaMock{}
+	lambdaMock.On("Invoke", mock.Anything).Return(&lambda.InvokeOutput{}, errors.New("failed")).Once()
+
+	_, err := Load(lambdaMock)
+	require.Error(t, err)
+	lambdaMock.AssertExpectations(t)
+}
@@ -0,0 +1,101 @@
+package registry
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/customlogs"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+const sourceAPIFunctionName = "panther-source-api"
+
+// the definitions (as JSON) of the custom log types registered by RegisterCustomLogTypes
+var customLogTypes = make(map[string]string)
+
+// LoadCustomLogTypes fetches the user defined log types from the source API and registers them
+func LoadCustomLogTypes(lambdaClient lambdaiface.LambdaAPI) error {
+	input := &sourcemodels.LambdaInput{
+		ListCustomLogTypes: &sourcemodels.ListCustomLogTypesInput{},
+	}
+	var output []*sourcemodels.CustomLogType
+	if err := genericapi.Invoke(lambdaClient, sourceAPIFunctionName, input, &output); err != nil {
+		return errors.Wrap(err, "failed to list custom log types")
+	}
+	RegisterCustomLogTypes(output)
+	return nil
+}
+
+// RegisterCustomLogTypes registers the parsers and Glue tables of the custom log types, they are the complete list.
+//
+// Log types whose definition changed since they were registered are registered again, the ones that are not in the list
+// anymore are removed. Invalid ones are logged and skipped so they do not affect the rest, keeping any previous definition.
+// NOTE: this is not safe for concurrent use, call it before processing starts.
+func RegisterCustomLogTypes(logTypes []*sourcemodels.CustomLogType) {
+	listed := make(map[string]struct{}, len(logTypes))
+	for _, logType := range logTypes {
+		name := aws.StringValue(logType.LogType)
+		listed[name] = struct{}{}
+		definition, err := jsoniter.MarshalToString(logType)
+		if err != nil {
+			zap.L().Error("failed to marshal custom log type", zap.String("logType", name), zap.Error(err))
+			continue
+		}
+		registered, found := customLogTypes[name]
+		if found && registered == definition {
+			continue
+		}
+		parser, err := customlogs.NewParser(logType)
+		if err != nil {
+			zap.L().Error("invalid custom log type", zap.String("logType", name), zap.Error(err))
+			continue
+		}
+		lpm := DefaultLogParser(parser, parser.EventStruct(), aws.StringValue(logType.Description))
+		lpm.Multiline = logType.Multiline
+		if found { // updated
+			parsersRegistry[name] = lpm
+		} else if err := Register(lpm); err != nil {
+			zap.L().Warn("failed to register custom log type", zap.String("logType", name), zap.Error(err))
+			continue
+		}
+		customLogTypes[name] = definition
+	}
+	for name := range customLogTypes {
+		if _, ok := listed[name]; !ok { // deleted
+			delete(parsersRegistry, name)
+			delete(customLogTypes, name)
+		}
+	}
+}
+
+// LookupTable returns the metadata of the parser that writes to the table, nil if there is none
+func LookupTable(tableName string) *LogParserMetadata {
+	for _, lpm := range parsersRegistry {
+		if lpm.GlueTableMetadata.TableName() == tableName {
+			return lpm
+		}
+	}
+	return nil
+}
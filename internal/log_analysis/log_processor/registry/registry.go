@@ -19,18 +19,27 @@ package registry
  */
 
 import (
+	"github.com/pkg/errors"
+
 	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/apachelogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/awslogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/azurelogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/ceflogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/fluentdsyslogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/gitlablogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/k8slogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/kvlogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/leeflogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/nginxlogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/osquerylogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/osseclogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/suricatalogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/sysloglogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/winlogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/zeeklogs"
 )
 
@@ -77,6 +86,20 @@ var (
 			&fluentdsyslogs.RFC5424{}, fluentdsyslogs.RFC5424Desc),
 		(&zeeklogs.ZeekDNSParser{}).LogType(): DefaultLogParser(&zeeklogs.ZeekDNSParser{},
 			&zeeklogs.ZeekDNS{}, zeeklogs.ZeekDNSDesc),
+		(&zeeklogs.ZeekConnParser{}).LogType(): DefaultLogParser(&zeeklogs.ZeekConnParser{},
+			&zeeklogs.ZeekConn{}, zeeklogs.ZeekConnDesc),
+		(&zeeklogs.ZeekHTTPParser{}).LogType(): DefaultLogParser(&zeeklogs.ZeekHTTPParser{},
+			&zeeklogs.ZeekHTTP{}, zeeklogs.ZeekHTTPDesc),
+		(&zeeklogs.ZeekSSLParser{}).LogType(): DefaultLogParser(&zeeklogs.ZeekSSLParser{},
+			&zeeklogs.ZeekSSL{}, zeeklogs.ZeekSSLDesc),
+		(&zeeklogs.ZeekFilesParser{}).LogType(): DefaultLogParser(&zeeklogs.ZeekFilesParser{},
+			&zeeklogs.ZeekFiles{}, zeeklogs.ZeekFilesDesc),
+		(&zeeklogs.ZeekX509Parser{}).LogType(): DefaultLogParser(&zeeklogs.ZeekX509Parser{},
+			&zeeklogs.ZeekX509{}, zeeklogs.ZeekX509Desc),
+		(&zeeklogs.ZeekNoticeParser{}).LogType(): DefaultLogParser(&zeeklogs.ZeekNoticeParser{},
+			&zeeklogs.ZeekNotice{}, zeeklogs.ZeekNoticeDesc),
+		(&zeeklogs.ZeekWeirdParser{}).LogType(): DefaultLogParser(&zeeklogs.ZeekWeirdParser{},
+			&zeeklogs.ZeekWeird{}, zeeklogs.ZeekWeirdDesc),
 		(&suricatalogs.AnomalyParser{}).LogType(): DefaultLogParser(&suricatalogs.AnomalyParser{},
 			&suricatalogs.Anomaly{}, suricatalogs.AnomalyDesc),
 		(&gitlablogs.APIParser{}).LogType(): DefaultLogParser(&gitlablogs.APIParser{},
@@ -95,8 +118,36 @@ var (
 			&awslogs.CloudTrailInsight{}, awslogs.CloudTrailInsightDesc),
 		(&awslogs.CloudTrailDigestParser{}).LogType(): DefaultLogParser(&awslogs.CloudTrailDigestParser{},
 			&awslogs.CloudTrailDigest{}, awslogs.CloudTrailDigestDesc),
+		(&awslogs.WAFWebACLParser{}).LogType(): DefaultLogParser(&awslogs.WAFWebACLParser{},
+			&awslogs.WAFWebACL{}, awslogs.WAFWebACLDesc),
+		(&awslogs.CloudFrontAccessParser{}).LogType(): DefaultLogParser(&awslogs.CloudFrontAccessParser{},
+			&awslogs.CloudFrontAccess{}, awslogs.CloudFrontAccessDesc),
+		(&awslogs.Route53ResolverQueryParser{}).LogType(): DefaultLogParser(&awslogs.Route53ResolverQueryParser{},
+			&awslogs.Route53ResolverQuery{}, awslogs.Route53ResolverQueryDesc),
+		(&awslogs.ClassicELBParser{}).LogType(): DefaultLogParser(&awslogs.ClassicELBParser{},
+			&awslogs.ClassicELB{}, awslogs.ClassicELBDesc),
+		(&awslogs.RedshiftUserActivityParser{}).LogType(): DefaultLogParser(&awslogs.RedshiftUserActivityParser{},
+			&awslogs.RedshiftUserActivity{}, awslogs.RedshiftUserActivityDesc),
+		(&awslogs.RedshiftConnectionParser{}).LogType(): DefaultLogParser(&awslogs.RedshiftConnectionParser{},
+			&awslogs.RedshiftConnection{}, awslogs.RedshiftConnectionDesc),
 		(&suricatalogs.DNSParser{}).LogType(): DefaultLogParser(&suricatalogs.DNSParser{},
 			&suricatalogs.DNS{}, suricatalogs.DNSDesc),
+		(&suricatalogs.AlertParser{}).LogType(): DefaultLogParser(&suricatalogs.AlertParser{},
+			&suricatalogs.Alert{}, suricatalogs.AlertDesc),
+		(&suricatalogs.FileInfoParser{}).LogType(): DefaultLogParser(&suricatalogs.FileInfoParser{},
+			&suricatalogs.FileInfo{}, suricatalogs.FileInfoDesc),
+		(&suricatalogs.FlowParser{}).LogType(): DefaultLogParser(&suricatalogs.FlowParser{},
+			&suricatalogs.Flow{}, suricatalogs.FlowDesc),
+		(&suricatalogs.HTTPParser{}).LogType(): DefaultLogParser(&suricatalogs.HTTPParser{},
+			&suricatalogs.HTTP{}, suricatalogs.HTTPDesc),
+		(&suricatalogs.NetflowParser{}).LogType(): DefaultLogParser(&suricatalogs.NetflowParser{},
+			&suricatalogs.Netflow{}, suricatalogs.NetflowDesc),
+		(&suricatalogs.SMTPParser{}).LogType(): DefaultLogParser(&suricatalogs.SMTPParser{},
+			&suricatalogs.SMTP{}, suricatalogs.SMTPDesc),
+		(&suricatalogs.SSHParser{}).LogType(): DefaultLogParser(&suricatalogs.SSHParser{},
+			&suricatalogs.SSH{}, suricatalogs.SSHDesc),
+		(&suricatalogs.TLSParser{}).LogType(): DefaultLogParser(&suricatalogs.TLSParser{},
+			&suricatalogs.TLS{}, suricatalogs.TLSDesc),
 		apachelogs.TypeAccessCommon: DefaultLogParser(
 			apachelogs.NewAccessCommonParser(),
 			&apachelogs.AccessCommon{},
@@ -107,6 +158,22 @@ var (
 			&apachelogs.AccessCombined{},
 			apachelogs.AccessCombinedDesc,
 		),
+		(&ceflogs.CEFParser{}).LogType(): DefaultLogParser(&ceflogs.CEFParser{},
+			&ceflogs.CEF{}, ceflogs.CEFDesc),
+		(&leeflogs.LEEFParser{}).LogType(): DefaultLogParser(&leeflogs.LEEFParser{},
+			&leeflogs.LEEF{}, leeflogs.LEEFDesc),
+		(&kvlogs.KeyValueParser{}).LogType(): DefaultLogParser(&kvlogs.KeyValueParser{},
+			&kvlogs.KeyValue{}, kvlogs.KeyValueDesc),
+		(&k8slogs.AuditParser{}).LogType(): DefaultLogParser(&k8slogs.AuditParser{},
+			&k8slogs.Audit{}, k8slogs.AuditDesc),
+		(&azurelogs.ActivityParser{}).LogType(): DefaultLogParser(&azurelogs.ActivityParser{},
+			&azurelogs.Activity{}, azurelogs.ActivityDesc),
+		(&azurelogs.SignInParser{}).LogType(): DefaultLogParser(&azurelogs.SignInParser{},
+			&azurelogs.SignIn{}, azurelogs.SignInDesc),
+		(&azurelogs.AuditParser{}).LogType(): DefaultLogParser(&azurelogs.AuditParser{},
+			&azurelogs.Audit{}, azurelogs.AuditDesc),
+		(&winlogs.EventLogParser{}).LogType(): DefaultLogParser(&winlogs.EventLogParser{},
+			&winlogs.EventLog{}, winlogs.EventLogDesc),
 	}
 )
 
@@ -126,6 +193,9 @@ func DefaultLogParser(p parsers.LogParser, eventStruct interface{}, description
 type LogParserMetadata struct {
 	Parser            parsers.LogParser          // does the work
 	GlueTableMetadata *awsglue.GlueTableMetadata // describes associated AWS Glue table (used to generate CF)
+
+	// How the lines of the data are joined into events, if nil each line is an event
+	Multiline *sourcemodels.MultilineConfig
 }
 
 // Return a map containing all the available parsers
@@ -133,6 +203,17 @@ func AvailableParsers() Registry {
 	return parsersRegistry
 }
 
+// Register adds a parser that is not known at compile time (e.g., a custom log type) to the available parsers.
+// NOTE: this is not safe for concurrent use, parsers should be registered before processing starts.
+func Register(lpm *LogParserMetadata) error {
+	logType := lpm.Parser.LogType()
+	if _, found := parsersRegistry[logType]; found {
+		return errors.Errorf("LogType already registered: %s", logType)
+	}
+	parsersRegistry[logType] = lpm
+	return nil
+}
+
 // Return a slice containing just the Glue tables
 func AvailableTables() (tables []*awsglue.GlueTableMetadata) {
 	for _, lpm := range parsersRegistry {
@@ -21,9 +21,72 @@ package registry
 import (
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
 )
 
 func TestPanic(t *testing.T) {
 	assert.Panics(t, func() { AvailableParsers().LookupParser("doesnotexist") }, "Failed to panic, this is very dangerous!")
 }
+
+func TestRegisterCustomLogTypes(t *testing.T) {
+	logType := &sourcemodels.CustomLogType{
+		LogType:     aws.String("Custom.Test"),
+		Description: aws.String("Test custom log type"),
+		Fields: []*sourcemodels.CustomLogTypeField{
+			{Name: aws.String("message"), Type: aws.String(sourcemodels.CustomFieldTypeString), Required: aws.Bool(true)},
+		},
+	}
+	invalidLogType := &sourcemodels.CustomLogType{
+		LogType: aws.String("Custom.Invalid"),
+	}
+	defer RegisterCustomLogTypes(nil)
+
+	RegisterCustomLogTypes([]*sourcemodels.CustomLogType{logType, invalidLogType})
+
+	lpm := AvailableParsers().LookupParser("Custom.Test")
+	assert.Equal(t, "custom_test", lpm.GlueTableMetadata.TableName())
+	assert.Equal(t, lpm, LookupTable("custom_test"))
+	assert.NotContains(t, AvailableParsers(), "Custom.Invalid")
+
+	// registering again fails
+	assert.Error(t, Register(lpm))
+}
+
+func TestRegisterCustomLogTypesRefresh(t *testing.T) {
+	logType := &sourcemodels.CustomLogType{
+		LogType:     aws.String("Custom.Test"),
+		Description: aws.String("Test custom log type"),
+		Fields: []*sourcemodels.CustomLogTypeField{
+			{Name: aws.String("message"), Type: aws.String(sourcemodels.CustomFieldTypeString), Required: aws.Bool(true)},
+		},
+	}
+	defer RegisterCustomLogTypes(nil)
+
+	RegisterCustomLogTypes([]*sourcemodels.CustomLogType{logType})
+	lpm := AvailableParsers().LookupParser("Custom.Test")
+
+	// unchanged types are kept as they are
+	RegisterCustomLogTypes([]*sourcemodels.CustomLogType{logType})
+	assert.Same(t, lpm, AvailableParsers().LookupParser("Custom.Test"))
+
+	// updated types are registered again
+	updated := *logType
+	updated.Fields = append(updated.Fields,
+		&sourcemodels.CustomLogTypeField{Name: aws.String("user"), Type: aws.String(sourcemodels.CustomFieldTypeString)})
+	RegisterCustomLogTypes([]*sourcemodels.CustomLogType{&updated})
+	updatedLPM := AvailableParsers().LookupParser("Custom.Test")
+	assert.NotSame(t, lpm, updatedLPM)
+
+	// invalid updates keep the previous definition
+	invalid := updated
+	invalid.Fields = nil
+	RegisterCustomLogTypes([]*sourcemodels.CustomLogType{&invalid})
+	assert.Same(t, updatedLPM, AvailableParsers().LookupParser("Custom.Test"))
+
+	// deleted types are removed
+	RegisterCustomLogTypes(nil)
+	assert.NotContains(t, AvailableParsers(), "Custom.Test")
+}
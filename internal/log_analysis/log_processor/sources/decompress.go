@@ -0,0 +1,511 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+const (
+	// http.DetectContentType uses up to the first 512 bytes, the tar magic is at offset 257
+	sniffLen = 512
+	// tar headers have the "ustar" magic at this offset
+	tarMagicOffset = 257
+	// compressed data is usually compressed once, this bounds the work done on crafted data
+	maxCompressionLayers = 2
+)
+
+// The limits on the data read from an object, variables so tests can lower them
+var (
+	// archives are spooled to the Lambda /tmp storage, which is 512MB
+	maxArchiveBytes int64 = 256 * 1024 * 1024
+	// the data of an object after decompression, this bounds the work done on compression bombs
+	maxDecompressedBytes int64 = 8 * 1024 * 1024 * 1024
+)
+
+// ErrUnsupportedData is the cause of the errors for data that cannot be read by the log processor (e.g., binary data),
+// reading it again fails the same way
+var ErrUnsupportedData = errors.New("unsupported data")
+
+// compression is a compression format recognized by the magic bytes at the start of the data
+type compression struct {
+	contentType string
+	magic       []byte
+	newReader   func(r io.Reader) (io.Reader, error)
+}
+
+// archive is an archive format containing many files, recognized by the first bytes of the data
+type archive struct {
+	contentType string
+	match       func(header []byte) bool
+	open        func(file *os.File, size int64) ([]*archiveEntry, error)
+}
+
+// archiveEntry is a file inside an archive
+type archiveEntry struct {
+	path string
+	open func() (io.ReadCloser, error)
+	// releases the archive once all its entries are released, it can be called more than once
+	release func() error
+}
+
+// The supported formats, new formats are added here
+var (
+	compressions = []*compression{
+		{
+			contentType: "application/x-gzip",
+			magic:       []byte{0x1f, 0x8b},
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		},
+		{
+			contentType: "application/zstd",
+			magic:       []byte{0x28, 0xb5, 0x2f, 0xfd},
+			newReader: func(r io.Reader) (io.Reader, error) {
+				decoder, err := zstd.NewReader(r)
+				if err != nil {
+					return nil, err
+				}
+				// the decoder runs goroutines until it is closed
+				return &eofCloser{reader: decoder, close: func() error {
+					decoder.Close()
+					return nil
+				}}, nil
+			},
+		},
+		{
+			contentType: "application/x-bzip2",
+			magic:       []byte("BZh"),
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return bzip2.NewReader(r), nil
+			},
+		},
+	}
+
+	archives = []*archive{
+		{
+			contentType: "application/zip",
+			match: func(header []byte) bool {
+				// the second signature is an empty archive
+				return bytes.HasPrefix(header, []byte("PK\x03\x04")) || bytes.HasPrefix(header, []byte("PK\x05\x06"))
+			},
+			open: openZip,
+		},
+		{
+			contentType: "application/x-tar",
+			match: func(header []byte) bool {
+				return len(header) > tarMagicOffset+5 && string(header[tarMagicOffset:tarMagicOffset+5]) == "ustar"
+			},
+			open: openTar,
+		},
+	}
+)
+
+// decompressedFile is a file read from the data, after decompression and expansion of archives
+type decompressedFile struct {
+	reader io.Reader
+	// closes the readers of the file, it must be called once the file is read or abandoned
+	closer io.Closer
+	// the content type of the data before decompression
+	contentType string
+	// the path of the file inside an archive, empty if the data is not an archive
+	archivePath string
+}
+
+// decompress returns the files of the data: plain text is returned as is, compressed data is decompressed and the
+// files of archives are returned separately. Unsupported formats (e.g., binary data) are an error.
+func decompress(reader io.Reader) (files []*decompressedFile, err error) {
+	bufferedReader := bufio.NewReader(reader)
+	contentType, err := detectContentType(bufferedReader)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := &byteLimit{remaining: maxDecompressedBytes}
+	var closers multiCloser // the decompression readers
+	defer func() {
+		if err != nil {
+			closers.Close() // nolint:errcheck
+		}
+	}()
+	stream := io.Reader(bufferedReader)
+	for layer := 0; ; layer++ {
+		if archive := findArchive(bufferedReader); archive != nil {
+			entries, err := spoolArchive(archive, stream)
+			if err != nil {
+				return nil, err
+			}
+			closers.Close() // nolint:errcheck // the archive was read entirely
+			files := make([]*decompressedFile, len(entries))
+			for i, entry := range entries {
+				entry := entry
+				entryReader := &lazyReader{
+					open: func() (io.Reader, io.Closer, error) {
+						reader, closer, err := entry.openDecompressed()
+						if err != nil {
+							return nil, nil, err
+						}
+						return limit.reader(reader), closer, nil
+					},
+					release: entry.release,
+				}
+				files[i] = &decompressedFile{
+					reader:      entryReader,
+					closer:      entryReader,
+					contentType: contentType,
+					archivePath: entry.path,
+				}
+			}
+			return files, nil
+		}
+
+		compression := findCompression(bufferedReader)
+		if compression == nil {
+			if err := checkPlainText(bufferedReader); err != nil {
+				return nil, err
+			}
+			if layer > 0 {
+				stream = limit.reader(stream)
+			}
+			return []*decompressedFile{{reader: stream, closer: closers, contentType: contentType}}, nil
+		}
+		if layer == maxCompressionLayers {
+			return nil, errors.Wrapf(ErrUnsupportedData, "data is compressed more than %d times", maxCompressionLayers)
+		}
+		if stream, err = compression.newReader(bufferedReader); err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s reader", compression.contentType)
+		}
+		if closer, ok := stream.(io.Closer); ok {
+			closers = append(closers, closer)
+		}
+		bufferedReader = bufio.NewReader(stream)
+		stream = bufferedReader
+	}
+}
+
+// openDecompressed opens the entry and decompresses it, archives inside archives are not supported.
+// The returned closer closes the readers of the entry.
+func (entry *archiveEntry) openDecompressed() (_ io.Reader, _ io.Closer, err error) {
+	entryReader, err := entry.open()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to open %s in archive", entry.path)
+	}
+	closers := multiCloser{entryReader}
+	defer func() {
+		if err != nil {
+			closers.Close() // nolint:errcheck
+		}
+	}()
+	reader := io.Reader(entryReader)
+	bufferedReader := bufio.NewReader(reader)
+	if archive := findArchive(bufferedReader); archive != nil {
+		return nil, nil, errors.Wrapf(ErrUnsupportedData, "%s in archive is an archive (%s), nested archives are not supported",
+			entry.path, archive.contentType)
+	}
+	if compression := findCompression(bufferedReader); compression != nil {
+		if reader, err = compression.newReader(bufferedReader); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to create %s reader for %s in archive", compression.contentType, entry.path)
+		}
+		if closer, ok := reader.(io.Closer); ok {
+			closers = append(closers, closer)
+		}
+		bufferedReader = bufio.NewReader(reader)
+	}
+	if err := checkPlainText(bufferedReader); err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot read %s in archive", entry.path)
+	}
+	return bufferedReader, closers, nil
+}
+
+// peek returns the first bytes of the data, less if the data is shorter
+func peek(reader *bufio.Reader) ([]byte, error) {
+	header, err := reader.Peek(sniffLen)
+	if err != nil && err != bufio.ErrBufferFull && err != io.EOF { // EOF or ErrBufferFull means data is shorter
+		return nil, errors.Wrap(err, "failed to Peek()")
+	}
+	return header, nil
+}
+
+func detectContentType(reader *bufio.Reader) (string, error) {
+	header, err := peek(reader)
+	if err != nil {
+		return "", err
+	}
+	for _, compression := range compressions {
+		if bytes.HasPrefix(header, compression.magic) {
+			return compression.contentType, nil
+		}
+	}
+	for _, archive := range archives {
+		if archive.match(header) {
+			return archive.contentType, nil
+		}
+	}
+	return http.DetectContentType(header), nil
+}
+
+func findCompression(reader *bufio.Reader) *compression {
+	header, _ := peek(reader) // read errors are returned by the reader
+	for _, compression := range compressions {
+		if bytes.HasPrefix(header, compression.magic) {
+			return compression
+		}
+	}
+	return nil
+}
+
+func findArchive(reader *bufio.Reader) *archive {
+	header, _ := peek(reader) // read errors are returned by the reader
+	for _, archive := range archives {
+		if archive.match(header) {
+			return archive
+		}
+	}
+	return nil
+}
+
+// checkPlainText returns an error if the data is not text (e.g., an unsupported compression format)
+func checkPlainText(reader *bufio.Reader) error {
+	header, err := peek(reader)
+	if err != nil {
+		return err
+	}
+	// Checking for prefix because the returned type can have also charset used
+	if contentType := http.DetectContentType(header); !strings.HasPrefix(contentType, "text/plain") {
+		return errors.Wrapf(ErrUnsupportedData, "unsupported content type %s", contentType)
+	}
+	return nil
+}
+
+// spoolArchive writes the archive to a temporary file so its entries can be read separately.
+// The file is removed right away, it is closed once all the entries are released.
+func spoolArchive(archive *archive, reader io.Reader) (entries []*archiveEntry, err error) {
+	file, err := ioutil.TempFile("", "archive")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temporary file for archive")
+	}
+	defer func() {
+		if err != nil || len(entries) == 0 {
+			file.Close() // nolint:errcheck
+		}
+	}()
+	if err = os.Remove(file.Name()); err != nil {
+		return nil, errors.Wrap(err, "failed to remove temporary file for archive")
+	}
+
+	size, err := io.Copy(file, io.LimitReader(reader, maxArchiveBytes+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s archive", archive.contentType)
+	}
+	if size > maxArchiveBytes {
+		return nil, errors.Wrapf(ErrUnsupportedData, "%s archive is larger than %d bytes", archive.contentType, maxArchiveBytes)
+	}
+	entries, err = archive.open(file, size)
+	if err != nil {
+		return nil, errors.Wrapf(ErrUnsupportedData, "failed to read %s archive: %s", archive.contentType, err)
+	}
+
+	// close the file once all entries are released
+	var lock sync.Mutex
+	openEntries := len(entries)
+	for _, entry := range entries {
+		var once sync.Once
+		entry.release = func() (err error) {
+			once.Do(func() {
+				lock.Lock()
+				defer lock.Unlock()
+				if openEntries--; openEntries == 0 {
+					err = file.Close()
+				}
+			})
+			return err
+		}
+	}
+	return entries, nil
+}
+
+func openZip(file *os.File, size int64) (entries []*archiveEntry, err error) {
+	zipReader, err := zip.NewReader(file, size)
+	if err != nil {
+		return nil, err
+	}
+	for _, zipFile := range zipReader.File {
+		if zipFile.FileInfo().IsDir() {
+			continue
+		}
+		entries = append(entries, &archiveEntry{
+			path: zipFile.Name,
+			open: zipFile.Open,
+		})
+	}
+	return entries, nil
+}
+
+func openTar(file *os.File, size int64) (entries []*archiveEntry, err error) {
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	// the tar reader reads whole blocks so the data of an entry starts where the reader stopped after its header
+	counter := &countingReader{reader: file}
+	tarReader := tar.NewReader(counter)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		section := io.NewSectionReader(file, counter.count, header.Size)
+		entries = append(entries, &archiveEntry{
+			path: header.Name,
+			open: func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(section), nil
+			},
+		})
+	}
+}
+
+// countingReader counts the bytes read
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+// eofCloser closes the reader once it is read entirely (or fails), so its resources are released before the stream is closed
+type eofCloser struct {
+	reader io.Reader
+	close  func() error
+	closed bool
+}
+
+func (r *eofCloser) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, io.EOF
+	}
+	n, err := r.reader.Read(p)
+	if err != nil {
+		if closeErr := r.Close(); closeErr != nil && err == io.EOF {
+			err = closeErr
+		}
+	}
+	return n, err
+}
+
+// Close closes the reader, it can be called more than once
+func (r *eofCloser) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	return r.close()
+}
+
+// lazyReader opens the reader when it is first read, so the files of an archive are not all open at once
+type lazyReader struct {
+	open    func() (io.Reader, io.Closer, error)
+	release func() error
+	reader  io.Reader
+	closer  io.Closer
+	err     error
+}
+
+func (r *lazyReader) Read(p []byte) (int, error) {
+	if r.reader == nil && r.err == nil {
+		r.reader, r.closer, r.err = r.open()
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.reader.Read(p)
+}
+
+// Close closes the reader if it was opened and releases the archive, it can be called more than once
+func (r *lazyReader) Close() error {
+	var err error
+	if r.closer != nil {
+		err = r.closer.Close()
+		r.closer = nil
+	}
+	r.reader, r.err = nil, os.ErrClosed
+	if releaseErr := r.release(); err == nil {
+		err = releaseErr
+	}
+	return err
+}
+
+// multiCloser closes the readers in reverse order, so readers are closed before the readers they read from
+type multiCloser []io.Closer
+
+func (c multiCloser) Close() (err error) {
+	for i := len(c) - 1; i >= 0; i-- {
+		if closeErr := c[i].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// byteLimit is the number of bytes that can still be read by the readers sharing it
+type byteLimit struct {
+	remaining int64
+}
+
+// reader returns a reader of the data that fails once the limit is reached
+func (l *byteLimit) reader(reader io.Reader) io.Reader {
+	return &limitedReader{reader: reader, limit: l}
+}
+
+type limitedReader struct {
+	reader io.Reader
+	limit  *byteLimit
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if atomic.AddInt64(&r.limit.remaining, -int64(n)) < 0 {
+		return 0, errors.Wrapf(ErrUnsupportedData, "decompressed data is larger than %d bytes", maxDecompressedBytes)
+	}
+	return n, err
+}
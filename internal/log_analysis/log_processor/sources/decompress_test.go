@@ -0,0 +1,264 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testData = "line1\nline2\n"
+
+func TestDecompressPlainText(t *testing.T) {
+	files, err := decompress(bytes.NewReader([]byte(testData)))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "text/plain; charset=utf-8", files[0].contentType)
+	assert.Empty(t, files[0].archivePath)
+	assert.Equal(t, testData, readAll(t, files[0].reader))
+}
+
+func TestDecompressEmpty(t *testing.T) {
+	files, err := decompress(bytes.NewReader(nil))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "", readAll(t, files[0].reader))
+}
+
+func TestDecompressGzip(t *testing.T) {
+	files, err := decompress(bytes.NewReader(gzipData(t, testData)))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "application/x-gzip", files[0].contentType)
+	assert.Equal(t, testData, readAll(t, files[0].reader))
+}
+
+func TestDecompressZstd(t *testing.T) {
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	data := encoder.EncodeAll([]byte(testData), nil)
+	require.NoError(t, encoder.Close())
+
+	files, err := decompress(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "application/zstd", files[0].contentType)
+	assert.Equal(t, testData, readAll(t, files[0].reader))
+}
+
+func TestDecompressBzip2(t *testing.T) {
+	// python3 -c "import bz2; print(bz2.compress(b'line1\nline2\n'))"
+	data, err := base64.StdEncoding.DecodeString("QlpoOTFBWSZTWRYFFUsAAARJAAAQMAACJSAAMQwAlGh6kmCJwni7kinChICwKKpY")
+	require.NoError(t, err)
+
+	files, err := decompress(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "application/x-bzip2", files[0].contentType)
+	assert.Equal(t, testData, readAll(t, files[0].reader))
+}
+
+func TestDecompressZip(t *testing.T) {
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	_, err := zipWriter.Create("logs/")
+	require.NoError(t, err)
+	writeZipFile(t, zipWriter, "logs/a.log", []byte("a\n"))
+	writeZipFile(t, zipWriter, "logs/b.log.gz", gzipData(t, "b\n"))
+	require.NoError(t, zipWriter.Close())
+
+	files, err := decompress(&buffer)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "application/zip", files[0].contentType)
+	assert.Equal(t, "logs/a.log", files[0].archivePath)
+	assert.Equal(t, "logs/b.log.gz", files[1].archivePath)
+	// the files can be read in any order
+	assert.Equal(t, "b\n", readAll(t, files[1].reader))
+	assert.Equal(t, "a\n", readAll(t, files[0].reader))
+}
+
+func TestDecompressEmptyZip(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, zip.NewWriter(&buffer).Close())
+
+	files, err := decompress(&buffer)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestDecompressTarGzip(t *testing.T) {
+	var buffer bytes.Buffer
+	tarWriter := tar.NewWriter(&buffer)
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "logs/", Typeflag: tar.TypeDir, Mode: 0755}))
+	writeTarFile(t, tarWriter, "logs/a.log", []byte("a\n"))
+	writeTarFile(t, tarWriter, "logs/b.log", bytes.Repeat([]byte("b\n"), 1000))
+	require.NoError(t, tarWriter.Close())
+
+	files, err := decompress(bytes.NewReader(gzipData(t, buffer.String())))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "application/x-gzip", files[0].contentType)
+	assert.Equal(t, "logs/a.log", files[0].archivePath)
+	assert.Equal(t, "logs/b.log", files[1].archivePath)
+	assert.Equal(t, "a\n", readAll(t, files[0].reader))
+	assert.Equal(t, string(bytes.Repeat([]byte("b\n"), 1000)), readAll(t, files[1].reader))
+}
+
+func TestDecompressUnsupported(t *testing.T) {
+	// xz magic
+	_, err := decompress(bytes.NewReader([]byte{0xfd, '7', 'z', 'X', 'Z', 0x00, 0x00, 0x04}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported content type")
+}
+
+func TestDecompressNestedArchive(t *testing.T) {
+	var inner bytes.Buffer
+	innerWriter := zip.NewWriter(&inner)
+	writeZipFile(t, innerWriter, "a.log", []byte("a\n"))
+	require.NoError(t, innerWriter.Close())
+
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	writeZipFile(t, zipWriter, "inner.zip", inner.Bytes())
+	require.NoError(t, zipWriter.Close())
+
+	files, err := decompress(&buffer)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	_, err = ioutil.ReadAll(files[0].reader)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nested archives are not supported")
+}
+
+func TestDecompressArchiveTooLarge(t *testing.T) {
+	defer func(max int64) { maxArchiveBytes = max }(maxArchiveBytes)
+	maxArchiveBytes = 1024
+
+	var buffer bytes.Buffer
+	tarWriter := tar.NewWriter(&buffer)
+	writeTarFile(t, tarWriter, "a.log", bytes.Repeat([]byte("a\n"), 1000))
+	require.NoError(t, tarWriter.Close())
+
+	_, err := decompress(bytes.NewReader(gzipData(t, buffer.String())))
+	require.Error(t, err)
+	assert.Equal(t, ErrUnsupportedData, errors.Cause(err))
+}
+
+func TestDecompressTooLarge(t *testing.T) {
+	defer func(max int64) { maxDecompressedBytes = max }(maxDecompressedBytes)
+	maxDecompressedBytes = 1024
+
+	files, err := decompress(bytes.NewReader(gzipData(t, strings.Repeat("a\n", 1000))))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	_, err = ioutil.ReadAll(files[0].reader)
+	require.Error(t, err)
+	assert.Equal(t, ErrUnsupportedData, errors.Cause(err))
+	require.NoError(t, files[0].closer.Close())
+}
+
+func TestDecompressZipTooLarge(t *testing.T) {
+	defer func(max int64) { maxDecompressedBytes = max }(maxDecompressedBytes)
+	maxDecompressedBytes = 1500
+
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	writeZipFile(t, zipWriter, "a.log", bytes.Repeat([]byte("a\n"), 500))
+	writeZipFile(t, zipWriter, "b.log", bytes.Repeat([]byte("b\n"), 500))
+	require.NoError(t, zipWriter.Close())
+
+	files, err := decompress(&buffer)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	// the limit applies to all the files of the archive
+	assert.Len(t, readAll(t, files[0].reader), 1000)
+	_, err = ioutil.ReadAll(files[1].reader)
+	require.Error(t, err)
+	assert.Equal(t, ErrUnsupportedData, errors.Cause(err))
+}
+
+func TestDecompressZipClose(t *testing.T) {
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	writeZipFile(t, zipWriter, "a.log", []byte("a\n"))
+	writeZipFile(t, zipWriter, "b.log", []byte("b\n"))
+	require.NoError(t, zipWriter.Close())
+
+	entries, err := spoolArchive(archives[0], &buffer)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	files := []*lazyReader{
+		{open: entries[0].openDecompressed, release: entries[0].release},
+		{open: entries[1].openDecompressed, release: entries[1].release},
+	}
+	assert.Equal(t, "a\n", readAll(t, files[0]))
+	require.NoError(t, files[0].Close())
+	require.NoError(t, files[0].Close()) // closing more than once releases the archive once
+	_, err = entries[1].open()
+	require.NoError(t, err)
+
+	// the archive is released once all files are closed, even those never read
+	require.NoError(t, files[1].Close())
+	_, err = entries[1].open()
+	require.Error(t, err)
+	_, err = files[1].Read(make([]byte, 1))
+	assert.Equal(t, os.ErrClosed, err)
+}
+
+func gzipData(t *testing.T, data string) []byte {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, err := writer.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func writeZipFile(t *testing.T, zipWriter *zip.Writer, name string, data []byte) {
+	writer, err := zipWriter.Create(name)
+	require.NoError(t, err)
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+}
+
+func writeTarFile(t *testing.T, tarWriter *tar.Writer, name string, data []byte) {
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Size: int64(len(data)), Mode: 0644}))
+	_, err := tarWriter.Write(data)
+	require.NoError(t, err)
+}
+
+func readAll(t *testing.T, reader io.Reader) string {
+	data, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	return string(data)
+}
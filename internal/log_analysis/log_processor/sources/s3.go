@@ -19,16 +19,11 @@ package sources
  */
 
 import (
-	"bufio"
-	"compress/gzip"
-	"io"
-	"net/http"
-	"strings"
-
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/sns"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
@@ -40,6 +35,11 @@ import (
 const (
 	s3TestEvent                 = "s3:TestEvent"
 	cloudTrailValidationMessage = "CloudTrail validation message."
+
+	// ReplayLogTypeAttribute is the SNS message attribute forcing the log type of replayed data
+	ReplayLogTypeAttribute = "replayLogType"
+	// ReplayDestinationPrefixAttribute is the SNS message attribute with the S3 key prefix the replayed data is written to
+	ReplayDestinationPrefixAttribute = "replayDestinationPrefix"
 )
 
 // ReadSnsMessages reads incoming messages containing SNS notifications and returns a slice of DataStream items
@@ -48,6 +48,7 @@ func ReadSnsMessages(messages []string) (result []*common.DataStream, err error)
 	for _, message := range messages {
 		snsNotificationMessage := &SnsNotification{}
 		if err := jsoniter.UnmarshalFromString(message, snsNotificationMessage); err != nil {
+			CloseDataStreams(result)
 			return nil, err
 		}
 
@@ -55,15 +56,18 @@ func ReadSnsMessages(messages []string) (result []*common.DataStream, err error)
 		case "Notification":
 			streams, err := handleNotificationMessage(snsNotificationMessage)
 			if err != nil {
+				CloseDataStreams(result)
 				return nil, err
 			}
 			result = append(result, streams...)
 		case "SubscriptionConfirmation":
 			err := ConfirmSubscription(snsNotificationMessage)
 			if err != nil {
+				CloseDataStreams(result)
 				return nil, err
 			}
 		default:
+			CloseDataStreams(result)
 			return nil, errors.New("received unexpected message in SQS queue")
 		}
 	}
@@ -101,34 +105,80 @@ func handleNotificationMessage(notification *SnsNotification) (result []*common.
 	if err != nil {
 		return nil, err
 	}
+	replayLogType := messageAttributeValue(notification, ReplayLogTypeAttribute)
+	replayPrefix := messageAttributeValue(notification, ReplayDestinationPrefixAttribute)
 	for _, s3Object := range s3Objects {
-		var dataStream *common.DataStream
-		dataStream, err = readS3Object(s3Object)
+		var dataStreams []*common.DataStream
+		dataStreams, err = readS3Object(s3Object)
 		if err != nil {
-			return
+			// data that cannot be read fails the same way when retried, it would block the whole batch
+			if errors.Cause(err) == ErrUnsupportedData {
+				zap.L().Warn("skipping unsupported S3 object",
+					zap.String("bucket", s3Object.S3Bucket),
+					zap.String("key", s3Object.S3ObjectKey),
+					zap.Error(err))
+				err = nil
+				continue
+			}
+			CloseDataStreams(result)
+			return nil, err
+		}
+		for _, dataStream := range dataStreams {
+			if replayLogType != "" { // replayed data is parsed as the log type chosen by the operator
+				dataStream.LogTypes = []string{replayLogType}
+			}
+			dataStream.DestinationPrefix = replayPrefix
 		}
-		result = append(result, dataStream)
+		result = append(result, dataStreams...)
 	}
 	return result, err
 }
 
-func readS3Object(s3Object *S3ObjectInfo) (dataStream *common.DataStream, err error) {
+// messageAttributeValue returns the value of a String message attribute of the notification, empty if not set
+func messageAttributeValue(notification *SnsNotification, name string) string {
+	attribute, ok := notification.MessageAttributes[name].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	value, _ := attribute["Value"].(string)
+	return value
+}
+
+func readS3Object(s3Object *S3ObjectInfo) (dataStreams []*common.DataStream, err error) {
+	s3Client, sourceInfo, err := getS3Client(s3Object)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get S3 client for s3://%s/%s",
+			s3Object.S3Bucket, s3Object.S3ObjectKey)
+		return nil, err
+	}
+
+	dataStreams, err = ReadS3Object(s3Client, s3Object)
+	if err != nil {
+		return nil, err
+	}
+	for _, dataStream := range dataStreams {
+		dataStream.LogTypes = aws.StringValueSlice(sourceInfo.LogTypes)
+		dataStream.SourceID = aws.StringValue(sourceInfo.IntegrationID)
+		dataStream.SourceLabel = aws.StringValue(sourceInfo.IntegrationLabel)
+		dataStream.Multiline = sourceInfo.Multiline
+	}
+	return dataStreams, nil
+}
+
+// ReadS3Object returns the DataStreams reading the S3 object with the given client, decompressing it if needed.
+// Archives (e.g., zip or tar.gz) have one DataStream per file. Objects that are not text, compressed text or
+// archives of (compressed) text are an error. The DataStreams must be closed once processed.
+func ReadS3Object(s3Client s3iface.S3API, s3Object *S3ObjectInfo) (dataStreams []*common.DataStream, err error) {
 	operation := common.OpLogManager.Start("readS3Object", common.OpLogS3ServiceDim)
 	defer func() {
 		operation.Stop()
 		operation.Log(err,
 			// s3 dim info
 			zap.String("bucket", s3Object.S3Bucket),
-			zap.String("key", s3Object.S3ObjectKey))
+			zap.String("key", s3Object.S3ObjectKey),
+			zap.Int("files", len(dataStreams)))
 	}()
 
-	s3Client, _, err := getS3Client(s3Object)
-	if err != nil {
-		err = errors.Wrapf(err, "failed to get S3 client for s3://%s/%s",
-			s3Object.S3Bucket, s3Object.S3ObjectKey)
-		return nil, err
-	}
-
 	getObjectInput := &s3.GetObjectInput{
 		Bucket: &s3Object.S3Bucket,
 		Key:    &s3Object.S3ObjectKey,
@@ -140,49 +190,51 @@ func readS3Object(s3Object *S3ObjectInfo) (dataStream *common.DataStream, err er
 		return nil, err
 	}
 
-	bufferedReader := bufio.NewReader(output.Body)
-
-	// We peek into the file header to identify the content type
-	// http.DetectContentType only uses up to the first 512 bytes
-	headerBytes, err := bufferedReader.Peek(512)
+	files, err := decompress(output.Body)
 	if err != nil {
-		if err != bufio.ErrBufferFull && err != io.EOF { // EOF or ErrBufferFull means file is shorter than n
-			err = errors.Wrapf(err, "failed to Peek() in S3 payload for s3://%s/%s",
-				s3Object.S3Bucket, s3Object.S3ObjectKey)
-			return nil, err
-		}
-		err = nil // not really an error
+		output.Body.Close() // nolint:errcheck
+		err = errors.Wrapf(err, "failed to read S3 payload for s3://%s/%s",
+			s3Object.S3Bucket, s3Object.S3ObjectKey)
+		return nil, err
+	}
+	isArchive := len(files) != 1 || files[0].archivePath != ""
+	if isArchive {
+		output.Body.Close() // nolint:errcheck // archives are spooled to a file, the payload was read entirely
 	}
-	contentType := http.DetectContentType(headerBytes)
-
-	var streamReader io.Reader
 
-	// Checking for prefix because the returned type can have also charset used
-	if strings.HasPrefix(contentType, "text/plain") {
-		// if it's plain text, just return the buffered reader
-		streamReader = bufferedReader
-	} else if strings.HasPrefix(contentType, "application/x-gzip") {
-		var gzipReader *gzip.Reader
-		gzipReader, err = gzip.NewReader(bufferedReader)
-		if err != nil {
-			err = errors.Wrapf(err, "failed to created gzip reader for s3://%s/%s",
-				s3Object.S3Bucket, s3Object.S3ObjectKey)
-			return nil, err
+	for _, file := range files {
+		closer := file.closer
+		if !isArchive {
+			closer = multiCloser{output.Body, file.closer}
+		}
+		dataStream := &common.DataStream{
+			Reader: file.reader,
+			Closer: closer,
+			Hints: common.DataStreamHints{
+				S3: &common.S3DataStreamHints{
+					Bucket:      s3Object.S3Bucket,
+					Key:         s3Object.S3ObjectKey,
+					ContentType: file.contentType,
+				},
+			},
 		}
-		streamReader = gzipReader
+		if file.archivePath != "" {
+			dataStream.Hints.Archive = &common.ArchiveDataStreamHints{
+				Path: file.archivePath,
+			}
+		}
+		dataStreams = append(dataStreams, dataStream)
 	}
+	return dataStreams, nil
+}
 
-	dataStream = &common.DataStream{
-		Reader: streamReader,
-		Hints: common.DataStreamHints{
-			S3: &common.S3DataStreamHints{
-				Bucket:      s3Object.S3Bucket,
-				Key:         s3Object.S3ObjectKey,
-				ContentType: contentType,
-			},
-		},
+// CloseDataStreams closes the data streams that will not be processed, logging the errors
+func CloseDataStreams(dataStreams []*common.DataStream) {
+	for _, dataStream := range dataStreams {
+		if err := dataStream.Close(); err != nil {
+			zap.L().Warn("failed to close data stream", zap.Error(err))
+		}
 	}
-	return dataStream, err
 }
 
 // ParseNotification parses a message received
@@ -89,20 +89,20 @@ func init() {
 
 // getS3Client Fetches
 // 1. S3 client with permissions to read data from the account that contains the event
-// 2. The type of the integration
-func getS3Client(s3Object *S3ObjectInfo) (s3iface.S3API, string, error) {
+// 2. The source integration the S3 object belongs to
+func getS3Client(s3Object *S3ObjectInfo) (s3iface.S3API, *models.SourceIntegration, error) {
 	sourceInfo, err := getSourceInfo(s3Object)
 	if err != nil {
-		return nil, "", errors.Wrapf(err, "failed to fetch the appropriate role arn to retrieve S3 object %#v", s3Object)
+		return nil, nil, errors.Wrapf(err, "failed to fetch the appropriate role arn to retrieve S3 object %#v", s3Object)
 	}
 
 	if sourceInfo == nil {
-		return nil, "", errors.Errorf("there is no source configured for S3 object %#v", s3Object)
+		return nil, nil, errors.Errorf("there is no source configured for S3 object %#v", s3Object)
 	}
 	roleArn := getSourceLogProcessingRole(sourceInfo)
 	awsCreds := getAwsCredentials(roleArn)
 	if awsCreds == nil {
-		return nil, "", errors.Errorf("failed to fetch credentials for assumed role to read %#v", s3Object)
+		return nil, nil, errors.Errorf("failed to fetch credentials for assumed role to read %#v", s3Object)
 	}
 
 	bucketRegion, ok := bucketCache.Get(s3Object.S3Bucket)
@@ -110,7 +110,7 @@ func getS3Client(s3Object *S3ObjectInfo) (s3iface.S3API, string, error) {
 		zap.L().Debug("bucket region was not cached, fetching it", zap.String("bucket", s3Object.S3Bucket))
 		bucketRegion, err = getBucketRegion(s3Object.S3Bucket, awsCreds)
 		if err != nil {
-			return nil, "", err
+			return nil, nil, err
 		}
 		bucketCache.Add(s3Object.S3Bucket, bucketRegion)
 	}
@@ -130,7 +130,7 @@ func getS3Client(s3Object *S3ObjectInfo) (s3iface.S3API, string, error) {
 		client = newS3ClientFunc(aws.String(bucketRegionString), awsCreds)
 		s3ClientCache.Add(cacheKey, client)
 	}
-	return client.(s3iface.S3API), *sourceInfo.IntegrationType, nil
+	return client.(s3iface.S3API), sourceInfo, nil
 }
 
 func getBucketRegion(s3Bucket string, awsCreds *credentials.Credentials) (string, error) {
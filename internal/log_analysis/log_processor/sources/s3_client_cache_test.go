@@ -82,16 +82,16 @@ func TestGetS3Client(t *testing.T) {
 		S3Bucket:    "test-bucket",
 		S3ObjectKey: "prefix/key",
 	}
-	result, sourceType, err := getS3Client(s3Object)
+	result, source, err := getS3Client(s3Object)
 	require.NoError(t, err)
 	require.NotNil(t, result)
-	require.Equal(t, models.IntegrationTypeAWS3, sourceType)
+	require.Equal(t, integration, source)
 
 	// Subsequent calls should use cache
-	result, sourceType, err = getS3Client(s3Object)
+	result, source, err = getS3Client(s3Object)
 	require.NoError(t, err)
 	require.NotNil(t, result)
-	require.Equal(t, models.IntegrationTypeAWS3, sourceType)
+	require.Equal(t, integration, source)
 
 	s3Mock.AssertExpectations(t)
 	lambdaMock.AssertExpectations(t)
@@ -125,10 +125,10 @@ func TestGetS3ClientUnknownBucket(t *testing.T) {
 		S3ObjectKey: "prefix/key",
 	}
 
-	result, sourceType, err := getS3Client(s3Object)
+	result, source, err := getS3Client(s3Object)
 	require.Error(t, err)
 	require.Nil(t, result)
-	require.Equal(t, "", sourceType)
+	require.Nil(t, source)
 
 	s3Mock.AssertExpectations(t)
 	lambdaMock.AssertExpectations(t)
@@ -175,10 +175,10 @@ func TestGetS3ClientSourceNoPrefix(t *testing.T) {
 		S3ObjectKey: "test",
 	}
 
-	result, sourceType, err := getS3Client(s3Object)
+	result, source, err := getS3Client(s3Object)
 	require.NoError(t, err)
 	require.NotNil(t, result)
-	require.Equal(t, models.IntegrationTypeAWS3, sourceType)
+	require.Equal(t, integration, source)
 
 	s3Mock.AssertExpectations(t)
 	lambdaMock.AssertExpectations(t)
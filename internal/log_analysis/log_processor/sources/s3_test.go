@@ -19,9 +19,17 @@ package sources
  */
 
 import (
+	"bytes"
+	"io/ioutil"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/service/s3"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/pkg/testutils"
 )
 
 func TestParseCloudTrailNotification(t *testing.T) {
@@ -84,3 +92,54 @@ func TestParseUnknownMessage(t *testing.T) {
 	_, err := ParseNotification(notification)
 	require.Error(t, err)
 }
+
+func TestReplayMessageAttributes(t *testing.T) {
+	message := `{"Type":"Notification","Message":"{}","MessageAttributes":{` +
+		`"replayLogType":{"Type":"String","Value":"AWS.VPCFlow"}}}`
+	notification := &SnsNotification{}
+	require.NoError(t, jsoniter.UnmarshalFromString(message, notification))
+	require.Equal(t, "AWS.VPCFlow", messageAttributeValue(notification, ReplayLogTypeAttribute))
+	require.Equal(t, "", messageAttributeValue(notification, ReplayDestinationPrefixAttribute))
+}
+
+func TestReadS3Object(t *testing.T) {
+	body := &closeRecorder{Reader: bytes.NewReader(gzipData(t, testData))}
+	s3Mock := &testutils.S3Mock{}
+	s3Mock.On("GetObject", mock.Anything).Return(&s3.GetObjectOutput{Body: body}, nil).Once()
+
+	dataStreams, err := ReadS3Object(s3Mock, &S3ObjectInfo{S3Bucket: "bucket", S3ObjectKey: "key"})
+	require.NoError(t, err)
+	require.Len(t, dataStreams, 1)
+	require.Equal(t, "application/x-gzip", dataStreams[0].Hints.S3.ContentType)
+	data, err := ioutil.ReadAll(dataStreams[0].Reader)
+	require.NoError(t, err)
+	require.Equal(t, testData, string(data))
+	require.False(t, body.closed)
+	require.NoError(t, dataStreams[0].Close())
+	require.True(t, body.closed)
+	s3Mock.AssertExpectations(t)
+}
+
+func TestReadS3ObjectUnsupported(t *testing.T) {
+	body := &closeRecorder{Reader: bytes.NewReader([]byte{0xfd, '7', 'z', 'X', 'Z', 0x00, 0x00, 0x04})}
+	s3Mock := &testutils.S3Mock{}
+	s3Mock.On("GetObject", mock.Anything).Return(&s3.GetObjectOutput{Body: body}, nil).Once()
+
+	_, err := ReadS3Object(s3Mock, &S3ObjectInfo{S3Bucket: "bucket", S3ObjectKey: "key"})
+	require.Error(t, err)
+	// the object is skipped instead of failing the batch
+	require.Equal(t, ErrUnsupportedData, errors.Cause(err))
+	require.True(t, body.closed)
+	s3Mock.AssertExpectations(t)
+}
+
+// closeRecorder records whether the reader was closed
+type closeRecorder struct {
+	*bytes.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
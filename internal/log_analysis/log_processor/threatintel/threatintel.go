@@ -0,0 +1,135 @@
+package threatintel
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package threatintel matches the Panther fields of parsed events against the indicators of compromise
+// of the threat intel feeds managed through the source API.
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+const sourceAPIFunctionName = "panther-source-api"
+
+// Matcher finds the indicators of the threat intel feeds in the Panther fields of events
+type Matcher struct {
+	// indicator type -> normalized indicator -> sorted feeds
+	indicators map[string]map[string][]string
+	count      int
+}
+
+// NewMatcher returns a Matcher for the indicators, invalid indicators are logged and skipped.
+func NewMatcher(indicators []*sourcemodels.ThreatIntelIndicator) *Matcher {
+	matcher := &Matcher{
+		indicators: make(map[string]map[string][]string),
+	}
+	for _, indicator := range indicators {
+		indicatorType, feed := aws.StringValue(indicator.Type), aws.StringValue(indicator.Feed)
+		value, err := sourcemodels.NormalizeThreatIntelIndicator(indicatorType, aws.StringValue(indicator.Value))
+		if err != nil {
+			zap.L().Warn("invalid threat intel indicator", zap.String("feed", feed), zap.Error(err))
+			continue
+		}
+		values, ok := matcher.indicators[indicatorType]
+		if !ok {
+			values = make(map[string][]string)
+			matcher.indicators[indicatorType] = values
+		}
+		feeds := values[value]
+		i := sort.SearchStrings(feeds, feed)
+		if i < len(feeds) && feeds[i] == feed { // duplicate
+			continue
+		}
+		feeds = append(feeds, "")
+		copy(feeds[i+1:], feeds[i:])
+		feeds[i] = feed
+		values[value] = feeds
+		matcher.count++
+	}
+	return matcher
+}
+
+// Load fetches the indicators of all threat intel feeds from the source API, one page at a time
+func Load(lambdaClient lambdaiface.LambdaAPI) (*Matcher, error) {
+	listInput := &sourcemodels.ListThreatIntelIndicatorsInput{}
+	input := &sourcemodels.LambdaInput{ListThreatIntelIndicators: listInput}
+	var indicators []*sourcemodels.ThreatIntelIndicator
+	for {
+		var output sourcemodels.ListThreatIntelIndicatorsOutput
+		if err := genericapi.Invoke(lambdaClient, sourceAPIFunctionName, input, &output); err != nil {
+			return nil, errors.Wrap(err, "failed to list threat intel indicators")
+		}
+		indicators = append(indicators, output.Indicators...)
+		if output.LastEvaluatedKey == nil {
+			return NewMatcher(indicators), nil
+		}
+		listInput.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// Len returns the number of distinct indicators (per feed) of the Matcher
+func (m *Matcher) Len() int {
+	return m.count
+}
+
+// Match adds the indicators found in the ip addresses, domain names and hashes of the event to its p_ioc_matches,
+// an indicator in several feeds is added once for each feed.
+func (m *Matcher) Match(event *parsers.PantherLog) {
+	if m.count == 0 {
+		return
+	}
+	m.match(event, sourcemodels.ThreatIntelIndicatorIP, event.PantherAnyIPAddresses)
+	m.match(event, sourcemodels.ThreatIntelIndicatorDomain, event.PantherAnyDomainNames)
+	m.match(event, sourcemodels.ThreatIntelIndicatorMD5, event.PantherAnyMD5Hashes)
+	m.match(event, sourcemodels.ThreatIntelIndicatorSHA1, event.PantherAnySHA1Hashes)
+	m.match(event, sourcemodels.ThreatIntelIndicatorSHA256, event.PantherAnySHA256Hashes)
+}
+
+func (m *Matcher) match(event *parsers.PantherLog, indicatorType string, field *parsers.PantherAnyString) {
+	values := m.indicators[indicatorType]
+	if len(values) == 0 {
+		return
+	}
+	for _, value := range field.Values() {
+		indicator, err := sourcemodels.NormalizeThreatIntelIndicator(indicatorType, value)
+		if err != nil {
+			continue
+		}
+		feeds, found := values[indicator]
+		if !found {
+			continue
+		}
+		for _, feed := range feeds {
+			event.PantherIOCMatches = append(event.PantherIOCMatches, parsers.PantherIOCMatch{
+				Indicator: aws.String(indicator),
+				Type:      aws.String(indicatorType),
+				Feed:      aws.String(feed),
+			})
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package threatintel
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	sourcemodels "github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func indicator(feed, indicatorType, value string) *sourcemodels.ThreatIntelIndicator {
+	return &sourcemodels.ThreatIntelIndicator{
+		Feed:  aws.String(feed),
+		Type:  aws.String(indicatorType),
+		Value: aws.String(value),
+	}
+}
+
+func iocMatch(indicatorType, indicator, feed string) parsers.PantherIOCMatch {
+	return parsers.PantherIOCMatch{
+		Indicator: aws.String(indicator),
+		Type:      aws.String(indicatorType),
+		Feed:      aws.String(feed),
+	}
+}
+
+func TestMatch(t *testing.T) {
+	matcher := NewMatcher([]*sourcemodels.ThreatIntelIndicator{
+		indicator("tor", "ip", "192.0.2.1"),
+		indicator("botnet", "ip", "192.0.2.1"),
+		indicator("botnet", "ip", "192.0.2.1"), // duplicate
+		indicator("botnet", "ip", "2001:db8::1"),
+		indicator("phishing", "domain", "Evil.Example.com"),
+		indicator("malware", "sha256", "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855"),
+		indicator("malware", "md5", "not a hash"), // invalid, skipped
+	})
+	assert.Equal(t, 5, matcher.Len())
+
+	event := &parsers.PantherLog{}
+	event.AppendAnyIPAddress("192.0.2.1")
+	event.AppendAnyIPAddress("192.0.2.2")
+	event.AppendAnyIPAddress("2001:DB8::1")
+	event.AppendAnyDomainNames("evil.example.com.")
+	event.AppendAnyDomainNames("example.com")
+	event.AppendAnySHA256Hashes("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	event.AppendAnyMD5Hashes("d41d8cd98f00b204e9800998ecf8427e")
+	matcher.Match(event)
+
+	assert.Equal(t, []parsers.PantherIOCMatch{
+		iocMatch("ip", "192.0.2.1", "botnet"),
+		iocMatch("ip", "192.0.2.1", "tor"),
+		iocMatch("ip", "2001:db8::1", "botnet"),
+		iocMatch("domain", "evil.example.com", "phishing"),
+		iocMatch("sha256", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "malware"),
+	}, event.PantherIOCMatches)
+}
+
+func TestMatchNoIndicators(t *testing.T) {
+	event := &parsers.PantherLog{}
+	event.AppendAnyIPAddress("192.0.2.1")
+	NewMatcher(nil).Match(event)
+	assert.Nil(t, event.PantherIOCMatches)
+}
+
+func TestLoad(t *testing.T) {
+	lambdaMock := &testutils.LambdaMock{}
+	firstPage, err := jsoniter.Marshal(&sourcemodels.ListThreatIntelIndicatorsOutput{
+		Indicators:       []*sourcemodels.ThreatIntelIndicator{indicator("tor", "ip", "192.0.2.1")},
+		LastEvaluatedKey: aws.String("tor#ip#192.0.2.1"),
+	})
+	require.NoError(t, err)
+	lastPage, err := jsoniter.Marshal(&sourcemodels.ListThreatIntelIndicatorsOutput{
+		Indicators: []*sourcemodels.ThreatIntelIndicator{indicator("tor", "ip", "192.0.2.2")},
+	})
+	require.NoError(t, err)
+	lambdaMock.On("Invoke", mock.Anything).Return(&lambda.InvokeOutput{Payload: firstPage}, nil).Once()
+	lambdaMock.On("Invoke", mock.MatchedBy(func(input *lambda.InvokeInput) bool {
+		return strings.Contains(string(input.Payload), `"exclusiveStartKey":"tor#ip#192.0.2.1"`)
+	})).Return(&lambda.InvokeOutput{Payload: lastPage}, nil).Once()
+
+	matcher, err := Load(lambdaMock)
+	require.NoError(t, err)
+	assert.Equal(t, 2, matcher.Len())
+	lambdaMock.AssertExpectations(t)
+}
+
+func TestLoadError(t *testing.T) {
+	lambdaMock := &testutils.LambdaMock{}
+	lambdaMock.On("Invoke", mock.Anything).Return(&lambda.InvokeOutput{}, errors.New("failed")).Once()
+
+	_, err := Load(lambdaMock)
+	require.Error(t, err)
+	lambdaMock.AssertExpectations(t)
+}
@@ -122,7 +122,11 @@ def _load_event(event: Dict[str, Any]) -> Dict[str, List[TextIOWrapper]]:
     for record in event['Records']:
         record_body = json.loads(record['body'])
         log_type = record['messageAttributes']['id']['stringValue']  # id attr holds log type
+        # data stored as Parquet is analyzed through its JSON copy, the analysisCopyKey attr holds its key
+        analysis_copy_key = record['messageAttributes'].get('analysisCopyKey', {}).get('stringValue')
         for bucket, object_key in _load_s3_notifications(record_body['Records']):
+            if analysis_copy_key:
+                object_key = analysis_copy_key
             _LOGGER.debug("loading object from S3, bucket [%s], key [%s]", bucket, object_key)
             log_type_to_data[log_type].append(_load_contents(bucket, object_key))
     return log_type_to_data
@@ -14,7 +14,9 @@
 # You should have received a copy of the GNU Affero General Public License
 # along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
+import json
 import os
+from typing import Any, Dict
 from unittest import TestCase, mock
 
 import boto3
@@ -37,7 +39,7 @@ with mock.patch.dict(os.environ, _ENV_VARIABLES_MOCK),\
      mock.patch.object(boto3, 'client', side_effect=mock_to_return), \
      mock.patch.object(SigV4Auth, 'add_auth'), \
      mock.patch.object(requests, 'get', return_value=_RESPONSE_MOCK):
-    from ..src.main import lambda_handler, _load_s3_notifications
+    from ..src.main import lambda_handler, _load_event, _load_s3_notifications
 
 
 class TestMainDirectAnalysis(TestCase):
@@ -131,3 +133,31 @@ class TestMainLoadS3Notifications(TestCase):
         ]
         expected_response = [('mybucket', 'mykey'), ('mybucket2', 'mykey2')]
         self.assertEqual(expected_response, _load_s3_notifications(notifications))
+
+
+class TestMainLoadEvent(TestCase):
+
+    @staticmethod
+    def _record(key: str, attributes: Dict[str, Any]) -> Dict[str, Any]:
+        notification = {'Records': [{'s3': {'bucket': {'name': 'mybucket'}, 'object': {'key': key, 'size': 100}}}]}
+        return {'body': json.dumps(notification), 'messageAttributes': attributes}
+
+    def test_load_event_analysis_copy(self) -> None:
+        event = {
+            'Records':
+                [
+                    self._record('logs/mytype/data.json.gz', {'id': {'stringValue': 'MyType'}}),
+                    self._record(
+                        'logs/mytype/data.parquet', {
+                            'id': {
+                                'stringValue': 'MyType'
+                            },
+                            'analysisCopyKey': {
+                                'stringValue': 'logs/mytype/_data.json.gz'
+                            }
+                        }
+                    )
+                ]
+        }
+        with mock.patch(_load_event.__module__ + '._load_contents', side_effect=lambda bucket, key: key):
+            self.assertEqual({'MyType': ['logs/mytype/data.json.gz', 'logs/mytype/_data.json.gz']}, _load_event(event))
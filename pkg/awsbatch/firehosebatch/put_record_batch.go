@@ -0,0 +1,112 @@
+package firehosebatch
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/firehose/firehoseiface"
+	"github.com/cenkalti/backoff/v4"
+	"go.uber.org/zap"
+)
+
+// AWS limit: each PutRecordBatch request can support up to 500 records.
+const maxRecords = 500
+
+type putRecordBatchRequest struct {
+	client       firehoseiface.FirehoseAPI
+	input        *firehose.PutRecordBatchInput
+	successCount int // Total number of records that have sent successfully across all requests
+}
+
+// send is a wrapper around firehose.PutRecordBatch which satisfies backoff.Operation.
+func (r *putRecordBatchRequest) send() error {
+	zap.L().Debug("invoking firehose.PutRecordBatch", zap.Int("records", len(r.input.Records)))
+	response, err := r.client.PutRecordBatch(r.input)
+
+	if err != nil {
+		// This was a service error - it can sometimes be retried
+		if awsErr, ok := err.(awserr.Error); ok {
+			if awsErr.Code() == firehose.ErrCodeServiceUnavailableException {
+				zap.L().Warn("backoff: delivery stream throughput exceeded", zap.Error(awsErr))
+				return awsErr
+			}
+		}
+		return &backoff.PermanentError{Err: err}
+	}
+
+	r.successCount += len(r.input.Records)
+
+	// Some subset of the records failed - retry only the failed ones
+	if response.FailedPutCount != nil && *response.FailedPutCount > 0 {
+		r.successCount -= int(*response.FailedPutCount)
+		err = fmt.Errorf("%d failed records", int(*response.FailedPutCount))
+		zap.L().Warn("backoff: batch put record batch failed", zap.Error(err))
+
+		var retryRecords []*firehose.Record
+		for i, result := range response.RequestResponses {
+			if result.ErrorMessage != nil {
+				zap.L().Warn("record failure", zap.String("error", *result.ErrorMessage))
+				retryRecords = append(retryRecords, r.input.Records[i])
+			}
+		}
+		r.input.Records = retryRecords
+		return err
+	}
+
+	return nil
+}
+
+// PutRecordBatch puts records to a Firehose delivery stream with paging, backoff, and auto-retry for failed items.
+func PutRecordBatch(
+	client firehoseiface.FirehoseAPI, maxElapsedTime time.Duration, input *firehose.PutRecordBatchInput) error {
+
+	zap.L().Info("starting firehosebatch.PutRecordBatch", zap.Int("totalRecords", len(input.Records)))
+	start := time.Now()
+
+	config := backoff.NewExponentialBackOff()
+	config.MaxElapsedTime = maxElapsedTime
+	allRecords := input.Records
+	request := &putRecordBatchRequest{client: client, input: input}
+
+	// Break records into multiple requests as necessary
+	for i := 0; i < len(allRecords); i += maxRecords {
+		if i+maxRecords >= len(allRecords) {
+			input.Records = allRecords[i:] // Last batch - whatever is left
+		} else {
+			input.Records = allRecords[i : i+maxRecords]
+		}
+
+		if err := backoff.Retry(request.send, config); err != nil {
+			zap.L().Error(
+				"PutRecordBatch permanently failed",
+				zap.Int("sentRecordCount", request.successCount),
+				zap.Int("failedRecordCount", len(allRecords)-request.successCount),
+				zap.Error(err),
+			)
+			return err
+		}
+	}
+
+	zap.L().Info("PutRecordBatch successful", zap.Duration("duration", time.Since(start)))
+	return nil
+}
@@ -0,0 +1,118 @@
+package firehosebatch
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/firehose/firehoseiface"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockFirehose struct {
+	firehoseiface.FirehoseAPI
+	unprocessedItems bool  // If True, only the first item in each batch will succeed
+	err              error // If AWS error, it will only trigger the first time
+	callCount        int   // Counts the number of PutRecordBatch calls for tests to verify
+}
+
+func (m *mockFirehose) PutRecordBatch(input *firehose.PutRecordBatchInput) (*firehose.PutRecordBatchOutput, error) {
+	m.callCount++
+
+	if m.err != nil {
+		returnErr := m.err
+		if _, ok := m.err.(awserr.Error); ok {
+			m.err = nil // The next call will not return a temporary AWS error
+		}
+		return nil, returnErr
+	}
+
+	result := &firehose.PutRecordBatchOutput{FailedPutCount: aws.Int64(0)}
+	for i := range input.Records {
+		if i == 0 || !m.unprocessedItems {
+			// Success if this is first record or failure not requested
+			result.RequestResponses = append(result.RequestResponses, &firehose.PutRecordBatchResponseEntry{
+				RecordId: aws.String(strconv.Itoa(i)),
+			})
+		} else {
+			// All other records fail
+			*result.FailedPutCount++
+			result.RequestResponses = append(result.RequestResponses, &firehose.PutRecordBatchResponseEntry{
+				ErrorCode:    aws.String("ServiceUnavailableException"),
+				ErrorMessage: aws.String("slow down!"),
+			})
+		}
+	}
+	return result, nil
+}
+
+func testInput() *firehose.PutRecordBatchInput {
+	return &firehose.PutRecordBatchInput{
+		Records: []*firehose.Record{
+			{Data: []byte("hello")},
+			{Data: []byte("world")},
+		},
+		DeliveryStreamName: aws.String("test-delivery-stream-name"),
+	}
+}
+
+func TestPutRecordBatch(t *testing.T) {
+	client := &mockFirehose{}
+	assert.Nil(t, PutRecordBatch(client, 5*time.Second, testInput()))
+	assert.Equal(t, 1, client.callCount)
+}
+
+// Unprocessed items are retried
+func TestPutRecordBatchBackoff(t *testing.T) {
+	client := &mockFirehose{unprocessedItems: true}
+	assert.Nil(t, PutRecordBatch(client, 5*time.Second, testInput()))
+	assert.Equal(t, 2, client.callCount)
+}
+
+// An unusual error is not retried
+func TestPutRecordBatchPermanentError(t *testing.T) {
+	client := &mockFirehose{err: errors.New("permanent")}
+	assert.NotNil(t, PutRecordBatch(client, 5*time.Second, testInput()))
+	assert.Equal(t, 1, client.callCount)
+}
+
+// A temporary error is retried
+func TestPutRecordBatchTemporaryError(t *testing.T) {
+	client := &mockFirehose{
+		err: awserr.New(firehose.ErrCodeServiceUnavailableException, "try again later", nil),
+	}
+	assert.Nil(t, PutRecordBatch(client, 5*time.Second, testInput()))
+	assert.Equal(t, 2, client.callCount)
+}
+
+// A large number of records are broken into multiple requests
+func TestPutRecordBatchPagination(t *testing.T) {
+	client := &mockFirehose{}
+	input := &firehose.PutRecordBatchInput{
+		Records: make([]*firehose.Record, maxRecords*2+1),
+	}
+	assert.Nil(t, PutRecordBatch(client, 5*time.Second, input))
+	assert.Equal(t, 3, client.callCount)
+}
@@ -107,6 +107,11 @@ func (m *DynamoDBMock) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, er
 	return args.Get(0).(*dynamodb.ScanOutput), args.Error(1)
 }
 
+func (m *DynamoDBMock) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*dynamodb.BatchWriteItemOutput), args.Error(1)
+}
+
 type SqsMock struct {
 	sqsiface.SQSAPI
 	mock.Mock
@@ -206,3 +211,8 @@ func (m *GlueMock) UpdatePartition(input *glue.UpdatePartitionInput) (*glue.Upda
 	args := m.Called(input)
 	return args.Get(0).(*glue.UpdatePartitionOutput), args.Error(1)
 }
+
+func (m *GlueMock) CreateTable(input *glue.CreateTableInput) (*glue.CreateTableOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*glue.CreateTableOutput), args.Error(1)
+}
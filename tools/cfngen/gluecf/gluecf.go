@@ -21,101 +21,14 @@ package gluecf
 // CloudFormation generation for Glue tables from parser event struct
 
 import (
-	"reflect"
-
-	jsoniter "github.com/json-iterator/go"
-
 	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
 	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
-	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
-	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/numerics"
-	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+	"github.com/panther-labs/panther/internal/log_analysis/glueschema"
 	"github.com/panther-labs/panther/tools/cfngen"
 )
 
-// Glue tables typ for timestamps that we will re-map Go times
-const GlueTimestampType = "timestamp"
-
 var (
 	CatalogIDRef = cfngen.Ref{Ref: "AWS::AccountId"} // macro expand to accountId for CF
-
-	// GlueMappings for custom Panther types.
-	GlueMappings = []CustomMapping{
-		{
-			From: reflect.TypeOf(timestamp.RFC3339{}),
-			To:   GlueTimestampType,
-		},
-		{
-			From: reflect.TypeOf(timestamp.ANSICwithTZ{}),
-			To:   GlueTimestampType,
-		},
-		{
-			From: reflect.TypeOf(timestamp.UnixMillisecond{}),
-			To:   GlueTimestampType,
-		},
-		{
-			From: reflect.TypeOf(timestamp.FluentdTimestamp{}),
-			To:   GlueTimestampType,
-		},
-		{
-			From: reflect.TypeOf(timestamp.UnixFloat{}),
-			To:   GlueTimestampType,
-		},
-		{
-			From: reflect.TypeOf(timestamp.SuricataTimestamp{}),
-			To:   GlueTimestampType,
-		},
-		{
-			From: reflect.TypeOf(parsers.PantherAnyString{}),
-			To:   "array<string>",
-		},
-		{
-			From: reflect.TypeOf(jsoniter.RawMessage{}),
-			To:   "string",
-		},
-		{
-			From: reflect.TypeOf(*new(numerics.Integer)),
-			To:   "bigint",
-		},
-		{
-			From: reflect.TypeOf(*new(numerics.Int64)),
-			To:   "bigint",
-		},
-	}
-
-	// RuleMatchColumns are columns added by the rules engine
-	RuleMatchColumns = []Column{
-		{
-			Name:    "p_rule_id",
-			Type:    "string",
-			Comment: "Rule id",
-		},
-		{
-			Name:    "p_alert_id",
-			Type:    "string",
-			Comment: "Alert id",
-		},
-		{
-			Name:    "p_alert_creation_time",
-			Type:    "timestamp",
-			Comment: "The time the alert was initially created (first match)",
-		},
-		{
-			Name:    "p_alert_update_time",
-			Type:    "timestamp",
-			Comment: "The time the alert last updated (last match)",
-		},
-		{
-			Name:    "p_rule_tags",
-			Type:    "array<string>",
-			Comment: "The tags of the rule that generated this alert",
-		},
-		{
-			Name:    "p_rule_reports",
-			Type:    "map<string,array<string>>",
-			Comment: "The tags of the rule that generated this alert",
-		},
-	}
 )
 
 // Output CloudFormation for all 'tables'
@@ -154,14 +67,13 @@ func GenerateTables(tables []*awsglue.GlueTableMetadata) (cf []byte, err error)
 		},
 	}
 
-	addTable := func(t *awsglue.GlueTableMetadata, extraColumns ...Column) {
+	addTable := func(t *awsglue.GlueTableMetadata, extraColumns ...glueschema.Column) {
 		location := cfngen.Sub{Sub: "s3://${" + bucketParam + "}/" + t.Prefix()}
 
-		columns := InferJSONColumns(t.EventStruct(), GlueMappings...)
+		columns := glueschema.InferJSONColumns(t.EventStruct(), glueschema.GlueMappings...)
 		columns = append(columns, extraColumns...)
 
-		// NOTE: currently all sources are JSONL (could add a type to LogParserMetadata struct if we need more types)
-		resources[cfngen.SanitizeResourceName(t.DatabaseName()+t.TableName())] = NewJSONLTable(&NewTableInput{
+		tableInput := &NewTableInput{
 			CatalogID:     CatalogIDRef,
 			DatabaseName:  cfngen.Ref{Ref: cfngen.SanitizeResourceName(t.DatabaseName())},
 			Name:          t.TableName(),
@@ -169,25 +81,35 @@ func GenerateTables(tables []*awsglue.GlueTableMetadata) (cf []byte, err error)
 			Location:      location,
 			Columns:       columns,
 			PartitionKeys: getPartitionKeys(t),
-		})
+		}
+
+		resourceName := cfngen.SanitizeResourceName(t.DatabaseName() + t.TableName())
+		if t.Format() == awsglue.ParquetStorageFormat {
+			resources[resourceName] = NewParquetTable(tableInput)
+		} else {
+			resources[resourceName] = NewJSONLTable(tableInput)
+		}
 	}
 
 	// add tables for all parsers, and matching tables for rule matches
 	for _, table := range tables {
 		addTable(table)
+		if table.DataType() != models.LogData { // e.g., classification failures are not processed by rules
+			continue
+		}
 		ruleTable := awsglue.NewGlueTableMetadata(
 			models.RuleData, table.LogType(), table.Description(), awsglue.GlueTableHourly, table.EventStruct())
 		// add a matching table for rule matches, add the columns that the rules engine appends
-		addTable(ruleTable, RuleMatchColumns...)
+		addTable(ruleTable, glueschema.RuleMatchColumns...)
 	}
 
 	// generate CF using cfngen
 	return cfngen.NewTemplate("Panther Glue Resources", parameters, resources, outputs).CloudFormation()
 }
 
-func getPartitionKeys(t *awsglue.GlueTableMetadata) (partitions []Column) {
+func getPartitionKeys(t *awsglue.GlueTableMetadata) (partitions []glueschema.Column) {
 	for _, partition := range t.PartitionKeys() {
-		partitions = append(partitions, Column{
+		partitions = append(partitions, glueschema.Column{
 			Name:    partition.Name,
 			Type:    partition.Type,
 			Comment: partition.Name,
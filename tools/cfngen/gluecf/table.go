@@ -19,8 +19,7 @@ package gluecf
  */
 
 import (
-	"fmt"
-	"strings"
+	"github.com/panther-labs/panther/internal/log_analysis/glueschema"
 )
 
 // Generate CF for a gluecf table: https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-resource-glue-table.html
@@ -29,13 +28,6 @@ import (
 
 // NOTE: the use of type interface{} allows strings and structs (e.g., cfngen.Ref{} and cfngen.Sub{} )
 
-type Column struct {
-	Name     string
-	Type     string // this is the Glue type
-	Comment  string `json:",omitempty"`
-	Required bool   `json:"-"` // do NOT serialize! Not used for Glue CF (used for doc).
-}
-
 type SerdeInfo struct {
 	SerializationLibrary string                 `json:",omitempty"`
 	Parameters           map[string]interface{} `json:",omitempty"`
@@ -44,13 +36,13 @@ type SerdeInfo struct {
 type StorageDescriptor struct { // nolint
 	InputFormat            string
 	OutputFormat           string
-	Compressed             bool        `json:",omitempty"`
-	Location               interface{} // required
-	BucketColumns          []Column    `json:",omitempty"`
-	SortColumns            []Column    `json:",omitempty"`
-	StoredAsSubDirectories bool        `json:",omitempty"`
+	Compressed             bool                `json:",omitempty"`
+	Location               interface{}         // required
+	BucketColumns          []glueschema.Column `json:",omitempty"`
+	SortColumns            []glueschema.Column `json:",omitempty"`
+	StoredAsSubDirectories bool                `json:",omitempty"`
 	SerdeInfo              SerdeInfo
-	Columns                []Column
+	Columns                []glueschema.Column
 }
 
 type TableInput struct {
@@ -58,7 +50,7 @@ type TableInput struct {
 	Name              interface{}
 	Description       interface{} `json:",omitempty"`
 	StorageDescriptor StorageDescriptor
-	PartitionKeys     []Column `json:",omitempty"`
+	PartitionKeys     []glueschema.Column `json:",omitempty"`
 }
 
 type TableProperties struct {
@@ -74,14 +66,14 @@ type Table struct {
 }
 
 // Core function to create a table
-func newExternalTable(catalogID, databaseName, name, description interface{}, sd *StorageDescriptor, pks []Column) (db *Table) {
+func newExternalTable(catalogID, databaseName, name, description interface{}, sd *StorageDescriptor, pks []glueschema.Column) (db *Table) {
 	db = &Table{
 		Type: "AWS::Glue::Table",
 		Properties: TableProperties{
 			CatalogID:    catalogID,
 			DatabaseName: databaseName,
 			TableInput: TableInput{
-				TableType:         "EXTERNAL_TABLE",
+				TableType:         glueschema.ExternalTableType,
 				Name:              name,
 				Description:       description,
 				StorageDescriptor: *sd,
@@ -100,16 +92,16 @@ type NewTableInput struct {
 	Name          interface{}
 	Description   interface{}
 	Location      interface{}
-	Columns       []Column
-	PartitionKeys []Column
+	Columns       []glueschema.Column
+	PartitionKeys []glueschema.Column
 }
 
 func NewParquetTable(input *NewTableInput) (db *Table) {
 	sd := &StorageDescriptor{
-		InputFormat:  "org.apache.hadoop.hive.ql.io.parquet.MapredParquetInputFormat",
-		OutputFormat: "org.apache.hadoop.hive.ql.io.parquet.MapredParquetOutputFormat",
+		InputFormat:  glueschema.ParquetInputFormat,
+		OutputFormat: glueschema.ParquetOutputFormat,
 		SerdeInfo: SerdeInfo{
-			SerializationLibrary: "org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe",
+			SerializationLibrary: glueschema.ParquetSerializationLibrary,
 			Parameters: map[string]interface{}{
 				"serialization.format": "1",
 			},
@@ -122,21 +114,16 @@ func NewParquetTable(input *NewTableInput) (db *Table) {
 }
 
 func NewJSONLTable(input *NewTableInput) (db *Table) {
-	descriptorParameters := map[string]interface{}{
-		"serialization.format": "1",
-		"case.insensitive":     "false", // Need to be case sensitive to deal with columns that have same name but different casing
-	}
-
-	// Adding mapping for column names. This is required when columns are case sensitive
-	for _, column := range input.Columns {
-		descriptorParameters[fmt.Sprintf("mapping.%s", strings.ToLower(column.Name))] = column.Name
+	descriptorParameters := make(map[string]interface{})
+	for key, value := range glueschema.JSONLSerdeParameters(input.Columns) {
+		descriptorParameters[key] = value
 	}
 
 	sd := &StorageDescriptor{
-		InputFormat:  "org.apache.hadoop.mapred.TextInputFormat",
-		OutputFormat: "org.apache.hadoop.hive.ql.io.HiveIgnoreKeyTextOutputFormat",
+		InputFormat:  glueschema.JSONLInputFormat,
+		OutputFormat: glueschema.JSONLOutputFormat,
 		SerdeInfo: SerdeInfo{
-			SerializationLibrary: "org.openx.data.jsonserde.JsonSerDe",
+			SerializationLibrary: glueschema.JSONLSerializationLibrary,
 			Parameters:           descriptorParameters,
 		},
 		Location: input.Location,
@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/panther-labs/panther/internal/log_analysis/glueschema"
 	"github.com/panther-labs/panther/tools/cfngen"
 )
 
@@ -48,12 +49,12 @@ func TestTables(t *testing.T) {
 	resources[dbName] = db
 
 	// same for both tables
-	columns := []Column{
+	columns := []glueschema.Column{
 		{Name: "c1", Type: "int", Comment: "foo"},
 		{Name: "c2", Type: "varchar", Comment: "bar"},
 	}
 
-	partitionKeys := []Column{
+	partitionKeys := []glueschema.Column{
 		{Name: "year", Type: "int", Comment: "year"},
 		{Name: "month", Type: "int", Comment: "month"},
 		{Name: "day", Type: "int", Comment: "day"},
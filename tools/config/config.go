@@ -36,7 +36,13 @@ type PantherConfig struct {
 
 type Infra struct {
 	BaseLayerVersionArns         string   `yaml:"BaseLayerVersionArns"`
+	FirehoseStreamName           string   `yaml:"FirehoseStreamName"`
+	GeoIPDatabaseBucket          string   `yaml:"GeoIPDatabaseBucket"`
+	GeoIPDatabases               []string `yaml:"GeoIPDatabases"`
+	KinesisStreamName            string   `yaml:"KinesisStreamName"`
+	LogProcessorDestinations     []string `yaml:"LogProcessorDestinations"`
 	LogProcessorLambdaMemorySize int      `yaml:"LogProcessorLambdaMemorySize"`
+	ParquetLogTypes              []string `yaml:"ParquetLogTypes"`
 	PipLayer                     []string `yaml:"PipLayer"`
 	PythonLayerVersionArn        string   `yaml:"PythonLayerVersionArn"`
 }
@@ -25,14 +25,26 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
 	"github.com/panther-labs/panther/tools/cfngen/gluecf"
+	"github.com/panther-labs/panther/tools/config"
 	"github.com/panther-labs/panther/tools/dashboards"
 )
 
 // Generate Glue tables for log processor output as CloudFormation
 func generateGlueTables() error {
-	tableResources := registry.AvailableTables()
+	settings, err := config.Settings()
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %v", config.Filepath, err)
+	}
+	// the tables of log types stored as Parquet use the Parquet serde
+	if err = destinations.EnableParquet(settings.Infra.ParquetLogTypes); err != nil {
+		return fmt.Errorf("invalid ParquetLogTypes in %s: %v", config.Filepath, err)
+	}
+
+	tableResources := append(registry.AvailableTables(), classification.FailureTable)
 	logger.Debugf("deploy: cfngen: loaded %d glue tables", len(tableResources))
 	cf, err := gluecf.GenerateTables(tableResources)
 	if err != nil {
@@ -32,6 +32,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/magefile/mage/sh"
 
@@ -40,10 +41,11 @@ import (
 	analysismodels "github.com/panther-labs/panther/api/gateway/analysis/models"
 	orgmodels "github.com/panther-labs/panther/api/lambda/organization/models"
 	usermodels "github.com/panther-labs/panther/api/lambda/users/models"
+	"github.com/panther-labs/panther/internal/log_analysis/athenaviews"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
 	"github.com/panther-labs/panther/pkg/awsathena"
 	"github.com/panther-labs/panther/pkg/gatewayapi"
 	"github.com/panther-labs/panther/pkg/shutil"
-	"github.com/panther-labs/panther/tools/athenaviews"
 	"github.com/panther-labs/panther/tools/config"
 )
 
@@ -385,6 +387,7 @@ func deployMainStacks(awsSession *session.Session, settings *config.PantherConfi
 			"ComplianceApiId":        outputs["ComplianceApiId"],
 			"DynamoScalingRoleArn":   outputs["DynamoScalingRoleArn"],
 			"OutputsKeyId":           outputs["OutputsEncryptionKeyId"],
+			"RedactionKeyId":         outputs["RedactionEncryptionKeyId"],
 			"SqsKeyId":               outputs["QueueEncryptionKeyId"],
 			"UserPoolId":             outputs["UserPoolId"],
 
@@ -415,6 +418,7 @@ func deployMainStacks(awsSession *session.Session, settings *config.PantherConfi
 		_, err := deployTemplate(awsSession, logAnalysisTemplate, sourceBucket, logAnalysisStack, map[string]string{
 			"AlarmTopicArn":         outputs["AlarmTopicArn"],
 			"AnalysisApiId":         outputs["AnalysisApiId"],
+			"AthenaResultsBucket":   outputs["AthenaResultsBucket"],
 			"ProcessedDataBucket":   outputs["ProcessedDataBucket"],
 			"ProcessedDataTopicArn": outputs["ProcessedDataTopicArn"],
 			"PythonLayerVersionArn": outputs["PythonLayerVersionArn"],
@@ -422,8 +426,14 @@ func deployMainStacks(awsSession *session.Session, settings *config.PantherConfi
 
 			"CloudWatchLogRetentionDays":   strconv.Itoa(settings.Monitoring.CloudWatchLogRetentionDays),
 			"Debug":                        strconv.FormatBool(settings.Monitoring.Debug),
+			"FirehoseStreamName":           settings.Infra.FirehoseStreamName,
+			"GeoIPDatabaseBucket":          settings.Infra.GeoIPDatabaseBucket,
+			"GeoIPDatabases":               strings.Join(settings.Infra.GeoIPDatabases, ","),
+			"KinesisStreamName":            settings.Infra.KinesisStreamName,
 			"LayerVersionArns":             settings.Infra.BaseLayerVersionArns,
+			"LogProcessorDestinations":     strings.Join(settings.Infra.LogProcessorDestinations, ","),
 			"LogProcessorLambdaMemorySize": strconv.Itoa(settings.Infra.LogProcessorLambdaMemorySize),
+			"ParquetLogTypes":              strings.Join(settings.Infra.ParquetLogTypes, ","),
 			"TracingMode":                  settings.Monitoring.TracingMode,
 		})
 		c <- goroutineResult{summary: logAnalysisStack, err: err}
@@ -468,6 +478,10 @@ func deployGlue(awsSession *session.Session, outputs map[string]string) error {
 	if err := awsathena.WorkgroupAssociateS3(awsSession, workgroup, athenaBucket); err != nil {
 		return fmt.Errorf("failed to associate %s Athena workgroup with %s bucket: %v", workgroup, athenaBucket, err)
 	}
+	// Custom log types are stored by the source-api, which does not exist yet on the initial deploy
+	if err := registry.LoadCustomLogTypes(lambda.New(awsSession)); err != nil {
+		logger.Warnf("failed to load custom log types, their tables will be added to the views with new data: %v", err)
+	}
 	if err := athenaviews.CreateOrReplaceViews(athenaBucket); err != nil {
 		return fmt.Errorf("failed to create/replace athena views for %s bucket: %v", athenaBucket, err)
 	}
@@ -93,12 +93,22 @@ export const LOG_TYPES = [
   'Apache.AccessCommon',
   'AWS.ALB',
   'AWS.AuroraMySQLAudit',
+  'AWS.ClassicELB',
+  'AWS.CloudFrontAccess',
   'AWS.CloudTrail',
   'AWS.CloudTrailDigest',
   'AWS.CloudTrailInsight',
   'AWS.GuardDuty',
+  'AWS.RedshiftConnection',
+  'AWS.RedshiftUserActivity',
+  'AWS.Route53ResolverQuery',
   'AWS.S3ServerAccess',
   'AWS.VPCFlow',
+  'AWS.WAFWebACL',
+  'Azure.Activity',
+  'Azure.Audit',
+  'Azure.SignIn',
+  'CEF.Event',
   'Fluentd.Syslog3164',
   'Fluentd.Syslog5424',
   'GitLab.API',
@@ -107,16 +117,36 @@ export const LOG_TYPES = [
   'GitLab.Git',
   'GitLab.Integrations',
   'GitLab.Rails',
+  'KeyValue.Event',
+  'Kubernetes.Audit',
+  'LEEF.Event',
   'Nginx.Access',
   'Osquery.Batch',
   'Osquery.Differential',
   'Osquery.Snapshot',
   'Osquery.Status',
   'OSSEC.EventInfo',
+  'Suricata.Alert',
   'Suricata.Anomaly',
   'Suricata.DNS',
+  'Suricata.FileInfo',
+  'Suricata.Flow',
+  'Suricata.HTTP',
+  'Suricata.Netflow',
+  'Suricata.SMTP',
+  'Suricata.SSH',
+  'Suricata.TLS',
   'Syslog.RFC3164',
   'Syslog.RFC5424',
+  'Windows.EventLog',
+  'Zeek.Conn',
+  'Zeek.DNS',
+  'Zeek.Files',
+  'Zeek.HTTP',
+  'Zeek.Notice',
+  'Zeek.SSL',
+  'Zeek.Weird',
+  'Zeek.X509',
 ] as const;
 
 export const SEVERITY_COLOR_MAP: { [key in SeverityEnum]: BadgeProps['color'] } = {